@@ -0,0 +1,107 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Candle is one OHLCV bar built from trades
+type Candle struct {
+	Start  time.Time
+	Open   decimal.Decimal
+	High   decimal.Decimal
+	Low    decimal.Decimal
+	Close  decimal.Decimal
+	Volume decimal.Decimal
+	Trades int64
+
+	openAt, closeAt time.Time
+}
+
+// CandleBuilder buckets trades into fixed-interval bars aligned to the interval (e.g. 5m bars start
+// at :00, :05, ...). Trades may be added in any order.
+type CandleBuilder struct {
+	interval time.Duration
+	candles  map[int64]*Candle
+
+	pricePrecision Precision
+	sizePrecision  Precision
+}
+
+func NewCandleBuilder(interval time.Duration) *CandleBuilder {
+	return &CandleBuilder{interval: interval, candles: make(map[int64]*Candle)}
+}
+
+// Add folds one trade into its bar; trades with unparseable price or size are rejected
+func (b *CandleBuilder) Add(at time.Time, price, size string) error {
+	px, err := ParseDecimal(price)
+	if err != nil {
+		return err
+	}
+	qty, err := ParseDecimal(size)
+	if err != nil {
+		return err
+	}
+
+	start := at.Truncate(b.interval)
+	c, ok := b.candles[start.UnixNano()]
+	if !ok {
+		c = &Candle{Start: start.UTC(), Open: px, High: px, Low: px, Close: px, openAt: at, closeAt: at}
+		b.candles[start.UnixNano()] = c
+	}
+
+	if at.Before(c.openAt) {
+		c.Open, c.openAt = px, at
+	}
+	if !at.Before(c.closeAt) {
+		c.Close, c.closeAt = px, at
+	}
+	if px.GreaterThan(c.High) {
+		c.High = px
+	}
+	if px.LessThan(c.Low) {
+		c.Low = px
+	}
+	c.Volume = c.Volume.Add(qty)
+	c.Trades++
+
+	b.pricePrecision.Observe(price)
+	b.sizePrecision.Observe(size)
+	return nil
+}
+
+// Candles returns the bars oldest first. Intervals without trades are omitted.
+func (b *CandleBuilder) Candles() []Candle {
+	result := make([]Candle, 0, len(b.candles))
+	for _, c := range b.candles {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+	return result
+}
+
+func (b *CandleBuilder) FormatPrice(d decimal.Decimal) string {
+	return b.pricePrecision.Format(d)
+}
+
+func (b *CandleBuilder) FormatSize(d decimal.Decimal) string {
+	return b.sizePrecision.Format(d)
+}
@@ -0,0 +1,57 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCandleBuilder(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewCandleBuilder(5 * time.Minute)
+
+	// Out of order within the first bar, one trade in the next bar
+	b.Add(base.Add(4*time.Minute), "101.50", "1")
+	b.Add(base.Add(time.Minute), "100.00", "0.5")
+	b.Add(base.Add(2*time.Minute), "103.25", "2")
+	b.Add(base.Add(3*time.Minute), "99.75", "0.25")
+	b.Add(base.Add(6*time.Minute), "102.00", "1")
+	if err := b.Add(base, "bad", "1"); err == nil {
+		t.Fatal("Expected error for unparseable price")
+	}
+
+	candles := b.Candles()
+	if len(candles) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(candles))
+	}
+
+	c := candles[0]
+	if !c.Start.Equal(base) || c.Trades != 4 {
+		t.Fatalf("Unexpected first candle %+v", c)
+	}
+	got := []string{b.FormatPrice(c.Open), b.FormatPrice(c.High), b.FormatPrice(c.Low), b.FormatPrice(c.Close), b.FormatSize(c.Volume)}
+	want := []string{"100.00", "103.25", "99.75", "101.50", "3.75"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected OHLCV %v, got %v", want, got)
+		}
+	}
+	if !candles[1].Start.Equal(base.Add(5 * time.Minute)) {
+		t.Fatalf("Expected second candle at 12:05, got %v", candles[1].Start)
+	}
+}
@@ -0,0 +1,96 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analytics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Prices and sizes arrive from FIX as strings. All arithmetic goes through decimal.Decimal
+// so sums and averages never pick up binary floating point error.
+
+// ParseDecimal parses a FIX price or size string
+func ParseDecimal(s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid decimal %q: %v", s, err)
+	}
+	return d, nil
+}
+
+// Places returns the number of digits after the decimal point as the exchange sent them,
+// e.g. "50000.10" -> 2. Trailing zeros are significant and kept.
+func Places(s string) int32 {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, "eE"); i != -1 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '.'); i != -1 {
+		return int32(len(s) - i - 1)
+	}
+	return 0
+}
+
+// IncrementPlaces returns the precision implied by a product increment such as "0.01000000" -> 2.
+// Unlike Places, trailing zeros are not significant here.
+func IncrementPlaces(s string) int32 {
+	s = strings.TrimSpace(s)
+	if strings.IndexByte(s, '.') != -1 && strings.IndexAny(s, "eE") == -1 {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return Places(s)
+}
+
+// Format renders d with exactly places digits after the decimal point
+func Format(d decimal.Decimal, places int32) string {
+	return d.StringFixed(places)
+}
+
+// FormatLike renders d with the same precision as a reference string from the exchange
+func FormatLike(d decimal.Decimal, reference string) string {
+	return Format(d, Places(reference))
+}
+
+// Precision tracks the widest precision seen across a stream of values, so derived numbers
+// (totals, averages) can be printed at exchange precision
+type Precision struct {
+	places int32
+}
+
+func (p *Precision) Observe(s string) {
+	if places := Places(s); places > p.places {
+		p.places = places
+	}
+}
+
+// AtLeast raises the tracked precision to places if it is currently lower
+func (p *Precision) AtLeast(places int32) {
+	if places > p.places {
+		p.places = places
+	}
+}
+
+func (p Precision) Places() int32 {
+	return p.places
+}
+
+func (p Precision) Format(d decimal.Decimal) string {
+	return Format(d, p.places)
+}
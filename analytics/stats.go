@@ -0,0 +1,100 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analytics
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// TradeStats accumulates count, volume, notional, VWAP and range over a stream of trades
+type TradeStats struct {
+	Count    int64
+	Volume   decimal.Decimal
+	Notional decimal.Decimal
+	High     decimal.Decimal
+	Low      decimal.Decimal
+	Last     decimal.Decimal
+
+	pricePrecision Precision
+	sizePrecision  Precision
+	quoteKnown     bool // The product's quote increment was given; see FormatNotional
+}
+
+// Add folds one trade into the stats; trades with unparseable price or size are rejected
+func (s *TradeStats) Add(price, size string) error {
+	px, err := ParseDecimal(price)
+	if err != nil {
+		return err
+	}
+	qty, err := ParseDecimal(size)
+	if err != nil {
+		return err
+	}
+
+	if s.Count == 0 || px.GreaterThan(s.High) {
+		s.High = px
+	}
+	if s.Count == 0 || px.LessThan(s.Low) {
+		s.Low = px
+	}
+	s.Last = px
+	s.Count++
+	s.Volume = s.Volume.Add(qty)
+	s.Notional = s.Notional.Add(px.Mul(qty))
+
+	s.pricePrecision.Observe(price)
+	s.sizePrecision.Observe(size)
+	return nil
+}
+
+// UseIncrements widens display precision to the product's quote (price) and base (size) increments,
+// so totals are not printed with fewer digits than the product trades in. Empty increments are ignored.
+func (s *TradeStats) UseIncrements(quoteIncrement, baseIncrement string) {
+	if quoteIncrement != "" {
+		s.pricePrecision.AtLeast(IncrementPlaces(quoteIncrement))
+		s.quoteKnown = true
+	}
+	if baseIncrement != "" {
+		s.sizePrecision.AtLeast(IncrementPlaces(baseIncrement))
+	}
+}
+
+// Vwap returns notional / volume, or zero when no volume has traded
+func (s *TradeStats) Vwap() decimal.Decimal {
+	if s.Volume.IsZero() {
+		return decimal.Zero
+	}
+	// Keep a few extra digits before rounding for display
+	return s.Notional.DivRound(s.Volume, s.pricePrecision.Places()+4)
+}
+
+func (s *TradeStats) FormatPrice(d decimal.Decimal) string {
+	return s.pricePrecision.Format(d)
+}
+
+func (s *TradeStats) FormatSize(d decimal.Decimal) string {
+	return s.sizePrecision.Format(d)
+}
+
+// FormatNotional uses price precision once the quote increment is known, since notional is in the
+// quote currency. Before that it uses price+size precision, which is exact for a product of the two.
+func (s *TradeStats) FormatNotional(d decimal.Decimal) string {
+	if s.quoteKnown {
+		return s.pricePrecision.Format(d)
+	}
+	return Format(d, s.pricePrecision.Places()+s.sizePrecision.Places())
+}
@@ -0,0 +1,133 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analytics
+
+import (
+	"testing"
+)
+
+func TestPlaces(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int32
+	}{
+		{"50000", 0},
+		{"50000.10", 2},
+		{"0.00010000", 8},
+		{" 1.5 ", 1},
+		{"1.25e3", 2},
+	}
+
+	for _, tc := range testCases {
+		if got := Places(tc.input); got != tc.expected {
+			t.Fatalf("Places(%q): expected %d, got %d", tc.input, tc.expected, got)
+		}
+	}
+}
+
+func TestRoundTripPreservesPrecision(t *testing.T) {
+	for _, s := range []string{"50000.10", "0.00010000", "3000", "1.50"} {
+		d, err := ParseDecimal(s)
+		if err != nil {
+			t.Fatalf("ParseDecimal(%q): %v", s, err)
+		}
+		if got := FormatLike(d, s); got != s {
+			t.Fatalf("Round trip of %q produced %q", s, got)
+		}
+	}
+
+	if _, err := ParseDecimal("abc"); err == nil {
+		t.Fatal("Expected error for invalid decimal")
+	}
+}
+
+func TestTradeStatsVwapIsExact(t *testing.T) {
+	var stats TradeStats
+
+	// Values that accumulate error with float64 arithmetic
+	for i := 0; i < 10; i++ {
+		if err := stats.Add("0.10", "0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stats.Add("0.40", "1.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Count != 11 {
+		t.Fatalf("Expected 11 trades, got %d", stats.Count)
+	}
+	if got := stats.FormatSize(stats.Volume); got != "2.0" {
+		t.Fatalf("Expected volume 2.0, got %s", got)
+	}
+	if got := stats.FormatNotional(stats.Notional); got != "0.500" {
+		t.Fatalf("Expected notional 0.500, got %s", got)
+	}
+	if got := stats.FormatPrice(stats.Vwap()); got != "0.25" {
+		t.Fatalf("Expected VWAP 0.25, got %s", got)
+	}
+	if got := stats.FormatPrice(stats.High); got != "0.40" {
+		t.Fatalf("Expected high 0.40, got %s", got)
+	}
+	if got := stats.FormatPrice(stats.Low); got != "0.10" {
+		t.Fatalf("Expected low 0.10, got %s", got)
+	}
+}
+
+func TestTradeStatsRejectsInvalid(t *testing.T) {
+	var stats TradeStats
+	if err := stats.Add("", "1"); err == nil {
+		t.Fatal("Expected error for empty price")
+	}
+	if stats.Count != 0 {
+		t.Fatal("Invalid trade should not be counted")
+	}
+	if !stats.Vwap().IsZero() {
+		t.Fatal("Expected zero VWAP with no volume")
+	}
+}
+
+func TestTradeStatsUseIncrements(t *testing.T) {
+	if got := IncrementPlaces("0.01000000"); got != 2 {
+		t.Fatalf("IncrementPlaces: expected 2, got %d", got)
+	}
+	if got := IncrementPlaces("1"); got != 0 {
+		t.Fatalf("IncrementPlaces: expected 0, got %d", got)
+	}
+
+	var stats TradeStats
+	if err := stats.Add("50000", "1"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	stats.UseIncrements("0.01", "0.00000001")
+
+	if got := stats.FormatPrice(stats.Last); got != "50000.00" {
+		t.Fatalf("Expected price at quote increment precision, got %s", got)
+	}
+	if got := stats.FormatSize(stats.Volume); got != "1.00000000" {
+		t.Fatalf("Expected size at base increment precision, got %s", got)
+	}
+	if got := stats.FormatNotional(stats.Notional); got != "50000.00" {
+		t.Fatalf("Expected notional at quote increment precision, got %s", got)
+	}
+
+	// Increments never reduce precision the exchange actually sent
+	stats.UseIncrements("1", "")
+	if got := stats.FormatPrice(stats.Last); got != "50000.00" {
+		t.Fatalf("Expected precision to be kept, got %s", got)
+	}
+}
@@ -0,0 +1,78 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// CustomTag is an extra tag=value appended to outgoing Logon and MarketDataRequest bodies,
+// for gateway-specific extensions
+type CustomTag struct {
+	Tag   quickfix.Tag
+	Value string
+}
+
+func (c CustomTag) String() string {
+	return fmt.Sprintf("%d=%s", c.Tag, c.Value)
+}
+
+// Tags owned by the session layer; overriding them would corrupt the message
+var reservedTags = map[quickfix.Tag]bool{
+	8: true, 9: true, 10: true, 34: true, 35: true, 49: true, 52: true, 56: true,
+}
+
+// ParseCustomTag parses a "tag=value" pair such as "9999=foo"
+func ParseCustomTag(s string) (CustomTag, error) {
+	tagStr, value, ok := strings.Cut(strings.TrimSpace(s), "=")
+	if !ok || value == "" {
+		return CustomTag{}, fmt.Errorf("invalid custom tag %q: expected tag=value", s)
+	}
+
+	tag, err := strconv.Atoi(tagStr)
+	if err != nil || tag <= 0 {
+		return CustomTag{}, fmt.Errorf("invalid custom tag %q: tag must be a positive number", s)
+	}
+	if reservedTags[quickfix.Tag(tag)] {
+		return CustomTag{}, fmt.Errorf("invalid custom tag %q: tag %d is managed by the session", s, tag)
+	}
+
+	return CustomTag{Tag: quickfix.Tag(tag), Value: value}, nil
+}
+
+func ParseCustomTags(pairs []string) ([]CustomTag, error) {
+	tags := make([]CustomTag, 0, len(pairs))
+	for _, pair := range pairs {
+		tag, err := ParseCustomTag(pair)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// ApplyCustomTags sets each custom tag on fs; later entries win over earlier ones and over built-in fields
+func ApplyCustomTags(fs FieldSetter, tags []CustomTag) {
+	for _, tag := range tags {
+		setString(fs, tag.Tag, tag.Value)
+	}
+}
@@ -0,0 +1,57 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"prime-fix-md-go/constants"
+)
+
+func TestParseCustomTag(t *testing.T) {
+	tag, err := ParseCustomTag(" 9999=foo=bar ")
+	if err != nil {
+		t.Fatalf("Expected valid tag, got %v", err)
+	}
+	if tag.Tag != 9999 || tag.Value != "foo=bar" {
+		t.Fatalf("Unexpected tag %s", tag)
+	}
+
+	for _, invalid := range []string{"9999", "9999=", "abc=1", "-5=1", "35=V", "52=now"} {
+		if _, err := ParseCustomTag(invalid); err == nil {
+			t.Fatalf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestApplyCustomTagsToMarketDataRequest(t *testing.T) {
+	tags, err := ParseCustomTags([]string{"9999=foo", "264=5", "9999=bar"})
+	if err != nil {
+		t.Fatalf("Failed to parse tags: %v", err)
+	}
+
+	msg := BuildMarketDataRequest("req", []string{"BTC-USD"}, constants.SubscriptionRequestTypeSnapshot, "0",
+		"SENDER", "TARGET", []string{constants.MdEntryTypeTrade})
+	ApplyCustomTags(&msg.Body, tags)
+
+	if v, _ := msg.Body.GetString(9999); v != "bar" {
+		t.Fatalf("Expected later tag to win, got %q", v)
+	}
+	if v, _ := msg.Body.GetString(constants.TagMarketDepth); v != "5" {
+		t.Fatalf("Expected custom tag to override MarketDepth, got %q", v)
+	}
+}
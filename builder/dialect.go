@@ -0,0 +1,46 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"prime-fix-md-go/constants"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Dialect holds the FIX version settings stamped on outgoing application messages,
+// for gateways that expect something other than the FIXT.1.1 defaults
+type Dialect struct {
+	BeginString string            // Header BeginString (8); empty keeps FIXT.1.1
+	ApplVerIds  map[string]string // MsgType (35) -> ApplVerID (1128) sent on that message type
+}
+
+// Apply sets BeginString and, when configured for the message type, ApplVerID on the header.
+// An ApplVerID already present (e.g. typed into a raw message) is kept.
+func (d Dialect) Apply(msg *quickfix.Message) {
+	if d.BeginString != "" {
+		setString(&msg.Header, constants.TagBeginString, d.BeginString)
+	}
+
+	msgType, err := msg.Header.GetString(constants.TagMsgType)
+	if err != nil {
+		return
+	}
+	if applVerId, ok := d.ApplVerIds[msgType]; ok && applVerId != "" && !msg.Header.Has(constants.TagApplVerId) {
+		setString(&msg.Header, constants.TagApplVerId, applVerId)
+	}
+}
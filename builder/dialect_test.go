@@ -0,0 +1,56 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"prime-fix-md-go/constants"
+)
+
+func TestDialectApply(t *testing.T) {
+	dialect := Dialect{BeginString: "FIX.4.4", ApplVerIds: map[string]string{"V": "9"}}
+
+	msg := BuildMarketDataRequest("req", []string{"BTC-USD"}, constants.SubscriptionRequestTypeSnapshot, "0",
+		"SENDER", "TARGET", []string{constants.MdEntryTypeTrade})
+	dialect.Apply(msg)
+
+	if got, _ := msg.Header.GetString(constants.TagBeginString); got != "FIX.4.4" {
+		t.Fatalf("Expected BeginString FIX.4.4, got %s", got)
+	}
+	if got, _ := msg.Header.GetString(constants.TagApplVerId); got != "9" {
+		t.Fatalf("Expected ApplVerID 9, got %s", got)
+	}
+
+	// An explicit ApplVerID in a raw message wins over the configured one
+	raw, err := BuildRawMessage("35=V|1128=7|262=test", "SENDER", "TARGET")
+	if err != nil {
+		t.Fatalf("BuildRawMessage: %v", err)
+	}
+	dialect.Apply(raw)
+	if got, _ := raw.Header.GetString(constants.TagApplVerId); got != "7" {
+		t.Fatalf("Expected raw ApplVerID 7 to be kept, got %s", got)
+	}
+
+	// The zero value leaves the defaults alone
+	plain := BuildMarketDataRequest("req", []string{"BTC-USD"}, constants.SubscriptionRequestTypeSnapshot, "0",
+		"SENDER", "TARGET", []string{constants.MdEntryTypeTrade})
+	Dialect{}.Apply(plain)
+	if got, _ := plain.Header.GetString(constants.TagBeginString); got != constants.FixBeginString || plain.Header.Has(constants.TagApplVerId) {
+		t.Fatalf("Expected default dialect, got BeginString %s", got)
+	}
+}
@@ -0,0 +1,66 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+// Default SecurityIDSource (22) when only a SecurityID is given: 8 = Exchange Symbol
+const DefaultSecurityIdSource = "8"
+
+// Instrument identifies one entry of the NoRelatedSym (146) group, either by Symbol (55)
+// or by SecurityID (48) with its SecurityIDSource (22)
+type Instrument struct {
+	Symbol           string
+	SecurityId       string
+	SecurityIdSource string
+}
+
+// Key is the identifier used to track the instrument locally: the symbol when known, else the SecurityID
+func (i Instrument) Key() string {
+	if i.Symbol != "" {
+		return i.Symbol
+	}
+	return i.SecurityId
+}
+
+func (i Instrument) String() string {
+	if i.SecurityId == "" {
+		return i.Symbol
+	}
+	id := i.SecurityId + " (source " + i.SecurityIdSource + ")"
+	if i.Symbol != "" {
+		return i.Symbol + " / " + id
+	}
+	return id
+}
+
+func SymbolInstruments(symbols []string) []Instrument {
+	instruments := make([]Instrument, 0, len(symbols))
+	for _, symbol := range symbols {
+		instruments = append(instruments, Instrument{Symbol: symbol})
+	}
+	return instruments
+}
+
+func SecurityIdInstruments(securityIds []string, source string) []Instrument {
+	if source == "" {
+		source = DefaultSecurityIdSource
+	}
+	instruments := make([]Instrument, 0, len(securityIds))
+	for _, id := range securityIds {
+		instruments = append(instruments, Instrument{SecurityId: id, SecurityIdSource: source})
+	}
+	return instruments
+}
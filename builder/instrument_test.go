@@ -0,0 +1,39 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"prime-fix-md-go/constants"
+)
+
+func TestMarketDataRequestWithSecurityId(t *testing.T) {
+	instruments := append(SymbolInstruments([]string{"BTC-USD"}), SecurityIdInstruments([]string{"ETH-USD"}, "")...)
+	msg := BuildMarketDataRequestForInstruments("req", instruments, constants.SubscriptionRequestTypeSnapshot, "0",
+		"SENDER", "TARGET", []string{constants.MdEntryTypeTrade})
+
+	raw := strings.ReplaceAll(msg.String(), "\x01", "|")
+	if !strings.Contains(raw, "|146=2|55=BTC-USD|48=ETH-USD|22=8|") {
+		t.Fatalf("Expected one symbol entry and one SecurityID entry, got %s", raw)
+	}
+
+	if key := instruments[1].Key(); key != "ETH-USD" {
+		t.Fatalf("Expected SecurityID as key, got %s", key)
+	}
+}
@@ -0,0 +1,53 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"prime-fix-md-go/constants"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// MdRequestOptions are optional MarketDataRequest settings beyond depth and entry types.
+// The zero value keeps the defaults.
+type MdRequestOptions struct {
+	FullRefresh    bool   // MdUpdateType (265) = full refresh instead of incremental on subscriptions
+	AggregatedBook string // AggregatedBook (266): Y, N, or empty to leave it to the venue
+}
+
+// BookMode describes the requested aggregation, or "" when it was left to the venue
+func (o MdRequestOptions) BookMode() string {
+	switch o.AggregatedBook {
+	case constants.AggregatedBookYes:
+		return "aggregated"
+	case constants.AggregatedBookNo:
+		return "unaggregated"
+	default:
+		return ""
+	}
+}
+
+// ApplyMdRequestOptions sets the optional fields on a MarketDataRequest built by BuildMarketDataRequest
+func ApplyMdRequestOptions(msg *quickfix.Message, opts MdRequestOptions) {
+	subscriptionType, _ := msg.Body.GetString(constants.TagSubscriptionRequestType)
+	if opts.FullRefresh && subscriptionType == constants.SubscriptionRequestTypeSubscribe {
+		setString(&msg.Body, constants.TagMdUpdateType, constants.MdUpdateTypeFullRefresh)
+	}
+	if opts.AggregatedBook != "" && subscriptionType != constants.SubscriptionRequestTypeUnsubscribe {
+		setString(&msg.Body, constants.TagAggregatedBook, opts.AggregatedBook)
+	}
+}
@@ -0,0 +1,72 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"prime-fix-md-go/constants"
+)
+
+func TestApplyMdRequestOptionsFullRefresh(t *testing.T) {
+	msg := BuildMarketDataRequest("req", []string{"BTC-USD"}, constants.SubscriptionRequestTypeSubscribe, "10",
+		"SENDER", "TARGET", []string{constants.MdEntryTypeBid, constants.MdEntryTypeOffer})
+	ApplyMdRequestOptions(msg, MdRequestOptions{})
+	if v, _ := msg.Body.GetString(constants.TagMdUpdateType); v != constants.MdUpdateTypeIncremental {
+		t.Fatalf("Expected incremental updates by default, got %q", v)
+	}
+
+	ApplyMdRequestOptions(msg, MdRequestOptions{FullRefresh: true})
+	if v, _ := msg.Body.GetString(constants.TagMdUpdateType); v != constants.MdUpdateTypeFullRefresh {
+		t.Fatalf("Expected full refresh, got %q", v)
+	}
+
+	snapshot := BuildMarketDataRequest("req", []string{"BTC-USD"}, constants.SubscriptionRequestTypeSnapshot, "10",
+		"SENDER", "TARGET", []string{constants.MdEntryTypeBid})
+	ApplyMdRequestOptions(snapshot, MdRequestOptions{FullRefresh: true})
+	if snapshot.Body.Has(constants.TagMdUpdateType) {
+		t.Fatalf("Expected no MdUpdateType on a snapshot request")
+	}
+}
+
+func TestApplyMdRequestOptionsAggregatedBook(t *testing.T) {
+	opts := MdRequestOptions{AggregatedBook: constants.AggregatedBookNo}
+	if opts.BookMode() != "unaggregated" {
+		t.Fatalf("Unexpected book mode %q", opts.BookMode())
+	}
+
+	msg := BuildMarketDataRequest("req", []string{"BTC-USD"}, constants.SubscriptionRequestTypeSnapshot, "10",
+		"SENDER", "TARGET", []string{constants.MdEntryTypeBid})
+	ApplyMdRequestOptions(msg, opts)
+	if v, _ := msg.Body.GetString(constants.TagAggregatedBook); v != constants.AggregatedBookNo {
+		t.Fatalf("Expected AggregatedBook=N, got %q", v)
+	}
+
+	unsubscribe := BuildMarketDataRequest("req", []string{"BTC-USD"}, constants.SubscriptionRequestTypeUnsubscribe, "0",
+		"SENDER", "TARGET", []string{constants.MdEntryTypeBid})
+	ApplyMdRequestOptions(unsubscribe, opts)
+	if unsubscribe.Body.Has(constants.TagAggregatedBook) {
+		t.Fatalf("Expected no AggregatedBook on an unsubscribe")
+	}
+
+	defaults := BuildMarketDataRequest("req", []string{"BTC-USD"}, constants.SubscriptionRequestTypeSnapshot, "10",
+		"SENDER", "TARGET", []string{constants.MdEntryTypeBid})
+	ApplyMdRequestOptions(defaults, MdRequestOptions{})
+	if defaults.Body.Has(constants.TagAggregatedBook) {
+		t.Fatalf("Expected AggregatedBook to be left to the venue by default")
+	}
+}
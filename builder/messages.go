@@ -57,6 +57,21 @@ func BuildMarketDataRequest(
 	senderCompId string,
 	targetCompId string,
 	mdEntryTypes []string,
+) *quickfix.Message {
+	return BuildMarketDataRequestForInstruments(mdReqId, SymbolInstruments(symbols), subscriptionRequestType,
+		marketDepth, senderCompId, targetCompId, mdEntryTypes)
+}
+
+// BuildMarketDataRequestForInstruments is BuildMarketDataRequest for instruments that may be
+// identified by SecurityID (48) / SecurityIDSource (22) instead of Symbol (55)
+func BuildMarketDataRequestForInstruments(
+	mdReqId string,
+	instruments []Instrument,
+	subscriptionRequestType string,
+	marketDepth string,
+	senderCompId string,
+	targetCompId string,
+	mdEntryTypes []string,
 ) *quickfix.Message {
 	m := quickfix.NewMessage()
 	setString(&m.Header, constants.TagBeginString, constants.FixBeginString)
@@ -85,11 +100,22 @@ func BuildMarketDataRequest(
 
 	relatedSymGroup := quickfix.NewRepeatingGroup(
 		constants.TagNoRelatedSym,
-		quickfix.GroupTemplate{quickfix.GroupElement(constants.TagSymbol)},
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(constants.TagSymbol),
+			quickfix.GroupElement(constants.TagSecurityId),
+			quickfix.GroupElement(constants.TagSecurityIdSource),
+		},
 	)
 
-	for _, symbol := range symbols {
-		setString(relatedSymGroup.Add(), constants.TagSymbol, symbol)
+	for _, instrument := range instruments {
+		entry := relatedSymGroup.Add()
+		if instrument.Symbol != "" {
+			setString(entry, constants.TagSymbol, instrument.Symbol)
+		}
+		if instrument.SecurityId != "" {
+			setString(entry, constants.TagSecurityId, instrument.SecurityId)
+			setString(entry, constants.TagSecurityIdSource, instrument.SecurityIdSource)
+		}
 	}
 	m.Body.SetGroup(relatedSymGroup)
 	return m
@@ -0,0 +1,153 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"prime-fix-md-go/constants"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Standard header tags that may be given in a raw message; everything else goes in the body
+var rawHeaderTags = map[quickfix.Tag]bool{
+	35: true, 43: true, 49: true, 50: true, 52: true, 56: true, 57: true,
+	97: true, 115: true, 122: true, 128: true, 1128: true,
+}
+
+// Length, checksum and sequence number are computed by the session and cannot be supplied
+var rawManagedTags = map[quickfix.Tag]bool{
+	8: true, 9: true, 10: true, 34: true,
+}
+
+// Body fields are written in tag order, so repeating groups must be declared to keep their
+// members together. These cover the market data messages this client deals with.
+var rawGroupTemplates = map[quickfix.Tag][]quickfix.Tag{
+	constants.TagNoMdEntryTypes: {constants.TagMdEntryType},
+	constants.TagNoRelatedSym:   {constants.TagSymbol, 48, 22, 460, 167},
+	constants.TagNoMdEntries: {
+		constants.TagMdUpdateAction, constants.TagMdEntryType, constants.TagMdEntryId, constants.TagMdEntryPx,
+		constants.TagMdEntrySize, constants.TagMdEntryDate, constants.TagMdEntryTime, constants.TagQuoteCondition,
+		constants.TagTradeCondition, constants.TagMdEntryPositionNo, constants.TagNumberOfOrders, constants.TagAggressorSide,
+	},
+}
+
+type rawField struct {
+	tag   quickfix.Tag
+	value string
+}
+
+// BuildRawMessage builds a message from "tag=value" pairs separated by '|' or SOH, e.g.
+// "35=V|262=test|263=0|264=1|267=1|269=2|146=1|55=BTC-USD". MsgType (35) is required;
+// BeginString, SenderCompID, TargetCompID and SendingTime are filled in unless given.
+// Group counts (e.g. 267, 146) are recomputed from the entries that follow them.
+func BuildRawMessage(raw, senderCompId, targetCompId string) (*quickfix.Message, error) {
+	fields, err := parseRawFields(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	m := quickfix.NewMessage()
+	setString(&m.Header, constants.TagBeginString, constants.FixBeginString)
+	setString(&m.Header, constants.TagSenderCompId, senderCompId)
+	setString(&m.Header, constants.TagTargetCompId, targetCompId)
+	setString(&m.Header, constants.TagSendingTime, time.Now().UTC().Format(constants.FixTimeFormat))
+
+	hasMsgType := false
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case rawHeaderTags[f.tag]:
+			hasMsgType = hasMsgType || f.tag == constants.TagMsgType
+			setString(&m.Header, f.tag, f.value)
+		case rawGroupTemplates[f.tag] != nil:
+			consumed := addRawGroup(&m.Body, f.tag, fields[i+1:])
+			i += consumed
+		default:
+			setString(&m.Body, f.tag, f.value)
+		}
+	}
+
+	if !hasMsgType {
+		return nil, fmt.Errorf("raw message must include MsgType (35)")
+	}
+	return m, nil
+}
+
+func parseRawFields(raw string) ([]rawField, error) {
+	raw = strings.ReplaceAll(raw, "\x01", "|")
+
+	var fields []rawField
+	for _, pair := range strings.Split(raw, "|") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		tagStr, value, ok := strings.Cut(pair, "=")
+		tagNum, err := strconv.Atoi(tagStr)
+		if !ok || err != nil || tagNum <= 0 {
+			return nil, fmt.Errorf("invalid field %q: expected tag=value", pair)
+		}
+		tag := quickfix.Tag(tagNum)
+		if rawManagedTags[tag] {
+			return nil, fmt.Errorf("tag %d is set by the session and cannot be supplied", tag)
+		}
+		fields = append(fields, rawField{tag: tag, value: value})
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty raw message")
+	}
+	return fields, nil
+}
+
+// addRawGroup consumes the group members that follow a count tag and returns how many fields it used.
+// A new entry starts whenever a tag repeats within the current one.
+func addRawGroup(body *quickfix.Body, countTag quickfix.Tag, rest []rawField) int {
+	template := rawGroupTemplates[countTag]
+	members := make(map[quickfix.Tag]bool, len(template))
+	groupTemplate := make(quickfix.GroupTemplate, 0, len(template))
+	for _, tag := range template {
+		members[tag] = true
+		groupTemplate = append(groupTemplate, quickfix.GroupElement(tag))
+	}
+
+	group := quickfix.NewRepeatingGroup(countTag, groupTemplate)
+	var entry *quickfix.Group
+	seen := map[quickfix.Tag]bool{}
+
+	consumed := 0
+	for _, f := range rest {
+		if !members[f.tag] {
+			break
+		}
+		if entry == nil || seen[f.tag] {
+			entry = group.Add()
+			seen = map[quickfix.Tag]bool{}
+		}
+		setString(entry, f.tag, f.value)
+		seen[f.tag] = true
+		consumed++
+	}
+
+	body.SetGroup(group)
+	return consumed
+}
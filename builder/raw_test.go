@@ -0,0 +1,53 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"prime-fix-md-go/constants"
+)
+
+func TestBuildRawMessageKeepsGroupsTogether(t *testing.T) {
+	msg, err := BuildRawMessage("35=V|262=test|263=0|264=1|267=2|269=0|269=1|146=1|55=BTC-USD", "SENDER", "TARGET")
+	if err != nil {
+		t.Fatalf("Failed to build raw message: %v", err)
+	}
+
+	if v, _ := msg.Header.GetString(constants.TagMsgType); v != "V" {
+		t.Fatalf("Expected MsgType V in header, got %q", v)
+	}
+	if v, _ := msg.Header.GetString(constants.TagSenderCompId); v != "SENDER" {
+		t.Fatalf("Expected SenderCompID to be filled in, got %q", v)
+	}
+
+	out := strings.ReplaceAll(msg.String(), "\x01", "|")
+	for _, want := range []string{"|146=1|55=BTC-USD|", "|267=2|269=0|269=1|", "|262=test|"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Expected %q in %s", want, out)
+		}
+	}
+}
+
+func TestBuildRawMessageErrors(t *testing.T) {
+	for _, raw := range []string{"", "262=test", "35=V|abc", "35=V|34=5", "35=V|10=000"} {
+		if _, err := BuildRawMessage(raw, "S", "T"); err == nil {
+			t.Fatalf("Expected error for %q", raw)
+		}
+	}
+}
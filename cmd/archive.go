@@ -0,0 +1,58 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+// runArchive implements "archive -older-than DURATION [-db FILE] [-dir DIR]" for one-off archiving
+func runArchive(args []string) error {
+	fs := flag.NewFlagSet("archive", flag.ContinueOnError)
+	dbPath := fs.String("db", "marketdata.db", "database to archive from")
+	dir := fs.String("dir", "archive", "directory for the compressed archive files")
+	olderThan := fs.Duration("older-than", 0, "archive rows received longer ago than this, e.g. 168h")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *olderThan <= 0 {
+		fs.Usage()
+		return errors.New("-older-than is required")
+	}
+
+	db, err := database.NewMarketDataDb(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stats, err := db.Archive(*dir, time.Now().Add(-*olderThan))
+	if err != nil {
+		return err
+	}
+	if stats.Path == "" {
+		fmt.Printf("Nothing in %s older than %s\n", *dbPath, *olderThan)
+		return nil
+	}
+	fmt.Printf("Archived %d trades, %d book entries and %d ohlcv rows to %s\n",
+		stats.Trades, stats.OrderBook, stats.Ohlcv, stats.Path)
+	return nil
+}
@@ -0,0 +1,52 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"prime-fix-md-go/config"
+	"prime-fix-md-go/fixclient"
+)
+
+// runAttach implements "attach [-config FILE] [-socket PATH]": it connects to the control socket
+// of a daemon started with --daemon and runs commands in it from this terminal
+func runAttach(args []string) error {
+	fs := flag.NewFlagSet("attach", flag.ContinueOnError)
+	configPath := fs.String("config", "config.json", "config file the daemon was started with, for daemon.controlSocket")
+	socket := fs.String("socket", "", "control socket path; overrides daemon.controlSocket")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: attach [-config FILE] [-socket PATH]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := *socket
+	if path == "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			return err
+		}
+		path = cfg.Daemon.ControlSocket
+	}
+	if path == "" {
+		return fmt.Errorf("no control socket: daemon.controlSocket is empty in %s and -socket was not given", *configPath)
+	}
+	return fixclient.Attach(path)
+}
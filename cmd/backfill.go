@@ -0,0 +1,114 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"prime-fix-md-go/config"
+	"prime-fix-md-go/database"
+	"prime-fix-md-go/fixclient"
+)
+
+// runBackfill implements "backfill -symbols A,B -from TIME [-to TIME] [-db FILE] [-config FILE]
+// [-arrow-trades PATH] [-arrow-book PATH]": stored data is written to the Arrow streams without
+// starting a session
+func runBackfill(args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	configPath := fs.String("config", "config.json", "application config file; arrow.trades and arrow.book are the default outputs")
+	dbPath := fs.String("db", "marketdata.db", "database to read from")
+	symbols := fs.String("symbols", "", "comma-separated symbols to backfill, e.g. BTC-USD,ETH-USD")
+	fromFlag := fs.String("from", "", "start of the window: RFC 3339 or YYYY-MM-DD")
+	toFlag := fs.String("to", "", "end of the window (default now)")
+	arrowTrades := fs.String("arrow-trades", "", "write trades as Arrow IPC to this file, or - for stdout; overrides arrow.trades")
+	arrowBook := fs.String("arrow-book", "", "write book entries as Arrow IPC to this file, or - for stdout; overrides arrow.book")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *symbols == "" || *fromFlag == "" {
+		fs.Usage()
+		return errors.New("-symbols and -from are required")
+	}
+
+	from, err := parseBackfillTime(*fromFlag)
+	if err != nil {
+		return fmt.Errorf("invalid -from %q (RFC 3339 or YYYY-MM-DD)", *fromFlag)
+	}
+	var to time.Time
+	if *toFlag != "" {
+		if to, err = parseBackfillTime(*toFlag); err != nil {
+			return fmt.Errorf("invalid -to %q (RFC 3339 or YYYY-MM-DD)", *toFlag)
+		}
+	}
+
+	appConfig, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	if *arrowTrades != "" {
+		appConfig.Arrow.Trades = *arrowTrades
+	}
+	if *arrowBook != "" {
+		appConfig.Arrow.Book = *arrowBook
+	}
+
+	db, err := database.NewMarketDataDb(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Progress goes to stderr when a stream owns stdout
+	report := os.Stdout
+	if appConfig.Arrow.Trades == fixclient.ArrowStdout || appConfig.Arrow.Book == fixclient.ArrowStdout {
+		report = os.Stderr
+	}
+	arrow, err := fixclient.OpenArrowOutput(appConfig.Arrow.Trades, appConfig.Arrow.Book, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	app := fixclient.NewFixApp(nil, db)
+	app.Arrow = arrow
+	var list []string
+	for _, symbol := range strings.Split(*symbols, ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			list = append(list, symbol)
+		}
+	}
+	stats, err := app.Backfill(list, from, to)
+	if closeErr := arrow.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(report, "Backfilled %d messages (%d trades, %d book entries) for %s\n",
+		stats.Messages, stats.Trades, stats.BookEntries, strings.Join(list, ", "))
+	return nil
+}
+
+func parseBackfillTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -17,37 +17,153 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"prime-fix-md-go/builder"
+	"prime-fix-md-go/config"
 	"prime-fix-md-go/database"
 	"prime-fix-md-go/fixclient"
 	"prime-fix-md-go/formatter"
+	"prime-fix-md-go/notify"
+	"prime-fix-md-go/primeapi"
+	"prime-fix-md-go/products"
+	"prime-fix-md-go/tracing"
 	"prime-fix-md-go/utils"
 
 	"github.com/quickfixgo/quickfix"
+	quickfixconfig "github.com/quickfixgo/quickfix/config"
 )
 
+// Session setting in fix.cfg that selects the portfolio (Account) for that session
+const portfolioIdSetting = "PortfolioId"
+
+// stringList collects a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
 func main() {
-	fmt.Printf("%s\n\n", utils.FullVersion())
+	if len(os.Args) > 1 {
+		subcommands := map[string]func([]string) error{"merge": runMerge, "archive": runArchive, "backfill": runBackfill, "attach": runAttach}
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
 
-	settings, err := utils.LoadSettings("fix.cfg")
+	var customTags stringList
+	flag.Var(&customTags, "tag", "extra tag=value appended to Logon and MarketDataRequest messages (repeatable)")
+	configPath := flag.String("config", "config.json", "path to the application config file")
+	portfolio := flag.String("portfolio", "", "portfolio name from config.json or a portfolio ID; overrides PRIME_PORTFOLIO_ID")
+	outputFormat := flag.String("output", fixclient.OutputTable, "console output format: table, plain, json or quiet")
+	arrowTrades := flag.String("arrow-trades", "", "stream trades as Arrow IPC to this file, or - for stdout")
+	arrowBook := flag.String("arrow-book", "", "stream book entries as Arrow IPC to this file, or - for stdout")
+	noPersist := flag.Bool("no-persist", false, "keep market data in memory only; nothing is written to marketdata.db")
+	timeZone := flag.String("tz", "", "zone for displayed times: UTC, Local or a name such as America/New_York; overrides display.timeZone")
+	quiet := flag.Bool("quiet", false, "start in quiet mode: live updates are stored and counted but not printed")
+	daemon := flag.Bool("daemon", false, "run without the REPL, driven by the subscriptions in config.json, with a PID file and log file")
+	version := flag.Bool("version", false, "print the version and build information and exit")
+	flag.Parse()
+
+	if *version {
+		fmt.Print(utils.VersionDetails())
+		return
+	}
+
+	// Flags override the file, on every reload as well as at startup
+	loadConfig := func() (*config.Config, error) {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			return nil, err
+		}
+		if *arrowTrades != "" {
+			cfg.Arrow.Trades = *arrowTrades
+		}
+		if *arrowBook != "" {
+			cfg.Arrow.Book = *arrowBook
+		}
+		if *noPersist {
+			cfg.Database.Persist = false
+		}
+		if *timeZone != "" {
+			cfg.Display.TimeZone = *timeZone
+		}
+		return cfg, nil
+	}
+	appConfig, err := loadConfig()
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err := fixclient.SetTimeZone(appConfig.Display.TimeZone); err != nil {
+		log.Fatal(err)
+	}
+	if err := fixclient.SetSymbolColors(appConfig.Display.SymbolColors); err != nil {
+		log.Fatal(err)
+	}
 
-	db, err := database.NewMarketDataDb("marketdata.db")
-	if err != nil {
-		log.Fatal("Database initialization failed:", err)
+	// When an Arrow stream owns stdout, everything that would print to the console goes to stderr
+	stdout := os.Stdout
+	if appConfig.Arrow.Trades == fixclient.ArrowStdout || appConfig.Arrow.Book == fixclient.ArrowStdout {
+		os.Stdout = os.Stderr
 	}
 
-	defer func(db *database.MarketDataDb) {
-		err := db.Close()
+	if *daemon {
+		if err := writePidFile(appConfig.Daemon.PidFile); err != nil {
+			log.Fatal(err)
+		}
+		defer os.Remove(appConfig.Daemon.PidFile)
+		logFile, err := openDaemonLog(appConfig.Daemon.LogFile)
 		if err != nil {
-
+			log.Fatal(err)
+		}
+		defer logFile.Close()
+		if len(appConfig.Subscriptions) == 0 {
+			log.Printf("Warning: no subscriptions in the config, so no market data will be requested")
 		}
-	}(db)
+	}
+
+	ascii, err := asciiOutput(appConfig.Display.ASCII)
+	if err != nil {
+		log.Fatal(err)
+	}
+	formatter.SetASCII(ascii)
+	log.SetOutput(formatter.ASCII(log.Writer()))
+
+	fmt.Printf("%s\n\n", utils.FullVersion())
+
+	settings, err := utils.LoadSettings("fix.cfg")
+	if err != nil {
+		log.Fatal(err)
+	}
+	settings, err = applyDialect(settings, appConfig.Fix)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := openDatabase(appConfig.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if db != nil {
+		defer db.Close()
+	}
 
 	config := fixclient.NewConfig(
 		os.Getenv("PRIME_ACCESS_KEY"),
@@ -58,12 +174,125 @@ func main() {
 		os.Getenv("PRIME_PORTFOLIO_ID"),
 	)
 
+	if *portfolio != "" {
+		config.PortfolioId = appConfig.ResolvePortfolio(*portfolio)
+	}
+	config.SessionPortfolios = sessionPortfolios(settings, appConfig)
+
+	config.Dialect = builder.Dialect{
+		BeginString: appConfig.Fix.BeginString,
+		ApplVerIds:  appConfig.Fix.ApplVerIds,
+	}
+
+	// Flag tags come after config tags so they win when the same tag is set twice
+	config.CustomTags, err = builder.ParseCustomTags(append(appConfig.Fix.CustomTags, customTags...))
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	app := fixclient.NewFixApp(config, db)
+	if err := app.SetOutputFormat(*outputFormat); err != nil {
+		log.Fatal(err)
+	}
+	app.Daemon = *daemon
+	app.SetQuiet(*quiet)
+	if err := applySettings(app, appConfig); err != nil {
+		log.Fatal(err)
+	}
+
+	logFactory := formatter.NewTableLogFactoryWithSummary(appConfig.Log.AdminSummaryInterval.Duration())
+	logFactory.Verbose = appConfig.Log.Verbose
+	sessionLogs, err := newSessionLogs(logFactory, appConfig.Log, settings)
+	if err != nil {
+		log.Fatal(err)
+	}
+	app.AdminCounters = logFactory.AdminCounters()
+	app.AdminCounters.SetHalfDeadAlert(appConfig.Session.HalfDeadAlertAfter.Duration())
+	app.Clock = fixclient.NewClockMonitor(appConfig.Clock.SkewWarnThreshold.Duration())
+	uploader, err := newUploader(appConfig.Upload)
+	if err != nil {
+		log.Fatal(err)
+	}
+	app.SetUploader(uploader, appConfig.Upload.Exports, appConfig.Upload.Archives)
+	if appConfig.Rest.Enabled {
+		app.Rest = primeapi.NewClient(appConfig.Rest.BaseUrl, config.ApiKey, config.ApiSecret,
+			config.Passphrase, appConfig.Rest.Timeout.Duration())
+		app.ShowPortfolioOnLogon = appConfig.Rest.ShowPortfolio
+	}
+	loadProducts(appConfig, app.Rest, config.PortfolioId, app.Products)
+	if appConfig.Arrow.Trades != "" || appConfig.Arrow.Book != "" {
+		app.Arrow, err = fixclient.OpenArrowOutput(appConfig.Arrow.Trades, appConfig.Arrow.Book, stdout)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := app.StartJobs(exportJobs(appConfig.Jobs)); err != nil {
+		log.Fatal(err)
+	}
+	if err := app.StartPipeline(appConfig.Pipeline.QueueSize, appConfig.Pipeline.Overflow); err != nil {
+		log.Fatalf("Invalid pipeline: %v", err)
+	}
+	if err := app.SetMemoryBudget(fixclient.MemoryBudget{
+		MaxEntries: appConfig.Memory.MaxEntries,
+		MaxBytes:   int64(appConfig.Memory.MaxMB) << 20,
+	}); err != nil {
+		log.Fatalf("Invalid memory: %v", err)
+	}
+	app.StartStaleWatch(appConfig.Md.StaleAfter.Duration())
+	app.StartWriterStatsLog(appConfig.Database.StatsInterval.Duration())
+	app.StartBookSnapshots(appConfig.Book.SnapshotInterval.Duration())
+	if h := appConfig.Heartbeat; h.Url != "" {
+		app.StartHeartbeat(&fixclient.HeartbeatNotifier{
+			Url:        h.Url,
+			FailUrl:    h.FailUrl,
+			Every:      h.Every.Duration(),
+			StaleAfter: appConfig.Md.StaleAfter.Duration(),
+			HttpClient: &http.Client{Timeout: h.Timeout.Duration()},
+		})
+	}
+	notifiers, enabled, err := alertNotifiers(appConfig.Alerts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	app.Alerts = newAlerts(notifiers, enabled)
+	if t := appConfig.Tracing; t.Endpoint != "" {
+		app.Tracer, err = tracing.NewTracer(t.Endpoint, t.ServiceName, t.SampleRate, t.Headers, t.Timeout.Duration())
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if a := appConfig.Archive; a.OlderThan > 0 {
+		if a.Every <= 0 {
+			log.Fatal("archive.every must be positive")
+		}
+		app.StartArchiver(a.Dir, a.OlderThan.Duration(), a.Every.Duration())
+	}
+
+	if *daemon && appConfig.Daemon.ControlSocket != "" {
+		if err := app.StartControlSocket(appConfig.Daemon.ControlSocket); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if api := appConfig.Api; api.Listen != "" {
+		token := api.Token
+		if token == "" {
+			token = os.Getenv("PRIME_ADMIN_TOKEN")
+		}
+		if err := app.StartAdminApi(&fixclient.AdminApi{Listen: api.Listen, Token: token, SetVerbose: logFactory.SetVerbose}); err != nil {
+			log.Fatalf("Invalid api: %v", err)
+		}
+	}
+
+	reload := &reloader{path: *configPath, load: loadConfig, app: app, logFactory: logFactory, current: appConfig}
+	app.ReloadConfig = reload.Reload
+	watchReloadSignal(reload)
 
 	initiator, err := quickfix.NewInitiator(app,
 		quickfix.NewMemoryStoreFactory(),
 		settings,
-		formatter.NewTableLogFactory(),
+		sessionLogs,
 	)
 	if err != nil {
 		log.Fatal("initiator error:", err)
@@ -72,7 +301,269 @@ func main() {
 	if err := initiator.Start(); err != nil {
 		log.Fatal("start error:", err)
 	}
-	defer initiator.Stop()
+	if *daemon {
+		waitForShutdown(app)
+	} else {
+		fixclient.Repl(app, fixclient.ReplOptions{
+			HistoryFile:  appConfig.Repl.HistoryFile,
+			HistorySize:  appConfig.Repl.HistorySize,
+			HistoryDedup: appConfig.Repl.HistoryDedup,
+		})
+	}
+
+	app.StopBackground()
+	initiator.Stop()
+	if err := sessionLogs.Close(); err != nil {
+		log.Printf("Failed to close session logs: %v", err)
+	}
+	app.StopPipeline()
+	app.EndDatabaseSessions()
+	app.Alerts.Close(alertsFlushTimeout)
+	app.Tracer.Shutdown()
+	if err := app.Arrow.Close(); err != nil {
+		log.Printf("%v", err)
+	}
+	if app.ShouldExit() {
+		if db != nil {
+			db.Close()
+		}
+		if *daemon {
+			os.Remove(appConfig.Daemon.PidFile)
+		}
+		os.Exit(1)
+	}
+}
+
+// newSessionLogs sends session logs to the console table log and to each configured destination
+func newSessionLogs(console *formatter.TableLogFactory, cfg config.LogConfig, settings *quickfix.Settings) (*formatter.CompositeLogFactory, error) {
+	factories := []quickfix.LogFactory{console}
+	if cfg.File.Dir != "" {
+		// A FileLogPath set for a session in fix.cfg still wins
+		settings.GlobalSettings().Set(quickfixconfig.FileLogPath, cfg.File.Dir)
+		files, err := quickfix.NewFileLogFactory(settings)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log.file: %v", err)
+		}
+		factories = append(factories, formatter.RedactedLogFactory(files))
+	}
+	if cfg.Json.Path != "" {
+		jsonLogs, err := formatter.NewJsonLogFactory(cfg.Json.Path, cfg.Json.Messages)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log.json: %v", err)
+		}
+		factories = append(factories, jsonLogs)
+	}
+	return formatter.NewCompositeLogFactory(factories...), nil
+}
+
+// asciiOutput resolves display.ascii: "auto" detects terminals that cannot show unicode
+func asciiOutput(mode string) (bool, error) {
+	switch strings.ToLower(mode) {
+	case "", "auto":
+		return formatter.NeedsASCII(), nil
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid display.ascii %q (expected auto, on or off)", mode)
+}
+
+func numberFormat(cfg config.DisplayConfig) fixclient.NumberFormat {
+	precision := make(map[string]fixclient.SymbolPrecision, len(cfg.Precision))
+	for symbol, p := range cfg.Precision {
+		precision[symbol] = fixclient.SymbolPrecision{Price: p.Price, Size: p.Size}
+	}
+	return fixclient.NumberFormat{
+		Thousands:    cfg.Thousands,
+		SizeNotation: cfg.SizeNotation,
+		Precision:    precision,
+	}
+}
+
+func largePrints(cfg config.LargePrintsConfig) fixclient.LargePrints {
+	symbols := make(map[string]fixclient.LargePrintThreshold, len(cfg.Symbols))
+	for symbol, t := range cfg.Symbols {
+		symbols[symbol] = fixclient.LargePrintThreshold{Size: t.Size, Notional: t.Notional}
+	}
+	return fixclient.LargePrints{Size: cfg.Size, Notional: cfg.Notional, Symbols: symbols, Bell: cfg.Bell}
+}
+
+// How long shutdown waits for queued alerts, e.g. the logon failure that caused the exit
+const alertsFlushTimeout = 10 * time.Second
+
+// newAlerts returns the alert dispatcher for notifiers. It is created even when no alert channel
+// is configured, so a reload that adds one reconfigures it rather than replacing it under the
+// goroutines sending to it.
+func newAlerts(notifiers []notify.Notifier, enabled map[string]bool) *notify.Dispatcher {
+	host, _ := os.Hostname()
+	return notify.NewDispatcher(notifiers, enabled, host)
+}
+
+// alertNotifiers builds the alert channels and the event types sent to them; none when no channel is configured
+func alertNotifiers(cfg config.AlertsConfig) ([]notify.Notifier, map[string]bool, error) {
+	client := &http.Client{Timeout: cfg.Timeout.Duration()}
+	var notifiers []notify.Notifier
+	if cfg.WebhookUrl != "" {
+		notifiers = append(notifiers, &notify.Webhook{Url: cfg.WebhookUrl, HttpClient: client})
+	}
+	botToken := cfg.Telegram.BotToken
+	if botToken == "" {
+		botToken = os.Getenv("PRIME_TELEGRAM_BOT_TOKEN")
+	}
+	if botToken != "" && cfg.Telegram.ChatId != "" {
+		notifiers = append(notifiers, &notify.Telegram{BotToken: botToken, ChatId: cfg.Telegram.ChatId, HttpClient: client})
+	}
+	if e := cfg.Email; e.Server != "" {
+		password := e.Password
+		if password == "" {
+			password = os.Getenv("PRIME_SMTP_PASSWORD")
+		}
+		email, err := notify.NewEmail(e.Server, e.Username, password, e.From, e.To, e.MinSeverity, e.Subject, e.Body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("alerts.email: %v", err)
+		}
+		notifiers = append(notifiers, email)
+	}
+	incidents := cfg.Incidents
+	if incidents.PagerDuty.RoutingKey == "" {
+		incidents.PagerDuty.RoutingKey = os.Getenv("PRIME_PAGERDUTY_ROUTING_KEY")
+	}
+	if incidents.Opsgenie.ApiKey == "" {
+		incidents.Opsgenie.ApiKey = os.Getenv("PRIME_OPSGENIE_API_KEY")
+	}
+	if incidents.PagerDuty.RoutingKey != "" {
+		notifiers = append(notifiers, &notify.Incidents{
+			Api:        &notify.PagerDuty{RoutingKey: incidents.PagerDuty.RoutingKey, HttpClient: client},
+			StaleAfter: incidents.StaleAfter.Duration(),
+		})
+	}
+	if incidents.Opsgenie.ApiKey != "" {
+		notifiers = append(notifiers, &notify.Incidents{
+			Api:        &notify.Opsgenie{ApiKey: incidents.Opsgenie.ApiKey, Url: incidents.Opsgenie.Url, HttpClient: client},
+			StaleAfter: incidents.StaleAfter.Duration(),
+		})
+	}
+	if len(notifiers) == 0 {
+		return nil, nil, nil
+	}
+	enabled := map[string]bool{
+		notify.EventDisconnect:   cfg.Events.Disconnect,
+		notify.EventLogonFailure: cfg.Events.LogonFailure,
+		notify.EventReject:       cfg.Events.Reject,
+		notify.EventStale:        cfg.Events.Stale,
+		notify.EventLargePrint:   cfg.Events.LargePrint,
+		notify.EventUnexpected:   cfg.Events.Unexpected,
+		notify.EventRejectBurst:  cfg.Events.RejectBurst,
+		notify.EventSession:      cfg.Events.Session,
+
+		notify.EventProlongedDisconnect: cfg.Events.ProlongedDisconnect,
+	}
+	return notifiers, enabled, nil
+}
+
+// openDatabase opens marketdata.db, or returns nil when persistence is disabled
+func openDatabase(cfg config.DatabaseConfig) (*database.MarketDataDb, error) {
+	if !cfg.Persist {
+		log.Printf("Persistence disabled: market data is kept in memory only")
+		return nil, nil
+	}
+
+	db, err := database.NewMarketDataDbWithOptions("marketdata.db", database.Options{
+		BusyTimeout: cfg.BusyTimeout.Duration(),
+		CacheSize:   cfg.CacheSize,
+		Synchronous: cfg.Synchronous,
+		MmapSize:    cfg.MmapSize,
+
+		SkipQuickCheck: !cfg.QuickCheck,
+		ResetIfCorrupt: cfg.ResetIfCorrupt,
+	})
+	if errors.Is(err, database.ErrCorrupt) {
+		return nil, fmt.Errorf("database initialization failed: %v\nRestore marketdata.db from a backup, move it aside, or set database.resetIfCorrupt to start a new one", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database initialization failed: %v", err)
+	}
+	return db, nil
+}
+
+func exportJobs(jobs []config.JobConfig) []fixclient.ExportJob {
+	exports := make([]fixclient.ExportJob, 0, len(jobs))
+	for _, j := range jobs {
+		exports = append(exports, fixclient.ExportJob{
+			Name:     j.Name,
+			Export:   j.Export,
+			Symbol:   j.Symbol,
+			Every:    j.Every.Duration(),
+			Window:   j.Window.Duration(),
+			Interval: j.Interval.Duration(),
+			Dir:      j.Dir,
+			Format:   j.Format,
+		})
+	}
+	return exports
+}
+
+// applyDialect overrides BeginString and DefaultApplVerID for every session in fix.cfg. A session's ID
+// includes its BeginString, so the sessions are re-added to a fresh Settings with the merged values.
+func applyDialect(settings *quickfix.Settings, fix config.FixConfig) (*quickfix.Settings, error) {
+	if fix.BeginString == "" && fix.DefaultApplVerId == "" {
+		return settings, nil
+	}
 
-	fixclient.Repl(app)
+	overridden := quickfix.NewSettings()
+	for _, sessionSettings := range settings.SessionSettings() {
+		if fix.BeginString != "" {
+			sessionSettings.Set(quickfixconfig.BeginString, fix.BeginString)
+		}
+		if fix.DefaultApplVerId != "" {
+			sessionSettings.Set(quickfixconfig.DefaultApplVerID, fix.DefaultApplVerId)
+		}
+		if _, err := overridden.AddSession(sessionSettings); err != nil {
+			return nil, fmt.Errorf("invalid FIX dialect settings: %v", err)
+		}
+	}
+	return overridden, nil
+}
+
+// sessionPortfolios reads the optional PortfolioId setting (a name or ID) from each [SESSION] in fix.cfg,
+// so several sessions can log on under different portfolios
+func sessionPortfolios(settings *quickfix.Settings, appConfig *config.Config) map[quickfix.SessionID]string {
+	portfolios := make(map[quickfix.SessionID]string)
+	for sid, sessionSettings := range settings.SessionSettings() {
+		if !sessionSettings.HasSetting(portfolioIdSetting) {
+			continue
+		}
+		value, err := sessionSettings.Setting(portfolioIdSetting)
+		if err != nil || value == "" {
+			continue
+		}
+		portfolios[sid] = appConfig.ResolvePortfolio(value)
+	}
+	return portfolios
+}
+
+// loadProducts fills the catalog from the Prime REST API when a client is given, falling back to the
+// configured symbol list. A REST failure is logged and does not stop startup.
+func loadProducts(appConfig *config.Config, client *primeapi.Client, portfolioId string, catalog *products.Catalog) {
+	if client != nil {
+		// Each page is bounded by the HTTP client timeout
+		list, err := client.ListProducts(context.Background(), portfolioId)
+		if err == nil && len(list) > 0 {
+			catalog.Replace(list)
+			log.Printf("Loaded %d products from Prime REST API", len(list))
+			return
+		}
+		if err != nil {
+			log.Printf("Product list unavailable, continuing without it: %v", err)
+		}
+	}
+
+	if len(appConfig.Products.Symbols) > 0 {
+		list := make([]products.Product, 0, len(appConfig.Products.Symbols))
+		for _, symbol := range appConfig.Products.Symbols {
+			list = append(list, products.Product{Symbol: symbol})
+		}
+		catalog.Replace(list)
+	}
 }
@@ -0,0 +1,67 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"prime-fix-md-go/database"
+)
+
+// runMerge implements "merge [-out FILE] SOURCE.db...": it merges captures from several days or
+// machines into one database without starting a FIX session
+func runMerge(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	out := fs.String("out", "merged.db", "database to merge into; created if missing, appended to otherwise")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: merge [-out FILE] SOURCE.db [SOURCE.db...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no source databases given")
+	}
+
+	// Check sources up front so a typo doesn't leave an empty output database behind
+	for _, src := range fs.Args() {
+		if _, err := os.Stat(src); err != nil {
+			return err
+		}
+	}
+
+	dst, err := database.NewMarketDataDb(*out)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	for _, src := range fs.Args() {
+		stats, err := dst.Merge(src)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d trades, %d book entries, %d ohlcv, %d sessions (%d renamed)\n",
+			src, stats.Trades, stats.OrderBook, stats.Ohlcv, stats.Sessions, stats.RenamedSessions)
+	}
+	fmt.Printf("Merged %d databases into %s\n", fs.NArg(), *out)
+	return nil
+}
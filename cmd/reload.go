@@ -0,0 +1,219 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+
+	"prime-fix-md-go/config"
+	"prime-fix-md-go/fixclient"
+	"prime-fix-md-go/formatter"
+	"prime-fix-md-go/notify"
+	"prime-fix-md-go/upload"
+)
+
+// reloader re-reads the config file on the reload command or SIGHUP and applies what can
+// change without dropping the FIX session
+type reloader struct {
+	mu         sync.Mutex
+	path       string
+	load       func() (*config.Config, error) // Reads path and applies command-line overrides
+	app        *fixclient.FixApp
+	logFactory *formatter.TableLogFactory
+	current    *config.Config
+}
+
+// Reload applies a changed config. Everything is validated first, so an invalid file changes nothing.
+func (r *reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := r.load()
+	if err != nil {
+		return err
+	}
+	if err := applySettings(fixclient.NewFixApp(r.app.Config, nil), cfg); err != nil {
+		return fmt.Errorf("%v; nothing was reloaded", err)
+	}
+	uploadChanged := !reflect.DeepEqual(cfg.Upload, r.current.Upload)
+	var uploader fixclient.Uploader
+	if uploadChanged {
+		if uploader, err = newUploader(cfg.Upload); err != nil {
+			return fmt.Errorf("%v; nothing was reloaded", err)
+		}
+	}
+
+	alertsChanged := !reflect.DeepEqual(cfg.Alerts, r.current.Alerts)
+	var notifiers []notify.Notifier
+	var enabled map[string]bool
+	if alertsChanged {
+		if notifiers, enabled, err = alertNotifiers(cfg.Alerts); err != nil {
+			return fmt.Errorf("%v; nothing was reloaded", err)
+		}
+	}
+
+	if cfg.Display.TimeZone != r.current.Display.TimeZone {
+		if err := fixclient.SetTimeZone(cfg.Display.TimeZone); err != nil {
+			return fmt.Errorf("%v; nothing was reloaded", err)
+		}
+	}
+	if cfg.Display.SymbolColors != r.current.Display.SymbolColors {
+		if err := fixclient.SetSymbolColors(cfg.Display.SymbolColors); err != nil {
+			return fmt.Errorf("%v; only display.timeZone was reloaded", err)
+		}
+	}
+	if err := applySettings(r.app, cfg); err != nil {
+		return err
+	}
+	r.logFactory.SetVerbose(cfg.Log.Verbose)
+	r.app.AdminCounters.SetHalfDeadAlert(cfg.Session.HalfDeadAlertAfter.Duration())
+	if alertsChanged {
+		// The dispatcher is reconfigured rather than replaced, since other goroutines may be sending to it
+		r.app.Alerts.Reconfigure(notifiers, enabled)
+	}
+	if uploadChanged {
+		r.app.SetUploader(uploader, cfg.Upload.Exports, cfg.Upload.Archives)
+	}
+
+	if restart := restartRequired(r.current, cfg); len(restart) > 0 {
+		log.Printf("Reloaded %s; restart to apply changes to %s", r.path, strings.Join(restart, ", "))
+	} else {
+		log.Printf("Reloaded %s", r.path)
+	}
+	r.current = cfg
+	return nil
+}
+
+// watchReloadSignal reloads the config on every SIGHUP until the process exits
+func watchReloadSignal(r *reloader) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			log.Printf("Received SIGHUP, reloading %s", r.path)
+			if err := r.Reload(); err != nil {
+				log.Printf("Reload failed: %v", err)
+			}
+		}
+	}()
+}
+
+// restartRequired names the config sections that changed but are only read at startup
+func restartRequired(old, cfg *config.Config) []string {
+	var changed []string
+	for _, section := range []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"log.adminSummaryInterval", old.Log.AdminSummaryInterval, cfg.Log.AdminSummaryInterval},
+		{"log.file", old.Log.File, cfg.Log.File},
+		{"log.json", old.Log.Json, cfg.Log.Json},
+		{"clock", old.Clock, cfg.Clock},
+		{"fix.customTags", old.Fix.CustomTags, cfg.Fix.CustomTags},
+		{"fix.beginString", old.Fix.BeginString, cfg.Fix.BeginString},
+		{"fix.defaultApplVerId", old.Fix.DefaultApplVerId, cfg.Fix.DefaultApplVerId},
+		{"fix.applVerIds", old.Fix.ApplVerIds, cfg.Fix.ApplVerIds},
+		{"products", old.Products, cfg.Products},
+		{"book.snapshotInterval", old.Book.SnapshotInterval, cfg.Book.SnapshotInterval},
+		{"rest", old.Rest, cfg.Rest},
+		{"md.staleAfter", old.Md.StaleAfter, cfg.Md.StaleAfter},
+		{"pipeline", old.Pipeline, cfg.Pipeline},
+		{"memory", old.Memory, cfg.Memory},
+		{"repl", old.Repl, cfg.Repl},
+		{"display.ascii", old.Display.ASCII, cfg.Display.ASCII},
+		{"arrow", old.Arrow, cfg.Arrow},
+		{"jobs", old.Jobs, cfg.Jobs},
+		{"archive", old.Archive, cfg.Archive},
+		{"database", old.Database, cfg.Database},
+		{"tracing", old.Tracing, cfg.Tracing},
+		{"daemon", old.Daemon, cfg.Daemon},
+		{"api", old.Api, cfg.Api},
+		{"heartbeat", old.Heartbeat, cfg.Heartbeat},
+		{"portfolios", old.Portfolios, cfg.Portfolios},
+	} {
+		if !reflect.DeepEqual(section.old, section.new) {
+			changed = append(changed, section.name)
+		}
+	}
+	return changed
+}
+
+// applySettings applies the config that the app reads as it runs: display options, md defaults,
+// subscriptions, book resyncs and sequence resets on logon. It is used at startup and again on every reload.
+func applySettings(app *fixclient.FixApp, cfg *config.Config) error {
+	if err := app.SetNumberFormat(numberFormat(cfg.Display)); err != nil {
+		return fmt.Errorf("invalid display config: %v", err)
+	}
+	app.SetCumulativeNotional(cfg.Display.CumNotional)
+	if err := app.SetLargePrints(largePrints(cfg.Display.LargePrints)); err != nil {
+		return fmt.Errorf("invalid display.largePrints: %v", err)
+	}
+	app.SetAggressorInference(cfg.Md.InferAggressor)
+	app.SetTradeAggregation(cfg.Display.AggregateTrades.Window.Duration(), cfg.Display.AggregateTrades.Store)
+	for format := range cfg.Display.Templates {
+		if format != fixclient.OutputTable && format != fixclient.OutputPlain {
+			return fmt.Errorf("invalid display.templates.%s: templates apply to the table and plain output formats", format)
+		}
+	}
+	// Formats missing from the config go back to the built-in lines
+	for _, format := range []string{fixclient.OutputTable, fixclient.OutputPlain} {
+		t := cfg.Display.Templates[format]
+		templates, err := fixclient.ParseLineTemplates(t.Trade, t.Book)
+		if err == nil {
+			err = app.SetLineTemplates(format, templates)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid display.templates.%s: %v", format, err)
+		}
+	}
+	if err := app.SetMdDefaults(cfg.Md.SubscriptionType, cfg.Md.Depth, cfg.Md.EntryTypes); err != nil {
+		return err
+	}
+	if err := app.SetMdTemplates(cfg.Md.Templates); err != nil {
+		return err
+	}
+	if err := app.SetDeclaredSubscriptions(cfg.Subscriptions); err != nil {
+		return err
+	}
+	if err := app.SetStrictValidation(cfg.Fix.StrictValidation); err != nil {
+		return err
+	}
+	app.SetRejectBurst(cfg.Alerts.RejectBurst.Count, cfg.Alerts.RejectBurst.Window.Duration())
+	app.SetProlongedDisconnect(cfg.Alerts.ProlongedDisconnectAfter.Duration())
+	app.SetAutoResync(cfg.Book.AutoResync)
+	app.SetResetSeqNumOnLogon(cfg.Session.ResetSeqNumOnLogon)
+	return nil
+}
+
+// newUploader returns the export and archive uploader, or nil when uploads are disabled
+func newUploader(u config.UploadConfig) (fixclient.Uploader, error) {
+	if u.Provider == "" {
+		return nil, nil
+	}
+	client, err := upload.NewClient(u.Provider, u.Bucket, u.Prefix, u.Region, u.Endpoint,
+		u.AccessKeyId, u.SecretAccessKey, u.Timeout.Duration())
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
@@ -0,0 +1,104 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"prime-fix-md-go/config"
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/fixclient"
+	"prime-fix-md-go/formatter"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Run with -race: reloads replace settings the pipeline and session goroutines read
+func TestReloadWhileMessagesFlow(t *testing.T) {
+	app := fixclient.NewFixApp(fixclient.NewConfig("", "", "", "", "", ""), nil)
+	app.SetConsole(io.Discard, io.Discard)
+	defer app.SetConsole(nil, nil)
+	logFactory := formatter.NewTableLogFactory()
+	app.AdminCounters = logFactory.AdminCounters()
+	app.Alerts = newAlerts(nil, nil)
+	defer app.Alerts.Close(0)
+
+	plain := config.Default()
+	styled := config.Default()
+	styled.Display.Thousands = true
+	styled.Display.SizeNotation = "compact"
+	styled.Display.TimeZone = "America/New_York"
+	styled.Display.SymbolColors = "on"
+	styled.Display.CumNotional = true
+	styled.Display.LargePrints = config.LargePrintsConfig{Size: "0.5", Bell: true}
+	styled.Display.Templates = map[string]config.TemplateConfig{"table": {Trade: "{{.Symbol}} {{.Price}}"}}
+	styled.Md.SubscriptionType = "subscribe"
+	styled.Md.EntryTypes = []string{"trades"}
+	styled.Book.AutoResync = true
+	styled.Session.ResetSeqNumOnLogon = true
+	styled.Alerts.WebhookUrl = "http://127.0.0.1:0/alerts"
+	styled.Upload = config.UploadConfig{Provider: "s3", Bucket: "md", AccessKeyId: "id", SecretAccessKey: "secret"}
+	if err := applySettings(app, plain); err != nil {
+		t.Fatal(err)
+	}
+
+	loads := 0
+	r := &reloader{app: app, logFactory: logFactory, current: plain, load: func() (*config.Config, error) {
+		loads++
+		if loads%2 == 1 {
+			return styled, nil
+		}
+		return plain, nil
+	}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			app.FromApp(rawMessage(fmt.Sprintf("35=W\x0149=COIN\x0156=CLIENT\x0134=%d\x0152=20250101-12:00:00.000\x01262=req\x0155=BTC-USD\x01"+
+				"268=2\x01269=0\x01270=99\x01271=1\x01269=2\x01270=100\x01271=1\x01", 2*i+2)), quickfix.SessionID{})
+			app.FromApp(rawMessage(fmt.Sprintf("35=X\x0149=COIN\x0156=CLIENT\x0134=%d\x0152=20250101-12:00:00.000\x01262=req\x01"+
+				"268=1\x01279=0\x01269=2\x0155=BTC-USD\x01270=100\x01271=1\x01", 2*i+3)), quickfix.SessionID{})
+			logon := quickfix.NewMessage()
+			logon.Header.SetField(constants.TagMsgType, quickfix.FIXString(constants.MsgTypeLogon))
+			app.ToAdmin(logon, quickfix.SessionID{})
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		if err := r.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+}
+
+func rawMessage(body string) *quickfix.Message {
+	raw := fmt.Sprintf("8=FIX.4.4\x019=%d\x01%s", len(body), body)
+	sum := 0
+	for i := 0; i < len(raw); i++ {
+		sum += int(raw[i])
+	}
+	raw += fmt.Sprintf("10=%03d\x01", sum%256)
+
+	msg := quickfix.NewMessage()
+	if err := quickfix.ParseMessage(msg, bytes.NewBufferString(raw)); err != nil {
+		panic(err)
+	}
+	return msg
+}
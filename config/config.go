@@ -0,0 +1,486 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config holds application settings that are not part of the QuickFIX session config (fix.cfg)
+type Config struct {
+	Log       LogConfig       `json:"log"`
+	Clock     ClockConfig     `json:"clock"`
+	Session   SessionConfig   `json:"session"`
+	Fix       FixConfig       `json:"fix"`
+	Products  ProductsConfig  `json:"products"`
+	Book      BookConfig      `json:"book"`
+	Rest      RestConfig      `json:"rest"`
+	Md        MdConfig        `json:"md"`
+	Pipeline  PipelineConfig  `json:"pipeline"`
+	Memory    MemoryConfig    `json:"memory"`
+	Repl      ReplConfig      `json:"repl"`
+	Display   DisplayConfig   `json:"display"`
+	Upload    UploadConfig    `json:"upload"`
+	Arrow     ArrowConfig     `json:"arrow"`
+	Jobs      []JobConfig     `json:"jobs"`
+	Archive   ArchiveConfig   `json:"archive"`
+	Database  DatabaseConfig  `json:"database"`
+	Tracing   TracingConfig   `json:"tracing"`
+	Daemon    DaemonConfig    `json:"daemon"`
+	Api       ApiConfig       `json:"api"`
+	Heartbeat HeartbeatConfig `json:"heartbeat"`
+	Alerts    AlertsConfig    `json:"alerts"`
+
+	Subscriptions []string `json:"subscriptions"` // md command arguments sent after every logon, e.g. "BTC-USD --subscribe --l1"
+
+	Portfolios map[string]string `json:"portfolios"` // Portfolio name -> Prime portfolio ID, selectable with --portfolio
+}
+
+// LogConfig controls the FIX session logs. The console table log is always on; other
+// destinations receive the same messages and events alongside it.
+type LogConfig struct {
+	Verbose              bool     `json:"verbose"`              // Render every inbound/outbound FIX message as a tag table
+	AdminSummaryInterval Duration `json:"adminSummaryInterval"` // How often to print the heartbeat/admin summary line; 0 disables it
+
+	File LogFileConfig `json:"file"`
+	Json LogJsonConfig `json:"json"`
+}
+
+// LogJsonConfig writes session events, and optionally messages, as JSON lines for ELK or Loki
+type LogJsonConfig struct {
+	Path     string `json:"path"`     // File appended to; empty disables the JSON log
+	Messages bool   `json:"messages"` // Also log every message with its direction, type and sequence number
+}
+
+// LogFileConfig captures raw FIX messages and session events with quickfix's FileLog
+type LogFileConfig struct {
+	Dir string `json:"dir"` // One messages and one event log per session here; empty disables the capture
+}
+
+type ClockConfig struct {
+	SkewWarnThreshold Duration `json:"skewWarnThreshold"` // Warn when local time and server SendingTime differ by more than this; 0 disables
+}
+
+type SessionConfig struct {
+	HalfDeadAlertAfter Duration `json:"halfDeadAlertAfter"` // Warn when a TestRequest stays unanswered this long; 0 disables
+	ResetSeqNumOnLogon bool     `json:"resetSeqNumOnLogon"` // Send ResetSeqNumFlag(141)=Y and start both sequence numbers at 1 on every logon
+}
+
+type FixConfig struct {
+	CustomTags []string `json:"customTags"` // "tag=value" pairs appended to Logon and MarketDataRequest messages
+
+	BeginString      string            `json:"beginString"`      // Overrides BeginString for every session in fix.cfg
+	DefaultApplVerId string            `json:"defaultApplVerId"` // Overrides DefaultApplVerID (1137) sent on Logon
+	ApplVerIds       map[string]string `json:"applVerIds"`       // MsgType -> ApplVerID (1128) stamped on that message type
+
+	StrictValidation bool `json:"strictValidation"` // Check inbound market data against the FIX 5.0 SP2 / Prime dictionary and warn on discrepancies
+}
+
+type BookConfig struct {
+	AutoResync       bool     `json:"autoResync"`       // Re-request a snapshot when a live book crosses or skips a RptSeq
+	SnapshotInterval Duration `json:"snapshotInterval"` // Copy each live book into book_snapshots this often; 0 disables
+}
+
+// MdConfig fills in whatever an md command leaves out, so "md BTC-USD" alone can be a complete request
+type MdConfig struct {
+	SubscriptionType string   `json:"subscriptionType"` // "snapshot" or "subscribe"; empty requires the flag on every request
+	Depth            *int     `json:"depth"`            // Market depth when --depth is not given; unset means full book (0)
+	EntryTypes       []string `json:"entryTypes"`       // md entry type flags without "--", e.g. ["trades"] or ["l1"]
+	StaleAfter       Duration `json:"staleAfter"`       // Warn when a live subscription receives nothing this long; 0 disables
+	InferAggressor   bool     `json:"inferAggressor"`   // Infer the side of trades sent without AggressorSide (2446)
+
+	Templates map[string]string `json:"templates"` // Name -> md flags, used as md <symbol> @name, e.g. "l10book": "--depth 10 --book --subscribe"
+}
+
+// PipelineConfig sizes the queue between the FIX session and market data handling
+type PipelineConfig struct {
+	QueueSize int    `json:"queueSize"` // Messages waiting for parsing, storage and display
+	Overflow  string `json:"overflow"`  // "block" holds up the FIX session when full; "drop" discards market data and counts it
+}
+
+// MemoryConfig bounds the market data held in memory; see fixclient.SetMemoryBudget
+type MemoryConfig struct {
+	MaxEntries int `json:"maxEntries"` // Trades, book levels and queued entries combined; 0 is unlimited
+	MaxMB      int `json:"maxMB"`      // Estimated size of the same, in MiB; 0 is unlimited
+}
+
+// ReplConfig controls the interactive prompt's command history
+type ReplConfig struct {
+	HistoryFile  string `json:"historyFile"`  // "~/" is expanded; empty uses ~/.fixmd_history
+	HistorySize  int    `json:"historySize"`  // Commands kept; -1 disables history
+	HistoryDedup bool   `json:"historyDedup"` // Keep only the latest copy of a repeated command
+}
+
+// DisplayConfig controls how market data is shown on the console
+type DisplayConfig struct {
+	Thousands       bool                       `json:"thousands"`          // Group integer digits, e.g. 50,000.10
+	SizeNotation    string                     `json:"sizeNotation"`       // "plain" or "compact" (1.5K, 2.3M)
+	Precision       map[string]PrecisionConfig `json:"precision"`          // Symbol -> decimal places, overriding product increments
+	TimeZone        string                     `json:"timeZone"`           // Zone for entry and update times: "UTC", "Local" or an IANA name
+	ASCII           string                     `json:"ascii"`              // "auto", "on" or "off": plain ASCII instead of box drawing and emoji
+	Templates       map[string]TemplateConfig  `json:"templates"`          // Output format (table or plain) -> update line templates
+	SymbolColors    string                     `json:"symbolColors"`       // "auto", "on" or "off": color each symbol in table updates
+	CumNotional     bool                       `json:"cumulativeNotional"` // Book snapshots and book add cumulative price x size
+	LargePrints     LargePrintsConfig          `json:"largePrints"`
+	AggregateTrades AggregateTradesConfig      `json:"aggregateTrades"`
+}
+
+// AggregateTradesConfig merges consecutive trades at the same price and aggressor within a window
+type AggregateTradesConfig struct {
+	Window Duration `json:"window"` // 0 shows every trade
+	Store  bool     `json:"store"`  // Also write aggregated prints to trade_prints
+}
+
+// LargePrintsConfig highlights trades at or above a size or notional; empty thresholds are off
+type LargePrintsConfig struct {
+	Size     string                         `json:"size"`     // Base currency size, e.g. "10"
+	Notional string                         `json:"notional"` // Price x size in quote currency, e.g. "1000000"
+	Symbols  map[string]LargePrintThreshold `json:"symbols"`  // Symbol -> thresholds replacing the defaults above
+	Bell     bool                           `json:"bell"`     // Ring the terminal bell on large prints
+}
+
+type LargePrintThreshold struct {
+	Size     string `json:"size"`
+	Notional string `json:"notional"`
+}
+
+// TemplateConfig holds Go text/template strings for streaming update lines, e.g. "{{.Symbol}},{{.Price}},{{.Size}}".
+// Empty keeps the built-in line.
+type TemplateConfig struct {
+	Trade string `json:"trade"`
+	Book  string `json:"book"` // Bid and offer entries
+}
+
+// PrecisionConfig sets the decimal places for one symbol; unset values come from the product
+// increments, or are shown as received
+type PrecisionConfig struct {
+	Price *int32 `json:"price"`
+	Size  *int32 `json:"size"`
+}
+
+type ProductsConfig struct {
+	Symbols []string `json:"symbols"` // Known symbols for validation when no product source is available; empty disables the check
+}
+
+type RestConfig struct {
+	Enabled bool     `json:"enabled"` // Fetch the Prime product list over REST at startup (uses the same PRIME_* credentials)
+	BaseUrl string   `json:"baseUrl"` // Prime REST API root
+	Timeout Duration `json:"timeout"` // Per-request HTTP timeout
+
+	ShowPortfolio bool `json:"showPortfolio"` // After logon, print the portfolio name and product entitlements for PRIME_PORTFOLIO_ID
+}
+
+// UploadConfig sends export files to S3 or GCS (through its S3-compatible API with HMAC keys)
+type UploadConfig struct {
+	Provider        string   `json:"provider"`        // "s3" or "gcs"; empty disables uploads
+	Bucket          string   `json:"bucket"`          // Destination bucket
+	Prefix          string   `json:"prefix"`          // Key prefix, e.g. "marketdata/exports"
+	Region          string   `json:"region"`          // S3 region (default us-east-1)
+	Endpoint        string   `json:"endpoint"`        // Override for S3-compatible stores; defaults per provider
+	AccessKeyId     string   `json:"accessKeyId"`     // Defaults to AWS_ACCESS_KEY_ID or GCS_HMAC_ACCESS_ID
+	SecretAccessKey string   `json:"secretAccessKey"` // Defaults to AWS_SECRET_ACCESS_KEY or GCS_HMAC_SECRET
+	Exports         bool     `json:"exports"`         // Upload every file written by candles --out and book export --out
+	Archives        bool     `json:"archives"`        // Upload every compressed file written by archiving
+	Timeout         Duration `json:"timeout"`         // Per-upload HTTP timeout
+}
+
+// ArrowConfig streams live market data as Arrow IPC for Python/Rust tooling
+type ArrowConfig struct {
+	Trades string `json:"trades"` // Trade stream path, or "-" for stdout; empty disables it
+	Book   string `json:"book"`   // Bid/offer entry stream path, or "-" for stdout; empty disables it
+}
+
+// JobConfig schedules a recurring export, e.g. every hour write the last hour of BTC-USD trades to /data
+type JobConfig struct {
+	Name     string   `json:"name"`     // Shown by the jobs command
+	Export   string   `json:"export"`   // "trades", "candles" or "book"
+	Symbol   string   `json:"symbol"`   // Instrument to export
+	Every    Duration `json:"every"`    // Run at multiples of this in UTC, e.g. "1h" runs on the hour
+	Window   Duration `json:"window"`   // How far back each trades/candles run reaches; defaults to every
+	Interval Duration `json:"interval"` // Bar size for candles
+	Dir      string   `json:"dir"`      // Output directory; files are named <symbol>-<export>-<run time>.<format>
+	Format   string   `json:"format"`   // csv (default), json, or arrow for trades; book is always json
+}
+
+// DatabaseConfig tunes SQLite for marketdata.db
+type DatabaseConfig struct {
+	Persist bool `json:"persist"` // Store market data in marketdata.db; false keeps it in memory only
+
+	BusyTimeout Duration `json:"busyTimeout"` // How long to wait for a lock held by another process (e.g. an external reader)
+	CacheSize   int      `json:"cacheSize"`   // PRAGMA cache_size: pages when positive, KiB when negative
+	Synchronous string   `json:"synchronous"` // OFF, NORMAL, FULL or EXTRA
+	MmapSize    int64    `json:"mmapSize"`    // Bytes to memory-map for reads; 0 disables mmap
+
+	QuickCheck     bool `json:"quickCheck"`     // Run PRAGMA quick_check when opening the database
+	ResetIfCorrupt bool `json:"resetIfCorrupt"` // Move a corrupt database aside and start a new one instead of exiting
+
+	StatsInterval Duration `json:"statsInterval"` // Log writer throughput, batch sizes, queue depth and latency this often; 0 disables
+}
+
+// ArchiveConfig moves old rows out of marketdata.db so long captures stay fast to query
+type ArchiveConfig struct {
+	OlderThan Duration `json:"olderThan"` // Archive rows received longer ago than this; 0 disables archiving
+	Every     Duration `json:"every"`     // How often to check for old rows
+	Dir       string   `json:"dir"`       // Where the compressed archive files are written
+}
+
+// DaemonConfig applies when running with --daemon
+type DaemonConfig struct {
+	PidFile string `json:"pidFile"` // Written at startup and removed on exit
+	LogFile string `json:"logFile"` // Receives the log and all console output
+
+	ControlSocket string `json:"controlSocket"` // Unix socket the attach subcommand connects to; empty disables it
+}
+
+// ApiConfig serves the HTTP admin API, for orchestration scripts driving a running client
+type ApiConfig struct {
+	Listen string `json:"listen"` // Address to listen on, e.g. 127.0.0.1:8089; empty disables the API
+	Token  string `json:"token"`  // Bearer token every request must carry; PRIME_ADMIN_TOKEN is used when empty
+}
+
+// HeartbeatConfig posts a periodic heartbeat to an external monitor (healthchecks.io style)
+type HeartbeatConfig struct {
+	Url     string   `json:"url"`     // Posted to every interval while healthy; empty disables the heartbeat
+	FailUrl string   `json:"failUrl"` // Posted to instead while disconnected or stalled, e.g. <url>/fail; empty always uses url
+	Every   Duration `json:"every"`   // How often to post
+	Timeout Duration `json:"timeout"` // Per-request HTTP timeout
+}
+
+// AlertsConfig sends operational problems to a webhook (Slack, Mattermost or any JSON endpoint), Telegram and email,
+// and pages through PagerDuty or Opsgenie for the serious ones
+type AlertsConfig struct {
+	WebhookUrl string          `json:"webhookUrl"` // Receives each alert as a JSON POST; empty disables the webhook
+	Telegram   TelegramConfig  `json:"telegram"`
+	Email      EmailConfig     `json:"email"`
+	Incidents  IncidentsConfig `json:"incidents"`
+	Timeout    Duration        `json:"timeout"` // Per-request HTTP timeout
+	Events     AlertsEvents    `json:"events"`
+
+	RejectBurst RejectBurstConfig `json:"rejectBurst"`
+
+	ProlongedDisconnectAfter Duration `json:"prolongedDisconnectAfter"` // Raise prolonged_disconnect when still logged out this long after a disconnect; 0 disables
+}
+
+// TelegramConfig sends alerts as messages from a Telegram bot; empty botToken or chatId disables it
+type TelegramConfig struct {
+	BotToken string `json:"botToken"` // From @BotFather; PRIME_TELEGRAM_BOT_TOKEN is used when empty
+	ChatId   string `json:"chatId"`   // Chat, group or channel the bot posts to
+}
+
+// IncidentsConfig pages through PagerDuty and/or Opsgenie for a logon failure, a prolonged
+// disconnect or a subscription stale for longer than staleAfter; other alerts are not sent there
+type IncidentsConfig struct {
+	PagerDuty  PagerDutyConfig `json:"pagerDuty"`
+	Opsgenie   OpsgenieConfig  `json:"opsgenie"`
+	StaleAfter Duration        `json:"staleAfter"` // How long after its stale alert a subscription must still be stale to open an incident
+}
+
+// PagerDutyConfig opens incidents with the Events API v2; an empty routingKey disables it
+type PagerDutyConfig struct {
+	RoutingKey string `json:"routingKey"` // Integration key; PRIME_PAGERDUTY_ROUTING_KEY is used when empty
+}
+
+// OpsgenieConfig opens alerts with the Opsgenie Alert API; an empty apiKey disables it
+type OpsgenieConfig struct {
+	ApiKey string `json:"apiKey"` // API integration key; PRIME_OPSGENIE_API_KEY is used when empty
+	Url    string `json:"url"`    // API root; empty uses https://api.opsgenie.com
+}
+
+// EmailConfig sends alerts over SMTP; an empty server disables it
+type EmailConfig struct {
+	Server      string   `json:"server"`      // SMTP host:port, e.g. smtp.example.com:587
+	Username    string   `json:"username"`    // Empty sends without authentication
+	Password    string   `json:"password"`    // PRIME_SMTP_PASSWORD is used when empty
+	From        string   `json:"from"`        // Sender address
+	To          []string `json:"to"`          // Recipients
+	MinSeverity string   `json:"minSeverity"` // info, warning or critical; lower severity alerts are not emailed
+	Subject     string   `json:"subject"`     // Go text/template over the alert; empty uses the default
+	Body        string   `json:"body"`        // Go text/template over the alert; empty uses the default
+}
+
+// RejectBurstConfig is how many rejects within a window count as abnormal
+type RejectBurstConfig struct {
+	Count  int      `json:"count"`  // Rejects of any kind within window that raise a reject_burst alert; 0 disables
+	Window Duration `json:"window"` // Sliding window the rejects are counted over
+}
+
+// AlertsEvents enables each alert type separately
+type AlertsEvents struct {
+	Disconnect   bool `json:"disconnect"`   // The FIX session logged out after being connected
+	LogonFailure bool `json:"logonFailure"` // Logon was refused or the session dropped straight after logon
+	Reject       bool `json:"reject"`       // A market data request was rejected
+	Stale        bool `json:"stale"`        // A subscription went quiet for md.staleAfter, and when it recovers
+	LargePrint   bool `json:"largePrint"`   // A trade reached the display.largePrints threshold
+	Unexpected   bool `json:"unexpected"`   // An application message type other than W, X or Y arrived
+	RejectBurst  bool `json:"rejectBurst"`  // alerts.rejectBurst.count rejects arrived within its window
+	Session      bool `json:"session"`      // Every FIX session event: logon, logout, disconnect, resend, sequence reset, reject
+
+	ProlongedDisconnect bool `json:"prolongedDisconnect"` // Still logged out after alerts.prolongedDisconnectAfter, and when the session is back
+}
+
+// TracingConfig exports sampled traces of the market data pipeline to an OpenTelemetry collector
+type TracingConfig struct {
+	Endpoint    string            `json:"endpoint"`    // OTLP/HTTP traces URL, e.g. http://localhost:4318/v1/traces; empty disables tracing
+	SampleRate  float64           `json:"sampleRate"`  // Fraction of market data messages traced, 0 to 1
+	ServiceName string            `json:"serviceName"` // service.name reported to the collector
+	Headers     map[string]string `json:"headers"`     // Extra HTTP headers, e.g. an API key for a hosted collector
+	Timeout     Duration          `json:"timeout"`     // Per-export HTTP timeout
+}
+
+// ResolvePortfolio maps a configured portfolio name to its ID; anything else is taken as a literal ID
+func (c *Config) ResolvePortfolio(nameOrId string) string {
+	if id, ok := c.Portfolios[nameOrId]; ok {
+		return id
+	}
+	return nameOrId
+}
+
+// Duration accepts Go duration strings ("30s", "5m") or plain seconds in JSON
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch value := v.(type) {
+	case float64:
+		*d = Duration(time.Duration(value * float64(time.Second)))
+	case string:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %v", value, err)
+		}
+		*d = Duration(parsed)
+	default:
+		return fmt.Errorf("invalid duration %s", string(data))
+	}
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func Default() *Config {
+	return &Config{
+		Log: LogConfig{
+			AdminSummaryInterval: Duration(time.Minute),
+		},
+		Clock: ClockConfig{
+			SkewWarnThreshold: Duration(time.Second),
+		},
+		Pipeline: PipelineConfig{
+			QueueSize: 10000,
+			Overflow:  "block",
+		},
+		Repl: ReplConfig{
+			HistorySize:  1000,
+			HistoryDedup: true,
+		},
+		Display: DisplayConfig{
+			SizeNotation: "plain",
+			TimeZone:     "UTC",
+			ASCII:        "auto",
+			SymbolColors: "auto",
+		},
+		Rest: RestConfig{
+			BaseUrl: "https://api.prime.coinbase.com",
+			Timeout: Duration(10 * time.Second),
+		},
+		Upload: UploadConfig{
+			Timeout: Duration(10 * time.Minute),
+		},
+		Database: DatabaseConfig{
+			Persist:     true,
+			BusyTimeout: Duration(5 * time.Second),
+			CacheSize:   1000,
+			Synchronous: "NORMAL",
+			QuickCheck:  true,
+
+			StatsInterval: Duration(time.Minute),
+		},
+		Archive: ArchiveConfig{
+			Every: Duration(time.Hour),
+			Dir:   "archive",
+		},
+		Daemon: DaemonConfig{
+			PidFile: "fix-md.pid",
+			LogFile: "fix-md.log",
+
+			ControlSocket: "fix-md.sock",
+		},
+		Heartbeat: HeartbeatConfig{
+			Every:   Duration(time.Minute),
+			Timeout: Duration(10 * time.Second),
+		},
+		Alerts: AlertsConfig{
+			Timeout: Duration(10 * time.Second),
+			Events: AlertsEvents{
+				Disconnect:   true,
+				LogonFailure: true,
+				Reject:       true,
+				Stale:        true,
+				Unexpected:   true,
+				RejectBurst:  true,
+
+				ProlongedDisconnect: true,
+			},
+			RejectBurst: RejectBurstConfig{
+				Count:  10,
+				Window: Duration(time.Minute),
+			},
+			Email: EmailConfig{
+				MinSeverity: "warning",
+			},
+			Incidents: IncidentsConfig{
+				StaleAfter: Duration(5 * time.Minute),
+			},
+		},
+		Tracing: TracingConfig{
+			SampleRate:  0.01,
+			ServiceName: "prime-fix-md-go",
+			Timeout:     Duration(5 * time.Second),
+		},
+	}
+}
+
+// Load reads the JSON config at path. A missing file is not an error; defaults are returned instead.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %v", path, err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+	return cfg, nil
+}
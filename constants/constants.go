@@ -20,9 +20,15 @@ import "github.com/quickfixgo/quickfix"
 
 const (
 	MsgTypeLogon                 = "A" // Logon
+	MsgTypeLogout                = "5" // Logout
+	MsgTypeResendRequest         = "2" // Resend Request
+	MsgTypeReject                = "3" // Session-level Reject
+	MsgTypeSequenceReset         = "4" // Sequence Reset
 	MsgTypeMarketDataRequest     = "V" // Market Data Request
 	MsgTypeMarketDataSnapshot    = "W" // Market Data Snapshot/Full Refresh
 	MsgTypeMarketDataIncremental = "X" // Market Data Incremental Refresh
+	MsgTypeMarketDataReject      = "Y" // Market Data Request Reject
+	MsgTypeBusinessMessageReject = "j" // Business Message Reject
 
 	FixTimeFormat     = "20060102-15:04:05.000"
 	FixBeginString    = "FIXT.1.1"
@@ -47,9 +53,18 @@ const (
 	MdUpdateTypeFullRefresh = "0" // Full refresh
 	MdUpdateTypeIncremental = "1" // Incremental refresh
 
+	AggregatedBookYes = "Y" // One entry per price level
+	AggregatedBookNo  = "N" // Multiple entries per price level (per order) where supported
+
+	MdUpdateActionNew    = "0" // New
+	MdUpdateActionChange = "1" // Change
+	MdUpdateActionDelete = "2" // Delete
+
 	TagAccount          = quickfix.Tag(1)
 	TagBeginString      = quickfix.Tag(8)
 	TagSymbol           = quickfix.Tag(55)
+	TagSecurityId       = quickfix.Tag(48)
+	TagSecurityIdSource = quickfix.Tag(22)
 	TagText             = quickfix.Tag(58)
 	TagSenderCompId     = quickfix.Tag(49)
 	TagSendingTime      = quickfix.Tag(52)
@@ -62,26 +77,47 @@ const (
 	TagAccessKey        = quickfix.Tag(9407)
 	TagEncryptMethod    = quickfix.Tag(98)
 	TagHeartBtInt       = quickfix.Tag(108)
+	TagResetSeqNumFlag  = quickfix.Tag(141)
 	TagDefaultApplVerId = quickfix.Tag(1137)
+	TagApplVerId        = quickfix.Tag(1128)
 	TagMsgSeqNum        = quickfix.Tag(34)
 
+	// Session Message Tags
+	TagBeginSeqNo  = quickfix.Tag(7)
+	TagEndSeqNo    = quickfix.Tag(16)
+	TagNewSeqNo    = quickfix.Tag(36)
+	TagRefSeqNum   = quickfix.Tag(45)
+	TagGapFillFlag = quickfix.Tag(123)
+
+	// Business Message Reject Tags
+	TagRefMsgType           = quickfix.Tag(372)
+	TagBusinessRejectReason = quickfix.Tag(380)
+
 	// Market Data Request Tags
 	TagNoRelatedSym            = quickfix.Tag(146)
 	TagMdReqId                 = quickfix.Tag(262)
 	TagSubscriptionRequestType = quickfix.Tag(263)
 	TagMarketDepth             = quickfix.Tag(264)
 	TagMdUpdateType            = quickfix.Tag(265)
+	TagAggregatedBook          = quickfix.Tag(266)
 	TagNoMdEntryTypes          = quickfix.Tag(267)
 	TagMdEntryType             = quickfix.Tag(269)
 
 	// Market Data Response Tags
 	TagMdEntryPx         = quickfix.Tag(270)
 	TagMdEntrySize       = quickfix.Tag(271)
+	TagMdEntryDate       = quickfix.Tag(272)
 	TagMdEntryTime       = quickfix.Tag(273)
 	TagMdReqRejReason    = quickfix.Tag(281)
 	TagNoMdEntries       = quickfix.Tag(268)
 	TagMdEntryPositionNo = quickfix.Tag(290)
+	TagNumberOfOrders    = quickfix.Tag(346)
+	TagMdEntryId         = quickfix.Tag(278)
+	TagQuoteCondition    = quickfix.Tag(276)
+	TagTradeCondition    = quickfix.Tag(277)
+	TagMdUpdateAction    = quickfix.Tag(279)
 	TagAggressorSide     = quickfix.Tag(2446)
+	TagRptSeq            = quickfix.Tag(83)
 
 	// MD Rejection Reasons
 	MdReqRejReasonUnknownSymbol              = "0"
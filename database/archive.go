@@ -0,0 +1,195 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package database
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ArchiveStats describes one archive file written by Archive
+type ArchiveStats struct {
+	Path      string // Empty when there was nothing to archive
+	Trades    int64
+	OrderBook int64
+	Ohlcv     int64
+}
+
+const archiveTimeFormat = "20060102T150405Z"
+
+// Archive moves rows received before cutoff out of the database into a gzip-compressed SQLite file
+// in dir, named after the time span it covers. Sessions stay in the hot database. An archive can be
+// restored with gunzip and Merge.
+func (mdb *MarketDataDb) Archive(dir string, cutoff time.Time) (ArchiveStats, error) {
+	var stats ArchiveStats
+	var oldest *int64
+	if err := mdb.db.QueryRow(`SELECT MIN(ns) FROM (
+		SELECT MIN(received_at_ns) AS ns FROM trades WHERE received_at_ns < ?1
+		UNION ALL SELECT MIN(received_at_ns) FROM order_book WHERE received_at_ns < ?1
+		UNION ALL SELECT MIN(received_at_ns) FROM ohlcv WHERE received_at_ns < ?1)`, cutoff.UnixNano()).Scan(&oldest); err != nil {
+		return stats, fmt.Errorf("failed to find rows to archive: %v", err)
+	}
+	if oldest == nil {
+		return stats, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return stats, fmt.Errorf("failed to create archive directory: %v", err)
+	}
+	name := fmt.Sprintf("marketdata-%s-%s.db", time.Unix(0, *oldest).UTC().Format(archiveTimeFormat), cutoff.UTC().Format(archiveTimeFormat))
+	dbPath := filepath.Join(dir, name)
+	if _, err := os.Stat(dbPath + ".gz"); err == nil {
+		return stats, fmt.Errorf("archive %s.gz already exists", dbPath)
+	}
+
+	archive, err := NewMarketDataDb(dbPath)
+	if err != nil {
+		return stats, err
+	}
+	// A rollback journal keeps every committed row in the file itself, ready to compress
+	_, err = archive.db.Exec("PRAGMA journal_mode=DELETE")
+	archive.Close()
+	if err != nil {
+		removeDbFiles(dbPath)
+		return stats, fmt.Errorf("failed to prepare archive: %v", err)
+	}
+
+	if err := mdb.write(func() error { return mdb.moveRows(dbPath, cutoff, &stats) }); err != nil {
+		removeDbFiles(dbPath)
+		return stats, err
+	}
+	if err := gzipFile(dbPath, dbPath+".gz"); err != nil {
+		// The rows are only in the uncompressed archive now, so it is kept
+		return stats, fmt.Errorf("rows were moved to %s but compressing it failed: %v", dbPath, err)
+	}
+	removeDbFiles(dbPath)
+	stats.Path = dbPath + ".gz"
+	return stats, nil
+}
+
+// moveRows copies old rows into the archive at dbPath and deletes them from mdb in one transaction
+func (mdb *MarketDataDb) moveRows(dbPath string, cutoff time.Time, stats *ArchiveStats) error {
+	ctx := context.Background()
+	conn, err := mdb.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open connection: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS archive", dbPath); err != nil {
+		return fmt.Errorf("failed to attach archive: %v", err)
+	}
+	defer conn.ExecContext(ctx, "DETACH DATABASE archive")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin archive: %v", err)
+	}
+	defer tx.Rollback()
+
+	counts := map[string]*int64{"trades": &stats.Trades, "order_book": &stats.OrderBook, "ohlcv": &stats.Ohlcv}
+	for _, table := range mergeTables {
+		columns, err := schemaColumns(ctx, tx, "main", table)
+		if err != nil {
+			return err
+		}
+		list := strings.Join(columns, ", ")
+		res, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO archive.%[1]s (%[2]s) SELECT %[2]s FROM main.%[1]s WHERE received_at_ns < ?",
+			table, list), cutoff.UnixNano())
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %v", table, err)
+		}
+		if *counts[table], err = res.RowsAffected(); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM main.%s WHERE received_at_ns < ?", table), cutoff.UnixNano()); err != nil {
+			return fmt.Errorf("failed to remove archived %s: %v", table, err)
+		}
+	}
+	// Book snapshots go with the rows they summarize
+	if _, err := tx.ExecContext(ctx, "INSERT OR REPLACE INTO archive.book_snapshots SELECT * FROM main.book_snapshots WHERE taken_at_ns < ?",
+		cutoff.UnixNano()); err != nil {
+		return fmt.Errorf("failed to archive book_snapshots: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM main.book_snapshots WHERE taken_at_ns < ?", cutoff.UnixNano()); err != nil {
+		return fmt.Errorf("failed to remove archived book_snapshots: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit archive: %v", err)
+	}
+	return nil
+}
+
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	zw := gzip.NewWriter(out)
+	zw.Name = filepath.Base(src)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	// The originals are removed once this returns, so the archive must be on disk by then,
+	// under its final name
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(dst))
+}
+
+// syncDir flushes dir's entries, making a rename into it durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func removeDbFiles(dbPath string) {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		os.Remove(dbPath + suffix)
+	}
+}
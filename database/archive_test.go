@@ -0,0 +1,114 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package database
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchiveMovesOldRows(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db.StoreTrade("BTC-USD", "100", "1", "Buy", "20250101-12:00:00", 1, "req", false)
+	db.StoreTrade("BTC-USD", "101", "1", "Buy", "20250108-12:00:00", 2, "req", false)
+	db.StoreOrderBookEntry("BTC-USD", "bid", "99", "1", 1, 1, "req", true)
+	old := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC).UnixNano()
+	db.db.Exec("UPDATE trades SET received_at_ns = ? WHERE seq_num = 1", old)
+	db.db.Exec("UPDATE order_book SET received_at_ns = ?", old)
+
+	dir := t.TempDir()
+	cutoff := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	stats, err := db.Archive(dir, cutoff)
+	if err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+	if stats.Trades != 1 || stats.OrderBook != 1 || stats.Ohlcv != 0 {
+		t.Fatalf("Expected 1 trade and 1 book entry archived, got %+v", stats)
+	}
+	if want := filepath.Join(dir, "marketdata-20250101T120000Z-20250102T000000Z.db.gz"); stats.Path != want {
+		t.Fatalf("Expected archive %s, got %s", want, stats.Path)
+	}
+
+	if trades, _ := db.QueryTrades("BTC-USD", TimeRange{}, 0); len(trades) != 1 || trades[0].SeqNum != 2 {
+		t.Fatalf("Expected only the recent trade to stay, got %+v", trades)
+	}
+
+	// The archive is a regular database once decompressed
+	restored := filepath.Join(t.TempDir(), "restored.db")
+	in, _ := os.Open(stats.Path)
+	defer in.Close()
+	zr, err := gzip.NewReader(in)
+	if err != nil {
+		t.Fatalf("Archive is not gzip: %v", err)
+	}
+	out, _ := os.Create(restored)
+	io.Copy(out, zr)
+	out.Close()
+
+	if _, err := db.Merge(restored); err != nil {
+		t.Fatalf("Merge of archive failed: %v", err)
+	}
+	if trades, _ := db.QueryTrades("BTC-USD", TimeRange{}, 0); len(trades) != 2 {
+		t.Fatalf("Expected the archived trade back after merging, got %d trades", len(trades))
+	}
+
+	if stats, err := db.Archive(dir, cutoff.Add(-time.Hour*48)); err != nil || stats.Path != "" {
+		t.Fatalf("Expected nothing to archive, got %+v (%v)", stats, err)
+	}
+}
+
+// Archiving runs on the single writer, so selecting old rows must not scan whole tables
+func TestArchiveQueriesUseReceiveTimeIndexes(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	queries := []string{
+		"SELECT MIN(received_at_ns) FROM trades WHERE received_at_ns < ?",
+		"DELETE FROM main.order_book WHERE received_at_ns < ?",
+		"DELETE FROM main.ohlcv WHERE received_at_ns < ?",
+		selectMessagesQuery,
+	}
+	for _, query := range queries {
+		rows, err := db.db.Query("EXPLAIN QUERY PLAN "+query, 0, 1)
+		if err != nil {
+			t.Fatalf("Failed to explain %q: %v", query, err)
+		}
+		var plan []string
+		for rows.Next() {
+			var id, parent, unused int
+			var detail string
+			if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+				t.Fatal(err)
+			}
+			plan = append(plan, detail)
+		}
+		rows.Close()
+		for _, step := range plan {
+			for _, table := range mergeTables {
+				if strings.HasPrefix(step, "SCAN "+table) {
+					t.Fatalf("Expected %q to search %s by receive time, got plan %q", query, table, plan)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrCorrupt is returned when opening a database that fails PRAGMA quick_check or is not SQLite at all
+var ErrCorrupt = errors.New("database is corrupt")
+
+// quickCheckLimit caps how many problems quick_check reports
+const quickCheckLimit = 10
+
+// checkIntegrity runs quick_check and folds a WAL left behind by a crash into the main file.
+// SQLite replays the WAL when the first connection opens the file, so any error here means
+// the data itself is damaged.
+func (mdb *MarketDataDb) checkIntegrity() error {
+	rows, err := mdb.db.Query(fmt.Sprintf("PRAGMA quick_check(%d)", quickCheckLimit))
+	if err != nil {
+		return corruptionError(err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return corruptionError(err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return corruptionError(err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrCorrupt, strings.Join(problems, "; "))
+	}
+
+	// Busy readers only make the checkpoint partial, which is harmless
+	if _, err := mdb.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint WAL: %v", err)
+	}
+	return nil
+}
+
+// corruptionError wraps errors SQLite raises for damaged or foreign files in ErrCorrupt
+// and returns any other error unchanged
+func corruptionError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrCorrupt || sqliteErr.Code == sqlite3.ErrNotADB) {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return err
+}
+
+// MoveAside renames dbPath and its WAL and shared-memory files to <dbPath>.corrupt-<time> so a
+// fresh database can be created in its place. It returns the new path of the main file.
+func MoveAside(dbPath string) (string, error) {
+	aside := fmt.Sprintf("%s.corrupt-%s", dbPath, time.Now().UTC().Format("20060102T150405Z"))
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Rename(dbPath+suffix, aside+suffix); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to move %s aside: %v", dbPath+suffix, err)
+		}
+	}
+	return aside, nil
+}
@@ -0,0 +1,55 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package database
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenCorruptDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "corrupt.db")
+	garbage := []byte(strings.Repeat("not a sqlite database ", 200))
+	if err := os.WriteFile(dbPath, garbage, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewMarketDataDb(dbPath); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("Expected ErrCorrupt, got %v", err)
+	}
+
+	opts := DefaultOptions()
+	opts.ResetIfCorrupt = true
+	db, err := NewMarketDataDbWithOptions(dbPath, opts)
+	if err != nil {
+		t.Fatalf("Expected a fresh database after moving the corrupt one aside, got %v", err)
+	}
+	defer db.Close()
+	if err := db.CreateSession("s1", "BTC-USD", "subscribe", "trades", "req-1", nil); err != nil {
+		t.Fatalf("Failed to write to fresh database: %v", err)
+	}
+
+	aside, _ := filepath.Glob(dbPath + ".corrupt-*")
+	if len(aside) != 1 {
+		t.Fatalf("Expected the corrupt file to be kept, found %v", aside)
+	}
+	if data, _ := os.ReadFile(aside[0]); string(data) != string(garbage) {
+		t.Fatal("Expected the corrupt file to be moved unchanged")
+	}
+}
@@ -18,76 +18,513 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 type MarketDataDb struct {
 	db *sql.DB
+
+	// Every write runs on one goroutine so ingest, exports and maintenance never contend for the write lock
+	writes      chan writeOp
+	writerDone  chan struct{}
+	writeMu     sync.RWMutex
+	writeClosed bool
+
+	metrics   writerMetrics
+	wroteRows int64 // Rows changed by the current write; only touched on the writer goroutine
+}
+
+type writeOp struct {
+	fn     func() error
+	done   chan error
+	queued time.Time
+}
+
+// Options tunes the SQLite connection. Zero values keep the defaults.
+type Options struct {
+	BusyTimeout time.Duration // How long a statement waits for a lock held by another connection or process
+	CacheSize   int           // PRAGMA cache_size: pages when positive, KiB when negative
+	Synchronous string        // OFF, NORMAL, FULL or EXTRA
+	MmapSize    int64         // Bytes of the file to memory-map for reads; 0 disables mmap
+
+	SkipQuickCheck bool // Open without running PRAGMA quick_check first
+	ResetIfCorrupt bool // Move a corrupt file aside (see MoveAside) and start with an empty database
+}
+
+func DefaultOptions() Options {
+	return Options{
+		BusyTimeout: 5 * time.Second,
+		CacheSize:   1000,
+		Synchronous: "NORMAL",
+	}
 }
 
 func NewMarketDataDb(dbPath string) (*MarketDataDb, error) {
-	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=WAL&_synchronous=NORMAL&_cache_size=1000")
+	return NewMarketDataDbWithOptions(dbPath, DefaultOptions())
+}
+
+func NewMarketDataDbWithOptions(dbPath string, opts Options) (*MarketDataDb, error) {
+	driver, dsn, err := opts.dsn(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %v", err)
 	}
 
-	mdb := &MarketDataDb{db: db}
+	mdb := &MarketDataDb{db: db, writes: make(chan writeOp), writerDone: make(chan struct{})}
+	if !opts.SkipQuickCheck {
+		if err := mdb.checkIntegrity(); err != nil {
+			db.Close()
+			if !errors.Is(err, ErrCorrupt) || !opts.ResetIfCorrupt {
+				return nil, fmt.Errorf("%s: %w", dbPath, err)
+			}
+			aside, moveErr := MoveAside(dbPath)
+			if moveErr != nil {
+				return nil, fmt.Errorf("%s: %w (%v)", dbPath, err, moveErr)
+			}
+			log.Printf("Warning: %s: %v; moved it to %s and starting with an empty database", dbPath, err, aside)
+			opts.ResetIfCorrupt = false
+			return NewMarketDataDbWithOptions(dbPath, opts)
+		}
+	}
 	if err := mdb.initSchema(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to initialize schema: %v", err)
+		return nil, fmt.Errorf("failed to initialize schema: %w", corruptionError(err))
 	}
+	go mdb.runWriter()
 
 	log.Printf("SQLite database initialized at %s", dbPath)
 	return mdb, nil
 }
 
+func (o Options) dsn(dbPath string) (string, string, error) {
+	defaults := DefaultOptions()
+	if o.Synchronous == "" {
+		o.Synchronous = defaults.Synchronous
+	}
+	switch strings.ToUpper(o.Synchronous) {
+	case "OFF", "NORMAL", "FULL", "EXTRA":
+	default:
+		return "", "", fmt.Errorf("invalid synchronous level %q (expected OFF, NORMAL, FULL or EXTRA)", o.Synchronous)
+	}
+	if o.CacheSize == 0 {
+		o.CacheSize = defaults.CacheSize
+	}
+	if o.BusyTimeout < 0 || o.MmapSize < 0 {
+		return "", "", fmt.Errorf("busy timeout and mmap size must not be negative")
+	}
+
+	dsn := fmt.Sprintf("%s?_journal_mode=WAL&_synchronous=%s&_cache_size=%d&_busy_timeout=%d",
+		dbPath, strings.ToUpper(o.Synchronous), o.CacheSize, o.BusyTimeout.Milliseconds())
+	return mmapDriver(o.MmapSize), dsn, nil
+}
+
+// The DSN has no mmap setting, so each distinct size gets a driver that applies it to every connection
+var (
+	mmapDriversMu sync.Mutex
+	mmapDrivers   = map[int64]string{}
+)
+
+func mmapDriver(size int64) string {
+	if size == 0 {
+		return "sqlite3"
+	}
+	mmapDriversMu.Lock()
+	defer mmapDriversMu.Unlock()
+	if name, ok := mmapDrivers[size]; ok {
+		return name
+	}
+	name := fmt.Sprintf("sqlite3_mmap_%d", size)
+	sql.Register(name, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			_, err := conn.Exec(fmt.Sprintf("PRAGMA mmap_size = %d", size), nil)
+			return err
+		},
+	})
+	mmapDrivers[size] = name
+	return name
+}
+
+func (mdb *MarketDataDb) runWriter() {
+	defer close(mdb.writerDone)
+	for op := range mdb.writes {
+		mdb.metrics.queued.Add(-1)
+		mdb.wroteRows = 0
+		err := op.fn()
+		now := time.Now()
+		mdb.metrics.record(mdb.wroteRows, now.Sub(op.queued), err, now)
+		op.done <- err
+	}
+}
+
+// write runs fn on the writer goroutine and waits for it
+func (mdb *MarketDataDb) write(fn func() error) error {
+	mdb.writeMu.RLock()
+	defer mdb.writeMu.RUnlock()
+	if mdb.writeClosed {
+		return errors.New("database is closed")
+	}
+	done := make(chan error, 1)
+	mdb.metrics.queued.Add(1)
+	mdb.writes <- writeOp{fn: fn, done: done, queued: time.Now()}
+	return <-done
+}
+
+// WriteBatch runs fn in one transaction on the writer goroutine, committing when it returns nil
+func (mdb *MarketDataDb) WriteBatch(fn func(tx *sql.Tx) error) error {
+	return mdb.write(func() error {
+		tx, err := mdb.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %v", err)
+		}
+		defer tx.Rollback()
+		var before, after int64
+		if err := tx.QueryRow("SELECT total_changes()").Scan(&before); err != nil {
+			return fmt.Errorf("failed to read change count: %v", err)
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+		// total_changes is per connection, and the transaction holds one connection throughout
+		if err := tx.QueryRow("SELECT total_changes()").Scan(&after); err != nil {
+			return fmt.Errorf("failed to read change count: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %v", err)
+		}
+		mdb.wroteRows = after - before
+		return nil
+	})
+}
+
+// Close waits for pending writes and closes the database
 func (mdb *MarketDataDb) Close() error {
+	mdb.writeMu.Lock()
+	if !mdb.writeClosed {
+		mdb.writeClosed = true
+		close(mdb.writes)
+	}
+	mdb.writeMu.Unlock()
+	<-mdb.writerDone
 	return mdb.db.Close()
 }
 
 // Session management
 func (mdb *MarketDataDb) CreateSession(sessionId, symbol, requestType, dataTypes, mdReqId string, depth *int) error {
-	_, err := mdb.db.Exec(insertSessionQuery, sessionId, symbol, requestType, dataTypes, depth, mdReqId)
-	return err
+	return mdb.CreateSessionWithLabel(sessionId, symbol, requestType, dataTypes, mdReqId, depth, "")
+}
+
+// CreateSessionWithLabel is CreateSession with a label naming what the capture is for; empty stores NULL
+func (mdb *MarketDataDb) CreateSessionWithLabel(sessionId, symbol, requestType, dataTypes, mdReqId string, depth *int, label string) error {
+	return mdb.exec(insertSessionQuery, sessionId, symbol, requestType, dataTypes, depth, mdReqId, label)
+}
+
+// Reasons recorded in sessions.end_reason
+const (
+	EndReasonUnsubscribed = "unsubscribed"
+	EndReasonRejected     = "rejected"
+	EndReasonExit         = "exit"
+	EndReasonLogout       = "logout"
+)
+
+// sessionTimeLayout is the CURRENT_TIMESTAMP format used for created_at, in UTC
+const sessionTimeLayout = "2006-01-02 15:04:05"
+
+// sessionTime matches the CURRENT_TIMESTAMP format used for created_at
+func sessionTime(t time.Time) string {
+	return t.UTC().Format(sessionTimeLayout)
+}
+
+// EndSession closes the open session rows of a request. totalUpdates is nil when the
+// request was not tracked, e.g. a rejected snapshot.
+func (mdb *MarketDataDb) EndSession(mdReqId, reason string, totalUpdates *int64, ended time.Time) error {
+	return mdb.exec(endSessionQuery, sessionTime(ended), totalUpdates, reason, mdReqId)
+}
+
+// StoreReject records a Market Data Request Reject. symbol may be empty when the reject did not carry one.
+func (mdb *MarketDataDb) StoreReject(mdReqId, symbol, reason, text string, rejected time.Time) error {
+	return mdb.exec(insertRejectQuery, mdReqId, symbol, reason, text, rejected.UnixNano())
+}
+
+// Events recorded in subscription_events
+const (
+	EventSubscribe        = "subscribe"
+	EventSnapshotReceived = "snapshot_received"
+	EventStale            = "stale"
+	EventRecovered        = "recovered"
+	EventResubscribe      = "resubscribe"
+	EventUnsubscribe      = "unsubscribe"
+	EventRejected         = "rejected"
+)
+
+// StoreSubscriptionEvent appends one event to a subscription's timeline
+func (mdb *MarketDataDb) StoreSubscriptionEvent(mdReqId, symbol, event, detail string, at time.Time) error {
+	return mdb.exec(insertSubscriptionEventQuery, mdReqId, symbol, event, detail, at.UnixNano())
+}
+
+// Events recorded in fix_session_events
+const (
+	FixEventCreated       = "created"
+	FixEventLogon         = "logon"
+	FixEventLogout        = "logout"     // A Logout (35=5) was sent or received
+	FixEventDisconnect    = "disconnect" // The session ended, with or without a Logout
+	FixEventResend        = "resend"     // A ResendRequest (35=2) was sent or received
+	FixEventSequenceReset = "sequence_reset"
+	FixEventReject        = "reject" // A session-level Reject (35=3)
+	FixEventSeqSet        = "seq_set"
+)
+
+// StoreFixSessionEvent appends one event to the FIX session timeline
+func (mdb *MarketDataDb) StoreFixSessionEvent(sessionId, event, detail string, at time.Time) error {
+	return mdb.exec(insertFixSessionEventQuery, sessionId, event, detail, at.UnixNano())
+}
+
+// StoreMdTemplate saves the md flags for a template name, replacing any saved before
+func (mdb *MarketDataDb) StoreMdTemplate(name, flags string, at time.Time) error {
+	return mdb.exec(upsertMdTemplateQuery, name, flags, at.UnixNano())
+}
+
+// DeleteMdTemplate removes a saved template; removing one that does not exist is not an error
+func (mdb *MarketDataDb) DeleteMdTemplate(name string) error {
+	return mdb.exec(deleteMdTemplateQuery, name)
+}
+
+// EndOpenSessions closes every session row that is still open, e.g. on exit
+func (mdb *MarketDataDb) EndOpenSessions(reason string, ended time.Time) error {
+	return mdb.exec(endOpenSessionsQuery, sessionTime(ended), reason)
+}
+
+// TradeRecord is one trade print. Optional FIX fields are empty when not sent.
+type TradeRecord struct {
+	Symbol         string
+	Price          string
+	Size           string
+	AggressorSide  string
+	TradeTime      string
+	SeqNum         int
+	MdReqId        string
+	IsSnapshot     bool
+	MdEntryId      string // MDEntryID (278)
+	UpdateAction   string // MDUpdateAction (279)
+	TradeCondition string // TradeCondition (277)
+
+	SecurityId       string // SecurityID (48)
+	SecurityIdSource string // SecurityIDSource (22)
+
+	AggressorInferred bool // AggressorSide was inferred, not sent in AggressorSide (2446)
+}
+
+func (r TradeRecord) args(received time.Time) []interface{} {
+	return []interface{}{r.Symbol, r.Price, r.Size, r.AggressorSide, r.TradeTime, r.SeqNum, r.MdReqId, r.IsSnapshot,
+		eventTimeNs(r.TradeTime, received), received.UnixNano(), nullIfEmpty(r.MdEntryId), nullIfEmpty(r.UpdateAction),
+		nullIfEmpty(r.TradeCondition), nullIfEmpty(r.SecurityId), nullIfEmpty(r.SecurityIdSource), r.AggressorInferred}
 }
 
 // Trade data storage
 func (mdb *MarketDataDb) StoreTrade(symbol, price, size, aggressorSide, tradeTime string, seqNum int, mdReqId string, isSnapshot bool) error {
-	_, err := mdb.db.Exec(insertTradeQuery, symbol, price, size, aggressorSide, tradeTime, seqNum, mdReqId, isSnapshot)
-	return err
+	return mdb.StoreTradeRecord(TradeRecord{Symbol: symbol, Price: price, Size: size, AggressorSide: aggressorSide,
+		TradeTime: tradeTime, SeqNum: seqNum, MdReqId: mdReqId, IsSnapshot: isSnapshot})
+}
+
+func (mdb *MarketDataDb) StoreTradeRecord(rec TradeRecord) error {
+	return mdb.exec(insertTradeQuery, rec.args(time.Now())...)
+}
+
+// TradePrintRecord is consecutive trades at one price and aggressor, coalesced into one print
+type TradePrintRecord struct {
+	Symbol        string
+	Price         string
+	Size          string // Total size
+	Count         int
+	AggressorSide string
+	FirstTrade    time.Time
+	LastTrade     time.Time
+	MdReqId       string
+}
+
+// StoreTradePrint writes an aggregated print to trade_prints
+func (mdb *MarketDataDb) StoreTradePrint(rec TradePrintRecord) error {
+	return mdb.exec(insertTradePrintQuery, rec.Symbol, rec.Price, rec.Size, rec.Count, rec.AggressorSide,
+		rec.FirstTrade.UnixNano(), rec.LastTrade.UnixNano(), rec.MdReqId, time.Now().UnixNano())
+}
+
+// OrderBookRecord is one bid or offer level. Optional FIX fields are nil when not sent.
+type OrderBookRecord struct {
+	Symbol         string
+	Side           string // "bid" or "offer"
+	Price          string
+	Size           string
+	Position       int
+	SeqNum         int
+	MdReqId        string
+	IsSnapshot     bool
+	NumOrders      *int
+	MdEntryId      string // MDEntryID (278)
+	UpdateAction   string // MDUpdateAction (279)
+	QuoteCondition string // QuoteCondition (276)
+
+	SecurityId       string // SecurityID (48)
+	SecurityIdSource string // SecurityIDSource (22)
+}
+
+func (r OrderBookRecord) args(receivedNs int64) []interface{} {
+	return []interface{}{r.Symbol, r.Side, r.Price, r.Size, r.Position, r.SeqNum, r.MdReqId, r.IsSnapshot, receivedNs, r.NumOrders,
+		nullIfEmpty(r.MdEntryId), nullIfEmpty(r.UpdateAction), nullIfEmpty(r.QuoteCondition),
+		nullIfEmpty(r.SecurityId), nullIfEmpty(r.SecurityIdSource)}
 }
 
 // Order book data storage
 func (mdb *MarketDataDb) StoreOrderBookEntry(symbol, side, price, size string, position, seqNum int, mdReqId string, isSnapshot bool) error {
-	_, err := mdb.db.Exec(insertOrderBookQuery, symbol, side, price, size, position, seqNum, mdReqId, isSnapshot)
-	return err
+	return mdb.StoreOrderBookRecord(OrderBookRecord{Symbol: symbol, Side: side, Price: price, Size: size,
+		Position: position, SeqNum: seqNum, MdReqId: mdReqId, IsSnapshot: isSnapshot})
+}
+
+func (mdb *MarketDataDb) StoreOrderBookRecord(rec OrderBookRecord) error {
+	return mdb.exec(insertOrderBookQuery, rec.args(time.Now().UnixNano())...)
 }
 
 // OHLCV data storage
 func (mdb *MarketDataDb) StoreOHLCV(symbol, dataType, value, entryTime string, seqNum int, mdReqId string) error {
-	_, err := mdb.db.Exec(insertOHLCVQuery, symbol, dataType, value, entryTime, seqNum, mdReqId)
-	return err
+	now := time.Now()
+	return mdb.exec(insertOHLCVQuery, symbol, dataType, value, entryTime, seqNum, mdReqId,
+		eventTimeNs(entryTime, now), now.UnixNano())
+}
+
+func (mdb *MarketDataDb) exec(query string, args ...interface{}) error {
+	return mdb.write(func() error {
+		res, err := mdb.db.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+		mdb.wroteRows, _ = res.RowsAffected()
+		return nil
+	})
 }
 
-// Batch operations for better performance
+// Batch operations for better performance.
+// BeginTransaction bypasses the writer goroutine; WriteBatch is preferred.
 func (mdb *MarketDataDb) BeginTransaction() (*sql.Tx, error) {
 	return mdb.db.Begin()
 }
 
 func (mdb *MarketDataDb) StoreTradeBatch(tx *sql.Tx, symbol, price, size, aggressorSide, tradeTime string, seqNum int, mdReqId string, isSnapshot bool) error {
-	_, err := tx.Exec(insertTradeQuery, symbol, price, size, aggressorSide, tradeTime, seqNum, mdReqId, isSnapshot)
+	return mdb.StoreTradeRecordBatch(tx, TradeRecord{Symbol: symbol, Price: price, Size: size, AggressorSide: aggressorSide,
+		TradeTime: tradeTime, SeqNum: seqNum, MdReqId: mdReqId, IsSnapshot: isSnapshot})
+}
+
+func (mdb *MarketDataDb) StoreTradeRecordBatch(tx *sql.Tx, rec TradeRecord) error {
+	_, err := tx.Exec(insertTradeQuery, rec.args(time.Now())...)
 	return err
 }
 
 func (mdb *MarketDataDb) StoreOrderBookBatch(tx *sql.Tx, symbol, side, price, size string, position, seqNum int, mdReqId string, isSnapshot bool) error {
-	_, err := tx.Exec(insertOrderBookQuery, symbol, side, price, size, position, seqNum, mdReqId, isSnapshot)
+	return mdb.StoreOrderBookRecordBatch(tx, OrderBookRecord{Symbol: symbol, Side: side, Price: price, Size: size,
+		Position: position, SeqNum: seqNum, MdReqId: mdReqId, IsSnapshot: isSnapshot})
+}
+
+func (mdb *MarketDataDb) StoreOrderBookRecordBatch(tx *sql.Tx, rec OrderBookRecord) error {
+	_, err := tx.Exec(insertOrderBookQuery, rec.args(time.Now().UnixNano())...)
 	return err
 }
 
 func (mdb *MarketDataDb) StoreOhlcvBatch(tx *sql.Tx, symbol, dataType, value, entryTime string, seqNum int, mdReqId string) error {
-	_, err := tx.Exec(insertOHLCVQuery, symbol, dataType, value, entryTime, seqNum, mdReqId)
+	now := time.Now()
+	_, err := tx.Exec(insertOHLCVQuery, symbol, dataType, value, entryTime, seqNum, mdReqId,
+		eventTimeNs(entryTime, now), now.UnixNano())
 	return err
 }
+
+// BookStateLevel is one level of the current book written to order_book_state
+type BookStateLevel struct {
+	Price     string
+	Size      string
+	NumOrders *int
+	EntryId   string
+}
+
+// StoreBookStateBatch makes order_book_state for symbol match bids and offers (best first). Levels are
+// upserted, rows that did not change are left alone, and levels beyond the current depth are removed.
+func (mdb *MarketDataDb) StoreBookStateBatch(tx *sql.Tx, symbol string, bids, offers []BookStateLevel, updated time.Time) error {
+	for side, levels := range map[string][]BookStateLevel{"bid": bids, "offer": offers} {
+		for i, level := range levels {
+			if _, err := tx.Exec(upsertBookStateQuery, symbol, side, i+1, level.Price, level.Size, level.NumOrders,
+				nullIfEmpty(level.EntryId), updated.UnixNano()); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(trimBookStateQuery, symbol, side, len(levels)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StoreBookSnapshot writes a full copy of the book for symbol (bids and offers best first) to
+// book_snapshots, stamped with the receive time of the last update it includes
+func (mdb *MarketDataDb) StoreBookSnapshot(symbol string, bids, offers []BookStateLevel, takenAt time.Time) error {
+	return mdb.WriteBatch(func(tx *sql.Tx) error {
+		for _, side := range []struct {
+			name   string
+			levels []BookStateLevel
+		}{{"bid", bids}, {"offer", offers}} {
+			for i, level := range side.levels {
+				if _, err := tx.Exec(insertBookSnapshotQuery, symbol, side.name, i+1, level.Price, level.Size,
+					level.NumOrders, nullIfEmpty(level.EntryId), takenAt.UnixNano()); err != nil {
+					return fmt.Errorf("failed to store book snapshot: %v", err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// SummaryUpdate is what one market data message changes in market_summary for a symbol
+type SummaryUpdate struct {
+	Symbol string
+
+	Trades        int // Trade entries in the message
+	LastPrice     string
+	LastSize      string
+	LastAggressor string
+	LastTradeTime time.Time // Zero when the message had no trades or the trade had no MDEntryTime
+
+	BookUpdates int  // Bid/offer entries in the message
+	HasQuote    bool // The fields below hold the current best bid/offer; otherwise the stored quote is kept
+	BidPrice    string
+	BidSize     string
+	OfferPrice  string
+	OfferSize   string
+
+	Updated time.Time
+}
+
+func (mdb *MarketDataDb) StoreSummaryBatch(tx *sql.Tx, u SummaryUpdate) error {
+	var lastPrice, lastSize, lastAggressor, lastTradeNs interface{}
+	if u.Trades > 0 {
+		lastPrice, lastSize, lastAggressor = u.LastPrice, u.LastSize, nullIfEmpty(u.LastAggressor)
+		if !u.LastTradeTime.IsZero() {
+			lastTradeNs = u.LastTradeTime.UnixNano()
+		}
+	}
+	_, err := tx.Exec(upsertSummaryQuery, u.Symbol, lastPrice, lastSize, lastAggressor, lastTradeNs,
+		nullIfEmpty(u.BidPrice), nullIfEmpty(u.BidSize), nullIfEmpty(u.OfferPrice), nullIfEmpty(u.OfferSize),
+		u.Trades, u.BookUpdates, u.Updated.UnixNano(), u.HasQuote)
+	return err
+}
+
+// Optional text fields are stored as NULL rather than empty strings
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
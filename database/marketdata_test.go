@@ -17,8 +17,13 @@
 package database
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -83,6 +88,106 @@ func TestCreateSession(t *testing.T) {
 	}
 }
 
+func TestEndSession(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.CreateSession("s1", "BTC-USD", "subscribe", "trades", "req-1", nil); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if err := db.CreateSession("s2", "ETH-USD", "snapshot", "trades", "req-2", nil); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	updates := int64(42)
+	ended := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	if err := db.EndSession("req-1", EndReasonUnsubscribed, &updates, ended); err != nil {
+		t.Fatalf("Failed to end session: %v", err)
+	}
+	if err := db.EndOpenSessions(EndReasonExit, ended.Add(time.Minute)); err != nil {
+		t.Fatalf("Failed to end open sessions: %v", err)
+	}
+
+	var (
+		endedAt, reason string
+		total           sql.NullInt64
+		active          bool
+	)
+	err := db.db.QueryRow("SELECT CAST(ended_at AS TEXT), total_updates, end_reason, is_active FROM sessions WHERE session_id = 's1'").
+		Scan(&endedAt, &total, &reason, &active)
+	if err != nil || endedAt != "2025-03-01 10:00:00" || total.Int64 != 42 || reason != EndReasonUnsubscribed || active {
+		t.Fatalf("Expected s1 unsubscribed with 42 updates, got %s %v %s %v (%v)", endedAt, total, reason, active, err)
+	}
+	err = db.db.QueryRow("SELECT total_updates, end_reason FROM sessions WHERE session_id = 's2'").Scan(&total, &reason)
+	if err != nil || total.Valid || reason != EndReasonExit {
+		t.Fatalf("Expected s2 closed on exit without a count, got %v %s (%v)", total, reason, err)
+	}
+}
+
+func TestSessionLabels(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.CreateSessionWithLabel("s1", "BTC-USD", "subscribe", "trades", "req-1", nil, "fomc-day"); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if err := db.CreateSession("s2", "ETH-USD", "subscribe", "trades", "req-2", nil); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	updates := int64(7)
+	if err := db.EndSession("req-1", EndReasonUnsubscribed, &updates, time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Failed to end session: %v", err)
+	}
+
+	labelled, err := db.QuerySessions("fomc-day", 0)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(labelled) != 1 || labelled[0].MdReqId != "req-1" || labelled[0].Label != "fomc-day" {
+		t.Fatalf("Expected the labelled request only, got %+v", labelled)
+	}
+	s := labelled[0]
+	if s.CreatedAt.IsZero() || !s.EndedAt.Equal(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)) || s.TotalUpdates == nil || *s.TotalUpdates != 7 {
+		t.Fatalf("Unexpected session fields %+v", s)
+	}
+
+	all, err := db.QuerySessions("", 0)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("Expected both requests without a label filter, got %d (%v)", len(all), err)
+	}
+	for _, s := range all {
+		if s.MdReqId == "req-2" && (s.Label != "" || !s.EndedAt.IsZero() || s.TotalUpdates != nil) {
+			t.Fatalf("Expected an open, unlabelled req-2, got %+v", s)
+		}
+	}
+}
+
+func TestStoreRejectLinksSession(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.CreateSession("s1", "BTC-USD", "subscribe", "trades", "req-1", nil); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	now := time.Now()
+	if err := db.StoreReject("req-1", "", "0", "Unknown symbol", now); err != nil {
+		t.Fatalf("Failed to store reject: %v", err)
+	}
+	if err := db.StoreReject("req-9", "", "3", "", now); err != nil {
+		t.Fatalf("Failed to store reject: %v", err)
+	}
+
+	var sessionId, symbol, text sql.NullString
+	err := db.db.QueryRow("SELECT session_id, symbol, text FROM rejects WHERE md_req_id = 'req-1'").Scan(&sessionId, &symbol, &text)
+	if err != nil || sessionId.String != "s1" || symbol.String != "BTC-USD" || text.String != "Unknown symbol" {
+		t.Fatalf("Expected reject linked to s1 for BTC-USD, got %v %v %v (%v)", sessionId, symbol, text, err)
+	}
+	err = db.db.QueryRow("SELECT session_id, symbol, text FROM rejects WHERE md_req_id = 'req-9'").Scan(&sessionId, &symbol, &text)
+	if err != nil || sessionId.Valid || symbol.Valid || text.Valid {
+		t.Fatalf("Expected unlinked reject without symbol or text, got %v %v %v (%v)", sessionId, symbol, text, err)
+	}
+}
+
 func TestStoreTrade(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -171,6 +276,36 @@ func TestStoreOHLCV(t *testing.T) {
 	}
 }
 
+func TestStoreTradePrint(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	first := time.Now()
+	err := db.StoreTradePrint(TradePrintRecord{
+		Symbol:        "BTC-USD",
+		Price:         "50000.00",
+		Size:          "1.75",
+		Count:         3,
+		AggressorSide: "1",
+		FirstTrade:    first,
+		LastTrade:     first.Add(100 * time.Millisecond),
+		MdReqId:       "req-126",
+	})
+	if err != nil {
+		t.Fatalf("Failed to store trade print: %v", err)
+	}
+
+	var count int
+	var span int64
+	err = db.db.QueryRow("SELECT trade_count, last_trade_time_ns - first_trade_time_ns FROM trade_prints WHERE symbol = ?", "BTC-USD").Scan(&count, &span)
+	if err != nil {
+		t.Fatalf("Failed to query trade print: %v", err)
+	}
+	if count != 3 || span != int64(100*time.Millisecond) {
+		t.Fatalf("Expected 3 trades over 100ms, found %d over %dns", count, span)
+	}
+}
+
 func TestBatchOperations(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -284,3 +419,507 @@ func TestTransactionRollback(t *testing.T) {
 		t.Fatalf("Expected 0 trades after rollback, found %d", count)
 	}
 }
+
+func TestQueryTradesOrdersByExchangeTime(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Inserted out of order and in mixed FIX/RFC3339 formats
+	inputs := []struct {
+		tradeTime string
+		price     string
+	}{
+		{"20250101-12:00:02.500", "3"},
+		{"2025-01-01T12:00:00Z", "1"},
+		{"20250101-12:00:01", "2"},
+		{"20250101-12:00:05.000", "4"},
+	}
+	for i, in := range inputs {
+		if err := db.StoreTrade("BTC-USD", in.price, "1", "Buy", in.tradeTime, i, "req", false); err != nil {
+			t.Fatalf("Failed to store trade: %v", err)
+		}
+	}
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	trades, err := db.QueryTrades("BTC-USD", TimeRange{From: base, To: base.Add(5 * time.Second)}, 0)
+	if err != nil {
+		t.Fatalf("QueryTrades failed: %v", err)
+	}
+	if len(trades) != 3 {
+		t.Fatalf("Expected 3 trades in range, got %d", len(trades))
+	}
+	for i, trade := range trades {
+		if trade.Price != fmt.Sprintf("%d.0", i+1) {
+			t.Fatalf("Expected trades ordered by time, got price %s at index %d", trade.Price, i)
+		}
+	}
+	if !trades[2].TradeTime.Equal(base.Add(2500 * time.Millisecond)) {
+		t.Fatalf("Expected millisecond precision, got %v", trades[2].TradeTime)
+	}
+
+	limited, err := db.QueryTrades("BTC-USD", TimeRange{}, 2)
+	if err != nil || len(limited) != 2 {
+		t.Fatalf("Expected 2 trades with limit, got %d (%v)", len(limited), err)
+	}
+}
+
+func TestQueryTradesAggressorInferred(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for i, inferred := range []bool{false, true} {
+		err := db.StoreTradeRecord(TradeRecord{Symbol: "BTC-USD", Price: "50000", Size: "1", AggressorSide: "Buy",
+			TradeTime: fmt.Sprintf("20250101-12:00:0%d", i), SeqNum: i, MdReqId: "req", AggressorInferred: inferred})
+		if err != nil {
+			t.Fatalf("Failed to store trade: %v", err)
+		}
+	}
+
+	trades, err := db.QueryTrades("BTC-USD", TimeRange{}, 0)
+	if err != nil {
+		t.Fatalf("QueryTrades failed: %v", err)
+	}
+	if len(trades) != 2 || trades[0].AggressorInferred || !trades[1].AggressorInferred {
+		t.Fatalf("Expected only the second trade marked inferred, got %+v", trades)
+	}
+}
+
+func TestQueryTradesPage(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Two trades share a timestamp, so the cursor has to break the tie by row
+	for i, tradeTime := range []string{"20250101-12:00:01", "20250101-12:00:02", "20250101-12:00:02", "20250101-12:00:03", "20250101-12:00:04"} {
+		if err := db.StoreTrade("BTC-USD", fmt.Sprint(i+1), "1", "Buy", tradeTime, i, "req", false); err != nil {
+			t.Fatalf("Failed to store trade: %v", err)
+		}
+	}
+
+	var prices []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		page, err := db.QueryTradesPage("BTC-USD", TimeRange{}, cursor, 2)
+		if err != nil {
+			t.Fatalf("QueryTradesPage failed: %v", err)
+		}
+		for _, trade := range page.Trades {
+			prices = append(prices, trade.Price)
+		}
+		if page.Next == "" {
+			if pages != 2 {
+				t.Fatalf("Expected 3 pages, got %d", pages+1)
+			}
+			break
+		}
+		cursor = page.Next
+	}
+	if strings.Join(prices, " ") != "1.0 2.0 3.0 4.0 5.0" {
+		t.Fatalf("Expected every trade once in order, got %v", prices)
+	}
+
+	if _, err := db.QueryTradesPage("BTC-USD", TimeRange{}, "not-a-cursor", 2); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestUnparseableTradeTimeFallsBackToReceiveTime(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	before := time.Now()
+	if err := db.StoreTrade("BTC-USD", "1", "1", "Buy", "not-a-time", 1, "req", false); err != nil {
+		t.Fatalf("Failed to store trade: %v", err)
+	}
+
+	trades, err := db.QueryTrades("BTC-USD", TimeRange{From: before}, 0)
+	if err != nil || len(trades) != 1 {
+		t.Fatalf("Expected 1 trade, got %d (%v)", len(trades), err)
+	}
+	if !trades[0].TradeTime.Equal(trades[0].ReceivedAt) {
+		t.Fatalf("Expected trade time to fall back to receive time, got %v vs %v", trades[0].TradeTime, trades[0].ReceivedAt)
+	}
+}
+
+func TestMigrateAddsEpochColumns(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+
+	// Create the pre-epoch-ns trades table and a row in it
+	legacy, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+	_, err = legacy.Exec(`CREATE TABLE trades (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, symbol TEXT NOT NULL, price REAL NOT NULL, size REAL NOT NULL,
+		aggressor_side TEXT, trade_time TEXT, seq_num INTEGER, md_req_id TEXT, is_snapshot BOOLEAN,
+		received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+		INSERT INTO trades (symbol, price, size, trade_time, received_at)
+		VALUES ('BTC-USD', 50000, 1, '20250101-12:00:00.250', '2025-01-01 12:00:01');`)
+	legacy.Close()
+	if err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+
+	db, err := NewMarketDataDb(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+	defer db.Close()
+
+	trades, err := db.QueryTrades("BTC-USD", TimeRange{}, 0)
+	if err != nil || len(trades) != 1 {
+		t.Fatalf("Expected migrated trade, got %d (%v)", len(trades), err)
+	}
+	if want := time.Date(2025, 1, 1, 12, 0, 0, 250000000, time.UTC); !trades[0].TradeTime.Equal(want) {
+		t.Fatalf("Expected backfilled trade time %v, got %v", want, trades[0].TradeTime)
+	}
+	if want := time.Date(2025, 1, 1, 12, 0, 1, 0, time.UTC); !trades[0].ReceivedAt.Equal(want) {
+		t.Fatalf("Expected backfilled receive time %v, got %v", want, trades[0].ReceivedAt)
+	}
+
+	var index string
+	if err := db.db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'index' AND name = 'idx_trades_received_ns'").Scan(&index); err != nil {
+		t.Fatalf("Expected the receive time index on the migrated trades table: %v", err)
+	}
+}
+
+func TestTimeOnlyEntryTimeUsesReceiveDate(t *testing.T) {
+	ref := time.Date(2025, 1, 2, 0, 0, 30, 0, time.UTC)
+
+	// Just before midnight belongs to the previous day
+	if ns := eventTimeNs("23:59:59.900", ref); ns != time.Date(2025, 1, 1, 23, 59, 59, 900000000, time.UTC).UnixNano() {
+		t.Fatalf("Unexpected time for late entry: %v", time.Unix(0, ns).UTC())
+	}
+	if ns := eventTimeNs("00:00:10", ref); ns != time.Date(2025, 1, 2, 0, 0, 10, 0, time.UTC).UnixNano() {
+		t.Fatalf("Unexpected time for same-day entry: %v", time.Unix(0, ns).UTC())
+	}
+}
+
+func TestStoreOrderBookRecordNumOrders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	numOrders := 4
+	err := db.StoreOrderBookRecord(OrderBookRecord{Symbol: "BTC-USD", Side: "bid", Price: "49999.99", Size: "2.0",
+		Position: 1, SeqNum: 1, MdReqId: "req", IsSnapshot: true, NumOrders: &numOrders})
+	if err != nil {
+		t.Fatalf("Failed to store order book record: %v", err)
+	}
+	if err := db.StoreOrderBookEntry("BTC-USD", "offer", "50000.01", "1.0", 1, 2, "req", true); err != nil {
+		t.Fatalf("Failed to store order book entry: %v", err)
+	}
+
+	var bidOrders sql.NullInt64
+	if err := db.db.QueryRow("SELECT num_orders FROM order_book WHERE side = 'bid'").Scan(&bidOrders); err != nil {
+		t.Fatalf("Failed to query bid: %v", err)
+	}
+	if !bidOrders.Valid || bidOrders.Int64 != 4 {
+		t.Fatalf("Expected 4 orders on bid, got %v", bidOrders)
+	}
+
+	var offerOrders sql.NullInt64
+	if err := db.db.QueryRow("SELECT num_orders FROM order_book WHERE side = 'offer'").Scan(&offerOrders); err != nil {
+		t.Fatalf("Failed to query offer: %v", err)
+	}
+	if offerOrders.Valid {
+		t.Fatalf("Expected NULL num_orders when not sent, got %d", offerOrders.Int64)
+	}
+}
+
+func TestQueryOrderBookEntryHistory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	updates := []OrderBookRecord{
+		{Symbol: "BTC-USD", Side: "bid", Price: "100", Size: "1", Position: 1, SeqNum: 1, MdEntryId: "b1", UpdateAction: "0"},
+		{Symbol: "BTC-USD", Side: "bid", Price: "101", Size: "1", Position: 1, SeqNum: 2, MdEntryId: "b2", UpdateAction: "0"},
+		{Symbol: "BTC-USD", Side: "bid", Price: "100", Size: "3", Position: 2, SeqNum: 3, MdEntryId: "b1", UpdateAction: "1"},
+		{Symbol: "BTC-USD", Side: "bid", Price: "100", Size: "0", Position: 2, SeqNum: 4, MdEntryId: "b1", UpdateAction: "2"},
+	}
+	for _, rec := range updates {
+		if err := db.StoreOrderBookRecord(rec); err != nil {
+			t.Fatalf("Failed to store order book record: %v", err)
+		}
+	}
+
+	history, err := db.QueryOrderBookEntry("BTC-USD", "b1")
+	if err != nil {
+		t.Fatalf("QueryOrderBookEntry failed: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 versions of b1, got %d", len(history))
+	}
+	expected := []struct {
+		action string
+		seqNum int
+	}{{"0", 1}, {"1", 3}, {"2", 4}}
+	for i, want := range expected {
+		if history[i].UpdateAction != want.action || history[i].SeqNum != want.seqNum {
+			t.Fatalf("Unexpected history entry %d: %+v", i, history[i])
+		}
+	}
+
+	if err := db.StoreTradeRecord(TradeRecord{Symbol: "BTC-USD", Price: "100", Size: "1", MdEntryId: "t1", TradeCondition: "R"}); err != nil {
+		t.Fatalf("Failed to store trade record: %v", err)
+	}
+	var entryId string
+	if err := db.db.QueryRow("SELECT md_entry_id FROM trades").Scan(&entryId); err != nil || entryId != "t1" {
+		t.Fatalf("Expected trade entry id t1, got %q (%v)", entryId, err)
+	}
+	trades, err := db.QueryTrades("BTC-USD", TimeRange{}, 0)
+	if err != nil || len(trades) != 1 || trades[0].TradeCondition != "R" {
+		t.Fatalf("Expected trade with condition R, got %+v (%v)", trades, err)
+	}
+}
+
+func TestStoreTradeSecurityId(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	rec := TradeRecord{Symbol: "BTC-USD", Price: "50000", Size: "1", TradeTime: "20250101-12:00:00.000",
+		MdReqId: "req", SecurityId: "BTC-USD", SecurityIdSource: "8"}
+	if err := db.StoreTradeRecord(rec); err != nil {
+		t.Fatalf("Failed to store trade: %v", err)
+	}
+	if err := db.StoreTrade("BTC-USD", "50001", "1", "Buy", "20250101-12:00:01.000", 2, "req", false); err != nil {
+		t.Fatalf("Failed to store trade: %v", err)
+	}
+
+	trades, err := db.QueryTrades("BTC-USD", TimeRange{}, 0)
+	if err != nil {
+		t.Fatalf("QueryTrades failed: %v", err)
+	}
+	if len(trades) != 2 {
+		t.Fatalf("Expected 2 trades, got %d", len(trades))
+	}
+	if trades[0].SecurityId != "BTC-USD" || trades[0].SecurityIdSource != "8" {
+		t.Fatalf("Expected SecurityID to round trip, got %q/%q", trades[0].SecurityId, trades[0].SecurityIdSource)
+	}
+	if trades[1].SecurityId != "" {
+		t.Fatalf("Expected empty SecurityID when not sent, got %q", trades[1].SecurityId)
+	}
+}
+
+func TestLatestTradeAndSnapshotTop(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if trade, err := db.LatestTrade("BTC-USD"); err != nil || trade != nil {
+		t.Fatalf("Expected no trade in an empty db, got %v (%v)", trade, err)
+	}
+
+	db.StoreTrade("BTC-USD", "101", "1", "Buy", "20250101-12:00:02", 1, "req", false)
+	db.StoreTrade("BTC-USD", "100", "2", "Sell", "20250101-12:00:01", 2, "req", false)
+	trade, err := db.LatestTrade("BTC-USD")
+	if err != nil || trade == nil || trade.Price != "101.0" {
+		t.Fatalf("Expected latest trade by exchange time, got %+v (%v)", trade, err)
+	}
+
+	// An older snapshot, then the latest one in a single message (seq 6)
+	db.StoreOrderBookEntry("BTC-USD", "bid", "90", "1", 1, 5, "req", true)
+	db.StoreOrderBookEntry("BTC-USD", "bid", "99", "1", 2, 6, "req", true)
+	db.StoreOrderBookEntry("BTC-USD", "bid", "99.5", "3", 1, 6, "req", true)
+	db.StoreOrderBookEntry("BTC-USD", "offer", "100.5", "2", 1, 6, "req", true)
+	db.StoreOrderBookEntry("BTC-USD", "offer", "102", "1", 2, 6, "req", true)
+	db.StoreOrderBookEntry("BTC-USD", "bid", "100", "1", 1, 7, "req", false)
+
+	bid, offer, err := db.LatestSnapshotTop("BTC-USD")
+	if err != nil {
+		t.Fatalf("LatestSnapshotTop failed: %v", err)
+	}
+	if bid == nil || bid.Price != "99.5" || offer == nil || offer.Price != "100.5" {
+		t.Fatalf("Expected 99.5 / 100.5 from the latest snapshot, got %+v / %+v", bid, offer)
+	}
+}
+
+func TestLatestOhlcv(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db.StoreOHLCV("BTC-USD", "open", "100", "20250101-12:00:00", 1, "req")
+	db.StoreOHLCV("BTC-USD", "high", "105", "20250101-12:00:00", 1, "req")
+	db.StoreOHLCV("BTC-USD", "high", "110", "20250101-13:00:00", 2, "req")
+	db.StoreOHLCV("ETH-USD", "open", "2000", "20250101-12:00:00", 1, "req")
+
+	rows, err := db.LatestOhlcv("BTC-USD", time.Date(2025, 1, 1, 12, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("LatestOhlcv failed: %v", err)
+	}
+	if len(rows) != 2 || rows[0].DataType != "high" || rows[0].Value != "105.0" || rows[1].DataType != "open" {
+		t.Fatalf("Expected high 105 and open at 12:30, got %+v", rows)
+	}
+
+	rows, err = db.LatestOhlcv("BTC-USD", time.Time{})
+	if err != nil || len(rows) != 2 || rows[0].Value != "110.0" {
+		t.Fatalf("Expected the latest high of 110, got %+v (%v)", rows, err)
+	}
+}
+
+func TestQueryMessages(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db.StoreOrderBookEntry("BTC-USD", "bid", "100", "1", 1, 5, "req", true)
+	db.StoreOrderBookEntry("BTC-USD", "offer", "101", "1", 1, 5, "req", true)
+	db.StoreTrade("BTC-USD", "100.5", "1", "Buy", "20250101-12:00:00", 6, "req", false)
+	db.StoreOHLCV("ETH-USD", "open", "2000", "20250101-12:00:00", 7, "req2")
+
+	messages, err := db.QueryMessages(TimeRange{})
+	if err != nil {
+		t.Fatalf("QueryMessages failed: %v", err)
+	}
+	if len(messages) != 3 || messages[0].SeqNum != 5 || messages[0].Entries != 2 || messages[2].Symbol != "ETH-USD" {
+		t.Fatalf("Expected three messages, the first with two entries, got %+v", messages)
+	}
+}
+
+func TestConcurrentWritesAndReads(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				if err := db.StoreTrade("BTC-USD", "100", "1", "Buy", "20250101-12:00:00", i*10+j, "req", false); err != nil {
+					errs <- err
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := db.QueryTrades("BTC-USD", TimeRange{}, 0); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Concurrent access failed: %v", err)
+	}
+
+	if trades, _ := db.QueryTrades("BTC-USD", TimeRange{}, 0); len(trades) != 100 {
+		t.Fatalf("Expected 100 trades, got %d", len(trades))
+	}
+}
+
+func TestDatabaseOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tuned.db")
+	db, err := NewMarketDataDbWithOptions(path, Options{BusyTimeout: time.Second, CacheSize: -2000, Synchronous: "full", MmapSize: 1 << 20})
+	if err != nil {
+		t.Fatalf("Failed to open tuned database: %v", err)
+	}
+	defer db.Close()
+
+	var mmap, synchronous int64
+	db.db.QueryRow("PRAGMA mmap_size").Scan(&mmap)
+	db.db.QueryRow("PRAGMA synchronous").Scan(&synchronous)
+	if mmap != 1<<20 || synchronous != 2 {
+		t.Fatalf("Expected mmap_size 1048576 and synchronous FULL (2), got %d and %d", mmap, synchronous)
+	}
+
+	if _, err := NewMarketDataDbWithOptions(path, Options{Synchronous: "fast"}); err == nil {
+		t.Fatalf("Expected an invalid synchronous level to be rejected")
+	}
+
+	db.Close()
+	if err := db.StoreTrade("BTC-USD", "1", "1", "Buy", "", 1, "req", false); err == nil {
+		t.Fatalf("Expected writes after Close to fail")
+	}
+}
+
+func TestLatestBookSnapshot(t *testing.T) {
+	db, err := NewMarketDataDb(filepath.Join(t.TempDir(), "snapshots.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	first := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	orders := 3
+	if err := db.StoreBookSnapshot("BTC-USD", []BookStateLevel{{Price: "100", Size: "1", NumOrders: &orders, EntryId: "b1"}},
+		[]BookStateLevel{{Price: "101", Size: "2"}}, first); err != nil {
+		t.Fatalf("Failed to store snapshot: %v", err)
+	}
+	if err := db.StoreBookSnapshot("BTC-USD", []BookStateLevel{{Price: "100", Size: "5"}, {Price: "99", Size: "1"}},
+		nil, first.Add(time.Minute)); err != nil {
+		t.Fatalf("Failed to store snapshot: %v", err)
+	}
+
+	rows, err := db.LatestBookSnapshot("BTC-USD", first.Add(30*time.Second))
+	if err != nil {
+		t.Fatalf("Failed to query snapshot: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Side != "bid" || rows[0].MdEntryId != "b1" || rows[0].NumOrders == nil || *rows[0].NumOrders != 3 ||
+		!rows[0].ReceivedAt.Equal(first) || rows[1].Side != "offer" || rows[1].Position != 1 {
+		t.Fatalf("Unexpected first snapshot %+v", rows)
+	}
+
+	rows, _ = db.LatestBookSnapshot("BTC-USD", time.Time{})
+	if len(rows) != 2 || rows[1].Position != 2 || !rows[1].ReceivedAt.Equal(first.Add(time.Minute)) {
+		t.Fatalf("Unexpected latest snapshot %+v", rows)
+	}
+
+	if rows, _ := db.LatestBookSnapshot("BTC-USD", first.Add(-time.Second)); len(rows) != 0 {
+		t.Fatalf("Expected nothing before the first snapshot, got %+v", rows)
+	}
+}
+
+func TestFixSessionEvents(t *testing.T) {
+	db, err := NewMarketDataDb(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, ev := range []string{FixEventLogon, FixEventLogout, FixEventDisconnect} {
+		detail := ""
+		if ev == FixEventLogout {
+			detail = "received: session closed"
+		}
+		if err := db.StoreFixSessionEvent("FIXT.1.1:A->COIN", ev, detail, start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("Failed to store event: %v", err)
+		}
+	}
+
+	events, err := db.QueryFixSessionEvents(start.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Event != FixEventLogout || events[1].Event != FixEventDisconnect {
+		t.Fatalf("Expected logout then disconnect, got %+v", events)
+	}
+	if events[0].Detail != "received: session closed" || events[1].Detail != "" || !events[0].At.Equal(start.Add(time.Minute)) {
+		t.Fatalf("Unexpected event fields %+v", events)
+	}
+}
+
+func TestMdTemplates(t *testing.T) {
+	db, err := NewMarketDataDb(filepath.Join(t.TempDir(), "templates.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, tmpl := range [][2]string{{"l10book", "--depth 10 --book"}, {"tape", "--trades"}, {"l10book", "--depth 10 --book --subscribe"}} {
+		if err := db.StoreMdTemplate(tmpl[0], tmpl[1], now); err != nil {
+			t.Fatalf("Failed to store template: %v", err)
+		}
+	}
+	if err := db.DeleteMdTemplate("tape"); err != nil {
+		t.Fatalf("Failed to delete template: %v", err)
+	}
+
+	templates, err := db.QueryMdTemplates()
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(templates) != 1 || templates[0].Flags != "--depth 10 --book --subscribe" || !templates[0].SavedAt.Equal(now) {
+		t.Fatalf("Expected the replaced l10book template only, got %+v", templates)
+	}
+}
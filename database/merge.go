@@ -0,0 +1,242 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MergeStats counts what Merge copied from one source database
+type MergeStats struct {
+	Trades          int64
+	OrderBook       int64
+	Ohlcv           int64
+	Sessions        int
+	RenamedSessions int // Sessions whose ID was already used by a different request
+}
+
+// Tables copied by Merge; rows are identified by message, i.e. (symbol, seq_num, md_req_id)
+var mergeTables = []string{"trades", "order_book", "ohlcv"}
+
+// Merge copies the market data stored in the database at srcPath into mdb. Rows of a message that
+// is already present are skipped, so overlapping captures (or the same file twice) merge safely.
+// Session rows are copied too, renamed when their ID is taken by a different request.
+// The source is only read; columns it lacks are left empty and backfilled where possible.
+func (mdb *MarketDataDb) Merge(srcPath string) (MergeStats, error) {
+	var stats MergeStats
+	err := mdb.write(func() (err error) {
+		stats, err = mdb.merge(srcPath)
+		return err
+	})
+	return stats, err
+}
+
+func (mdb *MarketDataDb) merge(srcPath string) (MergeStats, error) {
+	var stats MergeStats
+	if _, err := os.Stat(srcPath); err != nil {
+		return stats, fmt.Errorf("cannot merge %s: %v", srcPath, err)
+	}
+	if same, err := mdb.isFile(srcPath); err != nil || same {
+		if err == nil {
+			err = fmt.Errorf("cannot merge %s into itself", srcPath)
+		}
+		return stats, err
+	}
+
+	// ATTACH applies to one connection, so everything runs on the same one
+	ctx := context.Background()
+	conn, err := mdb.db.Conn(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("failed to open connection: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS src", srcPath); err != nil {
+		return stats, fmt.Errorf("failed to attach %s: %v", srcPath, err)
+	}
+	defer conn.ExecContext(ctx, "DETACH DATABASE src")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return stats, fmt.Errorf("failed to begin merge: %v", err)
+	}
+	defer tx.Rollback()
+
+	counts := map[string]*int64{"trades": &stats.Trades, "order_book": &stats.OrderBook, "ohlcv": &stats.Ohlcv}
+	for _, table := range mergeTables {
+		if *counts[table], err = mergeTable(ctx, tx, table); err != nil {
+			return stats, err
+		}
+	}
+	if stats.Sessions, stats.RenamedSessions, err = mergeSessions(ctx, tx); err != nil {
+		return stats, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return stats, fmt.Errorf("failed to commit merge: %v", err)
+	}
+	return stats, mdb.backfillEpochNs()
+}
+
+// isFile reports whether path is the file backing mdb
+func (mdb *MarketDataDb) isFile(path string) (bool, error) {
+	var file string
+	if err := mdb.db.QueryRow("SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&file); err != nil {
+		return false, fmt.Errorf("failed to read database path: %v", err)
+	}
+	a, errA := os.Stat(file)
+	b, errB := os.Stat(path)
+	return errA == nil && errB == nil && os.SameFile(a, b), nil
+}
+
+func mergeTable(ctx context.Context, tx *sql.Tx, table string) (int64, error) {
+	dst, err := schemaColumns(ctx, tx, "main", table)
+	if err != nil {
+		return 0, err
+	}
+	src, err := schemaColumns(ctx, tx, "src", table)
+	if err != nil {
+		return 0, err
+	}
+	if len(src) == 0 {
+		return 0, nil // Table not in the source
+	}
+
+	var columns, values []string
+	for _, c := range dst {
+		if c != "id" && contains(src, c) {
+			columns = append(columns, c)
+			values = append(values, "s."+c)
+		}
+	}
+
+	// SQLite materializes the SELECT before inserting because it reads the target table,
+	// so all rows of a message in the source are copied even though they share the key
+	query := fmt.Sprintf(`INSERT INTO main.%[1]s (%[2]s) SELECT %[3]s FROM src.%[1]s s
+		WHERE NOT EXISTS (SELECT 1 FROM main.%[1]s m WHERE m.symbol = s.symbol AND m.seq_num IS s.seq_num AND m.md_req_id IS s.md_req_id)
+		ORDER BY s.id`, table, strings.Join(columns, ", "), strings.Join(values, ", "))
+	res, err := tx.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to merge %s: %v", table, err)
+	}
+	return res.RowsAffected()
+}
+
+func schemaColumns(ctx context.Context, tx *sql.Tx, schema, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT name FROM pragma_table_info(?, ?) ORDER BY cid", table, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s.%s columns: %v", schema, table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
+
+// mergeSessions copies session rows. A session already present for the same request is skipped;
+// one whose ID belongs to a different request gets a numeric suffix.
+func mergeSessions(ctx context.Context, tx *sql.Tx) (copied, renamed int, err error) {
+	columns, err := schemaColumns(ctx, tx, "src", "sessions")
+	if err != nil || len(columns) == 0 {
+		return 0, 0, err
+	}
+	// Sources written before sessions were closed out have no end columns
+	end := "NULL, NULL, NULL"
+	if contains(columns, "end_reason") {
+		end = "CAST(ended_at AS TEXT), total_updates, end_reason"
+	}
+	label := "NULL"
+	if contains(columns, "label") {
+		label = "label"
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT session_id, symbol, request_type, data_types, depth, md_req_id, CAST(created_at AS TEXT), is_active, `+end+`, `+label+`
+		FROM src.sessions ORDER BY created_at, session_id`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read sessions: %v", err)
+	}
+	type sessionRow struct {
+		id, symbol, requestType, dataTypes, mdReqId string
+		depth, createdAt, isActive                  interface{}
+		endedAt, totalUpdates, endReason, label     interface{}
+	}
+	var sessions []sessionRow
+	for rows.Next() {
+		var s sessionRow
+		if err := rows.Scan(&s.id, &s.symbol, &s.requestType, &s.dataTypes, &s.depth, &s.mdReqId, &s.createdAt, &s.isActive, &s.endedAt, &s.totalUpdates, &s.endReason, &s.label); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to read sessions: %v", err)
+		}
+		sessions = append(sessions, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	for _, s := range sessions {
+		id := s.id
+		for n := 2; ; n++ {
+			var mdReqId string
+			err := tx.QueryRowContext(ctx, "SELECT md_req_id FROM main.sessions WHERE session_id = ?", id).Scan(&mdReqId)
+			if err == sql.ErrNoRows {
+				break
+			}
+			if err != nil {
+				return copied, renamed, fmt.Errorf("failed to check session %s: %v", id, err)
+			}
+			if mdReqId == s.mdReqId {
+				id = ""
+				break
+			}
+			id = fmt.Sprintf("%s_%d", s.id, n)
+		}
+		if id == "" {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `INSERT INTO main.sessions (session_id, symbol, request_type, data_types, depth, md_req_id, created_at, is_active,
+			ended_at, total_updates, end_reason, label) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, s.symbol, s.requestType, s.dataTypes, s.depth, s.mdReqId, s.createdAt, s.isActive, s.endedAt, s.totalUpdates, s.endReason, s.label); err != nil {
+			return copied, renamed, fmt.Errorf("failed to copy session %s: %v", s.id, err)
+		}
+		copied++
+		if id != s.id {
+			renamed++
+		}
+	}
+	return copied, renamed, nil
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package database
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func createMergeSource(t *testing.T, path string, build func(db *MarketDataDb)) {
+	db, err := NewMarketDataDb(path)
+	if err != nil {
+		t.Fatalf("Failed to create %s: %v", path, err)
+	}
+	build(db)
+	db.Close()
+}
+
+func TestMergeSkipsDuplicateMessages(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "a.db"), filepath.Join(dir, "b.db")
+
+	createMergeSource(t, first, func(db *MarketDataDb) {
+		db.CreateSession("BTC-USD_subscribe_1", "BTC-USD", "subscribe", "trades", "req1", nil)
+		db.StoreTrade("BTC-USD", "100", "1", "Buy", "20250101-12:00:00", 1, "req1", true)
+		db.StoreTrade("BTC-USD", "101", "1", "Buy", "20250101-12:00:01", 1, "req1", true)
+		db.StoreOrderBookEntry("BTC-USD", "bid", "99", "2", 1, 1, "req1", true)
+	})
+	// Overlaps the first capture (message 1, same session) and adds message 2 and a clashing session ID
+	createMergeSource(t, second, func(db *MarketDataDb) {
+		db.CreateSession("BTC-USD_subscribe_1", "BTC-USD", "subscribe", "trades", "req1", nil)
+		db.CreateSession("ETH-USD_subscribe_1", "ETH-USD", "subscribe", "trades", "req2", nil)
+		db.StoreTrade("BTC-USD", "100", "1", "Buy", "20250101-12:00:00", 1, "req1", true)
+		db.StoreTrade("BTC-USD", "101", "1", "Buy", "20250101-12:00:01", 1, "req1", true)
+		db.StoreTrade("BTC-USD", "102", "1", "Sell", "20250101-12:00:02", 2, "req1", false)
+	})
+	createMergeSource(t, filepath.Join(dir, "c.db"), func(db *MarketDataDb) {
+		db.CreateSession("ETH-USD_subscribe_1", "ETH-USD", "subscribe", "trades", "req3", nil)
+	})
+
+	dst, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	stats, err := dst.Merge(first)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if stats.Trades != 2 || stats.OrderBook != 1 || stats.Sessions != 1 {
+		t.Fatalf("Expected 2 trades, 1 book entry, 1 session from the first file, got %+v", stats)
+	}
+
+	stats, err = dst.Merge(second)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if stats.Trades != 1 || stats.Sessions != 1 || stats.RenamedSessions != 0 {
+		t.Fatalf("Expected only the new message and session from the second file, got %+v", stats)
+	}
+
+	stats, err = dst.Merge(filepath.Join(dir, "c.db"))
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if stats.Sessions != 1 || stats.RenamedSessions != 1 {
+		t.Fatalf("Expected the clashing session to be renamed, got %+v", stats)
+	}
+	var mdReqId string
+	if err := dst.db.QueryRow("SELECT md_req_id FROM sessions WHERE session_id = 'ETH-USD_subscribe_1_2'").Scan(&mdReqId); err != nil || mdReqId != "req3" {
+		t.Fatalf("Expected renamed session for req3, got %q (%v)", mdReqId, err)
+	}
+
+	trades, err := dst.QueryTrades("BTC-USD", TimeRange{}, 0)
+	if err != nil || len(trades) != 3 {
+		t.Fatalf("Expected 3 merged trades, got %d (%v)", len(trades), err)
+	}
+	if trades[0].ReceivedAt.IsZero() || trades[2].Price != "102.0" {
+		t.Fatalf("Expected merged rows to keep their values, got %+v", trades[2])
+	}
+
+	if stats, err := dst.Merge(second); err != nil || stats.Trades != 0 || stats.Sessions != 0 {
+		t.Fatalf("Expected merging the same file again to copy nothing, got %+v (%v)", stats, err)
+	}
+}
+
+func TestMergeRejectsSelfAndMissingFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "self.db")
+	db, err := NewMarketDataDb(path)
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Merge(path); err == nil {
+		t.Fatalf("Expected merging a database into itself to fail")
+	}
+	if _, err := db.Merge(filepath.Join(t.TempDir(), "missing.db")); err == nil {
+		t.Fatalf("Expected a missing source to fail")
+	}
+}
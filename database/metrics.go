@@ -0,0 +1,117 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package database
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// rateWindow is how many seconds RowsPerSec is averaged over
+const rateWindow = 10
+
+// WriterStats describes the work done by the database writer since the database was opened
+type WriterStats struct {
+	Writes      int64         `json:"writes"`      // Completed writes: transactions and single statements
+	Failed      int64         `json:"failed"`      // Writes that returned an error
+	Rows        int64         `json:"rows"`        // Rows inserted, updated or deleted; merges and archiving count as writes only
+	RowsPerSec  float64       `json:"rowsPerSec"`  // Over the last 10 seconds
+	AvgBatch    float64       `json:"avgBatch"`    // Rows per write
+	MaxBatch    int64         `json:"maxBatch"`    // Most rows in one write
+	QueueDepth  int64         `json:"queueDepth"`  // Writes waiting for the writer right now
+	AvgLatency  time.Duration `json:"avgLatency"`  // Queue wait plus execution
+	MaxLatency  time.Duration `json:"maxLatency"`  // Slowest write
+	LastLatency time.Duration `json:"lastLatency"` // Most recent write
+}
+
+type rateBucket struct {
+	second int64
+	rows   int64
+}
+
+type writerMetrics struct {
+	queued atomic.Int64
+
+	mu           sync.Mutex
+	writes       int64
+	failed       int64
+	rows         int64
+	maxBatch     int64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+	lastLatency  time.Duration
+	buckets      [rateWindow]rateBucket
+}
+
+// record adds one finished write that changed rows and took latency from being queued
+func (m *writerMetrics) record(rows int64, latency time.Duration, err error, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.writes++
+	if err != nil {
+		m.failed++
+	}
+	m.rows += rows
+	if rows > m.maxBatch {
+		m.maxBatch = rows
+	}
+	m.totalLatency += latency
+	m.lastLatency = latency
+	if latency > m.maxLatency {
+		m.maxLatency = latency
+	}
+
+	second := now.Unix()
+	b := &m.buckets[second%rateWindow]
+	if b.second != second {
+		*b = rateBucket{second: second}
+	}
+	b.rows += rows
+}
+
+func (m *writerMetrics) snapshot(now time.Time) WriterStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := WriterStats{
+		Writes:      m.writes,
+		Failed:      m.failed,
+		Rows:        m.rows,
+		MaxBatch:    m.maxBatch,
+		QueueDepth:  m.queued.Load(),
+		MaxLatency:  m.maxLatency,
+		LastLatency: m.lastLatency,
+	}
+	if m.writes > 0 {
+		stats.AvgBatch = float64(m.rows) / float64(m.writes)
+		stats.AvgLatency = m.totalLatency / time.Duration(m.writes)
+	}
+	var recent int64
+	for _, b := range m.buckets {
+		if b.second > now.Unix()-rateWindow {
+			recent += b.rows
+		}
+	}
+	stats.RowsPerSec = float64(recent) / rateWindow
+	return stats
+}
+
+// WriterStats returns throughput, batch size, queue depth and latency of the database writer
+func (mdb *MarketDataDb) WriterStats() WriterStats {
+	return mdb.metrics.snapshot(time.Now())
+}
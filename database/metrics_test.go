@@ -0,0 +1,66 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWriterStatsCountRowsPerBatch(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	err := db.WriteBatch(func(tx *sql.Tx) error {
+		for i := 0; i < 3; i++ {
+			if err := db.StoreTradeBatch(tx, "BTC-USD", "100", "1", "Buy", "20250301-10:00:00.000", i, "req-1", false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if err := db.CreateSession("s1", "BTC-USD", "subscribe", "trades", "req-1", nil); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	db.WriteBatch(func(tx *sql.Tx) error { return errors.New("boom") })
+
+	s := db.WriterStats()
+	if s.Writes != 3 || s.Failed != 1 || s.Rows != 4 || s.MaxBatch != 3 || s.QueueDepth != 0 {
+		t.Fatalf("Expected 3 writes (1 failed), 4 rows, max batch 3, got %+v", s)
+	}
+	if s.RowsPerSec != 0.4 || s.MaxLatency <= 0 {
+		t.Fatalf("Expected 4 rows over the 10s window and a latency, got %+v", s)
+	}
+}
+
+func TestWriterMetricsRateWindow(t *testing.T) {
+	var m writerMetrics
+	start := time.Unix(1000, 0)
+	m.record(50, time.Millisecond, nil, start)
+	m.record(50, time.Millisecond, nil, start.Add(5*time.Second))
+
+	if s := m.snapshot(start.Add(9 * time.Second)); s.RowsPerSec != 10 {
+		t.Fatalf("Expected 100 rows over 10s, got %v", s.RowsPerSec)
+	}
+	if s := m.snapshot(start.Add(12 * time.Second)); s.RowsPerSec != 5 {
+		t.Fatalf("Expected the first second to drop out of the window, got %v", s.RowsPerSec)
+	}
+}
@@ -0,0 +1,694 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package database
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TradeRow struct {
+	Id             int64
+	Symbol         string
+	Price          string
+	Size           string
+	AggressorSide  string
+	TradeTime      time.Time
+	SeqNum         int
+	MdReqId        string
+	IsSnapshot     bool
+	TradeCondition string
+	ReceivedAt     time.Time
+
+	SecurityId       string
+	SecurityIdSource string
+
+	AggressorInferred bool // AggressorSide was inferred rather than sent
+}
+
+type OrderBookRow struct {
+	Id             int64
+	Symbol         string
+	Side           string
+	Price          string
+	Size           string
+	Position       int
+	NumOrders      *int
+	MdEntryId      string
+	UpdateAction   string
+	QuoteCondition string
+	SeqNum         int
+	MdReqId        string
+	IsSnapshot     bool
+	ReceivedAt     time.Time
+}
+
+type OhlcvRow struct {
+	Id         int64
+	Symbol     string
+	DataType   string
+	Value      string
+	EntryTime  time.Time
+	SeqNum     int
+	MdReqId    string
+	ReceivedAt time.Time
+}
+
+// TimeRange bounds a query on exchange time; a zero From or To leaves that side open
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func (r TimeRange) bounds() (int64, int64) {
+	from, to := int64(0), int64(1<<63-1)
+	if !r.From.IsZero() {
+		from = r.From.UnixNano()
+	}
+	if !r.To.IsZero() {
+		to = r.To.UnixNano()
+	}
+	return from, to
+}
+
+const (
+	selectTradesQuery = `SELECT id, symbol, CAST(price AS TEXT), CAST(size AS TEXT), COALESCE(aggressor_side, ''),
+			  trade_time_ns, COALESCE(seq_num, 0), COALESCE(md_req_id, ''), COALESCE(is_snapshot, 0), COALESCE(trade_condition, ''),
+			  COALESCE(received_at_ns, 0), COALESCE(security_id, ''), COALESCE(security_id_source, ''),
+			  COALESCE(aggressor_inferred, 0)
+			  FROM trades WHERE symbol = ? AND trade_time_ns >= ? AND trade_time_ns < ?
+			  ORDER BY trade_time_ns, id LIMIT ?`
+
+	selectTradesPageQuery = `SELECT id, symbol, CAST(price AS TEXT), CAST(size AS TEXT), COALESCE(aggressor_side, ''),
+			  trade_time_ns, COALESCE(seq_num, 0), COALESCE(md_req_id, ''), COALESCE(is_snapshot, 0), COALESCE(trade_condition, ''),
+			  COALESCE(received_at_ns, 0), COALESCE(security_id, ''), COALESCE(security_id_source, ''),
+			  COALESCE(aggressor_inferred, 0)
+			  FROM trades WHERE symbol = ?1 AND trade_time_ns >= ?2 AND trade_time_ns < ?3
+			  AND (trade_time_ns > ?4 OR (trade_time_ns = ?4 AND id > ?5))
+			  ORDER BY trade_time_ns, id LIMIT ?6`
+
+	selectLatestTradeQuery = `SELECT id, symbol, CAST(price AS TEXT), CAST(size AS TEXT), COALESCE(aggressor_side, ''),
+			  trade_time_ns, COALESCE(seq_num, 0), COALESCE(md_req_id, ''), COALESCE(is_snapshot, 0), COALESCE(trade_condition, ''),
+			  COALESCE(received_at_ns, 0), COALESCE(security_id, ''), COALESCE(security_id_source, ''),
+			  COALESCE(aggressor_inferred, 0)
+			  FROM trades WHERE symbol = ?
+			  ORDER BY trade_time_ns DESC, id DESC LIMIT 1`
+
+	// Entries of the most recent snapshot message stored at or before a time
+	selectLatestSnapshotQuery = `WITH latest AS (
+			  SELECT seq_num, md_req_id FROM order_book WHERE symbol = ? AND is_snapshot = 1 AND received_at_ns <= ?
+			  ORDER BY received_at_ns DESC, id DESC LIMIT 1)
+			  SELECT id, symbol, side, CAST(price AS TEXT), CAST(size AS TEXT), COALESCE(position, 0), num_orders,
+			  COALESCE(md_entry_id, ''), COALESCE(update_action, ''), COALESCE(quote_condition, ''), COALESCE(seq_num, 0), COALESCE(md_req_id, ''), COALESCE(is_snapshot, 0),
+			  COALESCE(received_at_ns, 0)
+			  FROM order_book WHERE symbol = ? AND is_snapshot = 1
+			  AND seq_num = (SELECT seq_num FROM latest) AND md_req_id = (SELECT md_req_id FROM latest)
+			  ORDER BY id`
+
+	// Book snapshot levels come back as order book rows received at the time the copy was taken
+	selectLatestBookSnapshotQuery = `WITH latest AS (
+			  SELECT MAX(taken_at_ns) AS ns FROM book_snapshots WHERE symbol = ? AND taken_at_ns <= ?)
+			  SELECT 0, symbol, side, CAST(price AS TEXT), CAST(size AS TEXT), level, num_orders,
+			  COALESCE(md_entry_id, ''), '', '', 0, '', 1, taken_at_ns
+			  FROM book_snapshots WHERE symbol = ? AND taken_at_ns = (SELECT ns FROM latest)
+			  ORDER BY side, level`
+
+	selectOrderBookUpdatesQuery = `SELECT id, symbol, side, CAST(price AS TEXT), CAST(size AS TEXT), COALESCE(position, 0), num_orders,
+			  COALESCE(md_entry_id, ''), COALESCE(update_action, ''), COALESCE(quote_condition, ''), COALESCE(seq_num, 0), COALESCE(md_req_id, ''), COALESCE(is_snapshot, 0),
+			  COALESCE(received_at_ns, 0)
+			  FROM order_book WHERE symbol = ? AND is_snapshot = 0 AND received_at_ns >= ? AND received_at_ns < ?
+			  ORDER BY received_at_ns, id`
+
+	selectOrderBookRangeQuery = `SELECT id, symbol, side, CAST(price AS TEXT), CAST(size AS TEXT), COALESCE(position, 0), num_orders,
+			  COALESCE(md_entry_id, ''), COALESCE(update_action, ''), COALESCE(quote_condition, ''), COALESCE(seq_num, 0), COALESCE(md_req_id, ''), COALESCE(is_snapshot, 0),
+			  COALESCE(received_at_ns, 0)
+			  FROM order_book WHERE symbol = ? AND received_at_ns >= ? AND received_at_ns < ?
+			  ORDER BY received_at_ns, id`
+
+	selectOrderBookByEntryIdQuery = `SELECT id, symbol, side, CAST(price AS TEXT), CAST(size AS TEXT), COALESCE(position, 0), num_orders,
+			  md_entry_id, COALESCE(update_action, ''), COALESCE(quote_condition, ''), COALESCE(seq_num, 0), COALESCE(md_req_id, ''), COALESCE(is_snapshot, 0),
+			  COALESCE(received_at_ns, 0)
+			  FROM order_book WHERE symbol = ? AND md_entry_id = ?
+			  ORDER BY received_at_ns, id`
+
+	selectBookStateQuery = `SELECT symbol, side, level, CAST(price AS TEXT), CAST(size AS TEXT), num_orders,
+			  COALESCE(md_entry_id, ''), updated_at_ns
+			  FROM order_book_state WHERE symbol = ? ORDER BY side, level`
+
+	selectSubscriptionEventsQuery = `SELECT md_req_id, symbol, event, COALESCE(detail, ''), event_at_ns
+			  FROM subscription_events WHERE symbol = ? ORDER BY event_at_ns, id`
+
+	selectFixSessionEventsQuery = `SELECT session_id, event, COALESCE(detail, ''), event_at_ns
+			  FROM fix_session_events WHERE event_at_ns >= ? ORDER BY event_at_ns, id`
+
+	selectSessionsQuery = `SELECT session_id, symbol, request_type, data_types, md_req_id, COALESCE(label, ''),
+			  CAST(created_at AS TEXT), COALESCE(CAST(ended_at AS TEXT), ''), total_updates, COALESCE(end_reason, '')
+			  FROM sessions WHERE (?1 = '' OR label = ?1)
+			  ORDER BY created_at DESC, session_id LIMIT ?2`
+
+	selectMdTemplatesQuery = `SELECT name, flags, saved_at_ns FROM md_templates ORDER BY name`
+
+	selectSummaryQuery = `SELECT symbol, COALESCE(CAST(last_price AS TEXT), ''), COALESCE(CAST(last_size AS TEXT), ''),
+			  COALESCE(last_aggressor_side, ''), COALESCE(last_trade_time_ns, 0),
+			  COALESCE(CAST(bid_price AS TEXT), ''), COALESCE(CAST(bid_size AS TEXT), ''),
+			  COALESCE(CAST(offer_price AS TEXT), ''), COALESCE(CAST(offer_size AS TEXT), ''),
+			  trade_count, book_update_count, updated_at_ns
+			  FROM market_summary WHERE symbol = ?`
+
+	selectOhlcvQuery = `SELECT id, symbol, data_type, CAST(value AS TEXT), entry_time_ns,
+			  COALESCE(seq_num, 0), COALESCE(md_req_id, ''), COALESCE(received_at_ns, 0)
+			  FROM ohlcv WHERE symbol = ? AND entry_time_ns >= ? AND entry_time_ns < ?
+			  ORDER BY entry_time_ns, id LIMIT ?`
+
+	selectLatestOhlcvQuery = `SELECT id, symbol, data_type, CAST(value AS TEXT), entry_time_ns,
+			  COALESCE(seq_num, 0), COALESCE(md_req_id, ''), COALESCE(received_at_ns, 0)
+			  FROM (SELECT *, ROW_NUMBER() OVER (PARTITION BY data_type ORDER BY entry_time_ns DESC, id DESC) AS rn
+			  FROM ohlcv WHERE symbol = ? AND entry_time_ns < ?)
+			  WHERE rn = 1 ORDER BY data_type`
+)
+
+// QueryTrades returns trades for symbol in [From, To) ordered by exchange time.
+// limit <= 0 returns every matching row.
+func (mdb *MarketDataDb) QueryTrades(symbol string, r TimeRange, limit int) ([]TradeRow, error) {
+	from, to := r.bounds()
+	return mdb.queryTrades(selectTradesQuery, symbol, from, to, queryLimit(limit))
+}
+
+// ErrInvalidCursor is returned for a continuation token that was not returned by a page query
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TradePage is one page of trades; Next continues after it and is empty on the last page
+type TradePage struct {
+	Trades []TradeRow
+	Next   string
+}
+
+// QueryTradesPage returns up to limit trades for symbol in [From, To) in exchange time order,
+// starting after cursor (empty for the first page). The rows are found by seeking past the last
+// row returned, so each page costs the same however far into the capture it is, and rows stored
+// meanwhile with a later time are picked up by later pages.
+func (mdb *MarketDataDb) QueryTradesPage(symbol string, r TimeRange, cursor string, limit int) (TradePage, error) {
+	if limit <= 0 {
+		return TradePage{}, fmt.Errorf("page size must be positive, got %d", limit)
+	}
+	afterNs, afterId := int64(math.MinInt64), int64(0)
+	if cursor != "" {
+		var err error
+		if afterNs, afterId, err = parseTradeCursor(cursor); err != nil {
+			return TradePage{}, err
+		}
+	}
+	from, to := r.bounds()
+	// One row more than asked for says whether there is a next page
+	trades, err := mdb.queryTrades(selectTradesPageQuery, symbol, from, to, afterNs, afterId, limit+1)
+	if err != nil {
+		return TradePage{}, err
+	}
+	page := TradePage{Trades: trades}
+	if len(trades) > limit {
+		page.Trades = trades[:limit]
+		last := page.Trades[limit-1]
+		page.Next = tradeCursor(last.TradeTime.UnixNano(), last.Id)
+	}
+	return page, nil
+}
+
+// tradeCursor is an opaque token for the position after a trade
+func tradeCursor(tradeNs, id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("t%d.%d", tradeNs, id)))
+}
+
+func parseTradeCursor(cursor string) (int64, int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, ErrInvalidCursor
+	}
+	position, ok := strings.CutPrefix(string(data), "t")
+	ns, id, found := strings.Cut(position, ".")
+	if !ok || !found {
+		return 0, 0, ErrInvalidCursor
+	}
+	tradeNs, err := strconv.ParseInt(ns, 10, 64)
+	if err != nil {
+		return 0, 0, ErrInvalidCursor
+	}
+	rowId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, 0, ErrInvalidCursor
+	}
+	return tradeNs, rowId, nil
+}
+
+func (mdb *MarketDataDb) queryTrades(query string, args ...interface{}) ([]TradeRow, error) {
+	rows, err := mdb.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trades: %v", err)
+	}
+	defer rows.Close()
+
+	var trades []TradeRow
+	for rows.Next() {
+		t, err := scanTrade(rows)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, t)
+	}
+	return trades, rows.Err()
+}
+
+// LatestTrade returns the most recent stored trade for symbol by exchange time, or nil if there is none
+func (mdb *MarketDataDb) LatestTrade(symbol string) (*TradeRow, error) {
+	rows, err := mdb.db.Query(selectLatestTradeQuery, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest trade: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	t, err := scanTrade(rows)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func scanTrade(rows *sql.Rows) (TradeRow, error) {
+	var (
+		t                   TradeRow
+		tradeNs, receivedNs int64
+	)
+	if err := rows.Scan(&t.Id, &t.Symbol, &t.Price, &t.Size, &t.AggressorSide,
+		&tradeNs, &t.SeqNum, &t.MdReqId, &t.IsSnapshot, &t.TradeCondition, &receivedNs,
+		&t.SecurityId, &t.SecurityIdSource, &t.AggressorInferred); err != nil {
+		return t, fmt.Errorf("failed to scan trade: %v", err)
+	}
+	t.TradeTime = time.Unix(0, tradeNs).UTC()
+	t.ReceivedAt = time.Unix(0, receivedNs).UTC()
+	return t, nil
+}
+
+// LatestSnapshotTop returns the best bid and offer from the most recently stored book snapshot for
+// symbol. Either is nil when the snapshot had no entries on that side or nothing is stored.
+func (mdb *MarketDataDb) LatestSnapshotTop(symbol string) (bid, offer *OrderBookRow, err error) {
+	entries, err := mdb.LatestSnapshot(symbol, time.Time{})
+	if err != nil {
+		return nil, nil, err
+	}
+	var bestBid, bestOffer float64
+	for i := range entries {
+		e := &entries[i]
+		price, err := strconv.ParseFloat(e.Price, 64)
+		if err != nil {
+			continue
+		}
+		switch {
+		case e.Side == "bid" && (bid == nil || price > bestBid):
+			bid, bestBid = e, price
+		case e.Side == "offer" && (offer == nil || price < bestOffer):
+			offer, bestOffer = e, price
+		}
+	}
+	return bid, offer, nil
+}
+
+// LatestSnapshot returns the entries of the last book snapshot for symbol stored at or before at
+// (any time when at is zero), or none if there is no such snapshot
+func (mdb *MarketDataDb) LatestSnapshot(symbol string, at time.Time) ([]OrderBookRow, error) {
+	_, until := TimeRange{To: at}.bounds()
+	return mdb.queryOrderBook(selectLatestSnapshotQuery, symbol, until, symbol)
+}
+
+// LatestBookSnapshot returns the levels of the last stored copy of the book for symbol taken at
+// or before at (any time when at is zero), or none. Each row's ReceivedAt is when it was taken.
+func (mdb *MarketDataDb) LatestBookSnapshot(symbol string, at time.Time) ([]OrderBookRow, error) {
+	_, until := TimeRange{To: at}.bounds()
+	return mdb.queryOrderBook(selectLatestBookSnapshotQuery, symbol, until, symbol)
+}
+
+// QueryOrderBookUpdates returns incremental book entries for symbol received in [From, To), oldest first
+func (mdb *MarketDataDb) QueryOrderBookUpdates(symbol string, r TimeRange) ([]OrderBookRow, error) {
+	from, to := r.bounds()
+	return mdb.queryOrderBook(selectOrderBookUpdatesQuery, symbol, from, to)
+}
+
+// QueryOrderBook returns snapshot and incremental book entries for symbol received in [From, To), oldest first
+func (mdb *MarketDataDb) QueryOrderBook(symbol string, r TimeRange) ([]OrderBookRow, error) {
+	from, to := r.bounds()
+	return mdb.queryOrderBook(selectOrderBookRangeQuery, symbol, from, to)
+}
+
+// QueryOrderBookEntry returns every stored version of one book entry (new, changes, delete)
+// in the order they were received, for auditing incremental updates
+func (mdb *MarketDataDb) QueryOrderBookEntry(symbol, mdEntryId string) ([]OrderBookRow, error) {
+	return mdb.queryOrderBook(selectOrderBookByEntryIdQuery, symbol, mdEntryId)
+}
+
+func (mdb *MarketDataDb) queryOrderBook(query string, args ...interface{}) ([]OrderBookRow, error) {
+	rows, err := mdb.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order book: %v", err)
+	}
+	defer rows.Close()
+
+	var entries []OrderBookRow
+	for rows.Next() {
+		var (
+			e          OrderBookRow
+			numOrders  sql.NullInt64
+			receivedNs int64
+		)
+		if err := rows.Scan(&e.Id, &e.Symbol, &e.Side, &e.Price, &e.Size, &e.Position, &numOrders,
+			&e.MdEntryId, &e.UpdateAction, &e.QuoteCondition, &e.SeqNum, &e.MdReqId, &e.IsSnapshot, &receivedNs); err != nil {
+			return nil, fmt.Errorf("failed to scan order book entry: %v", err)
+		}
+		if numOrders.Valid {
+			n := int(numOrders.Int64)
+			e.NumOrders = &n
+		}
+		e.ReceivedAt = time.Unix(0, receivedNs).UTC()
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// BookStateRow is one level of the current book from order_book_state
+type BookStateRow struct {
+	Symbol    string
+	Side      string
+	Level     int
+	Price     string
+	Size      string
+	NumOrders *int
+	MdEntryId string
+	UpdatedAt time.Time
+}
+
+// QueryBookState returns the current stored book for symbol: bids then offers, best first
+func (mdb *MarketDataDb) QueryBookState(symbol string) ([]BookStateRow, error) {
+	rows, err := mdb.db.Query(selectBookStateQuery, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order book state: %v", err)
+	}
+	defer rows.Close()
+
+	var levels []BookStateRow
+	for rows.Next() {
+		var (
+			l         BookStateRow
+			numOrders sql.NullInt64
+			updatedNs int64
+		)
+		if err := rows.Scan(&l.Symbol, &l.Side, &l.Level, &l.Price, &l.Size, &numOrders, &l.MdEntryId, &updatedNs); err != nil {
+			return nil, fmt.Errorf("failed to scan order book state: %v", err)
+		}
+		if numOrders.Valid {
+			n := int(numOrders.Int64)
+			l.NumOrders = &n
+		}
+		l.UpdatedAt = time.Unix(0, updatedNs).UTC()
+		levels = append(levels, l)
+	}
+	return levels, rows.Err()
+}
+
+// SubscriptionEventRow is one entry of a subscription's timeline
+type SubscriptionEventRow struct {
+	MdReqId string
+	Symbol  string
+	Event   string
+	Detail  string
+	At      time.Time
+}
+
+// QuerySubscriptionEvents returns the events of every subscription to symbol, oldest first
+func (mdb *MarketDataDb) QuerySubscriptionEvents(symbol string) ([]SubscriptionEventRow, error) {
+	rows, err := mdb.db.Query(selectSubscriptionEventsQuery, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscription events: %v", err)
+	}
+	defer rows.Close()
+
+	var events []SubscriptionEventRow
+	for rows.Next() {
+		var (
+			e  SubscriptionEventRow
+			ns int64
+		)
+		if err := rows.Scan(&e.MdReqId, &e.Symbol, &e.Event, &e.Detail, &ns); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription event: %v", err)
+		}
+		e.At = time.Unix(0, ns).UTC()
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// FixSessionEventRow is one entry of the FIX session timeline
+type FixSessionEventRow struct {
+	SessionId string
+	Event     string
+	Detail    string
+	At        time.Time
+}
+
+// QueryFixSessionEvents returns the FIX session events at or after since, oldest first
+func (mdb *MarketDataDb) QueryFixSessionEvents(since time.Time) ([]FixSessionEventRow, error) {
+	rows, err := mdb.db.Query(selectFixSessionEventsQuery, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session events: %v", err)
+	}
+	defer rows.Close()
+
+	var events []FixSessionEventRow
+	for rows.Next() {
+		var (
+			e  FixSessionEventRow
+			ns int64
+		)
+		if err := rows.Scan(&e.SessionId, &e.Event, &e.Detail, &ns); err != nil {
+			return nil, fmt.Errorf("failed to scan session event: %v", err)
+		}
+		e.At = time.Unix(0, ns).UTC()
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// SessionRow is one market data request from the sessions table
+type SessionRow struct {
+	SessionId    string
+	Symbol       string
+	RequestType  string // "snapshot" or "subscribe"
+	DataTypes    string
+	MdReqId      string
+	Label        string
+	CreatedAt    time.Time
+	EndedAt      time.Time // Zero while the subscription is open
+	TotalUpdates *int64
+	EndReason    string
+}
+
+// QuerySessions returns the requests with label, or every request when label is empty, newest first
+func (mdb *MarketDataDb) QuerySessions(label string, limit int) ([]SessionRow, error) {
+	rows, err := mdb.db.Query(selectSessionsQuery, label, queryLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %v", err)
+	}
+	defer rows.Close()
+
+	var sessions []SessionRow
+	for rows.Next() {
+		var (
+			s              SessionRow
+			created, ended string
+		)
+		if err := rows.Scan(&s.SessionId, &s.Symbol, &s.RequestType, &s.DataTypes, &s.MdReqId, &s.Label,
+			&created, &ended, &s.TotalUpdates, &s.EndReason); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %v", err)
+		}
+		s.CreatedAt, _ = time.Parse(sessionTimeLayout, created)
+		s.EndedAt, _ = time.Parse(sessionTimeLayout, ended)
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// MdTemplateRow is one saved md template
+type MdTemplateRow struct {
+	Name    string
+	Flags   string
+	SavedAt time.Time
+}
+
+// QueryMdTemplates returns the saved md templates by name
+func (mdb *MarketDataDb) QueryMdTemplates() ([]MdTemplateRow, error) {
+	rows, err := mdb.db.Query(selectMdTemplatesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query md templates: %v", err)
+	}
+	defer rows.Close()
+
+	var templates []MdTemplateRow
+	for rows.Next() {
+		var (
+			t  MdTemplateRow
+			ns int64
+		)
+		if err := rows.Scan(&t.Name, &t.Flags, &ns); err != nil {
+			return nil, fmt.Errorf("failed to scan md template: %v", err)
+		}
+		t.SavedAt = time.Unix(0, ns).UTC()
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// SummaryRow is the market_summary row for one symbol. Empty strings mean no value yet
+type SummaryRow struct {
+	Symbol          string
+	LastPrice       string
+	LastSize        string
+	LastAggressor   string
+	LastTradeTime   time.Time
+	BidPrice        string
+	BidSize         string
+	OfferPrice      string
+	OfferSize       string
+	TradeCount      int64
+	BookUpdateCount int64
+	UpdatedAt       time.Time
+}
+
+// QueryMarketSummary returns the summary row for symbol, or sql.ErrNoRows if nothing was stored yet
+func (mdb *MarketDataDb) QueryMarketSummary(symbol string) (SummaryRow, error) {
+	var (
+		r                  SummaryRow
+		tradeNs, updatedNs int64
+	)
+	err := mdb.db.QueryRow(selectSummaryQuery, symbol).Scan(&r.Symbol, &r.LastPrice, &r.LastSize, &r.LastAggressor, &tradeNs,
+		&r.BidPrice, &r.BidSize, &r.OfferPrice, &r.OfferSize, &r.TradeCount, &r.BookUpdateCount, &updatedNs)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return r, err
+		}
+		return r, fmt.Errorf("failed to query market summary: %v", err)
+	}
+	if tradeNs != 0 {
+		r.LastTradeTime = time.Unix(0, tradeNs).UTC()
+	}
+	r.UpdatedAt = time.Unix(0, updatedNs).UTC()
+	return r, nil
+}
+
+// QueryOhlcv returns OHLCV entries for symbol in [From, To) ordered by exchange time.
+// limit <= 0 returns every matching row.
+func (mdb *MarketDataDb) QueryOhlcv(symbol string, r TimeRange, limit int) ([]OhlcvRow, error) {
+	from, to := r.bounds()
+	return mdb.queryOhlcv(selectOhlcvQuery, symbol, from, to, queryLimit(limit))
+}
+
+// LatestOhlcv returns the most recent entry of each OHLCV type for symbol at or before at
+// (exchange time), ordered by data type. A zero at means now.
+func (mdb *MarketDataDb) LatestOhlcv(symbol string, at time.Time) ([]OhlcvRow, error) {
+	until := int64(1<<63 - 1)
+	if !at.IsZero() {
+		until = at.UnixNano() + 1
+	}
+	return mdb.queryOhlcv(selectLatestOhlcvQuery, symbol, until)
+}
+
+func (mdb *MarketDataDb) queryOhlcv(query string, args ...interface{}) ([]OhlcvRow, error) {
+	rows, err := mdb.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ohlcv: %v", err)
+	}
+	defer rows.Close()
+
+	var entries []OhlcvRow
+	for rows.Next() {
+		var (
+			o                   OhlcvRow
+			entryNs, receivedNs int64
+		)
+		if err := rows.Scan(&o.Id, &o.Symbol, &o.DataType, &o.Value, &entryNs,
+			&o.SeqNum, &o.MdReqId, &receivedNs); err != nil {
+			return nil, fmt.Errorf("failed to scan ohlcv: %v", err)
+		}
+		o.EntryTime = time.Unix(0, entryNs).UTC()
+		o.ReceivedAt = time.Unix(0, receivedNs).UTC()
+		entries = append(entries, o)
+	}
+	return entries, rows.Err()
+}
+
+// SQLite treats a negative LIMIT as no limit
+func queryLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
+
+// MessageRow is one stored market data message: the rows sharing a request id and FIX sequence number
+type MessageRow struct {
+	MdReqId    string
+	Symbol     string
+	SeqNum     int
+	Entries    int
+	ReceivedAt time.Time
+}
+
+const selectMessagesQuery = `SELECT COALESCE(md_req_id, ''), symbol, COALESCE(seq_num, 0), COUNT(*), MIN(received_at_ns) FROM (
+			  SELECT md_req_id, symbol, seq_num, received_at_ns FROM trades WHERE received_at_ns >= ?1 AND received_at_ns < ?2
+			  UNION ALL SELECT md_req_id, symbol, seq_num, received_at_ns FROM order_book WHERE received_at_ns >= ?1 AND received_at_ns < ?2
+			  UNION ALL SELECT md_req_id, symbol, seq_num, received_at_ns FROM ohlcv WHERE received_at_ns >= ?1 AND received_at_ns < ?2)
+			  GROUP BY md_req_id, symbol, seq_num
+			  ORDER BY MIN(received_at_ns), seq_num`
+
+// QueryMessages returns every stored market data message received in [From, To), across all
+// symbols, oldest first
+func (mdb *MarketDataDb) QueryMessages(r TimeRange) ([]MessageRow, error) {
+	from, to := r.bounds()
+	rows, err := mdb.db.Query(selectMessagesQuery, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query messages: %v", err)
+	}
+	defer rows.Close()
+
+	var messages []MessageRow
+	for rows.Next() {
+		var (
+			m  MessageRow
+			ns int64
+		)
+		if err := rows.Scan(&m.MdReqId, &m.Symbol, &m.SeqNum, &m.Entries, &ns); err != nil {
+			return nil, fmt.Errorf("failed to scan message: %v", err)
+		}
+		m.ReceivedAt = time.Unix(0, ns).UTC()
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
@@ -17,27 +17,250 @@
 package database
 
 import (
+	"database/sql"
 	_ "embed"
+	"fmt"
+	"log"
+	"time"
+
+	"prime-fix-md-go/utils"
 )
 
 //go:embed schema.sql
 var schemaSQL string
 
 const (
-	insertSessionQuery = `INSERT INTO sessions (session_id, symbol, request_type, data_types, depth, md_req_id) 
-			  VALUES (?, ?, ?, ?, ?, ?)`
+	insertSessionQuery = `INSERT INTO sessions (session_id, symbol, request_type, data_types, depth, md_req_id, label) 
+			  VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''))`
 
-	insertTradeQuery = `INSERT INTO trades (symbol, price, size, aggressor_side, trade_time, seq_num, md_req_id, is_snapshot) 
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	endSessionQuery = `UPDATE sessions SET ended_at = ?, total_updates = ?, end_reason = ?, is_active = 0
+			  WHERE md_req_id = ? AND ended_at IS NULL`
+
+	// The session and symbol fall back to the first session row recorded for the request
+	insertRejectQuery = `INSERT INTO rejects (md_req_id, session_id, symbol, reject_reason, text, rejected_at_ns)
+			  SELECT ?1, s.session_id, COALESCE(NULLIF(?2, ''), s.symbol), NULLIF(?3, ''), NULLIF(?4, ''), ?5
+			  FROM (SELECT 1) LEFT JOIN (SELECT session_id, symbol FROM sessions WHERE md_req_id = ?1
+			  ORDER BY created_at, session_id LIMIT 1) s`
+
+	insertSubscriptionEventQuery = `INSERT INTO subscription_events (md_req_id, symbol, event, detail, event_at_ns)
+			  VALUES (?, ?, ?, NULLIF(?, ''), ?)`
+
+	insertFixSessionEventQuery = `INSERT INTO fix_session_events (session_id, event, detail, event_at_ns)
+			  VALUES (?, ?, NULLIF(?, ''), ?)`
+
+	upsertMdTemplateQuery = `INSERT INTO md_templates (name, flags, saved_at_ns) VALUES (?, ?, ?)
+			  ON CONFLICT(name) DO UPDATE SET flags = excluded.flags, saved_at_ns = excluded.saved_at_ns`
+
+	deleteMdTemplateQuery = `DELETE FROM md_templates WHERE name = ?`
+
+	endOpenSessionsQuery = `UPDATE sessions SET ended_at = ?, end_reason = ?, is_active = 0 WHERE ended_at IS NULL`
+
+	insertTradeQuery = `INSERT INTO trades (symbol, price, size, aggressor_side, trade_time, seq_num, md_req_id, is_snapshot, trade_time_ns, received_at_ns,
+			  md_entry_id, update_action, trade_condition, security_id, security_id_source, aggressor_inferred) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	insertOrderBookQuery = `INSERT INTO order_book (symbol, side, price, size, position, seq_num, md_req_id, is_snapshot) 
+	insertTradePrintQuery = `INSERT INTO trade_prints (symbol, price, size, trade_count, aggressor_side, first_trade_time_ns,
+			  last_trade_time_ns, md_req_id, received_at_ns) VALUES (?, ?, ?, ?, NULLIF(?, ''), ?, ?, NULLIF(?, ''), ?)`
+
+	insertOrderBookQuery = `INSERT INTO order_book (symbol, side, price, size, position, seq_num, md_req_id, is_snapshot, received_at_ns, num_orders,
+			  md_entry_id, update_action, quote_condition, security_id, security_id_source) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	upsertBookStateQuery = `INSERT INTO order_book_state (symbol, side, level, price, size, num_orders, md_entry_id, updated_at_ns)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT (symbol, side, level) DO UPDATE SET price = excluded.price, size = excluded.size,
+			  num_orders = excluded.num_orders, md_entry_id = excluded.md_entry_id, updated_at_ns = excluded.updated_at_ns
+			  WHERE price != excluded.price OR size != excluded.size OR num_orders IS NOT excluded.num_orders
+			  OR md_entry_id IS NOT excluded.md_entry_id`
+
+	trimBookStateQuery = `DELETE FROM order_book_state WHERE symbol = ? AND side = ? AND level > ?`
+
+	insertBookSnapshotQuery = `INSERT OR REPLACE INTO book_snapshots (symbol, side, level, price, size, num_orders, md_entry_id, taken_at_ns)
 			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
 
-	insertOHLCVQuery = `INSERT INTO ohlcv (symbol, data_type, value, entry_time, seq_num, md_req_id) 
-			  VALUES (?, ?, ?, ?, ?, ?)`
+	// ?13 says whether a new quote is given; without one the stored quote is kept
+	upsertSummaryQuery = `INSERT INTO market_summary (symbol, last_price, last_size, last_aggressor_side, last_trade_time_ns,
+			  bid_price, bid_size, offer_price, offer_size, trade_count, book_update_count, updated_at_ns)
+			  VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12)
+			  ON CONFLICT (symbol) DO UPDATE SET
+			  last_price = COALESCE(excluded.last_price, last_price),
+			  last_size = COALESCE(excluded.last_size, last_size),
+			  last_aggressor_side = CASE WHEN excluded.last_price IS NULL THEN last_aggressor_side ELSE excluded.last_aggressor_side END,
+			  last_trade_time_ns = CASE WHEN excluded.last_price IS NULL THEN last_trade_time_ns ELSE excluded.last_trade_time_ns END,
+			  bid_price = CASE WHEN ?13 THEN excluded.bid_price ELSE bid_price END,
+			  bid_size = CASE WHEN ?13 THEN excluded.bid_size ELSE bid_size END,
+			  offer_price = CASE WHEN ?13 THEN excluded.offer_price ELSE offer_price END,
+			  offer_size = CASE WHEN ?13 THEN excluded.offer_size ELSE offer_size END,
+			  trade_count = trade_count + excluded.trade_count,
+			  book_update_count = book_update_count + excluded.book_update_count,
+			  updated_at_ns = excluded.updated_at_ns`
+
+	insertOHLCVQuery = `INSERT INTO ohlcv (symbol, data_type, value, entry_time, seq_num, md_req_id, entry_time_ns, received_at_ns) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
 )
 
+// addedColumn is a column that was added to schema.sql after the table was first released.
+// Databases created before then get it through ALTER TABLE on open.
+type addedColumn struct {
+	table      string
+	column     string
+	definition string
+}
+
+var addedColumns = []addedColumn{
+	{"trades", "trade_time_ns", "INTEGER"},
+	{"trades", "received_at_ns", "INTEGER"},
+	{"order_book", "received_at_ns", "INTEGER"},
+	{"order_book", "num_orders", "INTEGER"},
+	{"trades", "md_entry_id", "TEXT"},
+	{"trades", "update_action", "TEXT"},
+	{"order_book", "md_entry_id", "TEXT"},
+	{"order_book", "update_action", "TEXT"},
+	{"trades", "trade_condition", "TEXT"},
+	{"order_book", "quote_condition", "TEXT"},
+	{"ohlcv", "entry_time_ns", "INTEGER"},
+	{"ohlcv", "received_at_ns", "INTEGER"},
+	{"trades", "security_id", "TEXT"},
+	{"trades", "security_id_source", "TEXT"},
+	{"order_book", "security_id", "TEXT"},
+	{"order_book", "security_id_source", "TEXT"},
+	{"sessions", "ended_at", "TIMESTAMP"},
+	{"sessions", "total_updates", "INTEGER"},
+	{"sessions", "end_reason", "TEXT"},
+	{"sessions", "label", "TEXT"},
+	{"trades", "aggressor_inferred", "BOOLEAN"},
+}
+
 func (mdb *MarketDataDb) initSchema() error {
-	_, err := mdb.db.Exec(schemaSQL)
-	return err
+	// Columns must exist before schema.sql creates indexes on them
+	if err := mdb.addMissingColumns(); err != nil {
+		return err
+	}
+	if _, err := mdb.db.Exec(schemaSQL); err != nil {
+		return err
+	}
+	return mdb.backfillEpochNs()
+}
+
+func (mdb *MarketDataDb) addMissingColumns() error {
+	for _, c := range addedColumns {
+		columns, err := mdb.tableColumns(c.table)
+		if err != nil {
+			return err
+		}
+		// Table does not exist yet; schema.sql will create it with every column
+		if len(columns) == 0 || columns[c.column] {
+			continue
+		}
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)
+		if _, err := mdb.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to add %s.%s: %v", c.table, c.column, err)
+		}
+	}
+	return nil
+}
+
+func (mdb *MarketDataDb) tableColumns(table string) (map[string]bool, error) {
+	rows, err := mdb.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid        int
+			name, typ  string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &defaultVal, &pk); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// backfillEpochNs fills the epoch-ns columns for rows written before they existed
+func (mdb *MarketDataDb) backfillEpochNs() error {
+	for _, table := range []string{"trades", "order_book", "ohlcv"} {
+		query := fmt.Sprintf(`UPDATE %s SET received_at_ns = CAST(strftime('%%s', received_at) AS INTEGER) * 1000000000
+			WHERE received_at_ns IS NULL AND received_at IS NOT NULL`, table)
+		if _, err := mdb.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to backfill %s.received_at_ns: %v", table, err)
+		}
+	}
+
+	if err := mdb.backfillEventTimeNs("trades", "trade_time", "trade_time_ns"); err != nil {
+		return err
+	}
+	return mdb.backfillEventTimeNs("ohlcv", "entry_time", "entry_time_ns")
+}
+
+func (mdb *MarketDataDb) backfillEventTimeNs(table, textColumn, nsColumn string) error {
+	rows, err := mdb.db.Query(fmt.Sprintf("SELECT id, %s, received_at_ns FROM %s WHERE %s IS NULL", textColumn, table, nsColumn))
+	if err != nil {
+		return err
+	}
+
+	type pendingRow struct {
+		id int64
+		ns int64
+	}
+	var pending []pendingRow
+	for rows.Next() {
+		var (
+			id         int64
+			text       sql.NullString
+			receivedNs sql.NullInt64
+		)
+		if err := rows.Scan(&id, &text, &receivedNs); err != nil {
+			rows.Close()
+			return err
+		}
+		received := time.Now()
+		if receivedNs.Valid {
+			received = time.Unix(0, receivedNs.Int64)
+		}
+		pending = append(pending, pendingRow{id: id, ns: eventTimeNs(text.String, received)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	tx, err := mdb.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ?", table, nsColumn))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, p := range pending {
+		if _, err := stmt.Exec(p.ns, p.id); err != nil {
+			return fmt.Errorf("failed to backfill %s.%s: %v", table, nsColumn, err)
+		}
+	}
+	log.Printf("Backfilled %s for %d rows", nsColumn, len(pending))
+	return tx.Commit()
+}
+
+// eventTimeNs converts an exchange timestamp to epoch ns, using the receive time
+// when the value is missing or unparseable so every row still orders correctly
+func eventTimeNs(value string, received time.Time) int64 {
+	if t, ok := utils.ParseFixTime(value, received); ok {
+		return t.UnixNano()
+	}
+	return received.UnixNano()
 }
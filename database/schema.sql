@@ -21,7 +21,11 @@ CREATE TABLE IF NOT EXISTS sessions (
 	depth INTEGER,              -- NULL for trades/ohlcv, number for order book
 	md_req_id TEXT NOT NULL,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-	is_active BOOLEAN DEFAULT 1
+	is_active BOOLEAN DEFAULT 1,
+	ended_at TIMESTAMP,         -- NULL while the subscription is open
+	total_updates INTEGER,      -- Market data entries received, NULL when not tracked (snapshots)
+	end_reason TEXT,            -- 'unsubscribed', 'rejected', 'logout' or 'exit'
+	label TEXT                  -- md --label, naming the capture's purpose; NULL when not given
 );
 
 -- All trade data (snapshots + streaming)
@@ -35,7 +39,15 @@ CREATE TABLE IF NOT EXISTS trades (
 	seq_num INTEGER,           -- FIX sequence number
 	md_req_id TEXT,
 	is_snapshot BOOLEAN,
-	received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	trade_time_ns INTEGER,     -- trade_time as epoch ns (falls back to received_at_ns if unparseable)
+	received_at_ns INTEGER,    -- Local receive time as epoch ns
+	md_entry_id TEXT,          -- MDEntryID (278), NULL if not sent
+	update_action TEXT,        -- MDUpdateAction (279): '0'=New, '1'=Change, '2'=Delete
+	trade_condition TEXT,      -- TradeCondition (277), space-separated codes, NULL if not sent
+	security_id TEXT,          -- SecurityID (48), NULL if not sent
+	security_id_source TEXT,   -- SecurityIDSource (22), NULL if not sent
+	aggressor_inferred BOOLEAN -- 1 when aggressor_side was inferred by md.inferAggressor rather than sent
 );
 
 -- All order book data (bids/offers, snapshots + streaming)  
@@ -49,7 +61,14 @@ CREATE TABLE IF NOT EXISTS order_book (
 	seq_num INTEGER,
 	md_req_id TEXT,
 	is_snapshot BOOLEAN,
-	received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	received_at_ns INTEGER,    -- Local receive time as epoch ns
+	num_orders INTEGER,        -- NumberOfOrders (346) at this level, NULL if not sent
+	md_entry_id TEXT,          -- MDEntryID (278), NULL if not sent
+	update_action TEXT,        -- MDUpdateAction (279): '0'=New, '1'=Change, '2'=Delete
+	quote_condition TEXT,      -- QuoteCondition (276), space-separated codes, NULL if not sent
+	security_id TEXT,          -- SecurityID (48), NULL if not sent
+	security_id_source TEXT    -- SecurityIDSource (22), NULL if not sent
 );
 
 -- OHLCV data (snapshots only)
@@ -61,11 +80,129 @@ CREATE TABLE IF NOT EXISTS ohlcv (
 	entry_time TEXT,           -- Exchange timestamp  
 	seq_num INTEGER,
 	md_req_id TEXT,
-	received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	entry_time_ns INTEGER,     -- entry_time as epoch ns (falls back to received_at_ns if unparseable)
+	received_at_ns INTEGER     -- Local receive time as epoch ns
+);
+
+-- Current book per symbol (level 1 = best), kept in step with the in-memory book
+CREATE TABLE IF NOT EXISTS order_book_state (
+	symbol TEXT NOT NULL,
+	side TEXT NOT NULL,        -- 'bid' or 'offer'
+	level INTEGER NOT NULL,    -- 1=best, 2=second, etc.
+	price REAL NOT NULL,
+	size REAL NOT NULL,
+	num_orders INTEGER,        -- NumberOfOrders (346) at this level, NULL if not sent
+	md_entry_id TEXT,          -- MDEntryID (278), NULL if not sent
+	updated_at_ns INTEGER NOT NULL, -- Local receive time of the update that last changed the row
+	PRIMARY KEY (symbol, side, level)
+);
+
+-- Periodic full copies of the in-memory book, so rebuilding a past book can start from the
+-- latest copy instead of the last snapshot from the gateway
+CREATE TABLE IF NOT EXISTS book_snapshots (
+	symbol TEXT NOT NULL,
+	side TEXT NOT NULL,        -- 'bid' or 'offer'
+	level INTEGER NOT NULL,    -- 1=best, 2=second, etc.
+	price REAL NOT NULL,
+	size REAL NOT NULL,
+	num_orders INTEGER,        -- NumberOfOrders (346) at this level, NULL if not sent
+	md_entry_id TEXT,          -- MDEntryID (278), NULL if not sent
+	taken_at_ns INTEGER NOT NULL, -- Receive time of the last update the book included
+	PRIMARY KEY (symbol, taken_at_ns, side, level)
+);
+
+-- Latest trade and quote per symbol, so dashboards don't scan trades for MAX(time)
+CREATE TABLE IF NOT EXISTS market_summary (
+	symbol TEXT PRIMARY KEY,
+	last_price REAL,           -- Most recent trade, NULL until one is received
+	last_size REAL,
+	last_aggressor_side TEXT,
+	last_trade_time_ns INTEGER, -- Exchange time of the most recent trade
+	bid_price REAL,            -- Best bid/offer of the in-memory book, NULL when that side is empty
+	bid_size REAL,
+	offer_price REAL,
+	offer_size REAL,
+	trade_count INTEGER NOT NULL DEFAULT 0,       -- Trade entries received
+	book_update_count INTEGER NOT NULL DEFAULT 0, -- Bid/offer entries received
+	updated_at_ns INTEGER NOT NULL
+);
+
+-- Every Market Data Request Reject (35=Y)
+CREATE TABLE IF NOT EXISTS rejects (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	md_req_id TEXT NOT NULL,
+	session_id TEXT,             -- sessions row of the rejected request, NULL if it was not recorded
+	symbol TEXT,                 -- From the reject, else the session; NULL when neither is known
+	reject_reason TEXT,          -- MDReqRejReason (281)
+	text TEXT,                   -- Text (58)
+	rejected_at_ns INTEGER NOT NULL
+);
+
+-- Timeline of each subscription: subscribe, snapshot_received, stale, recovered, resubscribe,
+-- unsubscribe and rejected
+CREATE TABLE IF NOT EXISTS subscription_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	md_req_id TEXT NOT NULL,
+	symbol TEXT NOT NULL,
+	event TEXT NOT NULL,
+	detail TEXT,                 -- e.g. the snapshot MdReqId of a resubscribe, or the quiet time for stale
+	event_at_ns INTEGER NOT NULL
+);
+
+-- FIX session lifecycle: created, logon, logout, disconnect, resend, sequence_reset, reject and seq_set
+CREATE TABLE IF NOT EXISTS fix_session_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	session_id TEXT NOT NULL,    -- quickfix session ID, e.g. FIXT.1.1:SENDER->COIN
+	event TEXT NOT NULL,
+	detail TEXT,                 -- e.g. the Text (58) of a logout or the range of a resend request
+	event_at_ns INTEGER NOT NULL
+);
+
+-- Named md flag combinations saved with the template command, used as md BTC-USD @name
+CREATE TABLE IF NOT EXISTS md_templates (
+	name TEXT PRIMARY KEY,
+	flags TEXT NOT NULL,         -- md flags, e.g. --depth 10 --book --subscribe
+	saved_at_ns INTEGER NOT NULL
+);
+
+-- Consecutive trades at one price and aggressor coalesced by display.aggregateTrades, when its
+-- store option is on. The individual trades are in trades as well.
+CREATE TABLE IF NOT EXISTS trade_prints (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	symbol TEXT NOT NULL,
+	price DECIMAL(20,8) NOT NULL,
+	size DECIMAL(20,8) NOT NULL,     -- Total of the trades
+	trade_count INTEGER NOT NULL,
+	aggressor_side TEXT,
+	first_trade_time_ns INTEGER NOT NULL,
+	last_trade_time_ns INTEGER NOT NULL,
+	md_req_id TEXT,
+	received_at_ns INTEGER NOT NULL  -- When the print was closed
 );
 
 -- Indexes for performance
 CREATE INDEX IF NOT EXISTS idx_trades_symbol_time ON trades(symbol, received_at);
 CREATE INDEX IF NOT EXISTS idx_orderbook_symbol_time ON order_book(symbol, received_at);
 CREATE INDEX IF NOT EXISTS idx_ohlcv_symbol_time ON ohlcv(symbol, received_at);
-CREATE INDEX IF NOT EXISTS idx_orderbook_symbol_side_pos ON order_book(symbol, side, position, received_at);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_orderbook_symbol_side_pos ON order_book(symbol, side, position, received_at);
+CREATE INDEX IF NOT EXISTS idx_trades_symbol_time_ns ON trades(symbol, trade_time_ns);
+CREATE INDEX IF NOT EXISTS idx_orderbook_symbol_received_ns ON order_book(symbol, received_at_ns);
+CREATE INDEX IF NOT EXISTS idx_ohlcv_symbol_time_ns ON ohlcv(symbol, entry_time_ns);
+CREATE INDEX IF NOT EXISTS idx_trades_entry_id ON trades(md_entry_id);
+CREATE INDEX IF NOT EXISTS idx_orderbook_entry_id ON order_book(md_entry_id);
+CREATE INDEX IF NOT EXISTS idx_trades_security_id ON trades(security_id, trade_time_ns);
+CREATE INDEX IF NOT EXISTS idx_orderbook_security_id ON order_book(security_id, received_at_ns);
+-- Receive time across all symbols, used by archiving and the message list
+CREATE INDEX IF NOT EXISTS idx_trades_received_ns ON trades(received_at_ns);
+CREATE INDEX IF NOT EXISTS idx_orderbook_received_ns ON order_book(received_at_ns);
+CREATE INDEX IF NOT EXISTS idx_ohlcv_received_ns ON ohlcv(received_at_ns);
+-- Message identity, used to skip duplicates when merging databases
+CREATE INDEX IF NOT EXISTS idx_trades_message ON trades(symbol, seq_num, md_req_id);
+CREATE INDEX IF NOT EXISTS idx_orderbook_message ON order_book(symbol, seq_num, md_req_id);
+CREATE INDEX IF NOT EXISTS idx_ohlcv_message ON ohlcv(symbol, seq_num, md_req_id);
+CREATE INDEX IF NOT EXISTS idx_rejects_symbol_time ON rejects(symbol, rejected_at_ns);
+CREATE INDEX IF NOT EXISTS idx_trade_prints_symbol_time ON trade_prints(symbol, first_trade_time_ns);
+CREATE INDEX IF NOT EXISTS idx_subscription_events_req ON subscription_events(md_req_id, event_at_ns);
+CREATE INDEX IF NOT EXISTS idx_subscription_events_symbol ON subscription_events(symbol, event_at_ns);
+CREATE INDEX IF NOT EXISTS idx_fix_session_events_time ON fix_session_events(event_at_ns);
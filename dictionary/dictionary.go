@@ -0,0 +1,261 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package dictionary checks inbound market data against an embedded FIX 5.0 SP2 / Prime
+// dictionary. The session runs with UseDataDictionary=N, so nothing else catches a gateway
+// sending, or the parser expecting, a different message layout.
+package dictionary
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+//go:embed prime_md.xml
+var primeMd []byte
+
+// Trailer tags end the last repeating group: SignatureLength, Signature and CheckSum
+var trailerTags = map[int]bool{93: true, 89: true, 10: true}
+
+// Validator reports how raw messages differ from a dictionary
+type Validator struct {
+	dict  *datadictionary.DataDictionary
+	rules map[string]messageRules // MsgType -> what the message must carry
+}
+
+type messageRules struct {
+	required   []*datadictionary.FieldDef // Body fields outside any repeating group
+	components []datadictionary.Component // Required components whose fields are all optional, e.g. the Instrument
+	groups     []*datadictionary.FieldDef
+}
+
+type field struct {
+	tag   int
+	value string
+}
+
+var (
+	primeOnce sync.Once
+	prime     *Validator
+	primeErr  error
+)
+
+// Prime returns the validator for the embedded market data dictionary, parsed on first use
+func Prime() (*Validator, error) {
+	primeOnce.Do(func() {
+		prime, primeErr = New(primeMd)
+	})
+	return prime, primeErr
+}
+
+// New builds a validator from QuickFIX dictionary XML
+func New(src []byte) (*Validator, error) {
+	dict, err := datadictionary.ParseSrc(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FIX dictionary: %w", err)
+	}
+	v := &Validator{dict: dict, rules: make(map[string]messageRules, len(dict.Messages))}
+	for msgType, def := range dict.Messages {
+		v.rules[msgType] = newMessageRules(def)
+	}
+	return v, nil
+}
+
+func newMessageRules(def *datadictionary.MessageDef) messageRules {
+	var r messageRules
+	add := func(f *datadictionary.FieldDef, required bool) {
+		if f.IsGroup() {
+			r.groups = append(r.groups, f)
+		} else if required && f.Required() {
+			r.required = append(r.required, f)
+		}
+	}
+	for _, part := range def.Parts {
+		switch p := part.(type) {
+		case *datadictionary.FieldDef:
+			add(p, true)
+		case datadictionary.Component:
+			for _, f := range p.Fields() {
+				add(f, p.Required())
+			}
+			// Such a component still needs one of its fields: an Instrument has a Symbol or a SecurityID
+			if p.Required() && len(p.RequiredFields()) == 0 {
+				r.components = append(r.components, p)
+			}
+		}
+	}
+	return r
+}
+
+// Knows reports whether the dictionary defines msgType
+func (v *Validator) Knows(msgType string) bool {
+	_, ok := v.rules[msgType]
+	return ok
+}
+
+// Validate lists the discrepancies in a raw message: missing required fields and a group
+// count that does not match the entries that follow. Message types the dictionary does not
+// define, and tags it does not know, are not checked.
+func (v *Validator) Validate(raw string) []string {
+	fields := splitFields(raw)
+	rules, ok := v.rules[msgTypeOf(fields)]
+	if !ok {
+		return nil
+	}
+
+	var problems []string
+
+	// Groups come last in the body, so the fields before the first one are the message's own
+	body := len(fields)
+	for i, f := range fields {
+		if isGroupTag(rules.groups, f.tag) {
+			body = i
+			break
+		}
+	}
+	for _, req := range rules.required {
+		if !hasTag(fields[:body], req.Tag()) {
+			problems = append(problems, fmt.Sprintf("missing required %s", v.name(req.Tag())))
+		}
+	}
+	for _, c := range rules.components {
+		var names []string
+		found := false
+		for _, f := range c.Fields() {
+			names = append(names, v.name(f.Tag()))
+			found = found || hasTag(fields[:body], f.Tag())
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("missing required %s: one of %s", c.Name(), strings.Join(names, ", ")))
+		}
+	}
+
+	for _, group := range rules.groups {
+		problems = append(problems, v.validateGroup(fields, group)...)
+	}
+	return problems
+}
+
+// validateGroup checks a group's count and each entry's required fields; entries run from
+// the count tag to the trailer and start at the group's first field
+func (v *Validator) validateGroup(fields []field, group *datadictionary.FieldDef) []string {
+	start := indexOf(fields, group.Tag())
+	if start == -1 {
+		if group.Required() {
+			return []string{fmt.Sprintf("missing required %s", v.name(group.Tag()))}
+		}
+		return nil
+	}
+
+	var problems []string
+	declared, err := strconv.Atoi(fields[start].value)
+	if err != nil || declared < 0 {
+		problems = append(problems, fmt.Sprintf("%s is not a count: %q", v.name(group.Tag()), fields[start].value))
+	}
+
+	end := start + 1
+	for end < len(fields) && !trailerTags[fields[end].tag] {
+		end++
+	}
+	rest := fields[start+1 : end]
+
+	delim := group.Fields[0].Tag()
+	if len(rest) > 0 && rest[0].tag != delim {
+		problems = append(problems, fmt.Sprintf("%s entries start with %s instead of %s",
+			v.name(group.Tag()), v.name(rest[0].tag), v.name(delim)))
+	}
+
+	var entries [][]field
+	for _, f := range rest {
+		if f.tag == delim {
+			entries = append(entries, nil)
+		}
+		if len(entries) > 0 {
+			entries[len(entries)-1] = append(entries[len(entries)-1], f)
+		}
+	}
+	if err == nil && declared != len(entries) {
+		problems = append(problems, fmt.Sprintf("%s is %d but %d entries follow", v.name(group.Tag()), declared, len(entries)))
+	}
+
+	for i, entry := range entries {
+		for _, req := range group.RequiredFields() {
+			if !hasTag(entry, req.Tag()) {
+				problems = append(problems, fmt.Sprintf("entry %d missing required %s", i+1, v.name(req.Tag())))
+			}
+		}
+	}
+	return problems
+}
+
+// name shows a tag as "MDEntryType (269)", or just its number when the dictionary lacks it
+func (v *Validator) name(tag int) string {
+	if ft, ok := v.dict.FieldTypeByTag[tag]; ok {
+		return fmt.Sprintf("%s (%d)", ft.Name(), tag)
+	}
+	return fmt.Sprintf("tag %d", tag)
+}
+
+// splitFields reads SOH-delimited tag=value fields, skipping any whose tag is not a number
+func splitFields(raw string) []field {
+	fields := make([]field, 0, strings.Count(raw, "\x01")+1)
+	for _, part := range strings.Split(raw, "\x01") {
+		tag, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(tag)
+		if err != nil {
+			continue
+		}
+		fields = append(fields, field{tag: n, value: value})
+	}
+	return fields
+}
+
+func msgTypeOf(fields []field) string {
+	if i := indexOf(fields, 35); i != -1 {
+		return fields[i].value
+	}
+	return ""
+}
+
+func indexOf(fields []field, tag int) int {
+	for i, f := range fields {
+		if f.tag == tag {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasTag(fields []field, tag int) bool {
+	return indexOf(fields, tag) != -1
+}
+
+func isGroupTag(groups []*datadictionary.FieldDef, tag int) bool {
+	for _, g := range groups {
+		if g.Tag() == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dictionary
+
+import (
+	"strings"
+	"testing"
+)
+
+// fix joins pipe-separated fields with SOH
+func fix(s string) string {
+	return strings.ReplaceAll(s, "|", "\x01")
+}
+
+func TestPrimeDictionaryLoads(t *testing.T) {
+	v, err := Prime()
+	if err != nil {
+		t.Fatalf("Embedded dictionary failed to load: %v", err)
+	}
+	for _, msgType := range []string{"W", "X", "Y"} {
+		if !v.Knows(msgType) {
+			t.Fatalf("Expected the dictionary to define %s", msgType)
+		}
+	}
+	if v.Knows("0") {
+		t.Fatalf("Expected admin messages to be left unchecked")
+	}
+}
+
+func TestValidateWellFormedMessages(t *testing.T) {
+	v, _ := Prime()
+	messages := []string{
+		"8=FIXT.1.1|9=120|35=W|34=5|262=req1|55=BTC-USD|268=2|269=0|270=49999|271=1|269=1|270=50001|271=2|10=123|",
+		"8=FIXT.1.1|9=90|35=X|34=6|262=req1|268=1|279=0|269=2|55=BTC-USD|270=50000|271=0.5|2446=1|10=045|",
+		"8=FIXT.1.1|9=40|35=Y|34=7|262=req1|281=0|58=Unknown symbol|10=200|",
+		"8=FIXT.1.1|9=40|35=0|34=8|10=200|",
+	}
+	for _, raw := range messages {
+		if problems := v.Validate(fix(raw)); len(problems) != 0 {
+			t.Fatalf("Expected no problems for %s, got %v", raw, problems)
+		}
+	}
+}
+
+// Symbol is optional in the Instrument, as in FIX 5.0 SP2, so a snapshot may name its instrument by SecurityID
+func TestValidateSecurityIdOnlySnapshot(t *testing.T) {
+	v, _ := Prime()
+	raw := "8=FIXT.1.1|9=100|35=W|34=5|262=req1|48=BTC-USD|22=8|268=1|269=2|270=50000|271=0.5|10=123|"
+	if problems := v.Validate(fix(raw)); len(problems) != 0 {
+		t.Fatalf("Expected a SecurityID-only snapshot to validate, got %v", problems)
+	}
+}
+
+func TestValidateReportsDiscrepancies(t *testing.T) {
+	v, _ := Prime()
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{
+			raw: "35=W|262=req1|268=3|269=0|270=49999|269=1|270=50001|10=123|",
+			want: []string{
+				"missing required Instrument: one of Symbol (55), SecurityID (48), SecurityIDSource (22)",
+				"NoMDEntries (268) is 3 but 2 entries follow",
+			},
+		},
+		{
+			raw:  "35=W|55=BTC-USD|10=123|",
+			want: []string{"missing required NoMDEntries (268)"},
+		},
+		{
+			raw: "35=X|268=2|269=2|279=0|270=50000|279=1|270=50001|10=045|",
+			want: []string{
+				"NoMDEntries (268) entries start with MDEntryType (269) instead of MDUpdateAction (279)",
+				"entry 1 missing required MDEntryType (269)",
+				"entry 2 missing required MDEntryType (269)",
+			},
+		},
+		{
+			raw:  "35=X|268=abc|279=0|269=2|10=045|",
+			want: []string{`NoMDEntries (268) is not a count: "abc"`},
+		},
+		{
+			raw:  "35=Y|281=0|10=200|",
+			want: []string{"missing required MDReqID (262)"},
+		},
+	}
+	for _, tt := range tests {
+		got := v.Validate(fix(tt.raw))
+		if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+			t.Fatalf("Validate(%s) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
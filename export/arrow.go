@@ -0,0 +1,253 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package export
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Arrow IPC streaming format (https://arrow.apache.org/docs/format/Columnar.html#ipc-streaming-format):
+// a Schema message followed by one RecordBatch message per write, then an end-of-stream marker.
+// Readers such as pyarrow.ipc.open_stream and arrow-rs StreamReader consume it without conversion.
+
+type arrowType int
+
+const (
+	arrowUtf8 arrowType = iota
+	arrowInt64
+	arrowFloat64
+	arrowBool
+	arrowTimestamp // Nanoseconds, UTC
+)
+
+type arrowField struct {
+	name     string
+	typ      arrowType
+	nullable bool
+}
+
+const (
+	arrowMetadataV5        = 4
+	arrowHeaderSchema      = 1
+	arrowHeaderRecordBatch = 3
+)
+
+var arrowContinuation = []byte{0xff, 0xff, 0xff, 0xff}
+
+// flatbuffer returns the Type union tag and table for a field type
+func (t arrowType) flatbuffer() (uint8, fbTable) {
+	switch t {
+	case arrowInt64:
+		return 2, fbTable{{id: 0, scalar: fbInt32(64)}, {id: 1, scalar: fbBool(true)}}
+	case arrowFloat64:
+		return 3, fbTable{{id: 0, scalar: fbInt16(2)}} // DOUBLE
+	case arrowBool:
+		return 6, fbTable{}
+	case arrowTimestamp:
+		return 10, fbTable{{id: 0, scalar: fbInt16(3)}, {id: 1, child: fbString("UTC")}} // NANOSECOND
+	default:
+		return 5, fbTable{} // Utf8
+	}
+}
+
+// arrowColumn accumulates one column of a record batch in its Arrow buffer layout
+type arrowColumn struct {
+	typ       arrowType
+	length    int
+	nullCount int
+	validity  []byte
+	offsets   []byte // Utf8 only
+	values    []byte
+}
+
+func newArrowColumn(typ arrowType) *arrowColumn {
+	c := &arrowColumn{typ: typ}
+	if typ == arrowUtf8 {
+		c.offsets = binary.LittleEndian.AppendUint32(nil, 0)
+	}
+	return c
+}
+
+func setBit(bits []byte, i int, v bool) []byte {
+	if i%8 == 0 {
+		bits = append(bits, 0)
+	}
+	if v {
+		bits[i/8] |= 1 << (i % 8)
+	}
+	return bits
+}
+
+func (c *arrowColumn) next(valid bool) {
+	c.validity = setBit(c.validity, c.length, valid)
+	if !valid {
+		c.nullCount++
+	}
+	c.length++
+}
+
+func (c *arrowColumn) appendString(s string) {
+	c.values = append(c.values, s...)
+	c.offsets = binary.LittleEndian.AppendUint32(c.offsets, uint32(len(c.values)))
+	c.next(true)
+}
+
+func (c *arrowColumn) appendInt64(v int64) {
+	c.values = binary.LittleEndian.AppendUint64(c.values, uint64(v))
+	c.next(true)
+}
+
+// appendFloat64 stores the value, or null when ok is false
+func (c *arrowColumn) appendFloat64(v float64, ok bool) {
+	if !ok {
+		v = 0
+	}
+	c.values = binary.LittleEndian.AppendUint64(c.values, math.Float64bits(v))
+	c.next(ok)
+}
+
+func (c *arrowColumn) appendBool(v bool) {
+	c.values = setBit(c.values, c.length, v)
+	c.next(true)
+}
+
+// appendTime stores t in nanoseconds, or null when t is zero
+func (c *arrowColumn) appendTime(t time.Time) {
+	var ns int64
+	if !t.IsZero() {
+		ns = t.UnixNano()
+	}
+	c.values = binary.LittleEndian.AppendUint64(c.values, uint64(ns))
+	c.next(!t.IsZero())
+}
+
+// buffers returns the column's buffers in IPC order; validity is omitted (empty) when nothing is null
+func (c *arrowColumn) buffers() [][]byte {
+	validity := c.validity
+	if c.nullCount == 0 {
+		validity = nil
+	}
+	if c.typ == arrowUtf8 {
+		return [][]byte{validity, c.offsets, c.values}
+	}
+	return [][]byte{validity, c.values}
+}
+
+// arrowStream writes record batches with a fixed schema to w
+type arrowStream struct {
+	w       io.Writer
+	fields  []arrowField
+	started bool
+}
+
+func (s *arrowStream) columns() []*arrowColumn {
+	columns := make([]*arrowColumn, len(s.fields))
+	for i, f := range s.fields {
+		columns[i] = newArrowColumn(f.typ)
+	}
+	return columns
+}
+
+func (s *arrowStream) start() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+
+	fields := make(fbVector, len(s.fields))
+	for i, f := range s.fields {
+		typeTag, typeTable := f.typ.flatbuffer()
+		fields[i] = fbTable{
+			{id: 0, child: fbString(f.name)},
+			{id: 1, scalar: fbBool(f.nullable)},
+			{id: 2, scalar: fbUint8(typeTag)},
+			{id: 3, child: typeTable},
+			{id: 5, child: fbVector{}}, // children
+		}
+	}
+	schema := fbTable{
+		{id: 0, scalar: fbInt16(0)}, // little endian
+		{id: 1, child: fields},
+	}
+	return s.writeMessage(arrowHeaderSchema, schema, nil)
+}
+
+func (s *arrowStream) writeBatch(columns []*arrowColumn) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	if len(columns) == 0 || columns[0].length == 0 {
+		return nil
+	}
+
+	var nodes, buffers, body []byte
+	for _, c := range columns {
+		nodes = binary.LittleEndian.AppendUint64(nodes, uint64(c.length))
+		nodes = binary.LittleEndian.AppendUint64(nodes, uint64(c.nullCount))
+		for _, buf := range c.buffers() {
+			buffers = binary.LittleEndian.AppendUint64(buffers, uint64(len(body)))
+			buffers = binary.LittleEndian.AppendUint64(buffers, uint64(len(buf)))
+			body = append(body, buf...)
+			body = append(body, make([]byte, padding(len(body)))...)
+		}
+	}
+
+	batch := fbTable{
+		{id: 0, scalar: fbInt64(int64(columns[0].length))},
+		{id: 1, child: fbStructVector{count: len(nodes) / 16, data: nodes}},
+		{id: 2, child: fbStructVector{count: len(buffers) / 16, data: buffers}},
+	}
+	return s.writeMessage(arrowHeaderRecordBatch, batch, body)
+}
+
+// writeMessage frames one IPC message: continuation marker, metadata length, metadata, body
+func (s *arrowStream) writeMessage(headerType uint8, header fbTable, body []byte) error {
+	metadata := fbRoot(fbTable{
+		{id: 0, scalar: fbInt16(arrowMetadataV5)},
+		{id: 1, scalar: fbUint8(headerType)},
+		{id: 2, child: header},
+		{id: 3, scalar: fbInt64(int64(len(body)))},
+	})
+
+	frame := make([]byte, 0, 8+len(metadata)+len(body))
+	frame = append(frame, arrowContinuation...)
+	frame = binary.LittleEndian.AppendUint32(frame, uint32(len(metadata)))
+	frame = append(frame, metadata...)
+	frame = append(frame, body...)
+	if _, err := s.w.Write(frame); err != nil {
+		return fmt.Errorf("failed to write arrow stream: %v", err)
+	}
+	return nil
+}
+
+// close writes the schema if nothing was written yet and the end-of-stream marker
+func (s *arrowStream) close() error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	if _, err := s.w.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}); err != nil {
+		return fmt.Errorf("failed to write arrow stream: %v", err)
+	}
+	return nil
+}
+
+func padding(n int) int {
+	return (8 - n%8) % 8
+}
@@ -0,0 +1,183 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// fbTab reads a flatbuffer table the way generated readers do, independently of how it was laid out
+type fbTab struct {
+	buf []byte
+	pos int
+}
+
+func (t fbTab) u16(pos int) int { return int(binary.LittleEndian.Uint16(t.buf[pos:])) }
+func (t fbTab) u32(pos int) int { return int(binary.LittleEndian.Uint32(t.buf[pos:])) }
+
+func (t fbTab) field(id int) int {
+	vtable := t.pos - int(int32(binary.LittleEndian.Uint32(t.buf[t.pos:])))
+	if 4+2*id >= t.u16(vtable) {
+		return 0
+	}
+	if off := t.u16(vtable + 4 + 2*id); off != 0 {
+		return t.pos + off
+	}
+	return 0
+}
+
+func (t fbTab) deref(id int) int {
+	pos := t.field(id)
+	return pos + t.u32(pos)
+}
+
+func (t fbTab) table(id int) fbTab { return fbTab{t.buf, t.deref(id)} }
+
+func (t fbTab) str(id int) string {
+	pos := t.deref(id)
+	return string(t.buf[pos+4 : pos+4+t.u32(pos)])
+}
+
+type arrowMessage struct {
+	headerType int
+	header     fbTab
+	body       []byte
+}
+
+func readArrowStream(t *testing.T, data []byte) []arrowMessage {
+	var messages []arrowMessage
+	for pos := 0; ; {
+		if !bytes.Equal(data[pos:pos+4], arrowContinuation) {
+			t.Fatalf("Missing continuation marker at %d", pos)
+		}
+		length := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		if length == 0 {
+			if pos+8 != len(data) {
+				t.Fatalf("Data after end of stream")
+			}
+			return messages
+		}
+		if (8+length)%8 != 0 {
+			t.Fatalf("Metadata length %d leaves the body unaligned", length)
+		}
+		meta := data[pos+8 : pos+8+length]
+		root := fbTab{meta, int(binary.LittleEndian.Uint32(meta))}
+		if v := root.u16(root.field(0)); v != arrowMetadataV5 {
+			t.Fatalf("Expected metadata version V5, got %d", v)
+		}
+		bodyLength := int(binary.LittleEndian.Uint64(meta[root.field(3):]))
+		start := pos + 8 + length
+		messages = append(messages, arrowMessage{
+			headerType: int(meta[root.field(1)]),
+			header:     root.table(2),
+			body:       data[start : start+bodyLength],
+		})
+		pos = start + bodyLength
+	}
+}
+
+func TestTradeArrowStream(t *testing.T) {
+	tradeTime := time.Date(2025, 1, 1, 12, 0, 0, 500, time.UTC)
+	var out bytes.Buffer
+	w := NewTradeArrowWriter(&out)
+	err := w.Write([]TradeUpdate{
+		{Symbol: "BTC-USD", TradeTime: tradeTime, ReceivedAt: tradeTime, Price: "101.5", Size: "0.25", AggressorSide: "1", SeqNum: 7, MdReqId: "md_1", IsSnapshot: true},
+		{Symbol: "ETH-USD", ReceivedAt: tradeTime, Price: "", Size: "2", SeqNum: 8, MdReqId: "md_1"},
+	})
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	messages := readArrowStream(t, out.Bytes())
+	if len(messages) != 2 || messages[0].headerType != arrowHeaderSchema || messages[1].headerType != arrowHeaderRecordBatch {
+		t.Fatalf("Expected schema then record batch, got %+v", messages)
+	}
+
+	// Schema: field names and type tags in order
+	schema := messages[0].header
+	fields := schema.deref(1)
+	if n := schema.u32(fields); n != len(tradeArrowFields) {
+		t.Fatalf("Expected %d fields, got %d", len(tradeArrowFields), n)
+	}
+	for i, want := range tradeArrowFields {
+		slot := fields + 4 + 4*i
+		field := fbTab{schema.buf, slot + schema.u32(slot)}
+		wantTag, _ := want.typ.flatbuffer()
+		if field.str(0) != want.name || schema.buf[field.field(2)] != wantTag {
+			t.Fatalf("Field %d: got %s type %d, want %s type %d", i, field.str(0), schema.buf[field.field(2)], want.name, wantTag)
+		}
+	}
+
+	// Record batch: two rows, buffers 8-aligned inside the body
+	batch := messages[1].header
+	if rows := binary.LittleEndian.Uint64(batch.buf[batch.field(0):]); rows != 2 {
+		t.Fatalf("Expected 2 rows, got %d", rows)
+	}
+	nodes, buffers := batch.deref(1), batch.deref(2)
+	buffer := func(i int) []byte {
+		pos := buffers + 4 + 16*i
+		offset := int(binary.LittleEndian.Uint64(batch.buf[pos:]))
+		if offset%8 != 0 {
+			t.Fatalf("Buffer %d is not 8-byte aligned", i)
+		}
+		return messages[1].body[offset : offset+int(binary.LittleEndian.Uint64(batch.buf[pos+8:]))]
+	}
+	nullCount := func(column int) uint64 {
+		return binary.LittleEndian.Uint64(batch.buf[nodes+4+16*column+8:])
+	}
+
+	// symbol: validity, offsets, data
+	offsets, symbols := buffer(1), buffer(2)
+	if got := string(symbols[binary.LittleEndian.Uint32(offsets[4:]):binary.LittleEndian.Uint32(offsets[8:])]); got != "ETH-USD" {
+		t.Fatalf("Expected second symbol ETH-USD, got %q", got)
+	}
+	// trade_time: second row is null
+	if nullCount(1) != 1 || buffer(3)[0] != 0b01 {
+		t.Fatalf("Expected a null trade_time in row 2")
+	}
+	if ns := int64(binary.LittleEndian.Uint64(buffer(4))); ns != tradeTime.UnixNano() {
+		t.Fatalf("Expected trade_time %d, got %d", tradeTime.UnixNano(), ns)
+	}
+	// price: buffers 7 (validity) and 8
+	if nullCount(3) != 1 || math.Float64frombits(binary.LittleEndian.Uint64(buffer(8))) != 101.5 {
+		t.Fatalf("Unexpected price column")
+	}
+	// is_snapshot is the last column, so its values bitmap is the last buffer
+	if bits := buffer(batch.u32(buffers) - 1); bits[0] != 0b01 {
+		t.Fatalf("Expected is_snapshot bits 01, got %b", bits[0])
+	}
+}
+
+func TestArrowStreamWithoutBatches(t *testing.T) {
+	var out bytes.Buffer
+	w := NewBookArrowWriter(&out)
+	if err := w.Write(nil); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if messages := readArrowStream(t, out.Bytes()); len(messages) != 1 || messages[0].headerType != arrowHeaderSchema {
+		t.Fatalf("Expected only a schema message, got %d messages", len(messages))
+	}
+}
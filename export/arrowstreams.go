@@ -0,0 +1,147 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package export
+
+import (
+	"io"
+	"strconv"
+	"time"
+)
+
+// TradeUpdate is one trade entry from a market data message. Price and size are parsed to float64;
+// values that don't parse, and a zero TradeTime, are written as nulls.
+type TradeUpdate struct {
+	Symbol         string
+	TradeTime      time.Time
+	ReceivedAt     time.Time
+	Price          string
+	Size           string
+	AggressorSide  string
+	TradeCondition string
+	SeqNum         int64
+	MdReqId        string
+	IsSnapshot     bool
+}
+
+var tradeArrowFields = []arrowField{
+	{name: "symbol", typ: arrowUtf8},
+	{name: "trade_time", typ: arrowTimestamp, nullable: true},
+	{name: "received_at", typ: arrowTimestamp},
+	{name: "price", typ: arrowFloat64, nullable: true},
+	{name: "size", typ: arrowFloat64, nullable: true},
+	{name: "aggressor_side", typ: arrowUtf8},
+	{name: "trade_condition", typ: arrowUtf8},
+	{name: "seq_num", typ: arrowInt64},
+	{name: "md_req_id", typ: arrowUtf8},
+	{name: "is_snapshot", typ: arrowBool},
+}
+
+// BookUpdate is one bid or offer entry from a snapshot or incremental refresh
+type BookUpdate struct {
+	Symbol       string
+	ReceivedAt   time.Time
+	Side         string // "bid" or "offer"
+	Price        string
+	Size         string
+	Position     int64
+	EntryId      string
+	UpdateAction string // 0=New, 1=Change, 2=Delete; empty in snapshots
+	SeqNum       int64
+	MdReqId      string
+	IsSnapshot   bool
+}
+
+var bookArrowFields = []arrowField{
+	{name: "symbol", typ: arrowUtf8},
+	{name: "received_at", typ: arrowTimestamp},
+	{name: "side", typ: arrowUtf8},
+	{name: "price", typ: arrowFloat64, nullable: true},
+	{name: "size", typ: arrowFloat64, nullable: true},
+	{name: "position", typ: arrowInt64},
+	{name: "entry_id", typ: arrowUtf8},
+	{name: "update_action", typ: arrowUtf8},
+	{name: "seq_num", typ: arrowInt64},
+	{name: "md_req_id", typ: arrowUtf8},
+	{name: "is_snapshot", typ: arrowBool},
+}
+
+// TradeArrowWriter writes trades as an Arrow IPC stream, one record batch per Write
+type TradeArrowWriter struct {
+	stream arrowStream
+}
+
+func NewTradeArrowWriter(w io.Writer) *TradeArrowWriter {
+	return &TradeArrowWriter{stream: arrowStream{w: w, fields: tradeArrowFields}}
+}
+
+func (t *TradeArrowWriter) Write(trades []TradeUpdate) error {
+	c := t.stream.columns()
+	for _, tr := range trades {
+		c[0].appendString(tr.Symbol)
+		c[1].appendTime(tr.TradeTime)
+		c[2].appendTime(tr.ReceivedAt)
+		c[3].appendFloat64(parseFloat(tr.Price))
+		c[4].appendFloat64(parseFloat(tr.Size))
+		c[5].appendString(tr.AggressorSide)
+		c[6].appendString(tr.TradeCondition)
+		c[7].appendInt64(tr.SeqNum)
+		c[8].appendString(tr.MdReqId)
+		c[9].appendBool(tr.IsSnapshot)
+	}
+	return t.stream.writeBatch(c)
+}
+
+// Close ends the stream; it does not close the underlying writer
+func (t *TradeArrowWriter) Close() error {
+	return t.stream.close()
+}
+
+// BookArrowWriter writes book entries as an Arrow IPC stream, one record batch per Write
+type BookArrowWriter struct {
+	stream arrowStream
+}
+
+func NewBookArrowWriter(w io.Writer) *BookArrowWriter {
+	return &BookArrowWriter{stream: arrowStream{w: w, fields: bookArrowFields}}
+}
+
+func (b *BookArrowWriter) Write(updates []BookUpdate) error {
+	c := b.stream.columns()
+	for _, u := range updates {
+		c[0].appendString(u.Symbol)
+		c[1].appendTime(u.ReceivedAt)
+		c[2].appendString(u.Side)
+		c[3].appendFloat64(parseFloat(u.Price))
+		c[4].appendFloat64(parseFloat(u.Size))
+		c[5].appendInt64(u.Position)
+		c[6].appendString(u.EntryId)
+		c[7].appendString(u.UpdateAction)
+		c[8].appendInt64(u.SeqNum)
+		c[9].appendString(u.MdReqId)
+		c[10].appendBool(u.IsSnapshot)
+	}
+	return b.stream.writeBatch(c)
+}
+
+// Close ends the stream; it does not close the underlying writer
+func (b *BookArrowWriter) Close() error {
+	return b.stream.close()
+}
+
+func parseFloat(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
@@ -0,0 +1,63 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// BookLevel is one exported bid or offer
+type BookLevel struct {
+	Price     string `json:"price"`
+	Size      string `json:"size"`
+	NumOrders string `json:"numOrders,omitempty"`
+	EntryId   string `json:"entryId,omitempty"`
+}
+
+// BookSnapshot is an order book at one point in time, best levels first on each side
+type BookSnapshot struct {
+	Symbol  string      `json:"symbol"`
+	Time    string      `json:"time"`   // Last update applied to the book, RFC 3339 UTC
+	Source  string      `json:"source"` // "live" (in-memory book) or "database" (reconstructed from stored entries)
+	Crossed bool        `json:"crossed"`
+	Bids    []BookLevel `json:"bids"`
+	Offers  []BookLevel `json:"offers"`
+}
+
+// WriteBookSnapshotFile writes the snapshot to a .json file
+func WriteBookSnapshotFile(path string, snapshot BookSnapshot) error {
+	if format, err := FormatFromPath(path); err != nil || format != FormatJson {
+		return fmt.Errorf("book export file %q must end in .json", path)
+	}
+	return writeFile(path, func(w io.Writer) error {
+		return WriteBookSnapshot(w, snapshot)
+	})
+}
+
+func WriteBookSnapshot(w io.Writer, snapshot BookSnapshot) error {
+	if snapshot.Bids == nil {
+		snapshot.Bids = []BookLevel{}
+	}
+	if snapshot.Offers == nil {
+		snapshot.Offers = []BookLevel{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snapshot)
+}
@@ -0,0 +1,121 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package export writes captured market data to files for downstream tooling
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatCsv  = "csv"
+	FormatJson = "json"
+)
+
+// FormatFromPath picks the file format from the extension
+func FormatFromPath(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return FormatCsv, nil
+	case ".json":
+		return FormatJson, nil
+	default:
+		return "", fmt.Errorf("unsupported export file %q (expected .csv or .json)", path)
+	}
+}
+
+// CandleRecord is one exported OHLCV bar. Prices and sizes are kept as strings at exchange precision.
+type CandleRecord struct {
+	Symbol   string `json:"symbol"`
+	Interval string `json:"interval"`
+	Start    string `json:"start"` // RFC 3339, UTC
+	Open     string `json:"open"`
+	High     string `json:"high"`
+	Low      string `json:"low"`
+	Close    string `json:"close"`
+	Volume   string `json:"volume"`
+	Trades   int64  `json:"trades"`
+}
+
+var candleCsvHeader = []string{"symbol", "interval", "start", "open", "high", "low", "close", "volume", "trades"}
+
+func (r CandleRecord) csvRow() []string {
+	return []string{r.Symbol, r.Interval, r.Start, r.Open, r.High, r.Low, r.Close, r.Volume, strconv.FormatInt(r.Trades, 10)}
+}
+
+// WriteCandlesFile writes candles to path as CSV or JSON depending on its extension
+func WriteCandlesFile(path string, candles []CandleRecord) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, func(w io.Writer) error {
+		return WriteCandles(w, format, candles)
+	})
+}
+
+// WriteCandles writes candles as CSV (with a header row) or as a JSON array
+func WriteCandles(w io.Writer, format string, candles []CandleRecord) error {
+	switch format {
+	case FormatCsv:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(candleCsvHeader); err != nil {
+			return err
+		}
+		for _, c := range candles {
+			if err := cw.Write(c.csvRow()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatJson:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(candles)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// writeFile writes through a temporary file in the same directory so readers never see a partial export
+func writeFile(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testCandles = []CandleRecord{
+	{Symbol: "BTC-USD", Interval: "5m0s", Start: "2025-01-01T12:00:00Z", Open: "100.00", High: "103.25",
+		Low: "99.75", Close: "101.50", Volume: "3.75", Trades: 4},
+}
+
+func TestWriteCandlesCsv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bars.csv")
+	if err := WriteCandlesFile(path, testCandles); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || lines[0] != "symbol,interval,start,open,high,low,close,volume,trades" {
+		t.Fatalf("Unexpected CSV:\n%s", data)
+	}
+	if lines[1] != "BTC-USD,5m0s,2025-01-01T12:00:00Z,100.00,103.25,99.75,101.50,3.75,4" {
+		t.Fatalf("Unexpected row %q", lines[1])
+	}
+}
+
+func TestWriteCandlesJson(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bars.json")
+	if err := WriteCandlesFile(path, testCandles); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	var decoded []CandleRecord
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded) != 1 || decoded[0] != testCandles[0] {
+		t.Fatalf("Unexpected JSON %s (%v)", data, err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Fatalf("Expected only the export file, got %d entries", len(entries))
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	if _, err := FormatFromPath("bars.txt"); err == nil {
+		t.Fatal("Expected error for unsupported extension")
+	}
+	if format, _ := FormatFromPath("BARS.CSV"); format != FormatCsv {
+		t.Fatalf("Expected csv, got %q", format)
+	}
+}
+
+func TestWriteBookSnapshotFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := WriteBookSnapshotFile(filepath.Join(dir, "book.csv"), BookSnapshot{}); err == nil {
+		t.Fatal("Expected error for a non-JSON book export")
+	}
+
+	path := filepath.Join(dir, "book.json")
+	snapshot := BookSnapshot{Symbol: "BTC-USD", Source: "live", Bids: []BookLevel{{Price: "100", Size: "1"}}}
+	if err := WriteBookSnapshotFile(path, snapshot); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if offers, ok := decoded["offers"].([]interface{}); !ok || len(offers) != 0 {
+		t.Fatalf("Expected empty offers array, got %v", decoded["offers"])
+	}
+}
@@ -0,0 +1,157 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package export
+
+import "encoding/binary"
+
+// A minimal flatbuffers encoder for Arrow IPC metadata. Objects are laid out front to back: each table
+// is preceded by its vtable and its children follow it, with offsets patched in once a child is placed.
+
+type fbBuilder struct {
+	buf []byte
+}
+
+func (b *fbBuilder) pad(prefix, align int) {
+	for (len(b.buf)+prefix)%align != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// link points the uoffset at pos to the object written by child
+func (b *fbBuilder) link(pos int, child fbObject) {
+	target := child.writeTo(b)
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(target-pos))
+}
+
+type fbObject interface {
+	writeTo(b *fbBuilder) int // Returns the position references should point at
+}
+
+// fbField is one table slot holding either an inline scalar or a reference to a child object
+type fbField struct {
+	id     int
+	scalar []byte
+	child  fbObject
+}
+
+type fbTable []fbField
+
+func (t fbTable) writeTo(b *fbBuilder) int {
+	slots := 0
+	offsets := make([]int, len(t))
+	size := 4 // soffset to the vtable
+	for i, f := range t {
+		n := 4
+		if f.child == nil {
+			n = len(f.scalar)
+		}
+		for size%n != 0 {
+			size++
+		}
+		offsets[i] = size
+		size += n
+		if f.id >= slots {
+			slots = f.id + 1
+		}
+	}
+
+	b.pad(0, 2)
+	vtable := len(b.buf)
+	b.buf = append(b.buf, make([]byte, 4+2*slots)...)
+	binary.LittleEndian.PutUint16(b.buf[vtable:], uint16(4+2*slots))
+	binary.LittleEndian.PutUint16(b.buf[vtable+2:], uint16(size))
+	for i, f := range t {
+		binary.LittleEndian.PutUint16(b.buf[vtable+4+2*f.id:], uint16(offsets[i]))
+	}
+
+	b.pad(0, 8)
+	table := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(b.buf[table:], uint32(int32(table-vtable)))
+	for i, f := range t {
+		if f.child == nil {
+			copy(b.buf[table+offsets[i]:], f.scalar)
+		}
+	}
+	for i, f := range t {
+		if f.child != nil {
+			b.link(table+offsets[i], f.child)
+		}
+	}
+	return table
+}
+
+// fbVector is a vector of tables or strings
+type fbVector []fbObject
+
+func (v fbVector) writeTo(b *fbBuilder) int {
+	b.pad(0, 4)
+	pos := len(b.buf)
+	b.buf = append(b.buf, make([]byte, 4+4*len(v))...)
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(len(v)))
+	for i, child := range v {
+		b.link(pos+4+4*i, child)
+	}
+	return pos
+}
+
+// fbStructVector is a vector of 8-byte aligned structs already encoded back to back
+type fbStructVector struct {
+	count int
+	data  []byte
+}
+
+func (v fbStructVector) writeTo(b *fbBuilder) int {
+	b.pad(4, 8)
+	pos := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(v.count))
+	b.buf = append(b.buf, v.data...)
+	return pos
+}
+
+type fbString string
+
+func (s fbString) writeTo(b *fbBuilder) int {
+	b.pad(0, 4)
+	pos := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(s)))
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return pos
+}
+
+// fbRoot encodes a buffer whose root object is table, padded to 8 bytes
+func fbRoot(table fbTable) []byte {
+	b := &fbBuilder{buf: make([]byte, 4)}
+	b.link(0, table)
+	b.pad(0, 8)
+	return b.buf
+}
+
+func fbUint8(v uint8) []byte { return []byte{v} }
+
+func fbInt16(v int16) []byte { return binary.LittleEndian.AppendUint16(nil, uint16(v)) }
+
+func fbInt32(v int32) []byte { return binary.LittleEndian.AppendUint32(nil, uint32(v)) }
+
+func fbInt64(v int64) []byte { return binary.LittleEndian.AppendUint64(nil, uint64(v)) }
+
+func fbBool(v bool) []byte {
+	if v {
+		return []byte{1}
+	}
+	return []byte{0}
+}
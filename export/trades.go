@@ -0,0 +1,128 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const FormatArrow = "arrow"
+
+// TradesFormatFromPath picks the trade export format from the extension: .csv, .json or .arrow
+func TradesFormatFromPath(path string) (string, error) {
+	if strings.EqualFold(filepath.Ext(path), ".arrow") {
+		return FormatArrow, nil
+	}
+	if format, err := FormatFromPath(path); err == nil {
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported trade export file %q (expected .csv, .json or .arrow)", path)
+}
+
+// tradeRecord is a trade as written to CSV and JSON; times are RFC 3339 UTC, empty when unknown
+type tradeRecord struct {
+	Symbol         string `json:"symbol"`
+	TradeTime      string `json:"tradeTime"`
+	ReceivedAt     string `json:"receivedAt"`
+	Price          string `json:"price"`
+	Size           string `json:"size"`
+	AggressorSide  string `json:"aggressorSide"`
+	TradeCondition string `json:"tradeCondition"`
+	SeqNum         int64  `json:"seqNum"`
+	MdReqId        string `json:"mdReqId"`
+	IsSnapshot     bool   `json:"isSnapshot"`
+}
+
+var tradeCsvHeader = []string{"symbol", "trade_time", "received_at", "price", "size", "aggressor_side",
+	"trade_condition", "seq_num", "md_req_id", "is_snapshot"}
+
+func newTradeRecord(t TradeUpdate) tradeRecord {
+	return tradeRecord{
+		Symbol:         t.Symbol,
+		TradeTime:      formatExportTime(t.TradeTime),
+		ReceivedAt:     formatExportTime(t.ReceivedAt),
+		Price:          t.Price,
+		Size:           t.Size,
+		AggressorSide:  t.AggressorSide,
+		TradeCondition: t.TradeCondition,
+		SeqNum:         t.SeqNum,
+		MdReqId:        t.MdReqId,
+		IsSnapshot:     t.IsSnapshot,
+	}
+}
+
+func (r tradeRecord) csvRow() []string {
+	return []string{r.Symbol, r.TradeTime, r.ReceivedAt, r.Price, r.Size, r.AggressorSide, r.TradeCondition,
+		strconv.FormatInt(r.SeqNum, 10), r.MdReqId, strconv.FormatBool(r.IsSnapshot)}
+}
+
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// WriteTradesFile writes trades to path as CSV, JSON or an Arrow IPC stream depending on its extension
+func WriteTradesFile(path string, trades []TradeUpdate) error {
+	format, err := TradesFormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, func(w io.Writer) error {
+		return WriteTrades(w, format, trades)
+	})
+}
+
+func WriteTrades(w io.Writer, format string, trades []TradeUpdate) error {
+	switch format {
+	case FormatArrow:
+		aw := NewTradeArrowWriter(w)
+		if err := aw.Write(trades); err != nil {
+			return err
+		}
+		return aw.Close()
+	case FormatCsv:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(tradeCsvHeader); err != nil {
+			return err
+		}
+		for _, t := range trades {
+			if err := cw.Write(newTradeRecord(t).csvRow()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatJson:
+		records := make([]tradeRecord, 0, len(trades))
+		for _, t := range trades {
+			records = append(records, newTradeRecord(t))
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
@@ -0,0 +1,297 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+// AdminApi serves HTTP endpoints for driving a running client without a TTY:
+//
+//	GET    /subscriptions              live subscriptions
+//	POST   /subscriptions              {"args": "BTC-USD --subscribe --l1"}, the arguments of an md command
+//	DELETE /subscriptions/{id}         unsubscribe a reqId (md_...) or every subscription of a symbol
+//	PUT    /log/verbose                {"verbose": true} renders every FIX message as a tag table
+//	POST   /jobs/{name}/run            run an export job now
+//	GET    /trades?symbol=BTC-USD      a page of trades; follow nextCursor with &cursor= for the next
+//
+// Every request needs "Authorization: Bearer <Token>".
+type AdminApi struct {
+	Listen     string
+	Token      string
+	SetVerbose func(verbose bool) // Switches verbose FIX session logs; nil makes /log/verbose unavailable
+}
+
+// StartAdminApi serves api until StopBackground
+func (a *FixApp) StartAdminApi(api *AdminApi) error {
+	if api.Token == "" {
+		return errors.New("the admin API needs a token")
+	}
+	listener, err := net.Listen("tcp", api.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to start the admin API: %v", err)
+	}
+	srv := &http.Server{Handler: a.adminHandler(api), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-a.done
+		srv.Close()
+	}()
+	go func() {
+		if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Admin API stopped: %v", err)
+		}
+	}()
+	log.Printf("Admin API listening on %s", listener.Addr())
+	return nil
+}
+
+func (a *FixApp) adminHandler(api *AdminApi) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /subscriptions", a.apiListSubscriptions)
+	mux.HandleFunc("POST /subscriptions", a.apiSubscribe)
+	mux.HandleFunc("DELETE /subscriptions/{id}", a.apiUnsubscribe)
+	mux.HandleFunc("PUT /log/verbose", func(w http.ResponseWriter, r *http.Request) {
+		apiSetVerbose(w, r, api.SetVerbose)
+	})
+	mux.HandleFunc("POST /jobs/{name}/run", a.apiRunJob)
+	mux.HandleFunc("GET /trades", a.apiListTrades)
+
+	want := []byte("Bearer " + api.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeApiError(w, http.StatusUnauthorized, errors.New("missing or wrong bearer token"))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (a *FixApp) apiListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	bySymbol := a.TradeStore.GetSubscriptionsBySymbol()
+	subs := []subscriptionJson{}
+	for _, symbol := range sortedSymbols(bySymbol) {
+		for _, sub := range bySymbol[symbol] {
+			subs = append(subs, newSubscriptionJson(sub))
+		}
+	}
+	writeApiJson(w, http.StatusOK, subs)
+}
+
+func (a *FixApp) apiSubscribe(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Args string `json:"args"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeApiError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %v", err))
+		return
+	}
+	args := strings.Fields(body.Args)
+	if len(args) == 0 {
+		writeApiError(w, http.StatusBadRequest, invalidRequest("args must hold md arguments, e.g. BTC-USD --subscribe --l1"))
+		return
+	}
+	if !a.IsConnected() {
+		writeApiError(w, http.StatusServiceUnavailable, ErrNotConnected)
+		return
+	}
+
+	a.remoteMu.Lock()
+	defer a.remoteMu.Unlock()
+	before := a.TradeStore.GetSubscriptionStatus()
+	if err := a.mdRequest(a.consoleOutput(), append([]string{"md"}, args...)); err != nil {
+		writeApiError(w, apiErrorStatus(err), err)
+		return
+	}
+	reqIds := []string{}
+	for reqId := range a.TradeStore.GetSubscriptionStatus() {
+		if _, ok := before[reqId]; !ok {
+			reqIds = append(reqIds, reqId)
+		}
+	}
+	writeApiJson(w, http.StatusOK, map[string][]string{"reqIds": reqIds})
+}
+
+func (a *FixApp) apiUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	a.remoteMu.Lock()
+	defer a.remoteMu.Unlock()
+	var err error
+	if strings.HasPrefix(id, "md_") {
+		err = a.sendUnsubscribeByReqId(a.consoleOutput(), id)
+	} else {
+		err = a.sendUnsubscribeBySymbol(a.consoleOutput(), strings.ToUpper(id))
+	}
+	if err != nil {
+		writeApiError(w, apiErrorStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiSetVerbose(w http.ResponseWriter, r *http.Request, setVerbose func(bool)) {
+	if setVerbose == nil {
+		writeApiError(w, http.StatusNotImplemented, errors.New("verbose logging cannot be switched in this client"))
+		return
+	}
+	var body struct {
+		Verbose *bool `json:"verbose"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Verbose == nil {
+		writeApiError(w, http.StatusBadRequest, errors.New(`body must be {"verbose": true} or {"verbose": false}`))
+		return
+	}
+	setVerbose(*body.Verbose)
+	log.Printf("Admin API set verbose FIX logs %v", *body.Verbose)
+	writeApiJson(w, http.StatusOK, map[string]bool{"verbose": *body.Verbose})
+}
+
+func (a *FixApp) apiRunJob(w http.ResponseWriter, r *http.Request) {
+	s := a.findJob(r.PathValue("name"))
+	if s == nil {
+		writeApiError(w, http.StatusNotFound, invalidRequest("no job named %s", r.PathValue("name")))
+		return
+	}
+	log.Printf("Admin API running job %s", s.job.Name)
+	go a.runJob(s, time.Now().UTC().Truncate(time.Second))
+	writeApiJson(w, http.StatusAccepted, map[string]string{"job": s.job.Name})
+}
+
+const (
+	defaultApiPageSize = 100
+	maxApiPageSize     = 1000
+)
+
+type tradePageJson struct {
+	Trades     []Trade `json:"trades"`
+	NextCursor string  `json:"nextCursor,omitempty"` // Absent on the last page
+}
+
+// apiListTrades pages through stored trades (source=db, the default when persisting) or the
+// entries held in memory (source=memory). Query parameters: symbol (required for db), limit
+// (default 100, at most 1000), cursor (nextCursor of the previous page), and for db from/to as
+// RFC 3339 times or dates.
+func (a *FixApp) apiListTrades(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	symbol := strings.ToUpper(q.Get("symbol"))
+	limit := defaultApiPageSize
+	if value := q.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxApiPageSize {
+			writeApiError(w, http.StatusBadRequest, invalidRequest("limit must be 1 to %d", maxApiPageSize))
+			return
+		}
+		limit = n
+	}
+	source := q.Get("source")
+	if source == "" {
+		source = "memory"
+		if a.Db != nil {
+			source = "db"
+		}
+	}
+
+	var page tradePageJson
+	var err error
+	switch source {
+	case "memory":
+		if q.Has("from") || q.Has("to") {
+			err = invalidRequest("from and to need source=db")
+			break
+		}
+		page.Trades, page.NextCursor, err = a.TradeStore.TradesPage(symbol, q.Get("cursor"), limit)
+	case "db":
+		page, err = a.storedTradesPage(symbol, q.Get("from"), q.Get("to"), q.Get("cursor"), limit)
+	default:
+		err = invalidRequest("source must be db or memory")
+	}
+	if err != nil {
+		writeApiError(w, apiErrorStatus(err), err)
+		return
+	}
+	writeApiJson(w, http.StatusOK, page)
+}
+
+func (a *FixApp) storedTradesPage(symbol, from, to, cursor string, limit int) (tradePageJson, error) {
+	if a.Db == nil {
+		return tradePageJson{}, invalidRequest("persistence is disabled; use source=memory")
+	}
+	if symbol == "" {
+		return tradePageJson{}, invalidRequest("symbol is required")
+	}
+	var r database.TimeRange
+	for _, bound := range []struct {
+		value string
+		t     *time.Time
+	}{{from, &r.From}, {to, &r.To}} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := parseExportTime(bound.value)
+		if err != nil {
+			return tradePageJson{}, invalidRequest("invalid time %q (RFC 3339 or YYYY-MM-DD)", bound.value)
+		}
+		*bound.t = t
+	}
+
+	stored, err := a.Db.QueryTradesPage(symbol, r, cursor, limit)
+	if errors.Is(err, database.ErrInvalidCursor) {
+		return tradePageJson{}, invalidRequest("invalid cursor %q", cursor)
+	}
+	if err != nil {
+		return tradePageJson{}, storageError("failed to query trades", err)
+	}
+	page := tradePageJson{Trades: make([]Trade, len(stored.Trades)), NextCursor: stored.Next}
+	for i, row := range stored.Trades {
+		page.Trades[i] = replayedTrade(row)
+		page.Trades[i].Timestamp = row.ReceivedAt
+	}
+	return page, nil
+}
+
+// apiErrorStatus maps the client's errors to HTTP status codes
+func apiErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNotConnected):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrNoSuchSubscription):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func writeApiJson(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeApiError(w http.ResponseWriter, status int, err error) {
+	writeApiJson(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,100 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdminApi(t *testing.T) {
+	app := createTestFixApp()
+	app.TradeStore.AddSubscription("BTC-USD", "1", "md_1")
+	var verbose bool
+	srv := httptest.NewServer(app.adminHandler(&AdminApi{Token: "secret", SetVerbose: func(v bool) { verbose = v }}))
+	defer srv.Close()
+
+	do := func(method, path, token, body string) *http.Response {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		return resp
+	}
+
+	if resp := do("GET", "/subscriptions", "wrong", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 for a wrong token, got %d", resp.StatusCode)
+	}
+
+	resp := do("GET", "/subscriptions", "secret", "")
+	var subs []subscriptionJson
+	if err := json.NewDecoder(resp.Body).Decode(&subs); err != nil || len(subs) != 1 || subs[0].MdReqId != "md_1" {
+		t.Fatalf("Expected the BTC-USD subscription, got %+v (%v)", subs, err)
+	}
+
+	if resp := do("POST", "/subscriptions", "secret", `{"args": "BTC-USD --subscribe --trades"}`); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 while not connected, got %d", resp.StatusCode)
+	}
+	if resp := do("DELETE", "/subscriptions/md_1", "secret", ""); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 while not connected, got %d", resp.StatusCode)
+	}
+
+	if resp := do("PUT", "/log/verbose", "secret", `{"verbose": true}`); resp.StatusCode != http.StatusOK || !verbose {
+		t.Fatalf("Expected verbose logs switched on, got %d", resp.StatusCode)
+	}
+	if resp := do("PUT", "/log/verbose", "secret", `{}`); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected 400 without verbose, got %d", resp.StatusCode)
+	}
+
+	if resp := do("POST", "/jobs/nightly/run", "secret", ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("Expected 404 for an unknown job, got %d", resp.StatusCode)
+	}
+
+	app.TradeStore.AddTrades("BTC-USD", []Trade{{Price: "1"}, {Price: "2"}, {Price: "3"}}, false, "md_1")
+	var page tradePageJson
+	resp = do("GET", "/trades?symbol=btc-usd&limit=2", "secret", "")
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil || len(page.Trades) != 2 || page.NextCursor == "" {
+		t.Fatalf("Expected a first page of 2 with a cursor, got %+v (%v)", page, err)
+	}
+	resp = do("GET", "/trades?symbol=BTC-USD&limit=2&cursor="+page.NextCursor, "secret", "")
+	page = tradePageJson{}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil || len(page.Trades) != 1 || page.Trades[0].Price != "3" || page.NextCursor != "" {
+		t.Fatalf("Expected the last trade without a cursor, got %+v (%v)", page, err)
+	}
+	if resp := do("GET", "/trades?source=db&symbol=BTC-USD", "secret", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected 400 without a database, got %d", resp.StatusCode)
+	}
+}
+
+func TestApiErrorStatus(t *testing.T) {
+	for err, want := range map[error]int{
+		ErrNotConnected:             http.StatusServiceUnavailable,
+		ErrNoSuchSubscription:       http.StatusNotFound,
+		invalidRequest("bad depth"): http.StatusBadRequest,
+		ErrStorage:                  http.StatusInternalServerError,
+	} {
+		if got := apiErrorStatus(err); got != want {
+			t.Fatalf("Expected %d for %v, got %d", want, err, got)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"prime-fix-md-go/analytics"
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/database"
+
+	"github.com/shopspring/decimal"
+)
+
+// Window used by aggregate on when display.aggregateTrades.window is not set
+const defaultAggregateWindow = 250 * time.Millisecond
+
+// tradeAggregation coalesces consecutive streaming trades of a symbol at one price and aggressor
+// into a single print, so very active symbols are readable. Each print is shown once a trade
+// that does not match arrives or the window since its first trade has passed.
+type tradeAggregation struct {
+	mu         sync.Mutex
+	window     time.Duration // 0 when off
+	configured time.Duration // What aggregate on switches to
+	store      bool          // Write closed prints to trade_prints
+	open       map[string]*tradePrint
+	flushOnce  sync.Once
+}
+
+// tradePrint is an aggregated print being built
+type tradePrint struct {
+	trade      Trade // The first trade, with Size the running total and Count the number of trades
+	size       decimal.Decimal
+	opened     time.Time
+	firstTrade time.Time
+	lastTrade  time.Time
+}
+
+// SetTradeAggregation sets the aggregation window, 0 to show every trade, and whether
+// aggregated prints are stored as well
+func (a *FixApp) SetTradeAggregation(window time.Duration, store bool) {
+	g := &a.aggregation
+	g.mu.Lock()
+	g.configured = window
+	g.store = store
+	g.mu.Unlock()
+	a.setAggregateWindow(window)
+}
+
+// setAggregateWindow switches aggregation on or off; switching it off shows the open prints
+func (a *FixApp) setAggregateWindow(window time.Duration) {
+	g := &a.aggregation
+	g.mu.Lock()
+	g.window = window
+	var closed []*tradePrint
+	if window <= 0 {
+		closed = g.closeWhere(func(*tradePrint) bool { return true })
+	}
+	store := g.store
+	g.mu.Unlock()
+	if store {
+		a.storePrints(closed)
+	}
+	a.showPrints(closed)
+}
+
+// aggregateTrades takes the entries of an incremental message and returns what to display now:
+// entries other than trades, plus prints closed by a trade that did not match them. Trades join
+// the open print of their symbol when they match it.
+func (a *FixApp) aggregateTrades(trades []Trade, now time.Time) []Trade {
+	g := &a.aggregation
+	g.mu.Lock()
+	if g.window <= 0 {
+		g.mu.Unlock()
+		return trades
+	}
+
+	var shown []Trade
+	var closed []*tradePrint
+	for _, trade := range trades {
+		if trade.EntryType != constants.MdEntryTypeTrade {
+			shown = append(shown, trade)
+			continue
+		}
+		size, err := analytics.ParseDecimal(trade.Size)
+		if err != nil {
+			shown = append(shown, trade)
+			continue
+		}
+		if p := g.open[trade.Symbol]; p != nil {
+			if p.trade.Price == trade.Price && p.trade.Aggressor == trade.Aggressor && now.Sub(p.opened) < g.window {
+				p.add(trade, size)
+				continue
+			}
+			delete(g.open, trade.Symbol)
+			closed = append(closed, p)
+			shown = append(shown, a.printTrade(p))
+		}
+		if g.open == nil {
+			g.open = make(map[string]*tradePrint)
+			// Started with the first print so apps that never see trades run no loop
+			g.flushOnce.Do(func() { go a.flushPrints() })
+		}
+		g.open[trade.Symbol] = newTradePrint(trade, size, now)
+	}
+	store := g.store
+	g.mu.Unlock()
+
+	if store {
+		a.storePrints(closed)
+	}
+	return shown
+}
+
+func newTradePrint(trade Trade, size decimal.Decimal, now time.Time) *tradePrint {
+	at := trade.EntryTime
+	if at.IsZero() {
+		at = now
+	}
+	p := &tradePrint{trade: trade, size: size, opened: now, firstTrade: at, lastTrade: at}
+	p.trade.Count = 1
+	return p
+}
+
+func (p *tradePrint) add(trade Trade, size decimal.Decimal) {
+	p.size = p.size.Add(size)
+	p.trade.Count++
+	p.trade.Large = p.trade.Large || trade.Large
+	if trade.EntryTime.After(p.lastTrade) {
+		p.lastTrade = trade.EntryTime
+	}
+}
+
+// printTrade is the print as one trade with the total size, marked large when the total is
+func (a *FixApp) printTrade(p *tradePrint) Trade {
+	trade := p.trade
+	if trade.Count > 1 {
+		trade.Size = p.size.String()
+		trade.Large = trade.Large || a.isLargePrint(trade)
+	}
+	return trade
+}
+
+// closeWhere removes and returns the open prints that match; g.mu must be held
+func (g *tradeAggregation) closeWhere(match func(*tradePrint) bool) []*tradePrint {
+	var closed []*tradePrint
+	for symbol, p := range g.open {
+		if match(p) {
+			closed = append(closed, p)
+			delete(g.open, symbol)
+		}
+	}
+	return closed
+}
+
+// flushPrints shows prints whose window has passed without a trade that closes them
+func (a *FixApp) flushPrints() {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.done:
+			return
+		case now := <-ticker.C:
+			g := &a.aggregation
+			g.mu.Lock()
+			closed := g.closeWhere(func(p *tradePrint) bool { return now.Sub(p.opened) >= g.window })
+			store := g.store
+			g.mu.Unlock()
+			if store {
+				a.storePrints(closed)
+			}
+			a.showPrints(closed)
+		}
+	}
+}
+
+func (a *FixApp) showPrints(prints []*tradePrint) {
+	if len(prints) == 0 || a.quietMessage("", true) {
+		return
+	}
+	sort.Slice(prints, func(i, j int) bool { return prints[i].opened.Before(prints[j].opened) })
+	trades := make([]Trade, len(prints))
+	for i, p := range prints {
+		trades[i] = a.printTrade(p)
+	}
+	a.consoleOutput().Updates(a.displayTrades(trades))
+}
+
+// storePrints writes aggregated prints to trade_prints; single trades are only in trades
+func (a *FixApp) storePrints(prints []*tradePrint) {
+	if a.Db == nil {
+		return
+	}
+	for _, p := range prints {
+		if p.trade.Count < 2 {
+			continue
+		}
+		if err := a.Db.StoreTradePrint(database.TradePrintRecord{
+			Symbol:        p.trade.Symbol,
+			Price:         p.trade.Price,
+			Size:          p.size.String(),
+			Count:         p.trade.Count,
+			AggressorSide: p.trade.Aggressor,
+			FirstTrade:    p.firstTrade,
+			LastTrade:     p.lastTrade,
+			MdReqId:       p.trade.MdReqId,
+		}); err != nil {
+			log.Printf("%v", storageError("failed to store trade print", err))
+		}
+	}
+}
+
+func (a *FixApp) handleAggregateRequest(out output, parts []string) {
+	if len(parts) > 1 {
+		switch arg := strings.ToLower(parts[1]); arg {
+		case "on":
+			a.aggregation.mu.Lock()
+			window := a.aggregation.configured
+			a.aggregation.mu.Unlock()
+			if window <= 0 {
+				window = defaultAggregateWindow
+			}
+			a.setAggregateWindow(window)
+		case "off":
+			a.setAggregateWindow(0)
+		default:
+			window, err := time.ParseDuration(arg)
+			if err != nil || window <= 0 {
+				fmt.Fprintln(out.Console(), "Usage: aggregate [on|off|<window>], e.g. aggregate 500ms")
+				return
+			}
+			a.setAggregateWindow(window)
+		}
+	}
+	a.aggregation.mu.Lock()
+	window := a.aggregation.window
+	a.aggregation.mu.Unlock()
+	if window > 0 {
+		out.Info("Trade aggregation on: trades at one price and aggressor within %s are shown as one print (aggregate off to show each)", window)
+	} else {
+		out.Info("Trade aggregation off: every trade is shown")
+	}
+}
@@ -0,0 +1,75 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/constants"
+)
+
+func TestAggregateTrades(t *testing.T) {
+	app := NewFixApp(nil, nil)
+	defer app.StopBackground()
+	var out bytes.Buffer
+	app.Renderer, _ = NewRenderer(OutputPlain, &out)
+
+	trade := func(symbol, price, size, aggressor string) Trade {
+		return Trade{Symbol: symbol, EntryType: constants.MdEntryTypeTrade, Price: price, Size: size, Aggressor: aggressor}
+	}
+	now := time.Now()
+	if shown := app.aggregateTrades([]Trade{trade("BTC-USD", "50000", "1", "1")}, now); len(shown) != 1 {
+		t.Fatalf("Expected trades to pass through with aggregation off, got %+v", shown)
+	}
+
+	app.SetTradeAggregation(time.Hour, false)
+	shown := app.aggregateTrades([]Trade{
+		trade("BTC-USD", "50000", "1", "1"),
+		trade("BTC-USD", "50000", "0.5", "1"),
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeBid, Price: "49999", Size: "2"},
+		trade("ETH-USD", "2000", "3", "2"),
+		trade("BTC-USD", "50000", "0.25", "1"),
+	}, now)
+	if len(shown) != 1 || shown[0].EntryType != constants.MdEntryTypeBid {
+		t.Fatalf("Expected only the bid while prints are open, got %+v", shown)
+	}
+
+	// A trade on the other side closes the BTC print
+	shown = app.aggregateTrades([]Trade{trade("BTC-USD", "50000", "2", "2")}, now)
+	if len(shown) != 1 || shown[0].Size != "1.75" || shown[0].Count != 3 {
+		t.Fatalf("Expected one print of 3 trades totalling 1.75, got %+v", shown)
+	}
+	if line := formatUpdateLine(shown[0]); !strings.Contains(line, "Size: 1.75 (3 trades)") {
+		t.Fatalf("Expected the trade count in the line, got %q", line)
+	}
+
+	// Past the window a matching trade starts a new print
+	shown = app.aggregateTrades([]Trade{trade("ETH-USD", "2000", "1", "2")}, now.Add(time.Hour))
+	if len(shown) != 1 || shown[0].Size != "3" || shown[0].Count != 1 {
+		t.Fatalf("Expected the single ETH trade unchanged, got %+v", shown)
+	}
+
+	app.handleAggregateRequest(app.consoleOutput(), []string{"aggregate", "off"})
+	if n := strings.Count(out.String(), "\n"); n < 2 || !strings.Contains(out.String(), "Trade aggregation off") {
+		t.Fatalf("Expected the open prints to be shown when switched off, got %q", out.String())
+	}
+	if len(app.aggregation.open) != 0 {
+		t.Fatalf("Expected no open prints after switching off")
+	}
+}
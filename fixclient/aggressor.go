@@ -0,0 +1,106 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"sync"
+
+	"prime-fix-md-go/analytics"
+	"prime-fix-md-go/constants"
+
+	"github.com/shopspring/decimal"
+)
+
+// aggressorInference fills in the side of trades sent without AggressorSide (2446)
+type aggressorInference struct {
+	mu   sync.Mutex
+	on   bool
+	last map[string]lastTick // Symbol -> previous trade
+}
+
+// lastTick is the previous trade of a symbol, for the tick rule
+type lastTick struct {
+	price decimal.Decimal
+	side  string // Sent or inferred; empty when neither
+}
+
+// SetAggressorInference turns inference of missing aggressor sides on or off
+func (a *FixApp) SetAggressorInference(on bool) {
+	a.inference.mu.Lock()
+	defer a.inference.mu.Unlock()
+	a.inference.on = on
+	if !on {
+		a.inference.last = nil
+	}
+}
+
+// inferAggressors sets the side of incremental trades that arrived without one, marking it as
+// inferred. A trade above the mid of the book before the message was a buy and one below it a
+// sell. At the mid, or without a two-sided book, the tick rule compares it with the symbol's
+// previous trade: an uptick is a buy, a downtick a sell and an unchanged price repeats the
+// previous side. The first trade of a symbol at the mid stays unknown.
+func (a *FixApp) inferAggressors(trades []Trade) {
+	g := &a.inference
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.on {
+		return
+	}
+
+	for i := range trades {
+		trade := &trades[i]
+		if trade.EntryType != constants.MdEntryTypeTrade {
+			continue
+		}
+		price, err := analytics.ParseDecimal(trade.Price)
+		if err != nil {
+			continue
+		}
+		prev, seen := g.last[trade.Symbol]
+		if trade.Aggressor == "" {
+			trade.Aggressor = a.inferSide(trade.Symbol, price, prev, seen)
+			trade.AggressorInferred = trade.Aggressor != ""
+		}
+		if g.last == nil {
+			g.last = make(map[string]lastTick)
+		}
+		g.last[trade.Symbol] = lastTick{price: price, side: trade.Aggressor}
+	}
+}
+
+func (a *FixApp) inferSide(symbol string, price decimal.Decimal, prev lastTick, seen bool) string {
+	if a.Books != nil {
+		if mid, ok := a.Books.Mid(symbol); ok {
+			switch price.Cmp(mid) {
+			case 1:
+				return "Buy"
+			case -1:
+				return "Sell"
+			}
+		}
+	}
+	if !seen {
+		return ""
+	}
+	switch price.Cmp(prev.price) {
+	case 1:
+		return "Buy"
+	case -1:
+		return "Sell"
+	default:
+		return prev.side
+	}
+}
@@ -0,0 +1,74 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"testing"
+	"time"
+
+	"prime-fix-md-go/constants"
+)
+
+func TestInferAggressors(t *testing.T) {
+	app := createTestFixApp()
+	trade := func(symbol, price, aggressor string) Trade {
+		return Trade{Symbol: symbol, EntryType: constants.MdEntryTypeTrade, Price: price, Size: "1", Aggressor: aggressor}
+	}
+
+	trades := []Trade{trade("BTC-USD", "100", "")}
+	app.inferAggressors(trades)
+	if trades[0].Aggressor != "" || trades[0].AggressorInferred {
+		t.Fatalf("Expected no inference while off, got %+v", trades[0])
+	}
+
+	app.SetAggressorInference(true)
+	trades = []Trade{
+		trade("ETH-USD", "2000", ""),    // First trade, no book: unknown
+		trade("ETH-USD", "2001", ""),    // Uptick
+		trade("ETH-USD", "2001", ""),    // Zero tick keeps the side
+		trade("ETH-USD", "2000.5", ""),  // Downtick
+		trade("ETH-USD", "2000", "Buy"), // Sent sides are kept
+		trade("ETH-USD", "2000", ""),    // and carried by a zero tick
+		{Symbol: "ETH-USD", EntryType: constants.MdEntryTypeBid, Price: "1999"},
+	}
+	app.inferAggressors(trades)
+	for i, want := range []string{"", "Buy", "Buy", "Sell", "Buy", "Buy", ""} {
+		if trades[i].Aggressor != want {
+			t.Fatalf("Trade %d: expected %q, got %q", i, want, trades[i].Aggressor)
+		}
+		if inferred := want != "" && i != 4; trades[i].AggressorInferred != inferred {
+			t.Fatalf("Trade %d: expected inferred=%v", i, inferred)
+		}
+	}
+
+	// With a book, the side comes from the mid before falling back to the tick rule
+	app.Books = NewBookManager()
+	app.Books.ApplySnapshot("BTC-USD", []Trade{
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeBid, Price: "99", Size: "1"},
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeOffer, Price: "101", Size: "1"},
+	}, time.Now())
+	trades = []Trade{trade("BTC-USD", "99", ""), trade("BTC-USD", "100", ""), trade("BTC-USD", "100.5", ""), trade("BTC-USD", "100", "")}
+	app.inferAggressors(trades)
+	for i, want := range []string{"Sell", "Buy", "Buy", "Sell"} {
+		if trades[i].Aggressor != want {
+			t.Fatalf("Book trade %d: expected %q, got %q", i, want, trades[i].Aggressor)
+		}
+	}
+
+	if line := formatUpdateLine(trades[0]); line != "BTC-USD Trade: 99 | Size: 1 | Aggressor: Sell (inferred)" {
+		t.Fatalf("Unexpected line %q", line)
+	}
+}
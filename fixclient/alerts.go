@@ -0,0 +1,173 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"prime-fix-md-go/formatter"
+	"prime-fix-md-go/notify"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// stopping reports whether StopBackground was called, i.e. the logout that follows is ours
+func (a *FixApp) stopping() bool {
+	select {
+	case <-a.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a *FixApp) alertLogout(sid quickfix.SessionID, event string) {
+	if a.stopping() {
+		return
+	}
+	ev := notify.Event{Type: event, Text: sid.String()}
+	if event == notify.EventLogonFailure {
+		ev.Title = "FIX logon failed"
+		ev.Text += ": check credentials and portfolio; the client is exiting"
+	} else {
+		ev.Title = "FIX session disconnected"
+		ev.Text += fmt.Sprintf(": %d subscriptions lost, reconnecting", len(a.TradeStore.GetSubscriptionStatus()))
+	}
+	a.Alerts.Send(ev)
+}
+
+func (a *FixApp) alertReject(rej *ErrRejected, sub *Subscription) {
+	ev := notify.Event{
+		Type:    notify.EventReject,
+		Title:   "Market data request rejected",
+		Text:    rej.Error(),
+		MdReqId: rej.MdReqId,
+	}
+	if sub != nil {
+		ev.Symbol = sub.Symbol
+		ev.Title = fmt.Sprintf("%s subscription rejected", sub.Symbol)
+	}
+	a.Alerts.Send(ev)
+}
+
+func (a *FixApp) alertStale(reqId, symbol, quietFor string, recovered bool) {
+	ev := notify.Event{
+		Type:    notify.EventStale,
+		Title:   fmt.Sprintf("%s market data stalled", symbol),
+		Text:    fmt.Sprintf("no updates for %s (reqId: %s)", quietFor, reqId),
+		Symbol:  symbol,
+		MdReqId: reqId,
+	}
+	if recovered {
+		ev.Title = fmt.Sprintf("%s market data resumed", symbol)
+		ev.Text = fmt.Sprintf("updates resumed (reqId: %s)", reqId)
+		ev.Resolve = true
+	}
+	a.Alerts.Send(ev)
+}
+
+func (a *FixApp) alertUnexpected(msgType string, count int64) {
+	a.Alerts.Send(notify.Event{
+		Type:  notify.EventUnexpected,
+		Title: fmt.Sprintf("Unexpected FIX message type %s", msgType),
+		Text:  fmt.Sprintf("%s has no handler; %d received so far", formatter.MsgTypeName(msgType), count),
+	})
+}
+
+func (a *FixApp) alertRejectBurst(n int, window time.Duration, latest string) {
+	a.Alerts.Send(notify.Event{
+		Type:  notify.EventRejectBurst,
+		Title: "Burst of FIX rejects",
+		Text:  fmt.Sprintf("%d rejects in the last %s; latest: %s", n, window, latest),
+	})
+}
+
+// alertSessionEvent forwards a FIX session timeline entry, for channels that follow the session
+func (a *FixApp) alertSessionEvent(e SessionEvent) {
+	text := e.SessionId
+	if e.Detail != "" {
+		text += ": " + e.Detail
+	}
+	a.Alerts.Send(notify.Event{
+		Type:  notify.EventSession,
+		Time:  e.At.UTC(),
+		Title: fmt.Sprintf("FIX session %s", e.Event),
+		Text:  text,
+	})
+}
+
+// SetProlongedDisconnect raises a prolonged_disconnect alert when the session is still logged out
+// after, and resolves it on the next logon; 0 disables it
+func (a *FixApp) SetProlongedDisconnect(after time.Duration) {
+	a.disconnect.after.Store(int64(after))
+}
+
+// disconnectWatch tracks how long the session has been down
+type disconnectWatch struct {
+	after    atomic.Int64 // time.Duration
+	logons   atomic.Int64 // Counts logons, so a timer from an earlier outage does nothing
+	armed    atomic.Bool  // A timer is running for the current outage; failed reconnects do not restart it
+	reported atomic.Bool  // A prolonged_disconnect alert is open
+	loggedOn atomic.Bool  // Logged on at least once since the client started
+}
+
+// watchDisconnect starts the prolonged disconnect timer when the session first drops
+func (a *FixApp) watchDisconnect(sid quickfix.SessionID) {
+	after := time.Duration(a.disconnect.after.Load())
+	if after <= 0 || a.disconnect.armed.Swap(true) {
+		return
+	}
+	logons := a.disconnect.logons.Load()
+	time.AfterFunc(after, func() {
+		if a.IsConnected() || a.stopping() || a.disconnect.logons.Load() != logons {
+			return
+		}
+		a.disconnect.reported.Store(true)
+		log.Printf("Warning: FIX session %s still logged out after %s", sid, after)
+		a.Alerts.Send(notify.Event{
+			Type:  notify.EventProlongedDisconnect,
+			Title: "FIX session down",
+			Text:  fmt.Sprintf("%s: not logged on for %s", sid, after),
+		})
+	})
+}
+
+// resolveDisconnect ends an open prolonged_disconnect alert once the session is back. The first
+// logon also resolves a logon failure from the previous run, which closes its incident.
+func (a *FixApp) resolveDisconnect(sid quickfix.SessionID) {
+	a.disconnect.logons.Add(1)
+	a.disconnect.armed.Store(false)
+	if !a.disconnect.loggedOn.Swap(true) {
+		a.Alerts.Send(notify.Event{
+			Type:    notify.EventLogonFailure,
+			Title:   "FIX logon succeeded",
+			Text:    sid.String() + ": logged on",
+			Resolve: true,
+		})
+	}
+	if !a.disconnect.reported.Swap(false) {
+		return
+	}
+	a.Alerts.Send(notify.Event{
+		Type:    notify.EventProlongedDisconnect,
+		Title:   "FIX session restored",
+		Text:    sid.String() + ": logged on again",
+		Resolve: true,
+	})
+}
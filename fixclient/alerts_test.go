@@ -0,0 +1,76 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/notify"
+)
+
+type recordingNotifier struct {
+	events []notify.Event
+}
+
+func (r *recordingNotifier) Name() string { return "recording" }
+
+func (r *recordingNotifier) Notify(_ context.Context, ev notify.Event) error {
+	r.events = append(r.events, ev)
+	return nil
+}
+
+func TestStaleAlertAndResolve(t *testing.T) {
+	rec := &recordingNotifier{}
+	app := createTestFixApp()
+	app.Renderer, _ = NewRenderer(OutputPlain, &bytes.Buffer{})
+	app.Alerts = notify.NewDispatcher([]notify.Notifier{rec}, map[string]bool{notify.EventStale: true}, "")
+	app.TradeStore.AddSubscription("BTC-USD", "1", "req-1")
+
+	stale := make(map[string]bool)
+	now := time.Now()
+	app.checkStale(stale, time.Minute, now.Add(2*time.Minute))
+	app.checkStale(stale, time.Minute, now.Add(3*time.Minute))
+	app.checkStale(stale, time.Minute, now)
+	app.Alerts.Close(5 * time.Second)
+
+	if len(rec.events) != 2 {
+		t.Fatalf("Expected a stale alert and its resolution, got %+v", rec.events)
+	}
+	if rec.events[0].Resolve || rec.events[0].Symbol != "BTC-USD" || rec.events[0].MdReqId != "req-1" {
+		t.Fatalf("Unexpected stale alert %+v", rec.events[0])
+	}
+	if !rec.events[1].Resolve {
+		t.Fatalf("Expected the second alert to resolve the first, got %+v", rec.events[1])
+	}
+}
+
+func TestNoDisconnectAlertOnShutdown(t *testing.T) {
+	rec := &recordingNotifier{}
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	app.Alerts = notify.NewDispatcher([]notify.Notifier{rec}, map[string]bool{notify.EventDisconnect: true}, "")
+	app.lastLogonTime = time.Now().Add(-time.Hour)
+
+	app.StopBackground()
+	app.OnLogout(app.SessionId)
+	app.Alerts.Close(5 * time.Second)
+
+	if len(rec.events) != 0 {
+		t.Fatalf("Expected no alert for our own logout, got %+v", rec.events)
+	}
+}
@@ -0,0 +1,105 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"prime-fix-md-go/builder"
+	"prime-fix-md-go/constants"
+)
+
+const (
+	allProductsBatchSize = 50                     // Symbols per MarketDataRequest for md --all
+	allProductsInterval  = 250 * time.Millisecond // Pause between those requests, so the gateway is not flooded
+)
+
+// isAllProductsRequest reports whether an md command names no instrument and asks for --all, which
+// then means every product rather than every entry type. Flags come back without --all.
+func isAllProductsRequest(symbols, flagArgs []string) (bool, []string) {
+	if len(symbols) > 0 || !slices.Contains(flagArgs, "--all") || slices.Contains(flagArgs, "--security-id") {
+		return false, flagArgs
+	}
+	return true, slices.DeleteFunc(slices.Clone(flagArgs), func(arg string) bool { return arg == "--all" })
+}
+
+// allProductSymbols is every product in the catalog, or for an unsubscribe every symbol with
+// a live subscription
+func (a *FixApp) allProductSymbols(subscriptionType string) ([]string, error) {
+	if subscriptionType == constants.SubscriptionRequestTypeUnsubscribe {
+		var symbols []string
+		for symbol := range a.TradeStore.GetSubscriptionsBySymbol() {
+			symbols = append(symbols, symbol)
+		}
+		if len(symbols) == 0 {
+			return nil, errors.New("no active subscriptions")
+		}
+		sort.Strings(symbols)
+		return symbols, nil
+	}
+	if a.Products == nil || a.Products.Len() == 0 {
+		return nil, invalidRequest("md --all needs the product list; set products.symbols or rest.enabled in the config")
+	}
+	return a.Products.Symbols(), nil
+}
+
+// batchInstruments splits instruments into requests of at most size
+func batchInstruments(instruments []builder.Instrument, size int) [][]builder.Instrument {
+	var batches [][]builder.Instrument
+	for len(instruments) > size {
+		batches = append(batches, instruments[:size])
+		instruments = instruments[size:]
+	}
+	if len(instruments) > 0 {
+		batches = append(batches, instruments)
+	}
+	return batches
+}
+
+// waitBetweenBatches paces md --all requests and reports false when the client is stopping
+func (a *FixApp) waitBetweenBatches() bool {
+	select {
+	case <-a.done:
+		return false
+	case <-time.After(allProductsInterval):
+		return true
+	}
+}
+
+// sendAllProducts sends an md --all request as several MarketDataRequests, stopping at the first
+// that fails
+func (a *FixApp) sendAllProducts(out output, instruments []builder.Instrument, flags MdRequestFlags, description string) {
+	batches := batchInstruments(instruments, allProductsBatchSize)
+	out.Info("Requesting %d products in %d requests", len(instruments), len(batches))
+	for i, batch := range batches {
+		if flags.dryRun {
+			a.previewMarketDataRequest(out, batch, flags.subscriptionType, flags.marketDepth, flags.entryTypes, flags.options)
+			continue
+		}
+		if i > 0 && !a.waitBetweenBatches() {
+			return
+		}
+		if _, err := a.sendMarketDataRequestWithOptions(out, batch, flags.subscriptionType, flags.marketDepth, flags.entryTypes,
+			flags.options, flags.label, description); err != nil {
+			out.Error(err)
+			out.Info("Stopped after %d of %d requests", i, len(batches))
+			return
+		}
+	}
+}
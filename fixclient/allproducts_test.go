@@ -0,0 +1,83 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"prime-fix-md-go/builder"
+	"prime-fix-md-go/products"
+)
+
+func TestIsAllProductsRequest(t *testing.T) {
+	all, flags := isAllProductsRequest(nil, []string{"--all", "--subscribe", "--trades"})
+	if !all || strings.Join(flags, " ") != "--subscribe --trades" {
+		t.Fatalf("Expected an all-products request without --all, got %v %v", all, flags)
+	}
+	// With a symbol or SecurityID, --all keeps meaning every entry type
+	if all, flags := isAllProductsRequest([]string{"BTC-USD"}, []string{"--all", "--snapshot"}); all || len(flags) != 2 {
+		t.Fatalf("Expected the --all preset for a symbol, got %v %v", all, flags)
+	}
+	if all, _ := isAllProductsRequest(nil, []string{"--security-id", "X", "--all"}); all {
+		t.Fatal("Expected the --all preset for a SecurityID")
+	}
+}
+
+func TestBatchInstruments(t *testing.T) {
+	instruments := builder.SymbolInstruments(make([]string, 120))
+	batches := batchInstruments(instruments, 50)
+	if len(batches) != 3 || len(batches[0]) != 50 || len(batches[2]) != 20 {
+		t.Fatalf("Expected batches of 50, 50 and 20, got %d batches", len(batches))
+	}
+	if batches := batchInstruments(nil, 50); len(batches) != 0 {
+		t.Fatalf("Expected no batches, got %d", len(batches))
+	}
+}
+
+func TestAllProductsDryRun(t *testing.T) {
+	var buf bytes.Buffer
+	app := newPreviewTestApp(&buf)
+
+	app.handleDirectMdRequest(app.consoleOutput(), []string{"md", "--all", "--snapshot", "--trades", "--dry-run"})
+	if !strings.Contains(buf.String(), "needs the product list") {
+		t.Fatalf("Expected an error without a product list, got:\n%s", buf.String())
+	}
+
+	list := make([]products.Product, 60)
+	for i := range list {
+		list[i].Symbol = fmt.Sprintf("COIN%02d-USD", i)
+	}
+	app.Products.Replace(list)
+	buf.Reset()
+	app.handleDirectMdRequest(app.consoleOutput(), []string{"md", "--all", "--snapshot", "--trades", "--dry-run"})
+
+	out := buf.String()
+	if !strings.Contains(out, "Requesting 60 products in 2 requests") {
+		t.Fatalf("Expected two batches, got:\n%s", out)
+	}
+	if n := strings.Count(out, "35\tMsgType\tV (MarketDataRequest)"); n != 2 {
+		t.Fatalf("Expected 2 previewed requests, got %d", n)
+	}
+	if !strings.Contains(out, "146\tNoRelatedSym\t50") || !strings.Contains(out, "146\tNoRelatedSym\t10") {
+		t.Fatalf("Expected requests of 50 and 10 symbols, got:\n%s", out)
+	}
+	if strings.Contains(out, "269\tMDEntryType\t0") {
+		t.Fatalf("Expected --all to select products, not entry types:\n%s", out)
+	}
+}
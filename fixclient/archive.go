@@ -0,0 +1,55 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"log"
+	"time"
+)
+
+// StartArchiver moves rows older than olderThan from the database into compressed files in dir,
+// now and then every interval, until StopBackground is called
+func (a *FixApp) StartArchiver(dir string, olderThan, every time.Duration) {
+	if a.Db == nil || olderThan <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		for {
+			a.archiveOnce(dir, olderThan)
+			select {
+			case <-a.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (a *FixApp) archiveOnce(dir string, olderThan time.Duration) {
+	stats, err := a.Db.Archive(dir, time.Now().Add(-olderThan))
+	if err != nil {
+		log.Printf("Archiving failed: %v", err)
+		return
+	}
+	if stats.Path != "" {
+		log.Printf("Archived %d trades, %d book entries and %d ohlcv rows to %s",
+			stats.Trades, stats.OrderBook, stats.Ohlcv, stats.Path)
+		a.archived(stats.Path)
+	}
+}
@@ -0,0 +1,81 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+// fakeUploader records uploads instead of sending them
+type fakeUploader struct {
+	uploaded map[string]string // Key -> local path
+}
+
+func (f *fakeUploader) Key(localPath string) string {
+	return "archive/" + filepath.Base(localPath)
+}
+
+func (f *fakeUploader) UploadFile(_ context.Context, localPath, key string) error {
+	if _, err := os.Stat(localPath); err != nil {
+		return err
+	}
+	f.uploaded[key] = localPath
+	return nil
+}
+
+func (f *fakeUploader) Location(key string) string {
+	return "s3://bucket/" + key
+}
+
+func TestArchiveUploadsArchiveFile(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "archive.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	app := createTestFixApp()
+	app.Db = db
+	uploader := &fakeUploader{uploaded: make(map[string]string)}
+
+	// A cutoff in the future archives everything stored so far
+	db.StoreTrade("BTC-USD", "100", "1", "Buy", "20250101-12:00:00", 1, "req", false)
+	app.SetUploader(uploader, true, false)
+	app.archiveOnce(t.TempDir(), -time.Minute)
+	if len(uploader.uploaded) != 0 {
+		t.Fatalf("Expected no upload without upload.archives, got %v", uploader.uploaded)
+	}
+
+	db.StoreTrade("BTC-USD", "101", "1", "Buy", "20250101-12:00:01", 2, "req", false)
+	dir := t.TempDir()
+	app.SetUploader(uploader, false, true)
+	app.archiveOnce(dir, -time.Minute)
+	if len(uploader.uploaded) != 1 {
+		t.Fatalf("Expected the archive to be uploaded, got %v", uploader.uploaded)
+	}
+	for key, path := range uploader.uploaded {
+		if filepath.Dir(path) != dir || !strings.HasSuffix(path, ".db.gz") || key != "archive/"+filepath.Base(path) {
+			t.Fatalf("Expected the compressed archive in %s uploaded under its name, got %s -> %s", dir, key, path)
+		}
+	}
+}
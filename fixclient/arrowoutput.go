@@ -0,0 +1,167 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/export"
+)
+
+// ArrowStdout selects standard output instead of a file for an Arrow stream
+const ArrowStdout = "-"
+
+// ArrowOutput streams trades and book entries as Arrow IPC, one record batch per market data message
+type ArrowOutput struct {
+	mu     sync.Mutex
+	trades *export.TradeArrowWriter
+	book   *export.BookArrowWriter
+	files  []*os.File
+	failed bool
+}
+
+// OpenArrowOutput opens the trade and book streams; an empty path disables that stream and
+// ArrowStdout writes it to stdout. At most one stream can go to stdout.
+func OpenArrowOutput(tradesPath, bookPath string, stdout io.Writer) (*ArrowOutput, error) {
+	if tradesPath == ArrowStdout && bookPath == ArrowStdout {
+		return nil, fmt.Errorf("only one arrow stream can be written to stdout")
+	}
+
+	o := &ArrowOutput{}
+	open := func(path string) (io.Writer, error) {
+		if path == ArrowStdout {
+			return stdout, nil
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			o.Close()
+			return nil, fmt.Errorf("failed to create arrow stream: %v", err)
+		}
+		o.files = append(o.files, f)
+		return f, nil
+	}
+
+	if tradesPath != "" {
+		w, err := open(tradesPath)
+		if err != nil {
+			return nil, err
+		}
+		o.trades = export.NewTradeArrowWriter(w)
+	}
+	if bookPath != "" {
+		w, err := open(bookPath)
+		if err != nil {
+			return nil, err
+		}
+		o.book = export.NewBookArrowWriter(w)
+	}
+	return o, nil
+}
+
+// Write appends the trade and book entries of one message. After a write error (e.g. the reader
+// on stdout went away) the output stops, so the error is only reported once.
+func (o *ArrowOutput) Write(entries []Trade, seqNum string, isSnapshot bool, received time.Time) error {
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.failed {
+		return nil
+	}
+
+	seq, _ := strconv.ParseInt(seqNum, 10, 64)
+	var trades []export.TradeUpdate
+	var book []export.BookUpdate
+	for _, e := range entries {
+		switch e.EntryType {
+		case constants.MdEntryTypeTrade:
+			trades = append(trades, export.TradeUpdate{
+				Symbol:         e.Symbol,
+				TradeTime:      e.EntryTime,
+				ReceivedAt:     received,
+				Price:          e.Price,
+				Size:           e.Size,
+				AggressorSide:  e.Aggressor,
+				TradeCondition: e.TradeCondition,
+				SeqNum:         seq,
+				MdReqId:        e.MdReqId,
+				IsSnapshot:     isSnapshot,
+			})
+		case constants.MdEntryTypeBid, constants.MdEntryTypeOffer:
+			side := "bid"
+			if e.EntryType == constants.MdEntryTypeOffer {
+				side = "offer"
+			}
+			position, _ := strconv.ParseInt(e.Position, 10, 64)
+			book = append(book, export.BookUpdate{
+				Symbol:       e.Symbol,
+				ReceivedAt:   received,
+				Side:         side,
+				Price:        e.Price,
+				Size:         e.Size,
+				Position:     position,
+				EntryId:      e.EntryId,
+				UpdateAction: e.UpdateAction,
+				SeqNum:       seq,
+				MdReqId:      e.MdReqId,
+				IsSnapshot:   isSnapshot,
+			})
+		}
+	}
+
+	var err error
+	if o.trades != nil && len(trades) > 0 {
+		err = o.trades.Write(trades)
+	}
+	if o.book != nil && len(book) > 0 && err == nil {
+		err = o.book.Write(book)
+	}
+	if err != nil {
+		o.failed = true
+		return fmt.Errorf("arrow output stopped: %w", err)
+	}
+	return nil
+}
+
+// Close ends both streams and closes their files
+func (o *ArrowOutput) Close() error {
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var errs []error
+	if o.trades != nil && !o.failed {
+		errs = append(errs, o.trades.Close())
+	}
+	if o.book != nil && !o.failed {
+		errs = append(errs, o.book.Close())
+	}
+	for _, f := range o.files {
+		errs = append(errs, f.Close())
+	}
+	o.files = nil
+	return errors.Join(errs...)
+}
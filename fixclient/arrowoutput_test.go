@@ -0,0 +1,61 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/constants"
+)
+
+func TestArrowOutputSplitsTradesAndBook(t *testing.T) {
+	bookPath := filepath.Join(t.TempDir(), "book.arrow")
+	var stdout bytes.Buffer
+	out, err := OpenArrowOutput(ArrowStdout, bookPath, &stdout)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	entries := []Trade{
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeTrade, Price: "100", Size: "1"},
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeBid, Price: "99", Size: "2", Position: "1"},
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeOpen, Price: "98"},
+	}
+	if err := out.Write(entries, "12", true, time.Now()); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	eos := []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}
+	book, _ := os.ReadFile(bookPath)
+	for name, data := range map[string][]byte{"trades": stdout.Bytes(), "book": book} {
+		if !bytes.HasSuffix(data, eos) || !bytes.Contains(data, []byte("BTC-USD")) {
+			t.Fatalf("Expected a finished %s stream containing BTC-USD, got %d bytes", name, len(data))
+		}
+	}
+}
+
+func TestArrowOutputSingleStdout(t *testing.T) {
+	if _, err := OpenArrowOutput(ArrowStdout, ArrowStdout, &bytes.Buffer{}); err == nil {
+		t.Fatalf("Expected an error when both streams use stdout")
+	}
+}
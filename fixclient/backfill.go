@@ -0,0 +1,62 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"prime-fix-md-go/constants"
+)
+
+// BackfillStats counts what Backfill delivered
+type BackfillStats struct {
+	Messages    int
+	Trades      int
+	BookEntries int
+}
+
+// Backfill writes the stored trades and book entries (snapshots included) for symbols received
+// in [from, to) to the Arrow streams, one record batch per original message and in the order
+// they were received, so data captured before a stream was set up, or while its reader was
+// down, can still be delivered. A zero to means now.
+func (a *FixApp) Backfill(symbols []string, from, to time.Time) (BackfillStats, error) {
+	var stats BackfillStats
+	if a.Arrow == nil {
+		return stats, errors.New("no Arrow stream to backfill; set arrow.trades or arrow.book")
+	}
+	for _, symbol := range symbols {
+		events, err := a.loadReplay(replayQuery{symbol: symbol, from: from, to: to}, true)
+		if err != nil {
+			return stats, err
+		}
+		for _, ev := range events {
+			if err := a.Arrow.Write(ev.entries, strconv.Itoa(ev.seqNum), ev.snapshot, ev.at); err != nil {
+				return stats, err
+			}
+			stats.Messages++
+			for _, e := range ev.entries {
+				if e.EntryType == constants.MdEntryTypeTrade {
+					stats.Trades++
+				} else {
+					stats.BookEntries++
+				}
+			}
+		}
+	}
+	return stats, nil
+}
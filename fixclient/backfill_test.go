@@ -0,0 +1,68 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+func TestBackfill(t *testing.T) {
+	dir := t.TempDir()
+	db, err := database.NewMarketDataDb(filepath.Join(dir, "backfill.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	from := time.Now().Add(-time.Minute)
+	tradeTime := time.Now().UTC().Format("20060102-15:04:05.000")
+	db.StoreOrderBookRecord(database.OrderBookRecord{Symbol: "BTC-USD", Side: "bid", Price: "100", Size: "1", Position: 1, SeqNum: 2, MdReqId: "md_1", IsSnapshot: true})
+	db.StoreOrderBookRecord(database.OrderBookRecord{Symbol: "BTC-USD", Side: "offer", Price: "101", Size: "1", Position: 1, SeqNum: 2, MdReqId: "md_1", IsSnapshot: true})
+	db.StoreOrderBookRecord(database.OrderBookRecord{Symbol: "BTC-USD", Side: "bid", Price: "100", Size: "2", Position: 1, SeqNum: 3, MdReqId: "md_1"})
+	db.StoreTradeRecord(database.TradeRecord{Symbol: "BTC-USD", Price: "100.5", Size: "0.2", TradeTime: tradeTime, SeqNum: 4, MdReqId: "md_1"})
+
+	app := createTestFixApp()
+	app.Db = db
+	if _, err := app.Backfill([]string{"BTC-USD"}, from, time.Time{}); err == nil {
+		t.Fatalf("Expected an error without an Arrow stream")
+	}
+
+	tradesPath, bookPath := filepath.Join(dir, "trades.arrows"), filepath.Join(dir, "book.arrows")
+	app.Arrow, err = OpenArrowOutput(tradesPath, bookPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to open arrow output: %v", err)
+	}
+	stats, err := app.Backfill([]string{"BTC-USD"}, from, time.Time{})
+	if err != nil {
+		t.Fatalf("Backfill failed: %v", err)
+	}
+	if err := app.Arrow.Close(); err != nil {
+		t.Fatalf("Failed to close arrow output: %v", err)
+	}
+	if stats != (BackfillStats{Messages: 3, Trades: 1, BookEntries: 3}) {
+		t.Fatalf("Unexpected stats %+v", stats)
+	}
+	for _, path := range []string{tradesPath, bookPath} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Fatalf("Expected %s to be written (%v)", path, err)
+		}
+	}
+}
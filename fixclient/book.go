@@ -0,0 +1,383 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"prime-fix-md-go/analytics"
+	"prime-fix-md-go/constants"
+
+	"github.com/shopspring/decimal"
+)
+
+// BookLevel is one resting bid or offer in the in-memory book
+type BookLevel struct {
+	Price     string
+	Size      string
+	NumOrders string
+	EntryId   string
+
+	price decimal.Decimal
+}
+
+// OrderBook is the current bids and offers for one symbol, built from snapshots and incremental updates
+type OrderBook struct {
+	Symbol     string
+	LastUpdate time.Time
+
+	bids    map[string]*BookLevel // keyed by MdEntryId, or price when the gateway sends no ids
+	offers  map[string]*BookLevel
+	rptSeq  int64 // Last RptSeq (83) seen; 0 when the gateway does not send it
+	crossed bool
+	bytes   int64 // Estimated size of the levels; see levelBytes
+	trimmed bool  // Deep levels were dropped for the memory budget, so the book is incomplete
+}
+
+func newOrderBook(symbol string) *OrderBook {
+	return &OrderBook{
+		Symbol: symbol,
+		bids:   make(map[string]*BookLevel),
+		offers: make(map[string]*BookLevel),
+	}
+}
+
+// Bids returns levels best (highest) first
+func (b *OrderBook) Bids() []BookLevel {
+	return sortedLevels(b.bids, true)
+}
+
+// Offers returns levels best (lowest) first
+func (b *OrderBook) Offers() []BookLevel {
+	return sortedLevels(b.offers, false)
+}
+
+// BestBid returns the highest bid, if any
+func (b *OrderBook) BestBid() (BookLevel, bool) {
+	level, ok := bestLevel(b.bids, true)
+	if !ok {
+		return BookLevel{}, false
+	}
+	return *level, true
+}
+
+// BestOffer returns the lowest offer, if any
+func (b *OrderBook) BestOffer() (BookLevel, bool) {
+	level, ok := bestLevel(b.offers, false)
+	if !ok {
+		return BookLevel{}, false
+	}
+	return *level, true
+}
+
+// Crossed reports whether the best bid is at or above the best offer
+func (b *OrderBook) Crossed() bool {
+	bid, okBid := bestLevel(b.bids, true)
+	offer, okOffer := bestLevel(b.offers, false)
+	return okBid && okOffer && bid.price.GreaterThanOrEqual(offer.price)
+}
+
+func (b *OrderBook) side(entryType string) map[string]*BookLevel {
+	switch entryType {
+	case constants.MdEntryTypeBid:
+		return b.bids
+	case constants.MdEntryTypeOffer:
+		return b.offers
+	}
+	return nil
+}
+
+func levelKey(entry Trade) string {
+	if entry.EntryId != "" {
+		return entry.EntryId
+	}
+	return entry.Price
+}
+
+// apply folds one incremental entry into the book. Without an MdUpdateAction a zero size removes
+// the level and anything else replaces it.
+func (b *OrderBook) apply(entry Trade) {
+	side := b.side(entry.EntryType)
+	if side == nil {
+		return
+	}
+	key := levelKey(entry)
+
+	remove := entry.UpdateAction == constants.MdUpdateActionDelete
+	if entry.UpdateAction == "" {
+		if size, err := analytics.ParseDecimal(entry.Size); err == nil && size.IsZero() {
+			remove = true
+		}
+	}
+	if remove {
+		if old, ok := side[key]; ok {
+			b.bytes -= levelBytes(old)
+			delete(side, key)
+		}
+		return
+	}
+
+	price, err := analytics.ParseDecimal(entry.Price)
+	if err != nil {
+		return
+	}
+	if old, ok := side[key]; ok {
+		b.bytes -= levelBytes(old)
+	}
+	level := &BookLevel{Price: entry.Price, Size: entry.Size, NumOrders: entry.NumOrders, EntryId: entry.EntryId, price: price}
+	side[key] = level
+	b.bytes += levelBytes(level)
+}
+
+func bestLevel(levels map[string]*BookLevel, highest bool) (*BookLevel, bool) {
+	var best *BookLevel
+	for _, level := range levels {
+		if best == nil || (highest && level.price.GreaterThan(best.price)) || (!highest && level.price.LessThan(best.price)) {
+			best = level
+		}
+	}
+	return best, best != nil
+}
+
+func sortedLevels(levels map[string]*BookLevel, descending bool) []BookLevel {
+	result := make([]BookLevel, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, *level)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if descending {
+			return result[i].price.GreaterThan(result[j].price)
+		}
+		return result[i].price.LessThan(result[j].price)
+	})
+	return result
+}
+
+// BookProblem describes why a book can no longer be trusted and should be rebuilt from a snapshot
+type BookProblem struct {
+	Symbol string
+	Reason string
+}
+
+func (p BookProblem) String() string {
+	return fmt.Sprintf("%s book out of sync: %s", p.Symbol, p.Reason)
+}
+
+// BookManager holds one OrderBook per symbol. Snapshots replace a book wholesale; readers get copies.
+type BookManager struct {
+	mu    sync.RWMutex
+	books map[string]*OrderBook
+}
+
+func NewBookManager() *BookManager {
+	return &BookManager{books: make(map[string]*OrderBook)}
+}
+
+// ApplySnapshot builds a fresh book from the bid/offer entries of a snapshot and swaps it in,
+// so readers never see a half-rebuilt book. Returns false when the snapshot had no book entries.
+func (m *BookManager) ApplySnapshot(symbol string, entries []Trade, received time.Time) bool {
+	book := newOrderBook(symbol)
+	hasBookEntries := false
+	for _, entry := range entries {
+		if book.side(entry.EntryType) == nil {
+			continue
+		}
+		hasBookEntries = true
+		entry.UpdateAction = constants.MdUpdateActionNew
+		book.apply(entry)
+		if seq := rptSeqOf(entry); seq > book.rptSeq {
+			book.rptSeq = seq
+		}
+	}
+	if !hasBookEntries {
+		return false
+	}
+	book.LastUpdate = received
+	book.crossed = book.Crossed()
+
+	m.mu.Lock()
+	m.books[symbol] = book
+	m.mu.Unlock()
+	return true
+}
+
+// ApplyIncremental applies updates to existing books and reports each book that became crossed
+// or skipped a RptSeq (83). Updates for symbols without a snapshot yet are ignored.
+func (m *BookManager) ApplyIncremental(entries []Trade, received time.Time) []BookProblem {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var problems []BookProblem
+	touched := make(map[string]*OrderBook)
+	for _, entry := range entries {
+		book, ok := m.books[entry.Symbol]
+		if !ok || book.side(entry.EntryType) == nil {
+			continue
+		}
+
+		if seq := rptSeqOf(entry); seq > 0 {
+			if book.rptSeq > 0 && seq > book.rptSeq+1 {
+				problems = append(problems, BookProblem{Symbol: entry.Symbol,
+					Reason: fmt.Sprintf("RptSeq gap %d -> %d", book.rptSeq, seq)})
+			}
+			if seq > book.rptSeq {
+				book.rptSeq = seq
+			}
+		}
+
+		book.apply(entry)
+		book.LastUpdate = received
+		touched[entry.Symbol] = book
+	}
+
+	// Report a crossed book once, when it crosses, not on every following update
+	for symbol, book := range touched {
+		crossed := book.Crossed()
+		if crossed && !book.crossed {
+			problems = append(problems, BookProblem{Symbol: symbol, Reason: "crossed book (best bid >= best offer)"})
+		}
+		book.crossed = crossed
+	}
+	return problems
+}
+
+// Get returns a copy of the book for symbol
+func (m *BookManager) Get(symbol string) (*OrderBook, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	book, ok := m.books[symbol]
+	if !ok {
+		return nil, false
+	}
+	snapshot := newOrderBook(book.Symbol)
+	snapshot.LastUpdate, snapshot.rptSeq, snapshot.crossed, snapshot.bytes = book.LastUpdate, book.rptSeq, book.crossed, book.bytes
+	snapshot.trimmed = book.trimmed
+	for key, level := range book.bids {
+		copied := *level
+		snapshot.bids[key] = &copied
+	}
+	for key, level := range book.offers {
+		copied := *level
+		snapshot.offers[key] = &copied
+	}
+	return snapshot, true
+}
+
+// Mid returns the midpoint of the best bid and offer, if symbol has a two-sided, uncrossed book
+func (m *BookManager) Mid(symbol string) (decimal.Decimal, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	book, ok := m.books[symbol]
+	if !ok {
+		return decimal.Zero, false
+	}
+	bid, okBid := bestLevel(book.bids, true)
+	offer, okOffer := bestLevel(book.offers, false)
+	if !okBid || !okOffer || bid.price.GreaterThanOrEqual(offer.price) {
+		return decimal.Zero, false
+	}
+	return bid.price.Add(offer.price).Div(decimal.NewFromInt(2)), true
+}
+
+// Symbols returns the symbols with a book, sorted
+func (m *BookManager) Symbols() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	symbols := make([]string, 0, len(m.books))
+	for symbol := range m.books {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Usage returns how many levels all books hold and their estimated size
+func (m *BookManager) Usage() (int, int64) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	levels := 0
+	var bytes int64
+	for _, book := range m.books {
+		levels += len(book.bids) + len(book.offers)
+		bytes += book.bytes
+	}
+	return levels, bytes
+}
+
+// SideDepths returns the number of levels on each side of every book
+func (m *BookManager) SideDepths() []int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	depths := make([]int, 0, 2*len(m.books))
+	for _, book := range m.books {
+		depths = append(depths, len(book.bids), len(book.offers))
+	}
+	return depths
+}
+
+// TrimDepth keeps the best depth levels on each side of every book and returns how many
+// levels were removed. Updates for removed levels are applied as usual and may add them back.
+func (m *BookManager) TrimDepth(depth int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for _, book := range m.books {
+		removed += book.trimSide(book.bids, depth, true)
+		removed += book.trimSide(book.offers, depth, false)
+	}
+	return removed
+}
+
+func (b *OrderBook) trimSide(levels map[string]*BookLevel, depth int, descending bool) int {
+	if len(levels) <= depth {
+		return 0
+	}
+	keys := make([]string, 0, len(levels))
+	for key := range levels {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if descending {
+			return levels[keys[i]].price.GreaterThan(levels[keys[j]].price)
+		}
+		return levels[keys[i]].price.LessThan(levels[keys[j]].price)
+	})
+	for _, key := range keys[depth:] {
+		b.bytes -= levelBytes(levels[key])
+		delete(levels, key)
+	}
+	b.trimmed = true
+	return len(keys) - depth
+}
+
+func rptSeqOf(entry Trade) int64 {
+	seq, err := strconv.ParseInt(entry.RptSeq, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return seq
+}
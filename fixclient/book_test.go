@@ -0,0 +1,201 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/database"
+)
+
+func bookEntry(entryType, price, size, entryId, action string) Trade {
+	return Trade{Symbol: "BTC-USD", EntryType: entryType, Price: price, Size: size, EntryId: entryId, UpdateAction: action}
+}
+
+func TestBookSnapshotAndIncremental(t *testing.T) {
+	books := NewBookManager()
+	now := time.Now()
+
+	ok := books.ApplySnapshot("BTC-USD", []Trade{
+		bookEntry("0", "99.5", "1", "b1", ""),
+		bookEntry("0", "100", "2", "b2", ""),
+		bookEntry("1", "101", "3", "o1", ""),
+	}, now)
+	if !ok {
+		t.Fatal("Expected snapshot with book entries to be applied")
+	}
+
+	problems := books.ApplyIncremental([]Trade{
+		bookEntry("0", "100", "5", "b2", constants.MdUpdateActionChange),
+		bookEntry("0", "99.5", "1", "b1", constants.MdUpdateActionDelete),
+		bookEntry("1", "100.5", "1", "o2", constants.MdUpdateActionNew),
+	}, now)
+	if len(problems) != 0 {
+		t.Fatalf("Expected no problems, got %v", problems)
+	}
+
+	book, _ := books.Get("BTC-USD")
+	bids, offers := book.Bids(), book.Offers()
+	if len(bids) != 1 || bids[0].Size != "5" {
+		t.Fatalf("Expected one bid of size 5, got %+v", bids)
+	}
+	if len(offers) != 2 || offers[0].Price != "100.5" {
+		t.Fatalf("Expected best offer 100.5 first, got %+v", offers)
+	}
+
+	// A trades-only snapshot must not wipe the book
+	if books.ApplySnapshot("BTC-USD", []Trade{bookEntry("2", "100", "1", "", "")}, now) {
+		t.Fatal("Expected trades-only snapshot to be ignored")
+	}
+	if book, _ := books.Get("BTC-USD"); len(book.Bids()) != 1 {
+		t.Fatal("Expected book to survive a trades-only snapshot")
+	}
+}
+
+func TestBookDetectsCrossAndGap(t *testing.T) {
+	books := NewBookManager()
+	now := time.Now()
+
+	bid := bookEntry("0", "100", "1", "", "")
+	bid.RptSeq = "10"
+	books.ApplySnapshot("BTC-USD", []Trade{bid, bookEntry("1", "101", "1", "", "")}, now)
+
+	offer := bookEntry("1", "99", "1", "", "")
+	offer.RptSeq = "12"
+	problems := books.ApplyIncremental([]Trade{offer}, now)
+	if len(problems) != 2 {
+		t.Fatalf("Expected a gap and a cross, got %v", problems)
+	}
+
+	// Still crossed, but already reported
+	removed := bookEntry("1", "101", "0", "", "")
+	removed.RptSeq = "13"
+	if problems := books.ApplyIncremental([]Trade{removed}, now); len(problems) != 0 {
+		t.Fatalf("Expected cross to be reported once, got %v", problems)
+	}
+}
+
+func TestResyncRequiresBookSubscription(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+	if _, err := app.resyncBook(app.consoleOutput(), "BTC-USD"); !errors.Is(err, ErrNoSuchSubscription) {
+		t.Fatalf("Expected ErrNoSuchSubscription, got %v", err)
+	}
+
+	// Trade-only subscriptions have no book to resync
+	app.TradeStore.AddSubscription("BTC-USD", constants.SubscriptionRequestTypeSubscribe, "md_1")
+	if _, err := app.resyncBook(app.consoleOutput(), "BTC-USD"); !errors.Is(err, ErrNoSuchSubscription) {
+		t.Fatalf("Expected ErrNoSuchSubscription for trades subscription, got %v", err)
+	}
+}
+
+func TestBookStateFollowsBook(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	app := createTestFixApp()
+	app.Db = db
+	app.Books = NewBookManager()
+	now := time.Now()
+
+	snapshot := []Trade{
+		bookEntry("0", "99.5", "1", "b1", ""),
+		bookEntry("0", "100", "2", "b2", ""),
+		bookEntry("1", "101", "3", "o1", ""),
+	}
+	app.Books.ApplySnapshot("BTC-USD", snapshot, now)
+	if err := app.storeTradesToDatabase(snapshot, "1", true); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if state, _ := db.QueryBookState("BTC-USD"); len(state) != 3 || state[0].Side != "bid" || state[0].Price != "100.0" {
+		t.Fatalf("Expected 2 bids (best 100) and 1 offer, got %+v", state)
+	}
+
+	update := []Trade{bookEntry("0", "100", "2", "b2", constants.MdUpdateActionDelete)}
+	app.Books.ApplyIncremental(update, now)
+	if err := app.storeTradesToDatabase(update, "2", false); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	state, _ := db.QueryBookState("BTC-USD")
+	if len(state) != 2 || state[0].Price != "99.5" || state[0].Level != 1 || state[1].Side != "offer" {
+		t.Fatalf("Expected bid 99.5 to move to level 1 and the old level 2 to be removed, got %+v", state)
+	}
+}
+
+func TestMarketSummaryFollowsIngest(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "summary.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	app := createTestFixApp()
+	app.Db = db
+	app.Books = NewBookManager()
+	now := time.Now()
+
+	snapshot := []Trade{
+		bookEntry("0", "100", "2", "b1", ""),
+		bookEntry("1", "101", "3", "o1", ""),
+	}
+	app.Books.ApplySnapshot("BTC-USD", snapshot, now)
+	if err := app.storeTradesToDatabase(snapshot, "1", true); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	trades := []Trade{
+		{Symbol: "BTC-USD", EntryType: "2", Price: "100.5", Size: "1", Aggressor: "Buy", EntryTime: now},
+		{Symbol: "BTC-USD", EntryType: "2", Price: "100.25", Size: "4", Aggressor: "Sell", EntryTime: now.Add(-time.Second)},
+	}
+	if err := app.storeTradesToDatabase(trades, "2", false); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	s, err := db.QueryMarketSummary("BTC-USD")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if s.LastPrice != "100.5" || s.LastAggressor != "Buy" || s.TradeCount != 2 {
+		t.Fatalf("Expected latest trade 100.5 Buy and 2 trades, got %+v", s)
+	}
+	if s.BidPrice != "100.0" || s.OfferPrice != "101.0" || s.BookUpdateCount != 2 {
+		t.Fatalf("Expected quote 100/101 kept across the trade message, got %+v", s)
+	}
+
+	// A trade without MDEntryTime does not displace a timed one in the same message...
+	untimed := Trade{Symbol: "BTC-USD", EntryType: "2", Price: "99", Size: "1", Aggressor: "Sell"}
+	if err := app.storeTradesToDatabase([]Trade{trades[0], untimed}, "3", false); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if s, _ := db.QueryMarketSummary("BTC-USD"); s.LastPrice != "100.5" || !s.LastTradeTime.Equal(now) {
+		t.Fatalf("Expected the timed trade kept over the untimed one, got %+v", s)
+	}
+
+	// ...and on its own it is stored without a time rather than a bogus one
+	if err := app.storeTradesToDatabase([]Trade{untimed}, "4", false); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if s, _ := db.QueryMarketSummary("BTC-USD"); s.LastPrice != "99.0" || !s.LastTradeTime.IsZero() {
+		t.Fatalf("Expected the untimed trade stored with no time, got %+v", s)
+	}
+}
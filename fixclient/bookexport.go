@@ -0,0 +1,224 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/database"
+	"prime-fix-md-go/export"
+)
+
+type bookExportQuery struct {
+	symbol string
+	at     time.Time // Zero exports the live book
+	out    string    // .json file; empty prints to the console
+}
+
+func parseBookExportQuery(args []string) (bookExportQuery, error) {
+	if len(args) < 1 {
+		return bookExportQuery{}, errors.New("usage: book export <symbol> [--at TIME] [--out FILE.json]")
+	}
+
+	q := bookExportQuery{symbol: strings.ToUpper(args[0])}
+	for i := 1; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return q, fmt.Errorf("%s requires a value", args[i])
+		}
+		switch args[i] {
+		case "--at":
+			at, err := parseExportTime(args[i+1])
+			if err != nil {
+				return q, fmt.Errorf("invalid --at %q (RFC 3339 or YYYY-MM-DD)", args[i+1])
+			}
+			q.at = at
+		case "--out":
+			q.out = args[i+1]
+		default:
+			return q, fmt.Errorf("unknown flag %q", args[i])
+		}
+		i++
+	}
+	return q, nil
+}
+
+func (a *FixApp) handleBookRequest(out output, parts []string) {
+	if len(parts) < 2 {
+		fmt.Fprintln(out.Console(), "Usage: book <symbol> [--depth N] [--notional] | book export <symbol> [--at TIME] [--out FILE.json]")
+		return
+	}
+	if parts[1] != "export" {
+		a.handleBookViewRequest(out, parts)
+		return
+	}
+
+	q, err := parseBookExportQuery(parts[2:])
+	if err != nil {
+		out.Error(err)
+		return
+	}
+
+	snapshot, err := a.bookSnapshot(q.symbol, q.at)
+	if err != nil {
+		out.Error(err)
+		return
+	}
+
+	if q.out == "" {
+		if err := export.WriteBookSnapshot(out.Console(), snapshot); err != nil {
+			out.Error(err)
+		}
+		return
+	}
+	if err := export.WriteBookSnapshotFile(q.out, snapshot); err != nil {
+		out.Error(err)
+		return
+	}
+	out.Info("Exported %s book (%d bids, %d offers, %s) to %s",
+		q.symbol, len(snapshot.Bids), len(snapshot.Offers), snapshot.Source, q.out)
+	a.exported(out, q.out)
+}
+
+// bookSnapshot uses the live in-memory book when no time is given, otherwise (or when there is
+// no live book) it rebuilds the book from the database
+func (a *FixApp) bookSnapshot(symbol string, at time.Time) (export.BookSnapshot, error) {
+	var (
+		book   *OrderBook
+		ok     bool
+		source = "live"
+	)
+	if at.IsZero() && a.Books != nil {
+		book, ok = a.Books.Get(symbol)
+	}
+	if !ok {
+		var err error
+		if book, err = a.reconstructBook(symbol, at); err != nil {
+			return export.BookSnapshot{}, err
+		}
+		source = "database"
+	}
+
+	snapshot := export.BookSnapshot{
+		Symbol:  symbol,
+		Time:    book.LastUpdate.UTC().Format(time.RFC3339Nano),
+		Source:  source,
+		Crossed: book.Crossed(),
+	}
+	for _, level := range book.Bids() {
+		snapshot.Bids = append(snapshot.Bids, exportLevel(level))
+	}
+	for _, level := range book.Offers() {
+		snapshot.Offers = append(snapshot.Offers, exportLevel(level))
+	}
+	return snapshot, nil
+}
+
+func exportLevel(level BookLevel) export.BookLevel {
+	return export.BookLevel{Price: level.Price, Size: level.Size, NumOrders: level.NumOrders, EntryId: level.EntryId}
+}
+
+// reconstructBook starts from the later of the last stored snapshot and the last periodic book
+// snapshot at or before at, and replays the incremental entries stored after it. A zero at means now.
+func (a *FixApp) reconstructBook(symbol string, at time.Time) (*OrderBook, error) {
+	if a.Db == nil {
+		return nil, fmt.Errorf("%w: no live book for %s and no database to rebuild it from", ErrStorage, symbol)
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	snapshot, err := a.Db.LatestSnapshot(symbol, at)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	checkpoint, err := a.Db.LatestBookSnapshot(symbol, at)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+
+	book := newOrderBook(symbol)
+	var from time.Time
+	if len(checkpoint) > 0 && (len(snapshot) == 0 || !checkpoint[0].ReceivedAt.Before(latestReceived(snapshot))) {
+		// A copy of the live book includes every update received up to when it was taken. Updates
+		// stored at that instant may already be in it; applying them again changes nothing.
+		for _, row := range checkpoint {
+			entry := storedBookEntry(row)
+			entry.UpdateAction = constants.MdUpdateActionNew
+			book.apply(entry)
+		}
+		book.LastUpdate = checkpoint[0].ReceivedAt
+		from = book.LastUpdate
+	} else {
+		if len(snapshot) == 0 {
+			return nil, fmt.Errorf("no stored book snapshot for %s at or before %s", symbol, at.UTC().Format(time.RFC3339))
+		}
+		for _, row := range snapshot {
+			entry := storedBookEntry(row)
+			entry.UpdateAction = constants.MdUpdateActionNew
+			book.apply(entry)
+		}
+		book.LastUpdate = latestReceived(snapshot)
+		from = book.LastUpdate.Add(time.Nanosecond)
+	}
+
+	updates, err := a.Db.QueryOrderBookUpdates(symbol,
+		database.TimeRange{From: from, To: at.Add(time.Nanosecond)})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	for _, row := range updates {
+		book.apply(storedBookEntry(row))
+		book.LastUpdate = row.ReceivedAt
+	}
+	return book, nil
+}
+
+func latestReceived(rows []database.OrderBookRow) time.Time {
+	var latest time.Time
+	for _, row := range rows {
+		if row.ReceivedAt.After(latest) {
+			latest = row.ReceivedAt
+		}
+	}
+	return latest
+}
+
+// storedBookEntry turns a stored order_book row back into the entry it was stored from
+func storedBookEntry(row database.OrderBookRow) Trade {
+	entry := Trade{
+		Symbol:       row.Symbol,
+		Price:        row.Price,
+		Size:         row.Size,
+		EntryId:      row.MdEntryId,
+		UpdateAction: row.UpdateAction,
+	}
+	switch row.Side {
+	case "bid":
+		entry.EntryType = constants.MdEntryTypeBid
+	case "offer":
+		entry.EntryType = constants.MdEntryTypeOffer
+	}
+	if row.NumOrders != nil {
+		entry.NumOrders = strconv.Itoa(*row.NumOrders)
+	}
+	return entry
+}
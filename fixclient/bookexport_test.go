@@ -0,0 +1,118 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+func TestReconstructBookFromDatabase(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "book.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	store := func(rec database.OrderBookRecord) {
+		rec.Symbol, rec.MdReqId = "BTC-USD", "md_1"
+		if err := db.StoreOrderBookRecord(rec); err != nil {
+			t.Fatalf("Failed to store entry: %v", err)
+		}
+	}
+	store(database.OrderBookRecord{Side: "bid", Price: "100", Size: "1", MdEntryId: "b1", SeqNum: 2, IsSnapshot: true})
+	store(database.OrderBookRecord{Side: "offer", Price: "101", Size: "2", MdEntryId: "o1", SeqNum: 2, IsSnapshot: true})
+	store(database.OrderBookRecord{Side: "bid", Price: "100.5", Size: "3", MdEntryId: "b2", SeqNum: 3, UpdateAction: "0"})
+	between := time.Now()
+	time.Sleep(time.Millisecond)
+	store(database.OrderBookRecord{Side: "offer", Price: "101", Size: "0", MdEntryId: "o1", SeqNum: 4, UpdateAction: "2"})
+
+	app := createTestFixApp()
+	app.Db = db
+
+	snapshot, err := app.bookSnapshot("BTC-USD", between)
+	if err != nil {
+		t.Fatalf("Failed to rebuild book: %v", err)
+	}
+	if snapshot.Source != "database" || len(snapshot.Bids) != 2 || snapshot.Bids[0].Price != "100.5" || len(snapshot.Offers) != 1 {
+		t.Fatalf("Unexpected book at %v: %+v", between, snapshot)
+	}
+
+	latest, err := app.bookSnapshot("BTC-USD", time.Time{})
+	if err != nil || len(latest.Offers) != 0 {
+		t.Fatalf("Expected the offer deleted in the latest book, got %+v (%v)", latest, err)
+	}
+
+	if _, err := app.bookSnapshot("BTC-USD", between.Add(-time.Hour)); err == nil {
+		t.Fatal("Expected error before the first snapshot")
+	}
+}
+
+func TestReconstructBookFromBookSnapshot(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "book.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	store := func(rec database.OrderBookRecord) {
+		rec.Symbol, rec.MdReqId = "BTC-USD", "md_1"
+		if err := db.StoreOrderBookRecord(rec); err != nil {
+			t.Fatalf("Failed to store entry: %v", err)
+		}
+	}
+	store(database.OrderBookRecord{Side: "bid", Price: "100", Size: "1", MdEntryId: "b1", SeqNum: 2, IsSnapshot: true})
+	store(database.OrderBookRecord{Side: "offer", Price: "101", Size: "2", MdEntryId: "o1", SeqNum: 2, IsSnapshot: true})
+
+	// The live book moved on from the snapshot; its copy is what reconstruction starts from
+	app := createTestFixApp()
+	app.Db = db
+	app.Books = NewBookManager()
+	taken := time.Now()
+	app.Books.ApplySnapshot("BTC-USD", []Trade{
+		{Symbol: "BTC-USD", EntryType: "0", Price: "99", Size: "5", EntryId: "b9"},
+		{Symbol: "BTC-USD", EntryType: "1", Price: "101", Size: "2", EntryId: "o1"},
+	}, taken)
+	copies := map[string]time.Time{}
+	if n := app.snapshotBooks(copies); n != 1 {
+		t.Fatalf("Expected one book stored, got %d", n)
+	}
+	if n := app.snapshotBooks(copies); n != 0 {
+		t.Fatalf("Expected an unchanged book to be skipped, got %d", n)
+	}
+
+	time.Sleep(time.Millisecond)
+	store(database.OrderBookRecord{Side: "offer", Price: "102", Size: "4", MdEntryId: "o2", SeqNum: 5, UpdateAction: "0"})
+
+	book, err := app.reconstructBook("BTC-USD", time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to rebuild book: %v", err)
+	}
+	bids, offers := book.Bids(), book.Offers()
+	if len(bids) != 1 || normalized(bids[0].Price) != "99" || len(offers) != 2 || normalized(offers[1].Price) != "102" {
+		t.Fatalf("Expected the copy plus the later offer, got bids %+v offers %+v", bids, offers)
+	}
+
+	// Before the copy was taken, the gateway snapshot is still used
+	early, err := app.reconstructBook("BTC-USD", taken.Add(-time.Nanosecond))
+	if err != nil || len(early.Bids()) != 1 || normalized(early.Bids()[0].Price) != "100" {
+		t.Fatalf("Expected the gateway snapshot before the copy, got %+v (%v)", early, err)
+	}
+}
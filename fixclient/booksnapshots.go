@@ -0,0 +1,67 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"log"
+	"time"
+)
+
+// StartBookSnapshots copies every live book into book_snapshots each interval, until
+// StopBackground is called, so rebuilding a past book only replays the updates since the
+// nearest copy
+func (a *FixApp) StartBookSnapshots(every time.Duration) {
+	if a.Db == nil || every <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		taken := make(map[string]time.Time) // symbol -> LastUpdate of the last copy stored
+		for {
+			select {
+			case <-a.done:
+				return
+			case <-ticker.C:
+				a.snapshotBooks(taken)
+			}
+		}
+	}()
+}
+
+// snapshotBooks stores each book that changed since its copy in taken. Books trimmed for the
+// memory budget are skipped, as a copy would be missing their deep levels.
+func (a *FixApp) snapshotBooks(taken map[string]time.Time) int {
+	stored := 0
+	for _, symbol := range a.Books.Symbols() {
+		book, ok := a.Books.Get(symbol)
+		if !ok || book.LastUpdate.IsZero() || book.LastUpdate.Equal(taken[symbol]) || book.trimmed {
+			continue
+		}
+		bids, offers := book.Bids(), book.Offers()
+		if len(bids) == 0 && len(offers) == 0 {
+			continue
+		}
+		if err := a.Db.StoreBookSnapshot(symbol, bookStateLevels(bids), bookStateLevels(offers), book.LastUpdate); err != nil {
+			log.Printf("Failed to store %s book snapshot: %v", symbol, err)
+			continue
+		}
+		taken[symbol] = book.LastUpdate
+		stored++
+	}
+	return stored
+}
@@ -0,0 +1,201 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"prime-fix-md-go/analytics"
+	"prime-fix-md-go/database"
+	"prime-fix-md-go/export"
+)
+
+// Without --since, enough history for this many bars is read
+const defaultCandleCount = 100
+
+type candlesQuery struct {
+	symbol   string
+	interval time.Duration
+	since    time.Duration
+	from, to time.Time // Absolute range; overrides since when from is set
+	limit    int       // Most recent bars to show; 0 shows all
+	out      string    // Export to this .csv or .json file instead of printing
+}
+
+// timeRange resolves the query window relative to now
+func (q candlesQuery) timeRange(now time.Time) database.TimeRange {
+	if !q.from.IsZero() {
+		return database.TimeRange{From: q.from, To: q.to}
+	}
+	return database.TimeRange{From: now.Add(-q.since), To: q.to}
+}
+
+// parseExportTime accepts RFC 3339 timestamps or plain UTC dates
+func parseExportTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func parseCandlesQuery(args []string) (candlesQuery, error) {
+	if len(args) < 2 {
+		return candlesQuery{}, errors.New("usage: candles <symbol> <interval> [--since DURATION | --from TIME [--to TIME]] [--limit N] [--out FILE]")
+	}
+
+	q := candlesQuery{symbol: strings.ToUpper(args[0])}
+	interval, err := time.ParseDuration(args[1])
+	if err != nil || interval <= 0 {
+		return q, fmt.Errorf("invalid interval %q (e.g. 1m, 5m, 1h)", args[1])
+	}
+	q.interval = interval
+	q.since = interval * defaultCandleCount
+
+	for i := 2; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return q, fmt.Errorf("%s requires a value", args[i])
+		}
+		switch args[i] {
+		case "--since":
+			since, err := time.ParseDuration(args[i+1])
+			if err != nil || since <= 0 {
+				return q, fmt.Errorf("invalid --since %q (e.g. 2h, 30m)", args[i+1])
+			}
+			q.since = since
+		case "--limit":
+			limit, err := strconv.Atoi(args[i+1])
+			if err != nil || limit <= 0 {
+				return q, fmt.Errorf("invalid --limit %q", args[i+1])
+			}
+			q.limit = limit
+		case "--from", "--to":
+			at, err := parseExportTime(args[i+1])
+			if err != nil {
+				return q, fmt.Errorf("invalid %s %q (RFC 3339 or YYYY-MM-DD)", args[i], args[i+1])
+			}
+			if args[i] == "--from" {
+				q.from = at
+			} else {
+				q.to = at
+			}
+		case "--out":
+			if _, err := export.FormatFromPath(args[i+1]); err != nil {
+				return q, err
+			}
+			q.out = args[i+1]
+		default:
+			return q, fmt.Errorf("unknown flag %q", args[i])
+		}
+		i++
+	}
+	if !q.from.IsZero() && !q.to.IsZero() && !q.to.After(q.from) {
+		return q, errors.New("--to must be after --from")
+	}
+	return q, nil
+}
+
+// handleCandlesRequest aggregates stored trades into OHLCV bars on the fly
+func (a *FixApp) handleCandlesRequest(out output, parts []string) {
+	q, err := parseCandlesQuery(parts[1:])
+	if err != nil {
+		out.Error(err)
+		return
+	}
+	bars, err := a.storedCandles(q.symbol, q.interval, q.timeRange(time.Now()))
+	if err != nil {
+		out.Error(err)
+		return
+	}
+
+	candles := bars.Candles()
+	if len(candles) == 0 {
+		out.Info("No stored trades for %s in the requested range", q.symbol)
+		return
+	}
+	if q.limit > 0 && len(candles) > q.limit {
+		candles = candles[len(candles)-q.limit:]
+	}
+
+	if q.out != "" {
+		records := candleRecords(q.symbol, q.interval, bars, candles)
+		if err := export.WriteCandlesFile(q.out, records); err != nil {
+			out.Error(err)
+			return
+		}
+		out.Info("Exported %d %s %s candles to %s", len(records), q.symbol, q.interval, q.out)
+		a.exported(out, q.out)
+		return
+	}
+
+	rows := make([][]string, 0, len(candles))
+	for _, c := range candles {
+		rows = append(rows, []string{
+			c.Start.Format("2006-01-02 15:04"),
+			bars.FormatPrice(c.Open),
+			bars.FormatPrice(c.High),
+			bars.FormatPrice(c.Low),
+			bars.FormatPrice(c.Close),
+			bars.FormatSize(c.Volume),
+			strconv.FormatInt(c.Trades, 10),
+		})
+	}
+	out.Table(fmt.Sprintf("%s %s candles from stored trades (UTC):", q.symbol, q.interval),
+		[]string{"Start", "Open", "High", "Low", "Close", "Volume", "Trades"}, rows)
+}
+
+// storedCandles aggregates the stored trades for symbol in r into bars of interval
+func (a *FixApp) storedCandles(symbol string, interval time.Duration, r database.TimeRange) (*analytics.CandleBuilder, error) {
+	if a.Db == nil {
+		return nil, fmt.Errorf("%w: no database for stored trades", ErrStorage)
+	}
+
+	trades, err := a.Db.QueryTrades(symbol, r, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+
+	bars := analytics.NewCandleBuilder(interval)
+	for _, trade := range trades {
+		if err := bars.Add(trade.TradeTime, trade.Price, trade.Size); err != nil {
+			log.Printf("Skipping stored trade %d: %v", trade.Id, err)
+		}
+	}
+	return bars, nil
+}
+
+func candleRecords(symbol string, interval time.Duration, bars *analytics.CandleBuilder, candles []analytics.Candle) []export.CandleRecord {
+	records := make([]export.CandleRecord, 0, len(candles))
+	for _, c := range candles {
+		records = append(records, export.CandleRecord{
+			Symbol:   symbol,
+			Interval: interval.String(),
+			Start:    c.Start.Format(time.RFC3339),
+			Open:     bars.FormatPrice(c.Open),
+			High:     bars.FormatPrice(c.High),
+			Low:      bars.FormatPrice(c.Low),
+			Close:    bars.FormatPrice(c.Close),
+			Volume:   bars.FormatSize(c.Volume),
+			Trades:   c.Trades,
+		})
+	}
+	return records
+}
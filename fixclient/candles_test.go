@@ -0,0 +1,53 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCandlesQuery(t *testing.T) {
+	q, err := parseCandlesQuery([]string{"btc-usd", "5m", "--since", "2h", "--limit", "10"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if q.symbol != "BTC-USD" || q.interval != 5*time.Minute || q.since != 2*time.Hour || q.limit != 10 {
+		t.Fatalf("Unexpected query %+v", q)
+	}
+
+	q, _ = parseCandlesQuery([]string{"BTC-USD", "1m"})
+	if q.since != 100*time.Minute {
+		t.Fatalf("Expected default lookback of 100 bars, got %s", q.since)
+	}
+
+	q, err = parseCandlesQuery([]string{"BTC-USD", "1h", "--from", "2025-01-01", "--to", "2025-01-02T00:00:00Z", "--out", "bars.csv"})
+	if err != nil || q.out != "bars.csv" {
+		t.Fatalf("Unexpected export query %+v (%v)", q, err)
+	}
+	r := q.timeRange(time.Now())
+	if !r.From.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) || !r.To.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Unexpected range %+v", r)
+	}
+
+	for _, invalid := range [][]string{{"BTC-USD"}, {"BTC-USD", "5x"}, {"BTC-USD", "5m", "--since"}, {"BTC-USD", "5m", "--bogus", "1"},
+		{"BTC-USD", "5m", "--out", "bars.txt"}, {"BTC-USD", "5m", "--from", "2025-01-02", "--to", "2025-01-01"}} {
+		if _, err := parseCandlesQuery(invalid); err == nil {
+			t.Fatalf("Expected error for %v", invalid)
+		}
+	}
+}
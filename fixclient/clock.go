@@ -0,0 +1,165 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/utils"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const (
+	DefaultSkewWarnThreshold = time.Second
+	clockWindowSize          = 200
+)
+
+// ClockStats summarizes offsets between local receive time and server SendingTime (52).
+// Every offset is clock skew plus one-way latency, so latency figures here include the skew.
+type ClockStats struct {
+	Samples    int64
+	Skew       time.Duration // Smallest offset in the recent window, the best available skew estimate
+	LastOffset time.Duration
+	AvgOffset  time.Duration // Mean offset over the recent window (skew + average latency)
+	MaxOffset  time.Duration // Largest offset in the recent window (skew + worst latency)
+	Threshold  time.Duration
+}
+
+// Exceeded reports whether the skew estimate is outside the warning threshold
+func (s ClockStats) Exceeded() bool {
+	return s.Threshold > 0 && s.Samples > 0 && absDuration(s.Skew) > s.Threshold
+}
+
+func (s ClockStats) String() string {
+	if s.Samples == 0 {
+		return "no SendingTime samples yet"
+	}
+	summary := fmt.Sprintf("skew ~%s, latency incl. skew avg %s / max %s over %d msgs",
+		formatOffset(s.Skew), formatOffset(s.AvgOffset), formatOffset(s.MaxOffset), s.Samples)
+	if s.Exceeded() {
+		summary += fmt.Sprintf(" (exceeds %s)", s.Threshold)
+	}
+	return summary
+}
+
+// ClockMonitor keeps a rolling skew estimate from inbound SendingTime values and warns when it
+// drifts past the threshold, since signed logons and latency measurements depend on sane clocks
+type ClockMonitor struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	window    []time.Duration
+	next      int
+	samples   int64
+	last      time.Duration
+	warned    bool
+}
+
+func NewClockMonitor(threshold time.Duration) *ClockMonitor {
+	return &ClockMonitor{threshold: threshold, window: make([]time.Duration, 0, clockWindowSize)}
+}
+
+// ObserveMessage records the SendingTime of an inbound message against the local clock
+func (c *ClockMonitor) ObserveMessage(msg *quickfix.Message, received time.Time) {
+	sendingTime, err := msg.Header.GetString(constants.TagSendingTime)
+	if err != nil {
+		return
+	}
+	sent, ok := utils.ParseFixTime(sendingTime, received)
+	if !ok {
+		return
+	}
+	c.Observe(sent, received)
+}
+
+func (c *ClockMonitor) Observe(sent, received time.Time) {
+	offset := received.Sub(sent)
+
+	c.mu.Lock()
+	if len(c.window) < clockWindowSize {
+		c.window = append(c.window, offset)
+	} else {
+		c.window[c.next] = offset
+		c.next = (c.next + 1) % clockWindowSize
+	}
+	c.samples++
+	c.last = offset
+	stats := c.statsLocked()
+
+	// Warn once when crossing the threshold and once when coming back
+	exceeded := stats.Exceeded()
+	changed := exceeded != c.warned
+	c.warned = exceeded
+	c.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if exceeded {
+		log.Printf("WARNING: Local clock differs from server SendingTime by ~%s (threshold %s). Signed logons and latency figures may be unreliable; check NTP.",
+			formatOffset(stats.Skew), stats.Threshold)
+	} else {
+		log.Printf("Clock skew back within threshold: ~%s", formatOffset(stats.Skew))
+	}
+}
+
+func (c *ClockMonitor) Stats() ClockStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.statsLocked()
+}
+
+func (c *ClockMonitor) statsLocked() ClockStats {
+	stats := ClockStats{Samples: c.samples, LastOffset: c.last, Threshold: c.threshold}
+	if len(c.window) == 0 {
+		return stats
+	}
+
+	var total time.Duration
+	stats.Skew, stats.MaxOffset = c.window[0], c.window[0]
+	for _, offset := range c.window {
+		total += offset
+		if offset < stats.Skew {
+			stats.Skew = offset
+		}
+		if offset > stats.MaxOffset {
+			stats.MaxOffset = offset
+		}
+	}
+	stats.AvgOffset = total / time.Duration(len(c.window))
+	return stats
+}
+
+// Positive offsets mean the local clock is ahead of the server (or the message was slow)
+func formatOffset(d time.Duration) string {
+	d = d.Round(time.Millisecond)
+	if d >= 0 {
+		return "+" + d.String()
+	}
+	return d.String()
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
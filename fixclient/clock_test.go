@@ -0,0 +1,82 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"testing"
+	"time"
+
+	"prime-fix-md-go/constants"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestClockMonitorSkewEstimate(t *testing.T) {
+	c := NewClockMonitor(time.Second)
+	sent := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Local clock 200ms ahead, with 10-50ms of latency on top
+	for _, latency := range []time.Duration{50, 10, 30} {
+		c.Observe(sent, sent.Add(200*time.Millisecond+latency*time.Millisecond))
+	}
+
+	stats := c.Stats()
+	if stats.Samples != 3 {
+		t.Fatalf("Expected 3 samples, got %d", stats.Samples)
+	}
+	if stats.Skew != 210*time.Millisecond {
+		t.Fatalf("Expected skew estimate 210ms, got %s", stats.Skew)
+	}
+	if stats.AvgOffset != 230*time.Millisecond || stats.MaxOffset != 250*time.Millisecond {
+		t.Fatalf("Unexpected offsets avg %s max %s", stats.AvgOffset, stats.MaxOffset)
+	}
+	if stats.Exceeded() {
+		t.Fatal("Skew under threshold should not be flagged")
+	}
+}
+
+func TestClockMonitorThreshold(t *testing.T) {
+	c := NewClockMonitor(time.Second)
+	received := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Local clock 3s behind the server
+	c.Observe(received.Add(3*time.Second), received)
+	if stats := c.Stats(); !stats.Exceeded() || stats.Skew != -3*time.Second {
+		t.Fatalf("Expected -3s skew to exceed threshold, got %+v", stats)
+	}
+
+	if NewClockMonitor(0).Stats().Exceeded() {
+		t.Fatal("Zero threshold should disable warnings")
+	}
+}
+
+func TestClockMonitorObserveMessage(t *testing.T) {
+	c := NewClockMonitor(time.Second)
+	received := time.Date(2025, 1, 1, 12, 0, 0, 500000000, time.UTC)
+
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(constants.TagSendingTime, "20250101-12:00:00.100")
+	c.ObserveMessage(msg, received)
+
+	// Messages without a SendingTime are ignored
+	c.ObserveMessage(quickfix.NewMessage(), received)
+
+	stats := c.Stats()
+	if stats.Samples != 1 || stats.LastOffset != 400*time.Millisecond {
+		t.Fatalf("Expected one 400ms sample, got %+v", stats)
+	}
+}
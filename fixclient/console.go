@@ -0,0 +1,101 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"prime-fix-md-go/formatter"
+)
+
+// Moves the cursor home and erases the screen
+const clearScreenSeq = "\x1b[H\x1b[2J"
+
+// SetConsole sends everything printed asynchronously (market data, session events, logs) to w.
+// The REPL passes its own writer, which erases the line being typed and redraws it afterwards
+// so streaming output never splits a half-typed command. nil goes back to stdout and stderr.
+func (a *FixApp) SetConsole(stdout, stderr io.Writer) {
+	if stdout == nil {
+		stdout, stderr = os.Stdout, os.Stderr
+	}
+	a.settingsMu.Lock()
+	a.console = stdout
+	if err := a.applyOutputFormat(a.outputFormat); err != nil {
+		log.Printf("Failed to switch console: %v", err)
+	}
+	a.settingsMu.Unlock()
+	formatter.SetConsole(stdout)
+	log.SetOutput(formatter.ASCII(stderr))
+}
+
+// Console is where command output goes
+func (a *FixApp) Console() io.Writer {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return formatter.ASCII(a.consoleWriter())
+}
+
+// consoleWriter is the console without ASCII fallback; the caller holds settingsMu
+func (a *FixApp) consoleWriter() io.Writer {
+	if a.console == nil {
+		return os.Stdout
+	}
+	return a.console
+}
+
+// currentFormat is the output format set by SetOutputFormat
+func (a *FixApp) currentFormat() string {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.outputFormat
+}
+
+// swapRenderer replaces the console renderer, e.g. to hold back live output while a command
+// owns the screen, and returns the one it replaced
+func (a *FixApp) swapRenderer(renderer Renderer) Renderer {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	previous := a.Renderer
+	a.Renderer = renderer
+	return previous
+}
+
+// output is where a command's results go. Commands print to the output they are handed rather
+// than to the app's renderer, so a command's results can be sent somewhere other than the console.
+type output struct {
+	Renderer
+	w       io.Writer
+	capture bool // Responses to requests the command sends are routed here; see routeResponses
+}
+
+// Console is where the command's free-form text goes
+func (o output) Console() io.Writer {
+	return formatter.ASCII(o.w)
+}
+
+// consoleOutput is the console, used by commands typed at the prompt and for live output
+func (a *FixApp) consoleOutput() output {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return output{Renderer: a.Renderer, w: a.consoleWriter()}
+}
+
+func (a *FixApp) handleClearRequest(out output) {
+	fmt.Fprint(out.Console(), clearScreenSeq)
+}
@@ -0,0 +1,43 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetConsoleKeepsOutputFormat(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	if err := app.SetOutputFormat(OutputJson); err != nil {
+		t.Fatalf("Failed to set output format: %v", err)
+	}
+
+	var out bytes.Buffer
+	app.SetConsole(&out, io.Discard)
+	defer app.SetConsole(nil, nil)
+
+	app.Renderer.Info("hello %s", "console")
+	app.handleClearRequest(app.consoleOutput())
+	if !strings.HasPrefix(out.String(), "{") || !strings.Contains(out.String(), "hello console") {
+		t.Fatalf("Expected JSON output on the new console, got %q", out.String())
+	}
+	if !strings.HasSuffix(out.String(), clearScreenSeq) {
+		t.Fatalf("Expected clear to write the clear-screen sequence, got %q", out.String())
+	}
+}
@@ -0,0 +1,194 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/chzyer/readline"
+)
+
+// controlEndOfReply ends the output of each command sent over the control socket
+const controlEndOfReply = "\x00"
+
+// Commands that follow output or wait for Ctrl-C on the daemon's own terminal
+var attachUnsupported = map[string]bool{"tail": true, "replay": true, "clear": true}
+
+// StartControlSocket accepts attach connections on a unix socket at path until StopBackground.
+// Each line received is run as a REPL command and its output sent back, so a running daemon can
+// be inspected and driven from another terminal. Remote commands run one at a time.
+func (a *FixApp) StartControlSocket(path string) error {
+	if err := removeStaleSocket(path); err != nil {
+		return err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to open control socket: %v", err)
+	}
+	// Anyone who can connect can send commands, so only the owner may
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to restrict control socket: %v", err)
+	}
+
+	go func() {
+		<-a.done
+		listener.Close()
+	}()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					log.Printf("Control socket stopped: %v", err)
+				}
+				return
+			}
+			go a.serveControl(conn)
+		}
+	}()
+	log.Printf("Control socket listening on %s; run attach to connect", path)
+	return nil
+}
+
+// removeStaleSocket removes a socket left behind by a process that did not shut down cleanly
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check control socket: %v", err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("control socket %s exists and is not a socket", path)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("control socket %s is in use by another process", path)
+	}
+	return os.Remove(path)
+}
+
+func (a *FixApp) serveControl(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		lines, detach := a.runControlCommand(scanner.Text())
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(conn, line); err != nil {
+				return
+			}
+		}
+		if _, err := fmt.Fprintln(conn, controlEndOfReply); err != nil || detach {
+			return
+		}
+	}
+}
+
+// runControlCommand runs one attached command and returns its output, and whether the client detached
+func (a *FixApp) runControlCommand(line string) ([]string, bool) {
+	line, pipe, err := splitPipe(line)
+	if err != nil {
+		return []string{"Error: " + err.Error()}, false
+	}
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return nil, false
+	}
+
+	cmd := strings.ToLower(parts[0])
+	switch {
+	case cmd == "exit" || cmd == "detach":
+		return []string{"Detached; the daemon keeps running"}, true
+	case attachUnsupported[cmd] || (cmd == "status" && strings.Contains(line, "--watch")) || pipe.kind == "less":
+		return []string{fmt.Sprintf("Error: %q is not available when attached", line)}, false
+	}
+
+	a.remoteMu.Lock()
+	defer a.remoteMu.Unlock()
+	lines, _ := a.captureCommand(line)
+	return pipe.filter(lines), false
+}
+
+// Attach connects to the control socket of a running daemon and sends each line typed to it,
+// printing the output, until exit or Ctrl-D
+func Attach(path string) error {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to attach to %s (is the daemon running with daemon.controlSocket set?): %v", path, err)
+	}
+	defer conn.Close()
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          "FIX-MD[attached]> ",
+		InterruptPrompt: "^C",
+		EOFPrompt:       "exit",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create readline: %v", err)
+	}
+	defer rl.Close()
+
+	fmt.Fprintf(rl.Stdout(), "Attached to %s. Commands run in the daemon; exit detaches and leaves it running.\n", path)
+	replies := bufio.NewReader(conn)
+	for {
+		line, err := rl.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		}
+		if err != nil {
+			line = "exit"
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if _, err := fmt.Fprintln(conn, line); err != nil {
+			return fmt.Errorf("daemon connection lost: %v", err)
+		}
+		if err := copyControlReply(rl.Stdout(), replies); err != nil {
+			return err
+		}
+		if cmd := strings.ToLower(strings.Fields(line)[0]); cmd == "exit" || cmd == "detach" {
+			return nil
+		}
+	}
+}
+
+// copyControlReply writes one command's output up to controlEndOfReply
+func copyControlReply(w io.Writer, replies *bufio.Reader) error {
+	for {
+		line, err := replies.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return errors.New("the daemon closed the connection")
+			}
+			return fmt.Errorf("daemon connection lost: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == controlEndOfReply {
+			return nil
+		}
+		fmt.Fprintln(w, line)
+	}
+}
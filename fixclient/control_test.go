@@ -0,0 +1,117 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestControlSocketRunsCommands(t *testing.T) {
+	// Unix socket paths are limited to about 100 bytes, too short for some test temp dirs
+	dir, err := os.MkdirTemp("", "ctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fix-md.sock")
+
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	var daemonOut bytes.Buffer
+	app.SetConsole(&daemonOut, &daemonOut)
+	defer app.SetConsole(nil, nil)
+	if err := app.StartControlSocket(path); err != nil {
+		t.Fatalf("Failed to start control socket: %v", err)
+	}
+	defer app.StopBackground()
+
+	if err := app.StartControlSocket(path); err == nil {
+		t.Fatal("Expected a second listener on the same socket to be refused")
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	replies := bufio.NewReader(conn)
+
+	send := func(line string) string {
+		fmt.Fprintln(conn, line)
+		var out bytes.Buffer
+		if err := copyControlReply(&out, replies); err != nil {
+			t.Fatalf("No reply to %q: %v", line, err)
+		}
+		return out.String()
+	}
+
+	if out := send("help status | head 1"); strings.TrimSpace(out) != "Usage: status [symbol] [--watch [seconds]]" {
+		t.Fatalf("Expected the first line of help status, got %q", out)
+	}
+	if out := send("tail BTC-USD"); !strings.Contains(out, "not available when attached") {
+		t.Fatalf("Expected tail to be refused, got %q", out)
+	}
+	if out := send("exit"); !strings.Contains(out, "Detached") {
+		t.Fatalf("Expected exit to detach, got %q", out)
+	}
+	if _, err := replies.ReadString('\n'); err == nil {
+		t.Fatal("Expected the connection to close after detaching")
+	}
+	if app.ShouldExit() {
+		t.Fatal("Detaching should not stop the daemon")
+	}
+}
+
+func TestControlCommandsWhileMessagesFlow(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	console := &lockedBuffer{}
+	app.SetConsole(console, console)
+	defer app.SetConsole(nil, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			app.FromApp(rawMessage(fmt.Sprintf("35=X\x0149=COIN\x0156=CLIENT\x0134=%d\x0152=20250101-12:00:00.000\x01262=req\x01"+
+				"268=1\x01279=0\x01269=2\x0155=BTC-USD\x01270=100\x01271=1\x01", i+2)), quickfix.SessionID{})
+		}
+	}()
+	var replies []string
+	for i := 0; i < 20; i++ {
+		for _, line := range []string{"stats", "output plain", "status", "output table"} {
+			lines, _ := app.runControlCommand(line)
+			replies = append(replies, lines...)
+		}
+	}
+	<-done
+
+	for _, reply := range replies {
+		if strings.Contains(reply, "BTC-USD Trade") {
+			t.Fatalf("Expected live updates to stay out of the attached client's replies, got %q", reply)
+		}
+	}
+	if !strings.Contains(console.String(), "BTC-USD Trade") {
+		t.Fatalf("Expected live updates on the console, got %q", console.String())
+	}
+}
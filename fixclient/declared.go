@@ -0,0 +1,81 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"prime-fix-md-go/database"
+)
+
+// SetDeclaredSubscriptions sets the md requests sent after every logon. Each entry is the
+// arguments of an md command, e.g. "BTC-USD ETH-USD --subscribe --l1".
+func (a *FixApp) SetDeclaredSubscriptions(entries []string) error {
+	for _, entry := range entries {
+		args := strings.Fields(entry)
+		if len(args) == 0 {
+			return invalidRequest("empty entry in subscriptions")
+		}
+		// Templates are expanded when the request is sent, since saved ones live in the database
+		var flagArgs []string
+		for i, arg := range args {
+			if strings.HasPrefix(arg, "--") {
+				flagArgs = slices.DeleteFunc(slices.Clone(args[i:]), func(arg string) bool { return strings.HasPrefix(arg, "@") })
+				break
+			}
+		}
+		if _, err := a.parseMdFlags(flagArgs); err != nil {
+			return fmt.Errorf("subscription %q: %w", entry, err)
+		}
+	}
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	a.declared = entries
+	return nil
+}
+
+// sendDeclaredSubscriptions sends the configured md requests. Subscriptions left over from
+// a previous connection are dropped first, since the gateway ended them at logout.
+func (a *FixApp) sendDeclaredSubscriptions() {
+	a.settingsMu.RLock()
+	declared := a.declared
+	a.settingsMu.RUnlock()
+	if len(declared) == 0 {
+		return
+	}
+
+	a.declaredMu.Lock()
+	defer a.declaredMu.Unlock()
+	for _, reqId := range a.declaredReqIds {
+		a.endDatabaseSession(reqId, database.EndReasonLogout, a.TradeStore.GetSubscriptionStatus()[reqId])
+		a.TradeStore.RemoveSubscriptionByReqId(reqId)
+	}
+	a.declaredReqIds = nil
+
+	for _, entry := range declared {
+		before := a.TradeStore.GetSubscriptionStatus()
+		log.Printf("Sending configured subscription: md %s", entry)
+		a.handleDirectMdRequest(a.consoleOutput(), append([]string{"md"}, strings.Fields(entry)...))
+		for reqId := range a.TradeStore.GetSubscriptionStatus() {
+			if _, ok := before[reqId]; !ok {
+				a.declaredReqIds = append(a.declaredReqIds, reqId)
+			}
+		}
+	}
+}
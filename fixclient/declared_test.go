@@ -0,0 +1,37 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetDeclaredSubscriptions(t *testing.T) {
+	app := createTestFixApp()
+	if err := app.SetDeclaredSubscriptions([]string{"BTC-USD ETH-USD --subscribe --l1", "SOL-USD --snapshot --trades"}); err != nil {
+		t.Fatalf("Expected valid subscriptions, got %v", err)
+	}
+	if len(app.declared) != 2 {
+		t.Fatalf("Expected 2 declared subscriptions, got %v", app.declared)
+	}
+
+	for _, bad := range []string{"BTC-USD --subscribe --bogus", "  "} {
+		if err := app.SetDeclaredSubscriptions([]string{bad}); !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("Expected ErrInvalidRequest for %q, got %v", bad, err)
+		}
+	}
+}
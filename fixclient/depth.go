@@ -0,0 +1,200 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"prime-fix-md-go/analytics"
+	"prime-fix-md-go/constants"
+
+	"github.com/shopspring/decimal"
+)
+
+const defaultBookViewDepth = 10
+
+// SetCumulativeNotional adds a cumulative notional (price x size) column next to cumulative size
+// in book snapshots
+func (a *FixApp) SetCumulativeNotional(on bool) {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	a.cumNotional = on
+}
+
+func (a *FixApp) cumulativeNotional() bool {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.cumNotional
+}
+
+// depthTotals is the running size and notional from the best level down to one level
+type depthTotals struct {
+	size, notional decimal.Decimal
+}
+
+// add accumulates one level. Levels whose price or size cannot be parsed add nothing.
+func (d *depthTotals) add(price, size string) {
+	s, err := analytics.ParseDecimal(size)
+	if err != nil {
+		return
+	}
+	d.size = d.size.Add(s)
+	if p, err := analytics.ParseDecimal(price); err == nil {
+		d.notional = d.notional.Add(p.Mul(s))
+	}
+}
+
+// addCumulativeDepth fills CumSize (and CumNotional when enabled) on bid and offer entries, in
+// the order they are displayed. Prices and sizes must still be the raw exchange strings.
+func (a *FixApp) addCumulativeDepth(trades []Trade) {
+	cumNotional := a.cumulativeNotional()
+	totals := make(map[string]*depthTotals)
+	for i := range trades {
+		entryType := trades[i].EntryType
+		if entryType != constants.MdEntryTypeBid && entryType != constants.MdEntryTypeOffer {
+			continue
+		}
+		t, ok := totals[entryType]
+		if !ok {
+			t = &depthTotals{}
+			totals[entryType] = t
+		}
+		t.add(trades[i].Price, trades[i].Size)
+		trades[i].CumSize = a.formatSize(trades[i].Symbol, t.size.String())
+		if cumNotional {
+			trades[i].CumNotional = a.formatPrice(trades[i].Symbol, t.notional.String())
+		}
+	}
+}
+
+type bookViewQuery struct {
+	symbol   string
+	depth    int
+	notional bool
+	tables   bool // Separate bid and offer tables instead of the ladder
+}
+
+func parseBookViewQuery(args []string, notional bool) (bookViewQuery, error) {
+	if len(args) < 1 {
+		return bookViewQuery{}, errors.New("usage: book <symbol> [--depth N] [--notional] [--tables]")
+	}
+
+	q := bookViewQuery{symbol: strings.ToUpper(args[0]), depth: defaultBookViewDepth, notional: notional}
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--notional":
+			q.notional = true
+		case "--tables":
+			q.tables = true
+		case "--depth":
+			if i+1 >= len(args) {
+				return q, fmt.Errorf("%s requires a value", args[i])
+			}
+			depth, err := strconv.Atoi(args[i+1])
+			if err != nil || depth < 1 {
+				return q, fmt.Errorf("invalid --depth %q (expected a positive number of levels)", args[i+1])
+			}
+			q.depth = depth
+			i++
+		default:
+			return q, fmt.Errorf("unknown flag %q", args[i])
+		}
+	}
+	return q, nil
+}
+
+// handleBookViewRequest prints the live book for a symbol, best levels first, with the size
+// (and optionally notional) available at or better than each price
+func (a *FixApp) handleBookViewRequest(out output, parts []string) {
+	q, err := parseBookViewQuery(parts[1:], a.cumulativeNotional())
+	if err != nil {
+		out.Error(err)
+		return
+	}
+
+	var book *OrderBook
+	ok := false
+	if a.Books != nil {
+		book, ok = a.Books.Get(q.symbol)
+	}
+	if !ok {
+		out.Info("No live book for %s; subscribe first, e.g. md %s --subscribe --depth 10", q.symbol, q.symbol)
+		return
+	}
+
+	if !q.tables {
+		headers, rows := a.ladder(q, book.Bids(), book.Offers())
+		out.Table(fmt.Sprintf("%s book (%d bids, %d offers):", q.symbol, len(book.Bids()), len(book.Offers())), headers, rows)
+		if book.Crossed() {
+			out.Info("Warning: the %s book is crossed", q.symbol)
+		}
+		return
+	}
+
+	headers := []string{"Pos", "Price", "Size", "Cum Size"}
+	if q.notional {
+		headers = append(headers, "Cum Notional")
+	}
+	headers = append(headers, "Orders")
+
+	for _, side := range []struct {
+		title  string
+		levels []BookLevel
+	}{{"Bids", book.Bids()}, {"Offers", book.Offers()}} {
+		out.Table(fmt.Sprintf("%s %s (%d levels):", q.symbol, side.title, len(side.levels)),
+			headers, a.bookViewRows(q, side.levels))
+	}
+	if book.Crossed() {
+		out.Info("Warning: the %s book is crossed", q.symbol)
+	}
+}
+
+func (a *FixApp) bookViewRows(q bookViewQuery, levels []BookLevel) [][]string {
+	var rows [][]string
+	for i, totals := range cumulativeLevels(levels, q.depth) {
+		level := levels[i]
+		orders := level.NumOrders
+		if orders == "" {
+			orders = "-"
+		}
+		row := []string{strconv.Itoa(i + 1), a.formatPrice(q.symbol, level.Price), a.formatSize(q.symbol, level.Size),
+			a.formatSize(q.symbol, totals.size.String())}
+		if q.notional {
+			row = append(row, a.formatPrice(q.symbol, totals.notional.String()))
+		}
+		rows = append(rows, append(row, orders))
+	}
+	return rows
+}
+
+// cumulativeLevels is the running totals for up to depth levels, best first
+func cumulativeLevels(levels []BookLevel, depth int) []depthTotals {
+	var (
+		result []depthTotals
+		totals depthTotals
+	)
+	for i, level := range levels {
+		if i >= depth {
+			break
+		}
+		totals.add(level.Price, level.Size)
+		result = append(result, totals)
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/constants"
+)
+
+func depthEntries() []Trade {
+	return []Trade{
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeBid, Price: "100", Size: "1.5", Position: "1"},
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeOffer, Price: "101", Size: "2", Position: "1"},
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeBid, Price: "99", Size: "0.5", Position: "2"},
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeOffer, Price: "102", Size: "1", Position: "2"},
+	}
+}
+
+func TestAddCumulativeDepth(t *testing.T) {
+	app := createTestFixApp()
+	app.SetCumulativeNotional(true)
+	trades := depthEntries()
+	app.addCumulativeDepth(trades)
+
+	want := []struct{ size, notional string }{{"1.5", "150"}, {"2", "202"}, {"2", "199.5"}, {"3", "304"}}
+	for i, w := range want {
+		if trades[i].CumSize != w.size || trades[i].CumNotional != w.notional {
+			t.Fatalf("Entry %d: expected cumulative %s/%s, got %s/%s", i, w.size, w.notional, trades[i].CumSize, trades[i].CumNotional)
+		}
+	}
+}
+
+func TestSnapshotShowsCumulativeSize(t *testing.T) {
+	var out bytes.Buffer
+	app := createTestFixApp()
+	app.Renderer, _ = NewRenderer(OutputTable, &out)
+	app.Renderer.Snapshot("BTC-USD", app.displayTrades(depthEntries()))
+
+	text := out.String()
+	if !strings.Contains(text, "Cum Size") || strings.Contains(text, "Cum Notional") {
+		t.Fatalf("Expected a Cum Size column only, got:\n%s", text)
+	}
+	if !strings.Contains(text, "│ 2   │ 99            │ 0.5            │ 2              │") {
+		t.Fatalf("Expected the second bid to show a cumulative size of 2, got:\n%s", text)
+	}
+}
+
+func TestBookViewRows(t *testing.T) {
+	app := createTestFixApp()
+	app.Books = NewBookManager()
+	app.Books.ApplySnapshot("BTC-USD", depthEntries(), time.Now())
+	book, _ := app.Books.Get("BTC-USD")
+
+	q, err := parseBookViewQuery([]string{"btc-usd", "--depth", "1", "--notional"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows := app.bookViewRows(q, book.Offers())
+	if len(rows) != 1 || strings.Join(rows[0], ",") != "1,101,2,2,202,-" {
+		t.Fatalf("Unexpected rows %v", rows)
+	}
+
+	if _, err := parseBookViewQuery([]string{"BTC-USD", "--depth", "0"}, false); err == nil {
+		t.Fatalf("Expected an error for --depth 0")
+	}
+}
+
+func TestLadder(t *testing.T) {
+	app := createTestFixApp()
+	app.Books = NewBookManager()
+	app.Books.ApplySnapshot("BTC-USD", depthEntries(), time.Now())
+	book, _ := app.Books.Get("BTC-USD")
+
+	headers, rows := app.ladder(bookViewQuery{symbol: "BTC-USD", depth: 10}, book.Bids(), book.Offers())
+	if strings.Join(headers, "|") != "Bid Cum|Bid Size|Price|Ask Size|Ask Cum" {
+		t.Fatalf("Unexpected headers %v", headers)
+	}
+	want := []string{
+		"       |        |102|1|3",
+		"       |        |101|2|2",
+		"    1.5|     1.5|100||",
+		"      2|     0.5|99||",
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("Expected %d rows, got %v", len(want), rows)
+	}
+	for i, row := range rows {
+		if got := strings.Join(row, "|"); got != want[i] {
+			t.Fatalf("Row %d: expected %q, got %q", i, want[i], got)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"prime-fix-md-go/analytics"
+
+	"github.com/shopspring/decimal"
+)
+
+const diffTimeFormat = "2006-01-02 15:04:05.000"
+
+type diffQuery struct {
+	symbol string
+	from   time.Time
+	to     time.Time // Zero compares against now
+	ohlcv  bool      // Compare OHLCV values instead of the book
+}
+
+func parseDiffQuery(args []string) (diffQuery, error) {
+	if len(args) < 1 {
+		return diffQuery{}, errors.New("usage: diff <symbol> --from TIME [--to TIME] [--ohlcv]")
+	}
+
+	q := diffQuery{symbol: strings.ToUpper(args[0])}
+	for i := 1; i < len(args); i++ {
+		if args[i] == "--ohlcv" {
+			q.ohlcv = true
+			continue
+		}
+		if i+1 >= len(args) {
+			return q, fmt.Errorf("%s requires a value", args[i])
+		}
+		switch args[i] {
+		case "--from", "--to":
+			at, err := parseExportTime(args[i+1])
+			if err != nil {
+				return q, fmt.Errorf("invalid %s %q (RFC 3339 or YYYY-MM-DD)", args[i], args[i+1])
+			}
+			if args[i] == "--from" {
+				q.from = at
+			} else {
+				q.to = at
+			}
+		default:
+			return q, fmt.Errorf("unknown flag %q", args[i])
+		}
+		i++
+	}
+	if q.from.IsZero() {
+		return q, errors.New("--from is required")
+	}
+	if !q.to.IsZero() && !q.to.After(q.from) {
+		return q, errors.New("--to must be after --from")
+	}
+	return q, nil
+}
+
+// levelChange is one price level whose total size differs between two books
+type levelChange struct {
+	side     string // "Bid" or "Offer"
+	price    decimal.Decimal
+	from, to decimal.Decimal // Zero when the level is absent
+}
+
+// levelSizes totals size per price, since a price can hold several entries when the gateway
+// sends entry ids
+func levelSizes(levels []BookLevel) map[string]decimal.Decimal {
+	sizes := make(map[string]decimal.Decimal)
+	for _, level := range levels {
+		price, err := analytics.ParseDecimal(level.Price)
+		if err != nil {
+			continue
+		}
+		size, _ := analytics.ParseDecimal(level.Size)
+		key := price.String()
+		sizes[key] = sizes[key].Add(size)
+	}
+	return sizes
+}
+
+// diffBooks lists the levels added, removed or resized between two books, offers above bids
+// and highest price first, as in the ladder
+func diffBooks(before, after *OrderBook) []levelChange {
+	var changes []levelChange
+	for _, side := range []struct {
+		name          string
+		before, after []BookLevel
+	}{{"Offer", before.Offers(), after.Offers()}, {"Bid", before.Bids(), after.Bids()}} {
+		from, to := levelSizes(side.before), levelSizes(side.after)
+		var sideChanges []levelChange
+		for key := range union(from, to) {
+			if from[key].Equal(to[key]) {
+				continue
+			}
+			price, _ := decimal.NewFromString(key)
+			sideChanges = append(sideChanges, levelChange{side: side.name, price: price, from: from[key], to: to[key]})
+		}
+		sort.Slice(sideChanges, func(i, j int) bool { return sideChanges[i].price.GreaterThan(sideChanges[j].price) })
+		changes = append(changes, sideChanges...)
+	}
+	return changes
+}
+
+func union(a, b map[string]decimal.Decimal) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	return keys
+}
+
+// handleDiffRequest rebuilds a symbol's book (or OHLCV values) at two times from stored data and
+// shows what changed between them
+func (a *FixApp) handleDiffRequest(out output, parts []string) {
+	q, err := parseDiffQuery(parts[1:])
+	if err != nil {
+		out.Error(err)
+		return
+	}
+
+	toLabel := "now"
+	if !q.to.IsZero() {
+		toLabel = displayTime(q.to, diffTimeFormat)
+	}
+	span := fmt.Sprintf("%s to %s", displayTime(q.from, diffTimeFormat), toLabel)
+
+	if q.ohlcv {
+		a.diffOhlcv(out, q, span)
+		return
+	}
+
+	before, err := a.reconstructBook(q.symbol, q.from)
+	if err != nil {
+		out.Error(err)
+		return
+	}
+	after, err := a.reconstructBook(q.symbol, q.to)
+	if err != nil {
+		out.Error(err)
+		return
+	}
+
+	out.Table(fmt.Sprintf("%s top of book, %s:", q.symbol, span), []string{"", "From", "To"}, [][]string{
+		a.topDiffRow("Best bid", q.symbol, before.BestBid, after.BestBid),
+		a.topDiffRow("Best offer", q.symbol, before.BestOffer, after.BestOffer),
+	})
+
+	changes := diffBooks(before, after)
+	if len(changes) == 0 {
+		out.Info("No level changes for %s, %s", q.symbol, span)
+		return
+	}
+	var rows [][]string
+	for _, c := range changes {
+		rows = append(rows, []string{c.side, a.formatPrice(q.symbol, c.price.String()),
+			a.diffSize(q.symbol, c.from), a.diffSize(q.symbol, c.to), a.sizeChange(q.symbol, c.from, c.to)})
+	}
+	out.Table(fmt.Sprintf("%s level changes (%d):", q.symbol, len(changes)),
+		[]string{"Side", "Price", "From", "To", "Change"}, rows)
+}
+
+func (a *FixApp) topDiffRow(name, symbol string, before, after func() (BookLevel, bool)) []string {
+	row := []string{name}
+	for _, best := range []func() (BookLevel, bool){before, after} {
+		if level, ok := best(); ok {
+			row = append(row, fmt.Sprintf("%s x %s", a.formatPrice(symbol, normalized(level.Price)), a.formatSize(symbol, normalized(level.Size))))
+		} else {
+			row = append(row, "-")
+		}
+	}
+	return row
+}
+
+// normalized drops the trailing zeros SQLite adds to stored values, e.g. 100.0
+func normalized(raw string) string {
+	if d, err := analytics.ParseDecimal(raw); err == nil {
+		return d.String()
+	}
+	return raw
+}
+
+func (a *FixApp) diffSize(symbol string, size decimal.Decimal) string {
+	if size.IsZero() {
+		return "-"
+	}
+	return a.formatSize(symbol, size.String())
+}
+
+func (a *FixApp) sizeChange(symbol string, from, to decimal.Decimal) string {
+	switch {
+	case from.IsZero():
+		return "added"
+	case to.IsZero():
+		return "removed"
+	}
+	return signed(to.Sub(from), func(d string) string { return a.formatSize(symbol, d) })
+}
+
+// signed formats a delta with an explicit + for increases
+func signed(delta decimal.Decimal, format func(string) string) string {
+	if delta.IsPositive() {
+		return "+" + format(delta.String())
+	}
+	return format(delta.String())
+}
+
+var ohlcvDiffOrder = []string{"open", "high", "low", "close", "volume"}
+
+func (a *FixApp) diffOhlcv(out output, q diffQuery, span string) {
+	if a.Db == nil {
+		out.Error(fmt.Errorf("%w: no database to read OHLCV values from", ErrStorage))
+		return
+	}
+	values := make([]map[string]string, 2)
+	for i, at := range []time.Time{q.from, q.to} {
+		rows, err := a.Db.LatestOhlcv(q.symbol, at)
+		if err != nil {
+			out.Error(fmt.Errorf("%w: %v", ErrStorage, err))
+			return
+		}
+		values[i] = make(map[string]string, len(rows))
+		for _, row := range rows {
+			values[i][row.DataType] = normalized(row.Value)
+		}
+	}
+	if len(values[0]) == 0 && len(values[1]) == 0 {
+		out.Info("No stored OHLCV values for %s, %s", q.symbol, span)
+		return
+	}
+
+	var rows [][]string
+	for _, dataType := range ohlcvDiffOrder {
+		from, to := values[0][dataType], values[1][dataType]
+		if from == "" && to == "" {
+			continue
+		}
+		format := func(v string) string { return a.formatPrice(q.symbol, v) }
+		if dataType == "volume" {
+			format = func(v string) string { return a.formatSize(q.symbol, v) }
+		}
+		row := []string{strings.ToUpper(dataType[:1]) + dataType[1:], dashIfEmpty(format(from)), dashIfEmpty(format(to)), "-"}
+		f, errFrom := analytics.ParseDecimal(from)
+		t, errTo := analytics.ParseDecimal(to)
+		if errFrom == nil && errTo == nil {
+			row[3] = signed(t.Sub(f), format)
+		}
+		rows = append(rows, row)
+	}
+	out.Table(fmt.Sprintf("%s OHLCV, %s:", q.symbol, span), []string{"", "From", "To", "Change"}, rows)
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
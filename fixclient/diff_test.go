@@ -0,0 +1,92 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/database"
+)
+
+func TestDiffBooks(t *testing.T) {
+	before, after := newOrderBook("BTC-USD"), newOrderBook("BTC-USD")
+	for _, e := range []Trade{
+		{EntryType: constants.MdEntryTypeBid, Price: "100", Size: "1"},
+		{EntryType: constants.MdEntryTypeBid, Price: "99", Size: "2"},
+		{EntryType: constants.MdEntryTypeOffer, Price: "101", Size: "1"},
+	} {
+		before.apply(e)
+	}
+	for _, e := range []Trade{
+		{EntryType: constants.MdEntryTypeBid, Price: "100", Size: "1.5"},
+		{EntryType: constants.MdEntryTypeBid, Price: "99.00", Size: "2"}, // Same level, written differently
+		{EntryType: constants.MdEntryTypeOffer, Price: "102", Size: "3"},
+	} {
+		after.apply(e)
+	}
+
+	var got []string
+	for _, c := range diffBooks(before, after) {
+		got = append(got, strings.Join([]string{c.side, c.price.String(), c.from.String(), c.to.String()}, " "))
+	}
+	want := "Offer 102 0 3|Offer 101 1 0|Bid 100 1 1.5"
+	if strings.Join(got, "|") != want {
+		t.Fatalf("Expected %q, got %q", want, strings.Join(got, "|"))
+	}
+}
+
+func TestDiffCommand(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "diff.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	store := func(rec database.OrderBookRecord) {
+		rec.Symbol, rec.MdReqId = "BTC-USD", "md_1"
+		if err := db.StoreOrderBookRecord(rec); err != nil {
+			t.Fatalf("Failed to store entry: %v", err)
+		}
+	}
+	store(database.OrderBookRecord{Side: "bid", Price: "100", Size: "1", SeqNum: 2, IsSnapshot: true})
+	store(database.OrderBookRecord{Side: "offer", Price: "101", Size: "2", SeqNum: 2, IsSnapshot: true})
+	from := time.Now()
+	time.Sleep(time.Millisecond)
+	store(database.OrderBookRecord{Side: "bid", Price: "100", Size: "4", SeqNum: 3})
+
+	var out bytes.Buffer
+	app := createTestFixApp()
+	app.Db = db
+	app.Renderer, _ = NewRenderer(OutputPlain, &out)
+	app.handleDiffRequest(app.consoleOutput(), []string{"diff", "btc-usd", "--from", from.Format(time.RFC3339Nano)})
+
+	text := out.String()
+	if !strings.Contains(text, "Best bid\t100 x 1\t100 x 4") || !strings.Contains(text, "Bid\t100\t1\t4\t+3") {
+		t.Fatalf("Unexpected diff output:\n%s", text)
+	}
+	if strings.Contains(text, "Offer\t") {
+		t.Fatalf("Expected the unchanged offer to be left out:\n%s", text)
+	}
+
+	if _, err := parseDiffQuery([]string{"BTC-USD", "--to", "2025-01-01"}); err == nil {
+		t.Fatalf("Expected an error without --from")
+	}
+}
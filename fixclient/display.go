@@ -18,135 +18,53 @@ package fixclient
 
 import (
 	"fmt"
-	"log"
 
 	"prime-fix-md-go/constants"
 )
 
-func (a *FixApp) displayHelp() {
-	fmt.Print(`Commands:
+func (a *FixApp) displayHelp(out output) {
+	fmt.Fprint(out.Console(), `Commands:
   md <symbol> [flags...]        - Market data request
   unsubscribe <symbol|reqId>    - Stop subscription(s) (auto-detects symbol vs reqId)
-  status                        - Show active subscriptions (live data streams only)
-  help                          - Show this help message
-  version, exit
-
-Market Data Request Types:
-  --snapshot                    - One-time data request
-  --subscribe                   - Live data stream (tracked in status)
-  --unsubscribe                 - Cancel specific subscription by original reqId
-
-Market Data Types:
-  --depth N                     - Order book data to specified depth (bids and offers)
-  --trades                      - Executed trades (snap is always 100 most recent)
-  --o, --c, --h, --l, --v       - OHLCV candle data (snapshot is always 100 most recent)
-
-Depth Options:
-  --depth 0                     - Full order book (all available price levels)
-  --depth 1                     - Top of book L1 (best bid + best offer only)
-  --depth 10                    - L10 book (best 10 bids + best 10 offers)
-  --depth N                     - LN book (best N bids + best N offers)
-
-Examples:
-  md BTC-USD --snapshot --trades                      - 100 most recent trades booked
-  md BTC-USD --snapshot --depth 0                     - Complete L2 order book snapshot
-  md BTC-USD --snapshot --depth 1                     - L1 snapshot (best bid + best offer)
-  md BTC-USD --snapshot --depth 10                    - L10 snapshot (best 10 bids + 10 offers)
-  md BTC-USD --subscribe --trades                     - Live trade stream (tracked)
-  md BTC-USD --subscribe --depth 5                    - Live L5 book updates 
-  md ETH-USD --snapshot --o --c --h --l --v           - 100 most recent OHLCV values 
-  md BTC-USD --subscribe --o --c --h --l --v          - Live candle updates
-
-Unsubscribe Examples:
-  unsubscribe BTC-USD                                 - Cancel ALL BTC-USD subscriptions
-  unsubscribe md_1757035274634111000                  - Cancel specific subscription by reqId
-  unsubscribe --reqid md_1757035274634111000          - Cancel specific subscription (explicit)
-  status                                              - See active subscriptions with reqIds
+  template save|list|delete     - Named md flags, used as md <symbol> @name
+  watchlist import <file>       - Load symbols from CSV/JSON (--subscribe to request them all); list, subscribe
+  status [sym] [--watch [secs]] - Show active subscriptions (live data streams only), or debug one symbol
+  stats [symbol...]             - Trade count, volume, notional, VWAP and range from received trades
+  top                           - Best bid/ask, spread and last trade for each subscribed symbol
+  tail <symbol>                 - Follow one symbol's trades only until Ctrl-C
+  last <symbol>                 - Latest trade and best bid/ask (from memory, else the database)
+  candles <symbol> <interval>   - OHLCV bars from stored trades, e.g. candles BTC-USD 5m --since 2h
+                                  (--from/--to for a fixed range, --out bars.csv|bars.json to export)
+  book <symbol> [--depth N]     - Live book as a price ladder with cumulative sizes (--notional, --tables)
+  book export <symbol>          - Current book as JSON (--at TIME to rebuild a past book from the database, --out FILE.json)
+  diff <symbol> --from TIME     - Levels added, removed or resized in the stored book up to --to (or now);
+                                  --ohlcv compares OHLCV values instead
+  replay <symbol> --from TIME   - Play stored updates back, e.g. replay BTC-USD --from 09:30 --to 09:35 --speed 10x
+  gaps [symbol] [--since 24h]   - Suspected gaps in stored data: missing sequence numbers, long silences
+  upload <file> [key]           - Copy a file (export, database copy, ...) to the configured S3/GCS bucket
+  jobs [run <name>]             - Scheduled exports with their last result, or run one now
+  output <format>               - Switch output format (table, plain, json, quiet)
+  resync <symbol>               - Rebuild a live subscription's book from a fresh snapshot
+  raw <tag=value|...>           - Send a hand-built FIX message (advanced, for debugging)
+  preview <md|raw> ...          - Show the message a command would send, without sending it
+  events [--since 1h]           - FIX session timeline: logons, logouts, disconnects, resends, rejects
+  sessions [--label <label>]    - Stored market data requests with their labels, newest first
+  logon --reset                 - Log out and back on with sequence numbers reset to 1
+  seq show                      - The session's next inbound and outbound sequence numbers
+  seq set [--in N] [--out M]    - Change them after confirming, to fix a sequence mismatch
+  quiet [on|off]                - Stop or resume printing live updates (still stored and counted)
+  aggregate [on|off|window]     - Show same-price trades within a window as one print
+  clear                         - Clear the screen (or Ctrl-L)
+  reload                        - Re-read config.json (display, alerts, log level...) without reconnecting
+  help [command]                - This list, or usage, flags and examples for one command
+  version [--json]              - Version, commit, build date, Go version and features
+  exit
+
+Type 'help md' for market data flags and examples, 'help export' for exporting data.
+End a command with '| head N', '| tail N' or '| less' to trim or page long output.
 `)
 }
 
-func (a *FixApp) displaySnapshotTrades(trades []Trade, symbol string) {
-	log.Printf("\n📋 Market Data Snapshot for %s:", symbol)
-
-	// Group entries by type
-	byType := make(map[string][]Trade)
-	for _, trade := range trades {
-		entryType := trade.EntryType
-		if entryType == "" {
-			entryType = "2" // Default to Trade if not specified
-		}
-		byType[entryType] = append(byType[entryType], trade)
-	}
-
-	// Display each type separately
-	for entryType, entries := range byType {
-		typeName := getMdEntryTypeName(entryType)
-		log.Printf("\n🔹 %s Entries (%d):", typeName, len(entries))
-
-		if entryType == constants.MdEntryTypeBid || entryType == constants.MdEntryTypeOffer {
-			// Display bid/offer book format
-			fmt.Printf("┌─────┬───────────────┬────────────────┬───────────────┬──────────┐\n")
-			fmt.Printf("│ Pos │ Price         │ Size           │ Time          │ Type     │\n")
-			fmt.Printf("├─────┼───────────────┼────────────────┼───────────────┼──────────┤\n")
-
-			for _, entry := range entries {
-				pos := entry.Position
-				if pos == "" {
-					pos = "-"
-				}
-				fmt.Printf("│ %-3s │ %-13s │ %-14s │ %-13s │ %-8s │\n",
-					pos, entry.Price, entry.Size, entry.Time, typeName)
-			}
-			fmt.Printf("└─────┴───────────────┴────────────────┴───────────────┴──────────┘\n")
-
-		} else if entryType == constants.MdEntryTypeTrade {
-			// Display trade format
-			fmt.Printf("┌─────┬───────────────┬────────────────┬───────────────┬───────────┐\n")
-			fmt.Printf("│ #   │ Price         │ Size           │ Time          │ Aggressor │\n")
-			fmt.Printf("├─────┼───────────────┼────────────────┼───────────────┼───────────┤\n")
-
-			for i, entry := range entries {
-				aggressor := entry.Aggressor
-				if aggressor == "" {
-					aggressor = "-"
-				}
-				fmt.Printf("│ %-3d │ %-13s │ %-14s │ %-13s │ %-9s │\n",
-					i+1, entry.Price, entry.Size, entry.Time, aggressor)
-			}
-			fmt.Printf("└─────┴───────────────┴────────────────┴───────────────┴───────────┘\n")
-
-		} else {
-			// Display OHLC/Volume format (no size column - not relevant for these data types)
-			fmt.Printf("┌─────┬───────────────┬───────────────┐\n")
-			fmt.Printf("│ #   │ Value         │ Time          │\n")
-			fmt.Printf("├─────┼───────────────┼───────────────┤\n")
-
-			for i, entry := range entries {
-				value := entry.Price
-				if entryType == constants.MdEntryTypeVolume {
-					value = entry.Size // For volume, the "size" field contains the volume
-				}
-
-				fmt.Printf("│ %-3d │ %-13s │ %-13s │\n",
-					i+1, value, entry.Time)
-			}
-			fmt.Printf("└─────┴───────────────┴───────────────┘\n")
-		}
-	}
-
-	log.Printf("\nTotal Entries Displayed: %d", len(trades))
-}
-
-func (a *FixApp) displayIncrementalTrades(trades []Trade) {
-	for _, trade := range trades {
-		a.TradeStore.DisplayRealtimeUpdate(trade)
-	}
-	// Add visual separator after each batch of incremental updates
-	if len(trades) > 0 {
-		log.Println("────────────────────────────────────────────────")
-	}
-}
-
 func (a *FixApp) getSubscriptionTypeDesc(subType string) string {
 	switch subType {
 	case "0":
@@ -205,33 +123,31 @@ func getAggressorSideDesc(side string) string {
 	}
 }
 
-func (a *FixApp) displayMarketDataReject(mdReqId, rejReason, reasonDesc, text string) {
-	log.Printf("Market Data Request REJECTED")
-	log.Printf("   MdReqId: %s", mdReqId)
-	log.Printf("   Reason: %s (%s)", rejReason, reasonDesc)
-	if text != "" {
-		log.Printf("   Text: %s", text)
-	}
-}
-
-func (a *FixApp) displayMarketDataRejectHelp(rejReason string) {
+// mdReqRejHint suggests a fix for the common reject reasons
+func mdReqRejHint(rejReason string) string {
 	switch rejReason {
-	case "0":
-		log.Printf("Try a different symbol format (e.g., BTCUSD vs BTC-USD)")
-	case "3":
-		log.Printf("Check if your account has market data permissions")
-	case "5":
-		log.Printf("Try MarketDepth=0 (full depth) or MarketDepth=1 (top of book)")
-	case "8":
-		log.Printf("Try different MdEntryType: 0=Bids, 1=Offers, 2=Trades")
+	case constants.MdReqRejReasonUnknownSymbol:
+		return "Try a different symbol format (e.g., BTCUSD vs BTC-USD)"
+	case constants.MdReqRejReasonInsufficientPermission:
+		return "Check if your account has market data permissions"
+	case constants.MdReqRejReasonInvalidMarketDepth:
+		return "Try MarketDepth=0 (full depth) or MarketDepth=1 (top of book)"
+	case constants.MdReqRejReasonUnsupportedMdEntryType:
+		return "Try different MdEntryType: 0=Bids, 1=Offers, 2=Trades"
+	default:
+		return ""
 	}
 }
 
-func (a *FixApp) displayConnectionSuccess() {
-	fmt.Print("Connected! Market data connection established.\n\n")
-}
-
-func (a *FixApp) displayMarketDataReceived(msgType, symbol, mdReqId, noMdEntries, seqNum string) {
-	log.Printf("Market Data %s for %s (ReqId: %s, Entries: %s, Seq: %s)",
-		getMarketDataTypeName(msgType), symbol, mdReqId, noMdEntries, seqNum)
+func getMdUpdateActionName(action string) string {
+	switch action {
+	case constants.MdUpdateActionNew:
+		return "New"
+	case constants.MdUpdateActionChange:
+		return "Change"
+	case constants.MdUpdateActionDelete:
+		return "Delete"
+	default:
+		return action
+	}
 }
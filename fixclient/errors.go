@@ -0,0 +1,53 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotConnected       = errors.New("not connected to FIX session")
+	ErrNoSuchSubscription = errors.New("no such subscription")
+	ErrStorage            = errors.New("storage error")
+	ErrTimeout            = errors.New("timed out waiting for response")
+	ErrInvalidRequest     = errors.New("invalid market data request")
+)
+
+// ErrRejected is returned when the gateway answers a MarketDataRequest with a 35=Y
+type ErrRejected struct {
+	MdReqId string
+	Reason  string // MdReqRejReason (281)
+	Text    string
+}
+
+func (e *ErrRejected) Error() string {
+	msg := fmt.Sprintf("market data request %s rejected: %s (%s)", e.MdReqId, getMdReqRejReasonDesc(e.Reason), e.Reason)
+	if e.Text != "" {
+		msg += ": " + e.Text
+	}
+	return msg
+}
+
+func storageError(op string, err error) error {
+	return fmt.Errorf("%w: %s: %v", ErrStorage, op, err)
+}
+
+func invalidRequest(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRequest, fmt.Sprintf(format, args...))
+}
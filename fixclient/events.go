@@ -0,0 +1,88 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+// recordEvent appends an event to the subscription_events timeline of reqId
+func (a *FixApp) recordEvent(reqId, symbol, event, detail string) {
+	if a.Db == nil {
+		return
+	}
+	if err := a.Db.StoreSubscriptionEvent(reqId, symbol, event, detail, time.Now()); err != nil {
+		log.Printf("%v", storageError("failed to store subscription event", err))
+	}
+}
+
+// StartStaleWatch warns about, and records, live subscriptions that receive nothing for
+// after, and records when they recover, until StopBackground is called
+func (a *FixApp) StartStaleWatch(after time.Duration) {
+	if after <= 0 {
+		return
+	}
+
+	every := after / 2
+	if every < time.Second {
+		every = time.Second
+	}
+	go func() {
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		stale := make(map[string]bool)
+		for {
+			select {
+			case <-a.done:
+				return
+			case now := <-ticker.C:
+				a.checkStale(stale, after, now)
+			}
+		}
+	}()
+}
+
+// checkStale compares each subscription's last update with after; stale holds the reqIds
+// already reported and is updated in place
+func (a *FixApp) checkStale(stale map[string]bool, after time.Duration, now time.Time) {
+	subs := a.TradeStore.GetSubscriptionStatus()
+	for reqId := range stale {
+		if _, ok := subs[reqId]; !ok {
+			delete(stale, reqId)
+		}
+	}
+
+	for reqId, sub := range subs {
+		quiet := now.Sub(sub.LastUpdate)
+		switch {
+		case quiet >= after && !stale[reqId]:
+			stale[reqId] = true
+			quietFor := quiet.Truncate(time.Second).String()
+			a.consoleOutput().Info("Warning: no updates for %s (reqId: %s) in %s", sub.Symbol, reqId, quietFor)
+			a.recordEvent(reqId, sub.Symbol, database.EventStale, fmt.Sprintf("no updates for %s", quietFor))
+			a.alertStale(reqId, sub.Symbol, quietFor, false)
+		case quiet < after && stale[reqId]:
+			delete(stale, reqId)
+			a.consoleOutput().Info("Updates for %s (reqId: %s) resumed", sub.Symbol, reqId)
+			a.recordEvent(reqId, sub.Symbol, database.EventRecovered, "")
+			a.alertStale(reqId, sub.Symbol, "", true)
+		}
+	}
+}
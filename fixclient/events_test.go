@@ -0,0 +1,59 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+func TestCheckStaleRecordsStaleAndRecovered(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	var out bytes.Buffer
+	app := createTestFixApp()
+	app.Db = db
+	app.Renderer, _ = NewRenderer(OutputPlain, &out)
+	app.TradeStore.AddSubscription("BTC-USD", "1", "req-1")
+
+	stale := make(map[string]bool)
+	now := time.Now()
+	app.checkStale(stale, time.Minute, now.Add(2*time.Minute))
+	app.checkStale(stale, time.Minute, now.Add(3*time.Minute)) // Already reported
+	if !strings.Contains(out.String(), "no updates for BTC-USD") {
+		t.Fatalf("Expected a stale warning, got %q", out.String())
+	}
+
+	app.TradeStore.AddTrades("BTC-USD", []Trade{{EntryType: "2", Price: "100", Size: "1"}}, false, "req-1")
+	app.checkStale(stale, time.Minute, time.Now())
+
+	events, err := db.QuerySubscriptionEvents("BTC-USD")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Event != database.EventStale || events[1].Event != database.EventRecovered {
+		t.Fatalf("Expected stale then recovered, got %+v", events)
+	}
+}
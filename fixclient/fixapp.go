@@ -17,12 +17,24 @@
 package fixclient
 
 import (
+	"container/list"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"prime-fix-md-go/builder"
 	"prime-fix-md-go/constants"
 	"prime-fix-md-go/database"
+	"prime-fix-md-go/dictionary"
+	"prime-fix-md-go/formatter"
+	"prime-fix-md-go/notify"
+	"prime-fix-md-go/primeapi"
+	"prime-fix-md-go/products"
+	"prime-fix-md-go/tracing"
 	"prime-fix-md-go/utils"
 
 	"github.com/quickfixgo/quickfix"
@@ -35,6 +47,10 @@ type Config struct {
 	SenderCompId string
 	TargetCompId string
 	PortfolioId  string
+	CustomTags   []builder.CustomTag // Appended to Logon and MarketDataRequest bodies
+	Dialect      builder.Dialect     // BeginString/ApplVerID overrides for outgoing application messages
+
+	SessionPortfolios map[quickfix.SessionID]string // Per-session Account (1) overrides; PortfolioId is used otherwise
 }
 
 type FixApp struct {
@@ -43,9 +59,80 @@ type FixApp struct {
 	SessionId  quickfix.SessionID
 	TradeStore *TradeStore
 	Db         *database.MarketDataDb
+	Renderer   Renderer // Console renderer, replaced under settingsMu; read it through consoleOutput once running
+
+	AdminCounters *formatter.AdminCounters // Optional; set when the TableLog factory is in use
+	Clock         *ClockMonitor
+	Products      *products.Catalog // Known symbols; empty until a product source fills it
+	Rest          *primeapi.Client  // Optional Prime REST client; nil when REST is disabled
+	Arrow         *ArrowOutput      // Optional Arrow IPC streams of trades and book entries
+
+	ShowPortfolioOnLogon bool
+
+	Tracer *tracing.Tracer    // Sampled traces of the market data pipeline; nil disables tracing
+	Daemon bool               // Running without the REPL; skips interactive output such as help on logon
+	Alerts *notify.Dispatcher // Pages on disconnects, rejects and stalled subscriptions; nil disables alerting
+
+	ReloadConfig func() error // Re-reads the config file for the reload command and SIGHUP; nil disables reloading
+
+	console       io.Writer                  // Where the renderer writes; the REPL swaps in its prompt-aware writer
+	confirm       func(question string) bool // Asks the REPL user a yes/no question; nil (no REPL) always answers no
+	remoteMu      sync.Mutex                 // Serializes commands from the control socket and the admin API
+	settingsMu    sync.RWMutex               // Guards the output and settings below; commands and reloads replace them while the worker reads them
+	outputFormat  string
+	numbers       NumberFormat              // Console precision and notation for prices and sizes
+	cumNotional   bool                      // Book snapshots show cumulative notional as well as size
+	largePrints   *largePrintRules          // Nil when large prints are not highlighted
+	templates     map[string]*LineTemplates // Output format -> user templates for update lines
+	mdDefaults    MdRequestFlags            // Applied to md requests for anything they leave out
+	declared      []string                  // md requests sent after every logon
+	autoResync    bool                      // Resync a book automatically when it crosses or skips a RptSeq
+	resetOnLogon  bool                      // Every logon asks the gateway to start both sequence numbers at 1
+	uploader      Uploader                  // Optional object storage for exports and archives; nil when uploads are disabled
+	uploadExport  bool                      // Upload every export file after it is written
+	uploadArchive bool                      // Upload every archive file after it is written
+
+	Books *BookManager
+
+	mdTemplates mdTemplateStore // Named md flags, used as @name
+
+	jobs     []*jobState   // Scheduled exports, fixed once started
+	done     chan struct{} // Closed by StopBackground to end jobs and archiving
+	stopOnce sync.Once
 
 	shouldExit    bool
+	startedAt     time.Time
 	lastLogonTime time.Time
+	timeline      sessionTimeline // Session lifecycle events for the events command
+	connected     atomic.Bool
+	quiet         atomic.Bool // Live updates are not printed; see SetQuiet
+	aggregation   tradeAggregation
+	inference     aggressorInference
+	resetPending  atomic.Bool // The next logon carries ResetSeqNumFlag; see ResetSequence
+	disconnect    disconnectWatch
+
+	pendingMu    sync.Mutex
+	pending      map[string]pendingRequest // reqId -> first response or reject
+	pendingOrder list.List                 // reqIds, oldest first; see trackRequest
+
+	routes responseRoutes // Responses to captured commands' requests; see routeResponses
+
+	declaredMu     sync.Mutex
+	declaredReqIds []string // Subscriptions created from declared, dropped on the next logon
+
+	watchlistMu sync.Mutex
+	watchlist   []watchlistEntry // Symbols loaded by watchlist import
+
+	pipeline *pipeline    // Nil until StartPipeline; messages are then handled on its worker
+	budget   *budgetState // Nil when memory is not limited
+
+	strict     atomic.Pointer[dictionary.Validator] // Nil unless inbound messages are checked; see SetStrictValidation
+	validation validationCounters
+	msgTypes   msgTypeCounter // Application messages by MsgType, and recent rejects
+
+	resyncMu   sync.Mutex
+	resyncs    map[string]string    // resync snapshot reqId -> symbol
+	lastResync map[string]time.Time // symbol -> last resync request
 }
 
 func NewConfig(apiKey, apiSecret, passphrase, senderCompId, targetCompId, portfolioId string) *Config {
@@ -59,32 +146,68 @@ func NewConfig(apiKey, apiSecret, passphrase, senderCompId, targetCompId, portfo
 	}
 }
 
+// PortfolioFor returns the portfolio ID sent as Account (1) on the given session's Logon
+func (c *Config) PortfolioFor(sid quickfix.SessionID) string {
+	if id, ok := c.SessionPortfolios[sid]; ok {
+		return id
+	}
+	return c.PortfolioId
+}
+
 func NewFixApp(config *Config, db *database.MarketDataDb) *FixApp {
 	tradeStore := NewTradeStore(10000, "")
+	renderer, _ := NewRenderer(OutputTable, formatter.ASCII(os.Stdout))
 
 	return &FixApp{
 		Config:     config,
 		TradeStore: tradeStore,
 		Db:         db,
+		Renderer:   renderer,
+		console:    os.Stdout,
+		Clock:      NewClockMonitor(DefaultSkewWarnThreshold),
+		Products:   products.NewCatalog(),
+		Books:      NewBookManager(),
 		shouldExit: false,
+		startedAt:  time.Now(),
+		done:       make(chan struct{}),
+		pending:    make(map[string]pendingRequest),
+		resyncs:    make(map[string]string),
+		lastResync: make(map[string]time.Time),
 	}
 }
 
 func (a *FixApp) OnCreate(sid quickfix.SessionID) {
 	a.SessionId = sid
+	a.recordSessionEvent(sid, database.FixEventCreated, "")
 }
 
 func (a *FixApp) OnLogout(sid quickfix.SessionID) {
+	a.connected.Store(false)
 	log.Println("Logout", sid)
+	if a.lastLogonTime.IsZero() {
+		a.recordSessionEvent(sid, database.FixEventDisconnect, "before logon")
+	} else {
+		a.recordSessionEvent(sid, database.FixEventDisconnect, fmt.Sprintf("after %s logged on", time.Since(a.lastLogonTime).Truncate(time.Second)))
+	}
+	if a.resetPending.Load() {
+		log.Printf("Disconnected to reset sequence numbers; logging on again with ResetSeqNumFlag=Y")
+		return
+	}
 
 	timeSinceLogon := time.Since(a.lastLogonTime)
 	if timeSinceLogon < 5*time.Second || a.lastLogonTime.IsZero() {
 		log.Printf("Authentication failed. Exiting to prevent reconnection loop.")
 		a.shouldExit = true
+		a.alertLogout(sid, notify.EventLogonFailure)
+		return
 	}
+	a.alertLogout(sid, notify.EventDisconnect)
+	a.watchDisconnect(sid)
 }
 
-func (a *FixApp) FromAdmin(_ *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+func (a *FixApp) FromAdmin(msg *quickfix.Message, sid quickfix.SessionID) quickfix.MessageRejectError {
+	a.Clock.ObserveMessage(msg, time.Now())
+	a.observeSessionMessage(msg, sid, true)
 	return nil
 }
 
@@ -95,12 +218,26 @@ func (a *FixApp) ToApp(_ *quickfix.Message, _ quickfix.SessionID) error {
 func (a *FixApp) OnLogon(sid quickfix.SessionID) {
 	a.SessionId = sid
 	a.lastLogonTime = time.Now()
-	log.Println("✓ FIX logon", sid)
-	a.displayConnectionSuccess()
-	a.displayHelp()
+	a.connected.Store(true)
+	a.resetPending.Store(false)
+	log.Printf("✓ FIX logon %s (portfolio %s)", sid, a.Config.PortfolioFor(sid))
+	a.recordSessionEvent(sid, database.FixEventLogon, "portfolio "+a.Config.PortfolioFor(sid))
+	a.resolveDisconnect(sid)
+	a.consoleOutput().Info("Connected! Market data connection established.\n")
+	if !a.Daemon {
+		a.displayHelp(a.consoleOutput())
+	}
+	// Off the session goroutine, which must be free to send the requests
+	go a.sendDeclaredSubscriptions()
+
+	if a.Rest != nil && a.ShowPortfolioOnLogon {
+		// Off the session goroutine so a slow REST call cannot delay FIX traffic
+		go a.showPortfolioDetails()
+	}
 }
 
-func (a *FixApp) ToAdmin(msg *quickfix.Message, _ quickfix.SessionID) {
+func (a *FixApp) ToAdmin(msg *quickfix.Message, sid quickfix.SessionID) {
+	a.observeSessionMessage(msg, sid, false)
 	if t, _ := msg.Header.GetString(constants.TagMsgType); t == constants.MsgTypeLogon {
 		ts := time.Now().UTC().Format(constants.FixTimeFormat)
 		builder.BuildLogon(
@@ -110,20 +247,42 @@ func (a *FixApp) ToAdmin(msg *quickfix.Message, _ quickfix.SessionID) {
 			a.Config.ApiSecret,
 			a.Config.Passphrase,
 			a.Config.TargetCompId,
-			a.Config.PortfolioId,
+			a.Config.PortfolioFor(sid),
 		)
+		builder.ApplyCustomTags(&msg.Body, a.Config.CustomTags)
+		// quickfix resets its own sequence numbers when it sees the flag on an outgoing logon
+		if a.resetSeqNumOnLogon() || a.resetPending.Load() {
+			msg.Body.SetField(constants.TagResetSeqNumFlag, quickfix.FIXBoolean(true))
+		}
 	}
 }
 
 func (a *FixApp) FromApp(msg *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
-	if t, _ := msg.Header.GetString(constants.TagMsgType); t == constants.MsgTypeMarketDataSnapshot || t == constants.MsgTypeMarketDataIncremental {
-		a.handleMarketDataMessage(msg)
-	} else if t == "Y" { // Market Data Request Reject
+	received := time.Now()
+	a.Clock.ObserveMessage(msg, received)
+	a.dispatch(msg, received)
+	return nil
+}
+
+// handleApplicationMessage runs on the pipeline worker, or inline when there is no pipeline
+func (a *FixApp) handleApplicationMessage(msg *quickfix.Message, received time.Time) {
+	if v := a.strict.Load(); v != nil {
+		a.validateInbound(v, msg, received)
+	}
+	t, _ := msg.Header.GetString(constants.TagMsgType)
+	count := a.msgTypes.countMessage(t)
+	switch t {
+	case constants.MsgTypeMarketDataSnapshot, constants.MsgTypeMarketDataIncremental:
+		a.handleMarketDataMessage(msg, received)
+		a.enforceMemoryBudget()
+	case constants.MsgTypeMarketDataReject:
 		a.handleMarketDataReject(msg)
-	} else {
-		log.Printf("Received application message type %s", t)
+		a.recordReject(received, "market data reject of "+utils.GetString(msg, constants.TagMdReqId))
+	case constants.MsgTypeBusinessMessageReject:
+		a.handleBusinessReject(msg, received)
+	default:
+		a.handleUnexpectedMessage(t, count, received)
 	}
-	return nil
 }
 
 func (a *FixApp) handleMarketDataReject(msg *quickfix.Message) {
@@ -131,11 +290,23 @@ func (a *FixApp) handleMarketDataReject(msg *quickfix.Message) {
 	rejReason := utils.GetString(msg, constants.TagMdReqRejReason)
 	text := utils.GetString(msg, constants.TagText)
 
-	reasonDesc := getMdReqRejReasonDesc(rejReason)
+	rej := &ErrRejected{MdReqId: mdReqId, Reason: rejReason, Text: text}
 
-	a.displayMarketDataReject(mdReqId, rejReason, reasonDesc, text)
+	a.responseRenderer(mdReqId).Reject(rej, mdReqRejHint(rejReason))
+	symbol := utils.GetString(msg, constants.TagSymbol)
+	a.storeReject(rej, symbol)
+	sub := a.TradeStore.GetSubscriptionStatus()[mdReqId]
+	if sub != nil {
+		a.recordEvent(mdReqId, sub.Symbol, database.EventRejected, rej.Error())
+		symbol = sub.Symbol
+	}
+	if symbol != "" {
+		a.TradeStore.RecordReject(symbol, SubscriptionReject{MdReqId: mdReqId, Detail: rej.Error(), At: time.Now()})
+	}
+	a.alertReject(rej, sub)
+	a.endDatabaseSession(mdReqId, database.EndReasonRejected, sub)
 	a.TradeStore.RemoveSubscriptionByReqId(mdReqId)
-	a.displayMarketDataRejectHelp(rejReason)
+	a.resolveRequest(mdReqId, rej)
 }
 
 func getMdReqRejReasonDesc(reason string) string {
@@ -163,31 +334,143 @@ func getMdReqRejReasonDesc(reason string) string {
 	}
 }
 
+// SetOutputFormat switches all console output to one of table, plain, json or quiet
+func (a *FixApp) SetOutputFormat(format string) error {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	return a.applyOutputFormat(format)
+}
+
+// applyOutputFormat rebuilds the console renderer; the caller holds settingsMu
+func (a *FixApp) applyOutputFormat(format string) error {
+	renderer, err := NewRenderer(format, formatter.ASCII(a.consoleWriter()))
+	if err != nil {
+		return err
+	}
+	setRendererTemplates(renderer, a.templates[rendererFormat(format)])
+	a.Renderer = renderer
+	a.outputFormat = format
+	return nil
+}
+
+// StopBackground ends scheduled jobs and archiving; call it before closing the database
+func (a *FixApp) StopBackground() {
+	a.stopOnce.Do(func() { close(a.done) })
+}
+
 func (a *FixApp) ShouldExit() bool {
 	return a.shouldExit
 }
 
-func (a *FixApp) handleMarketDataMessage(msg *quickfix.Message) {
+// IsConnected reports whether the FIX session is currently logged on
+func (a *FixApp) IsConnected() bool {
+	return a.connected.Load()
+}
+
+func (a *FixApp) handleMarketDataMessage(msg *quickfix.Message, received time.Time) {
+	span := a.startMessageSpan(msg, received)
+	defer span.End()
+
+	parse := span.Child("parse")
 	msgType, _ := msg.Header.GetString(constants.TagMsgType)
 	mdReqId := utils.GetString(msg, constants.TagMdReqId)
 	symbol := utils.GetString(msg, constants.TagSymbol)
+	securityId := utils.GetString(msg, constants.TagSecurityId)
+	securityIdSource := utils.GetString(msg, constants.TagSecurityIdSource)
 	noMdEntries := utils.GetString(msg, constants.TagNoMdEntries)
 	seqNum, _ := msg.Header.GetString(constants.TagMsgSeqNum)
 
+	// Instruments requested by SecurityID are tracked under the ID when no symbol comes back
+	if symbol == "" {
+		symbol = securityId
+	}
+
 	isSnapshot := msgType == constants.MsgTypeMarketDataSnapshot
 	isIncremental := msgType == constants.MsgTypeMarketDataIncremental
 
-	a.displayMarketDataReceived(msgType, symbol, mdReqId, noMdEntries, seqNum)
+	out := a.responseRenderer(mdReqId)
+	quiet := a.quietMessage(mdReqId, isIncremental)
+	if !quiet {
+		out.MarketDataReceived(msgType, symbol, mdReqId, noMdEntries, seqNum)
+	}
+
+	// The entries are only valid until this returns; see tradeBatches
+	batch := getTradeBatch()
+	defer putTradeBatch(batch)
+	trades := a.appendTrades(*batch, msg, symbol, mdReqId, isSnapshot, seqNum)
+	*batch = trades
+	for i := range trades {
+		if trades[i].SecurityId == "" {
+			trades[i].SecurityId, trades[i].SecurityIdSource = securityId, securityIdSource
+		}
+	}
+	if isIncremental {
+		a.inferAggressors(trades)
+		a.markLargePrints(trades, quiet)
+	}
+	span.SetAttr("fix.msg_type", msgType)
+	span.SetAttr("fix.seq_num", seqNum)
+	span.SetAttr("md.symbol", symbol)
+	span.SetAttr("md.req_id", mdReqId)
+	span.SetAttr("md.entries", len(trades))
+	parse.End()
 
-	trades := a.extractTrades(msg, symbol, mdReqId, isSnapshot, seqNum)
+	book := span.Child("book")
+	if a.TradeStore.AddTrades(symbol, trades, isSnapshot, mdReqId) {
+		a.recordEvent(mdReqId, symbol, database.EventSnapshotReceived, fmt.Sprintf("%d entries", len(trades)))
+	}
 
-	a.TradeStore.AddTrades(symbol, trades, isSnapshot, mdReqId)
+	if isSnapshot {
+		a.Books.ApplySnapshot(symbol, trades, received)
+	} else if isIncremental {
+		a.handleBookProblems(a.Books.ApplyIncremental(trades, received))
+	}
+	book.End()
 
-	a.storeTradesToDatabase(trades, seqNum, isSnapshot)
+	store := span.Child("store")
+	if err := a.storeTradesToDatabase(trades, seqNum, isSnapshot); err != nil {
+		store.SetError(err)
+		log.Printf("%v", err)
+	}
+	if err := a.Arrow.Write(trades, seqNum, isSnapshot, received); err != nil {
+		store.SetError(err)
+		log.Printf("%v", err)
+	}
+	store.End()
 
+	display := span.Child("display")
 	if isSnapshot {
-		a.displaySnapshotTrades(trades, symbol)
+		if !quiet {
+			out.Snapshot(symbol, a.displayTrades(trades))
+		}
+		a.completeResync(mdReqId)
 	} else if isIncremental {
-		a.displayIncrementalTrades(trades)
+		// Aggregation runs while quiet too, so prints held back are not shown out of order later
+		if updates := a.aggregateTrades(trades, received); !quiet {
+			out.Updates(a.displayTrades(updates))
+		}
+	}
+	display.End()
+
+	// After display, so a waiting command sees the response already printed
+	a.resolveRequest(mdReqId, nil)
+}
+
+// startMessageSpan starts a sampled trace of one market data message. It begins at the
+// message's SendingTime (52), so the "receive" child covers the trip from the gateway.
+func (a *FixApp) startMessageSpan(msg *quickfix.Message, received time.Time) *tracing.Span {
+	if a.Tracer == nil {
+		return nil
 	}
+	start := received
+	if sendingTime, err := msg.Header.GetString(constants.TagSendingTime); err == nil {
+		// A clock behind the gateway's would put SendingTime after receipt
+		if sent, ok := utils.ParseFixTime(sendingTime, received); ok && sent.Before(received) {
+			start = sent
+		}
+	}
+
+	span := a.Tracer.Start("market_data", start)
+	span.ChildAt("receive", start).EndAt(received)
+	return span
 }
@@ -0,0 +1,186 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+const (
+	defaultGapWindow  = 24 * time.Hour
+	defaultGapSilence = time.Minute
+
+	// Heartbeats, test requests and other session messages use sequence numbers but are not
+	// stored, so a few missing numbers in a row are expected
+	defaultSeqTolerance = 2
+)
+
+type gapsQuery struct {
+	symbol       string // Empty checks every symbol
+	from, to     time.Time
+	silence      time.Duration
+	seqTolerance int
+}
+
+func parseGapsQuery(args []string, now time.Time) (gapsQuery, error) {
+	q := gapsQuery{from: now.Add(-defaultGapWindow), silence: defaultGapSilence, seqTolerance: defaultSeqTolerance}
+	i := 0
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		q.symbol = strings.ToUpper(args[0])
+		i = 1
+	}
+	for ; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return q, fmt.Errorf("%s requires a value", args[i])
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "--since":
+			since, err := time.ParseDuration(value)
+			if err != nil || since <= 0 {
+				return q, fmt.Errorf("invalid --since %q (e.g. 2h, 30m)", value)
+			}
+			q.from = now.Add(-since)
+		case "--from", "--to":
+			at, err := parseExportTime(value)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s %q (RFC 3339 or YYYY-MM-DD)", args[i], value)
+			}
+			if args[i] == "--from" {
+				q.from = at
+			} else {
+				q.to = at
+			}
+		case "--silence":
+			silence, err := time.ParseDuration(value)
+			if err != nil || silence <= 0 {
+				return q, fmt.Errorf("invalid --silence %q (e.g. 30s, 5m)", value)
+			}
+			q.silence = silence
+		case "--seq-tolerance":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return q, fmt.Errorf("invalid --seq-tolerance %q", value)
+			}
+			q.seqTolerance = n
+		default:
+			return q, fmt.Errorf("unknown flag %q", args[i])
+		}
+		i++
+	}
+	if !q.to.IsZero() && !q.to.After(q.from) {
+		return q, fmt.Errorf("--to must be after --from")
+	}
+	return q, nil
+}
+
+// captureGap is one stretch of a capture that should not be trusted
+type captureGap struct {
+	kind     string // "missing seq", "seq reset" or "silence"
+	symbol   string
+	mdReqId  string
+	from, to time.Time
+	detail   string
+}
+
+// findGaps looks for FIX sequence numbers that never made it into storage and for long quiet
+// spells inside a subscription's stream. Sequence numbers are shared by all symbols, so they
+// are checked across every stored message; silences are checked per request id.
+func findGaps(messages []database.MessageRow, q gapsQuery) []captureGap {
+	var gaps []captureGap
+
+	var prev *database.MessageRow
+	for i := range messages {
+		m := &messages[i]
+		if m.SeqNum == 0 {
+			continue
+		}
+		if prev != nil && m.SeqNum != prev.SeqNum {
+			switch missing := m.SeqNum - prev.SeqNum - 1; {
+			case m.SeqNum < prev.SeqNum:
+				gaps = append(gaps, captureGap{kind: "seq reset", from: prev.ReceivedAt, to: m.ReceivedAt,
+					detail: fmt.Sprintf("seq %d -> %d: new FIX session, messages in between may be lost", prev.SeqNum, m.SeqNum)})
+			case missing > q.seqTolerance:
+				gaps = append(gaps, captureGap{kind: "missing seq", from: prev.ReceivedAt, to: m.ReceivedAt,
+					detail: fmt.Sprintf("seq %d-%d not stored (%d messages)", prev.SeqNum+1, m.SeqNum-1, missing)})
+			}
+		}
+		prev = m
+	}
+
+	last := make(map[string]database.MessageRow)
+	for _, m := range messages {
+		if q.symbol != "" && m.Symbol != q.symbol {
+			continue
+		}
+		if p, ok := last[m.MdReqId]; ok {
+			if quiet := m.ReceivedAt.Sub(p.ReceivedAt); quiet >= q.silence {
+				gaps = append(gaps, captureGap{kind: "silence", symbol: m.Symbol, mdReqId: m.MdReqId, from: p.ReceivedAt, to: m.ReceivedAt,
+					detail: fmt.Sprintf("no data for %s", quiet.Round(time.Second))})
+			}
+		}
+		last[m.MdReqId] = m
+	}
+	return gaps
+}
+
+func (a *FixApp) handleGapsRequest(out output, parts []string) {
+	q, err := parseGapsQuery(parts[1:], time.Now())
+	if err != nil {
+		out.Error(err)
+		return
+	}
+	if a.Db == nil {
+		out.Error(fmt.Errorf("%w: no database to check", ErrStorage))
+		return
+	}
+	messages, err := a.Db.QueryMessages(database.TimeRange{From: q.from, To: q.to})
+	if err != nil {
+		out.Error(fmt.Errorf("%w: %v", ErrStorage, err))
+		return
+	}
+
+	scope := "all symbols"
+	if q.symbol != "" {
+		scope = q.symbol
+	}
+	if len(messages) == 0 {
+		out.Info("No stored messages in the window for %s", scope)
+		return
+	}
+
+	gaps := findGaps(messages, q)
+	out.Info("Checked %d stored messages for %s from %s to %s (silence over %s, more than %d missing seq numbers)",
+		len(messages), scope, displayTime(messages[0].ReceivedAt, diffTimeFormat),
+		displayTime(messages[len(messages)-1].ReceivedAt, diffTimeFormat), q.silence, q.seqTolerance)
+	if len(gaps) == 0 {
+		out.Info("No suspected gaps")
+		return
+	}
+
+	var rows [][]string
+	for _, g := range gaps {
+		rows = append(rows, []string{g.kind, dashIfEmpty(g.symbol), dashIfEmpty(g.mdReqId),
+			displayTime(g.from, diffTimeFormat), displayTime(g.to, diffTimeFormat), g.detail})
+	}
+	out.Table(fmt.Sprintf("Suspected gaps (%d):", len(gaps)),
+		[]string{"Kind", "Symbol", "MdReqId", withZone("From"), withZone("To"), "Detail"}, rows)
+}
@@ -0,0 +1,75 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+func TestFindGaps(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	msg := func(seq int, symbol, reqId string, after time.Duration) database.MessageRow {
+		return database.MessageRow{SeqNum: seq, Symbol: symbol, MdReqId: reqId, ReceivedAt: start.Add(after)}
+	}
+	messages := []database.MessageRow{
+		msg(10, "BTC-USD", "md_1", 0),
+		msg(11, "ETH-USD", "md_2", time.Second),
+		msg(13, "BTC-USD", "md_1", 2*time.Second), // One heartbeat in between
+		msg(20, "BTC-USD", "md_1", 3*time.Second), // Six missing
+		msg(21, "ETH-USD", "md_2", 5*time.Minute), // ETH quiet for almost 5 minutes
+		msg(2, "BTC-USD", "md_1", 6*time.Minute),  // New session
+	}
+
+	q := gapsQuery{silence: time.Minute, seqTolerance: 2}
+	var got []string
+	for _, g := range findGaps(messages, q) {
+		got = append(got, g.kind+" "+g.symbol+" "+g.detail)
+	}
+	want := []string{
+		"missing seq  seq 14-19 not stored (6 messages)",
+		"seq reset  seq 21 -> 2: new FIX session, messages in between may be lost",
+		"silence ETH-USD no data for 4m59s",
+		"silence BTC-USD no data for 5m57s",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+
+	q.symbol = "BTC-USD"
+	for _, g := range findGaps(messages, q) {
+		if g.symbol == "ETH-USD" {
+			t.Fatalf("Expected only BTC-USD silences, got %+v", g)
+		}
+	}
+}
+
+func TestParseGapsQuery(t *testing.T) {
+	now := time.Now()
+	q, err := parseGapsQuery([]string{"btc-usd", "--since", "2h", "--silence", "30s"}, now)
+	if err != nil || q.symbol != "BTC-USD" || !q.from.Equal(now.Add(-2*time.Hour)) || q.silence != 30*time.Second {
+		t.Fatalf("Unexpected query %+v (%v)", q, err)
+	}
+	if q, err := parseGapsQuery(nil, now); err != nil || q.symbol != "" || q.seqTolerance != defaultSeqTolerance {
+		t.Fatalf("Unexpected defaults %+v (%v)", q, err)
+	}
+	if _, err := parseGapsQuery([]string{"--silence", "0s"}, now); err == nil {
+		t.Fatalf("Expected an error for a zero silence")
+	}
+}
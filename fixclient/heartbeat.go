@@ -0,0 +1,144 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"prime-fix-md-go/utils"
+)
+
+// Heartbeat is the JSON body posted by the heartbeat notifier
+type Heartbeat struct {
+	Status        string    `json:"status"` // "ok", "disconnected" or "stalled"
+	Version       string    `json:"version"`
+	Host          string    `json:"host"`
+	Time          time.Time `json:"time"`
+	Uptime        string    `json:"uptime"`
+	Connected     bool      `json:"connected"`
+	Subscriptions int       `json:"subscriptions"`
+	Stalled       []string  `json:"stalled,omitempty"` // Symbols with no updates for staleAfter
+	Updates       int64     `json:"updates"`           // Entries received across live subscriptions
+	LastUpdate    time.Time `json:"lastUpdate,omitempty"`
+	RowsWritten   int64     `json:"rowsWritten"`
+}
+
+// HeartbeatNotifier posts a Heartbeat to Url at every interval, or to FailUrl (when set) while
+// the client is disconnected or a feed has stalled, so a monitor such as healthchecks.io
+// notices both a dead process and a dead feed
+type HeartbeatNotifier struct {
+	Url        string
+	FailUrl    string
+	Every      time.Duration
+	StaleAfter time.Duration // A live subscription quiet this long counts as stalled; 0 ignores stalls
+	HttpClient *http.Client
+}
+
+// StartHeartbeat runs n until StopBackground is called
+func (a *FixApp) StartHeartbeat(n *HeartbeatNotifier) {
+	if n == nil || n.Url == "" || n.Every <= 0 {
+		return
+	}
+
+	started := time.Now()
+	go func() {
+		ticker := time.NewTicker(n.Every)
+		defer ticker.Stop()
+		failing := false
+		for {
+			hb := a.heartbeat(started, n.StaleAfter, time.Now())
+			url := n.Url
+			if hb.Status != "ok" && n.FailUrl != "" {
+				url = n.FailUrl
+			}
+			// Log the first failure and the recovery rather than every attempt
+			if err := n.post(url, hb); err != nil {
+				if !failing {
+					log.Printf("Heartbeat to %s failed: %v", url, err)
+				}
+				failing = true
+			} else if failing {
+				log.Printf("Heartbeat delivered again")
+				failing = false
+			}
+
+			select {
+			case <-a.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (a *FixApp) heartbeat(started time.Time, staleAfter time.Duration, now time.Time) Heartbeat {
+	host, _ := os.Hostname()
+	hb := Heartbeat{
+		Status:    "ok",
+		Version:   utils.FullVersion(),
+		Host:      host,
+		Time:      now.UTC(),
+		Uptime:    now.Sub(started).Truncate(time.Second).String(),
+		Connected: a.IsConnected(),
+	}
+
+	subs := a.TradeStore.GetSubscriptionStatus()
+	hb.Subscriptions = len(subs)
+	for _, sub := range subs {
+		hb.Updates += sub.TotalUpdates
+		if sub.LastUpdate.After(hb.LastUpdate) {
+			hb.LastUpdate = sub.LastUpdate.UTC()
+		}
+		if staleAfter > 0 && now.Sub(sub.LastUpdate) >= staleAfter {
+			hb.Stalled = append(hb.Stalled, sub.Symbol)
+		}
+	}
+	if a.Db != nil {
+		hb.RowsWritten = a.Db.WriterStats().Rows
+	}
+
+	switch {
+	case !hb.Connected:
+		hb.Status = "disconnected"
+	case len(hb.Stalled) > 0:
+		hb.Status = "stalled"
+	}
+	return hb
+}
+
+func (n *HeartbeatNotifier) post(url string, hb Heartbeat) error {
+	body, err := json.Marshal(hb)
+	if err != nil {
+		return err
+	}
+	resp, err := n.HttpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatStatus(t *testing.T) {
+	app := createTestFixApp()
+	app.TradeStore.AddSubscription("BTC-USD", "1", "req-1")
+	now := time.Now()
+
+	if hb := app.heartbeat(now, time.Minute, now); hb.Status != "disconnected" || hb.Subscriptions != 1 {
+		t.Fatalf("Expected a disconnected heartbeat with 1 subscription, got %+v", hb)
+	}
+
+	app.connected.Store(true)
+	if hb := app.heartbeat(now, time.Minute, now); hb.Status != "ok" {
+		t.Fatalf("Expected ok, got %+v", hb)
+	}
+	hb := app.heartbeat(now, time.Minute, now.Add(2*time.Minute))
+	if hb.Status != "stalled" || len(hb.Stalled) != 1 || hb.Stalled[0] != "BTC-USD" {
+		t.Fatalf("Expected BTC-USD stalled, got %+v", hb)
+	}
+}
+
+func TestHeartbeatPostsToFailUrl(t *testing.T) {
+	paths := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var hb Heartbeat
+		if err := json.NewDecoder(r.Body).Decode(&hb); err != nil {
+			t.Errorf("Invalid heartbeat body: %v", err)
+		}
+		paths <- r.URL.Path + " " + hb.Status
+	}))
+	defer server.Close()
+
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	defer app.StopBackground()
+	app.StartHeartbeat(&HeartbeatNotifier{
+		Url:        server.URL + "/ping",
+		FailUrl:    server.URL + "/ping/fail",
+		Every:      time.Hour,
+		HttpClient: server.Client(),
+	})
+
+	select {
+	case got := <-paths:
+		if got != "/ping/fail disconnected" {
+			t.Fatalf("Expected a disconnected heartbeat on the fail URL, got %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No heartbeat received")
+	}
+}
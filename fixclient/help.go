@@ -0,0 +1,493 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/chzyer/readline"
+)
+
+// helpTopics is the focused help for each command, shown by "help <command>" and when a
+// command is given without its arguments
+var helpTopics = map[string]string{
+	"md": `Usage: md <symbol1> [symbol2 symbol3 ...] [flags...]
+       md --all [flags...]   - Every product in the product list
+
+Subscription Flags:
+  --snapshot              - Snapshot only
+  --subscribe             - Snapshot + live updates
+  --unsubscribe           - Stop updates
+
+Depth Flag:
+  --depth N               - Market depth (0=full, 1=top, N=best N levels)
+                            Automatically includes both bids and offers
+
+Entry Type Flags:
+  --trades                - Executed trades
+  --bids                  - Bids only
+  --offers                - Offers only
+  --o                     - Opening price
+  --c                     - Closing price
+  --h                     - High price
+  --l                     - Low price
+  --v                     - Trading volume
+
+Preset Flags (combine with each other or the flags above):
+  --l1                    - Top of book: bids + offers at depth 1
+  --book                  - Bids + offers at --depth (default full book)
+  --ohlcv                 - Open, close, high, low and volume
+  --all                   - Trades, bids + offers and OHLCV
+
+Instrument ID Flags (instead of or in addition to symbols):
+  --security-id ID        - Request by SecurityID (48); repeat for several
+  --id-source CODE        - SecurityIDSource (22) for --security-id (default 8 = Exchange Symbol)
+
+Update Flags:
+  --full-refresh          - With --subscribe, receive full books (MdUpdateType=0) instead of incremental updates
+  --aggregated            - One entry per price level (AggregatedBook=Y)
+  --unaggregated          - Per-order book entries where supported (AggregatedBook=N)
+
+Other Flags:
+  --dry-run               - Print the MarketDataRequest instead of sending it
+  --force                 - Send even if a symbol is not in the product list
+  --label NAME            - Tag the request with what it is for, e.g. fomc-day; shown in status
+                            and stored with the request (see sessions)
+
+Examples:
+  md BTC-USD --snapshot --trades
+  md BTC-USD ETH-USD --snapshot --depth 1
+  md BTC-USD ETH-USD SOL-USD --subscribe --depth 10
+  md ETH-USD --snapshot --o --c --h --l --v
+  md ETH-USD --snapshot --ohlcv
+  md BTC-USD --subscribe --l1 --trades
+  md BTC-USD --subscribe --trades --label fomc-day
+  md BTC-USD --unsubscribe
+  md --security-id BTC-USD --id-source 8 --snapshot --trades
+  md --all --subscribe --trades
+  md BTC-USD ETH-USD @l10book       - Flags from a template; see template
+
+Without a symbol, --all requests every product instead of every entry type, 50 symbols per
+request with a short pause between requests. With --unsubscribe it stops every live subscription.
+`,
+
+	"unsubscribe": `Usage: unsubscribe <symbol|reqId>
+Examples:
+  unsubscribe BTC-USD           - Cancel ALL BTC-USD subscriptions
+  unsubscribe md_1234567890     - Cancel specific subscription by reqId
+  unsubscribe --reqid md_123    - Cancel specific subscription (explicit)
+
+  unsubscribe BTC-USD ETH-USD   - Cancel several symbols at once
+
+Run status to see active subscriptions with their reqIds.
+`,
+
+	"template": `Usage: template save <name> <md flags...> | template list | template delete <name>
+
+Names a combination of md flags so it can be reused as @name after the symbols:
+  template save l10book --depth 10 --bids --offers --subscribe
+  md BTC-USD @l10book
+  md ETH-USD @l10book --trades      - Templates combine with other flags
+
+Saved templates are kept in marketdata.db (table md_templates); without a database they last for
+this session. Templates can also be set in md.templates in the config; a saved template with the
+same name takes precedence, and template delete only removes saved ones.
+`,
+
+	"watchlist": `Usage: watchlist import <file> [--force] [--subscribe|--snapshot md flags...] | watchlist [list] | watchlist subscribe [md flags...]
+
+Loads a list of symbols, replacing the current watchlist, and requests market data for all of them:
+  watchlist import symbols.csv --subscribe --trades
+  watchlist import universe.json
+  watchlist subscribe --l1          - Subscribe to the loaded watchlist
+
+CSV files have the columns symbol, depth and entryTypes; the last two are optional, and a header
+row and # comments are skipped. Entry types are md flag names without --, separated by spaces, ;
+or |, e.g.
+  symbol,depth,entryTypes
+  BTC-USD,10,bids offers
+  ETH-USD,,trades
+  SOL-USD
+JSON files (.json) hold an array of symbols, or of {"symbol": "BTC-USD", "depth": 10,
+"entryTypes": ["trades"]} objects. A symbol's depth or entry types replace those among the md flags.
+Symbols with the same overrides share requests of up to 50 symbols, paced as md --all is.
+Symbols must be in the product catalog when one is loaded, unless --force is given.
+`,
+
+	"status": `Usage: status [symbol] [--watch [seconds]]
+
+Shows whether the FIX session is connected, heartbeat and clock skew figures, and the live
+subscriptions (--subscribe) with type, mode, update count, last update time, reqId and label.
+"By Type" splits the update count into bids (B), offers (O), trades (T), OHLCV and other
+entries; types the subscription asked for show even at 0, so a book receiving offers but no
+bids stands out.
+Each symbol also shows the last price, high, low and volume of the trades streamed since the
+client started, so there is market context without an OHLCV subscription; "-" until it trades.
+Snapshots are not listed. Use the reqIds with unsubscribe.
+
+With a symbol, each of its subscriptions is expanded to debug one stream: full reqId, requested
+depth and entry types, when it was subscribed and the latest snapshot arrived, updates per
+second over the last 10 seconds and since subscribing, and its last 5 entries. The session
+summary and the last 5 rejected requests for the symbol follow, so a request that was
+rejected and never tracked still shows up.
+
+--watch redraws the status in place every 2 seconds, or the number given, until Ctrl-C.
+Market data and log output are held back while it runs.
+
+Examples:
+  status
+  status --watch                - Refresh every 2 seconds
+  status --watch 10             - Refresh every 10 seconds
+  status BTC-USD                - Debug the BTC-USD streams
+  status BTC-USD --watch        - Watch their rates and latest entries
+`,
+
+	"stats": `Usage: stats [symbol...]
+
+Trade count, volume, notional, VWAP and low/high/last for the trades received this session,
+per symbol, at exchange precision. Without symbols every traded symbol is shown. Database
+writer throughput, batch sizes, queue depth and latency follow, then the message pipeline's
+queue depth and dropped messages, and estimated memory use with anything shed to stay
+within the memory budget.
+
+Examples:
+  stats
+  stats BTC-USD ETH-USD
+`,
+
+	"top": `Usage: top
+
+One row per subscribed symbol with best bid/ask and sizes, spread, last trade price/size and
+last update time, from the in-memory books and trades. Bid/ask need a book subscription
+(e.g. --l1); last trade needs --trades.
+`,
+
+	"tail": `Usage: tail <symbol>
+
+Follows one symbol's trades, like tail -f, until Ctrl-C. Everything else on the console is
+suppressed meanwhile; data keeps being stored. Needs a live trade subscription.
+
+Example:
+  md BTC-USD --subscribe --trades
+  tail BTC-USD
+`,
+
+	"last": `Usage: last <symbol>
+
+The most recent trade and current best bid/ask. Uses what was received this session and
+falls back to the database, with a Source column saying which.
+`,
+
+	"candles": `Usage: candles <symbol> <interval> [--since DURATION | --from TIME [--to TIME]] [--limit N] [--out FILE]
+
+OHLCV bars aggregated from stored trades, aligned to the interval in UTC. Intervals without
+trades are omitted. Follows the output format.
+
+Flags:
+  --since DURATION        - Bars from this long ago until now (default: the last 100 intervals)
+  --from TIME, --to TIME  - Fixed range, RFC 3339 or YYYY-MM-DD
+  --limit N               - At most N bars
+  --out FILE              - Write the bars to a .csv or .json file instead of printing them
+
+Examples:
+  candles BTC-USD 5m --since 2h
+  candles BTC-USD 1h --from 2025-01-01 --to 2025-01-08 --out btc-1h.csv
+`,
+
+	"book": `Usage: book <symbol> [--depth N] [--notional] [--tables]
+       book export <symbol> [--at TIME] [--out FILE.json]
+
+book <symbol> shows the live in-memory book as a price ladder: prices in the middle, highest
+first, bid sizes on the left and offer sizes on the right, each with a cumulative column: the
+size available at that price or better. --notional adds the cumulative price x size (on by
+default with display.cumulativeNotional). --tables shows separate bid and offer tables instead.
+
+book export serializes an order book to JSON: bids and offers best first with price, size, number of
+orders and entry id. Without --at the live in-memory book is used. With --at (RFC 3339 or
+YYYY-MM-DD), or when there is no live book, the book is rebuilt from the database.
+
+Flags:
+  --depth N               - Levels per side to show (default 10)
+  --notional              - Add a cumulative notional column
+  --tables                - Separate bid and offer tables instead of the ladder
+  --at TIME               - Rebuild the book as it was at TIME (export)
+  --out FILE.json         - Write to a file instead of the console (export)
+
+Examples:
+  book BTC-USD --depth 5 --notional
+  book export BTC-USD
+  book export BTC-USD --at 2025-01-01T12:00:00Z --out btc-book.json
+`,
+
+	"diff": `Usage: diff <symbol> --from TIME [--to TIME] [--ohlcv]
+
+Rebuilds the order book at two times from stored data and shows what changed: best bid and
+offer at each time, then every level that was added, removed or resized, with the size
+change. With --ohlcv, compares the latest stored open, high, low, close and volume values at
+each time instead. Times are RFC 3339 or YYYY-MM-DD; --to defaults to now.
+
+Flags:
+  --from TIME             - The earlier time
+  --to TIME               - The later time (default now)
+  --ohlcv                 - Compare OHLCV values instead of the book
+
+Examples:
+  diff BTC-USD --from 2025-01-01T14:30:00Z --to 2025-01-01T14:31:00Z
+  diff ETH-USD --from 2025-01-01 --ohlcv
+`,
+
+	"replay": `Usage: replay <symbol> --from TIME [--to TIME] [--speed 10x|max] [--sinks]
+
+Plays stored trades and book updates back through the console, spaced out as they were
+received, for reviewing a captured session. The book as it stood at --from is shown first.
+Live data keeps being stored but is not printed until the replay ends or Ctrl-C stops it.
+Quiet stretches are shortened to at most 5 seconds.
+
+Flags:
+  --from TIME             - Start: HH:MM[:SS] today in the display zone, RFC 3339 or YYYY-MM-DD
+  --to TIME               - End (default now)
+  --speed N               - Multiple of real time, e.g. 10x or 0.5x, or max for no delay (default 1x)
+  --sinks                 - Also write the replayed entries to the Arrow streams
+
+Examples:
+  replay BTC-USD --from 09:30 --to 09:35 --speed 10x
+  replay ETH-USD --from 2025-01-01T14:00:00Z --to 2025-01-01T15:00:00Z --speed max
+`,
+
+	"gaps": `Usage: gaps [symbol] [--since DURATION | --from TIME [--to TIME]] [--silence DURATION] [--seq-tolerance N]
+
+Checks what was stored for signs of lost data, so you know which parts of a capture to
+distrust:
+  missing seq  - FIX sequence numbers that never reached storage. Numbers are shared by every
+                 symbol, so this is checked across all stored messages
+  seq reset    - The sequence restarted, i.e. a new FIX session; data in between may be lost
+  silence      - A subscription (request id) went quiet for --silence between two messages
+Heartbeats and other session messages use sequence numbers too and are not stored, so a few
+missing numbers in a row (--seq-tolerance) are not reported.
+
+Flags:
+  --since DURATION        - Check the last DURATION (default 24h)
+  --from TIME / --to TIME - Check a fixed range (RFC 3339 or YYYY-MM-DD)
+  --silence DURATION      - Quiet time reported as a gap (default 1m)
+  --seq-tolerance N       - Missing sequence numbers allowed in a row (default 2)
+
+Examples:
+  gaps
+  gaps BTC-USD --since 2h --silence 30s
+`,
+
+	"export": `Exporting data:
+  book export <symbol> [--at TIME] [--out FILE.json]   - An order book as JSON (help book)
+  candles <symbol> <interval> --out FILE.csv|FILE.json - OHLCV bars (help candles)
+  jobs [run <name>]                                    - Scheduled exports from the config (help jobs)
+  upload <file> [key]                                  - Copy an export to the S3/GCS bucket (help upload)
+`,
+
+	"upload": `Usage: upload <file> [key]
+
+Copies a file (an export, a database copy, ...) to the configured S3/GCS bucket, under
+upload.prefix unless a key is given.
+
+Examples:
+  upload btc-1h.csv
+  upload marketdata.db backups/marketdata.db
+`,
+
+	"jobs": `Usage: jobs [run <name>]
+
+Lists the export jobs from the config with run and failure counts, last run, last result and
+next run. jobs run <name> runs one now over the window ending now.
+`,
+
+	"output": `Usage: output <table|plain|json|quiet>
+
+Switches the console output format:
+  table                   - Aligned tables (default)
+  plain                   - Plain lines
+  json                    - One JSON object per line
+  quiet                   - Errors only
+`,
+
+	"resync": `Usage: resync <symbol> [symbol...]
+
+Re-requests a full snapshot at the depth of the symbol's live book subscription and swaps the
+in-memory book for the rebuilt one when it arrives. Updates keep streaming meanwhile.
+`,
+
+	"raw": `Usage: raw <tag=value|tag=value|...>
+
+Builds a message from the given fields and sends it on the session. MsgType (35) is required;
+BeginString, SenderCompID, TargetCompID and SendingTime are filled in unless given, and
+BodyLength, MsgSeqNum and CheckSum are always set by the session.
+
+Example:
+  raw 35=V|262=test|263=0|264=1|267=1|269=2|146=1|55=BTC-USD
+`,
+
+	"preview": `Usage: preview <md|raw> ...
+
+Builds the message the command would send and prints its tags without sending it.
+
+Examples:
+  preview md BTC-USD --subscribe --depth 10
+  preview raw 35=V|262=test|263=0|264=1|267=1|269=2|146=1|55=BTC-USD
+`,
+
+	"events": `Usage: events [--since <duration>]
+
+Prints the FIX session timeline: created, logon, logout (a Logout sent or received, with its
+Text), disconnect, resend (ResendRequest ranges), sequence_reset, reject (session-level
+Rejects) and seq_set (logon --reset and seq set). Without --since the timeline starts when the
+client did. Events are stored in marketdata.db (table fix_session_events), so --since reaches
+back into earlier runs; with --no-persist the latest 1000 are kept in memory.
+
+Examples:
+  events
+  events --since 24h
+`,
+
+	"sessions": `Usage: sessions [--label <label>] [--limit N]
+
+Lists the market data requests stored in marketdata.db (table sessions), newest first: when
+each started and ended, symbol, snapshot or subscribe, data stored, update count, reqId and the
+label given with md --label. --label shows only the requests with that label, so a capture can
+be found by its purpose rather than its reqId. Shows the latest 50 unless --limit is given.
+
+Examples:
+  sessions
+  sessions --label fomc-day
+`,
+
+	"logon": `Usage: logon --reset
+
+Recovers a session whose sequence numbers no longer match the gateway's. The session logs out,
+both sequence numbers are reset to 1, and the reconnect (after ReconnectInterval in fix.cfg)
+sends Logon with ResetSeqNumFlag(141)=Y. Live subscriptions end with the old connection;
+subscriptions from config.json are sent again after logon. When not logged on, the next logon
+carries the flag. Set session.resetSeqNumOnLogon in config.json to send it on every logon.
+
+Example:
+  logon --reset
+`,
+
+	"seq": `Usage: seq show
+       seq set [--in N] [--out M]
+
+show prints the session's next sequence numbers from the message store: In is the MsgSeqNum
+expected on the next message from the gateway, Out the MsgSeqNum of the next message sent.
+
+set overwrites either or both, after asking for confirmation, for fixing a sequence mismatch
+without editing store files by hand. A too-low Out or too-high In makes the gateway or the
+client log out; a too-high Out or too-low In triggers a resend or gap fill. To start both over
+at 1, use logon --reset instead.
+
+Examples:
+  seq show
+  seq set --in 1042
+  seq set --in 1042 --out 377
+`,
+
+	"quiet": `Usage: quiet [on|off]
+
+Quiet mode stops printing market data for live subscriptions while it keeps being stored,
+streamed to Arrow and counted in status and stats. Snapshots you request with md, command
+output and session events still print. Without an argument, shows whether quiet mode is on.
+Start with --quiet to begin quiet, for headless capture with occasional inspection.
+`,
+
+	"aggregate": `Usage: aggregate [on|off|<window>]
+
+Coalesces consecutive trades of a symbol at the same price and aggressor into one print,
+showing the total size and the number of trades, e.g. "aggregate 500ms". A print is shown
+when a trade at another price or side arrives or when the window since its first trade has
+passed, so streaming trades appear up to one window late. "on" uses display.aggregateTrades.window,
+or 250ms when the config has none; "off" shows the open prints and then every trade again.
+Each trade is still stored on its own; with display.aggregateTrades.store on, prints of two
+or more trades are also written to the trade_prints table. Without an argument, shows the
+current state.
+`,
+
+	"clear": `Usage: clear
+
+Clears the screen; Ctrl-L does the same while typing. Streaming output is printed above the
+prompt, and the line being typed is redrawn after it, so commands can be typed during a live
+stream.
+`,
+	"reload": `Usage: reload
+
+Re-reads the config file (config.json, or the --config path) and applies what can change while
+connected: log.verbose, session.halfDeadAlertAfter, display settings, alerts, md defaults,
+book.autoResync and upload. Changed subscriptions are sent from the next logon. Other changed
+settings are listed in the log and take effect after a restart. An invalid file is reported
+and nothing is changed. Sending the process SIGHUP does the same.
+
+Example:
+  reload
+`,
+
+	"help": `Usage: help [command]
+
+Without a command, lists all commands. With one, shows its usage, flags and examples.
+
+Any command can end with "| head [N]", "| tail [N]" or "| less" to trim or page its output.
+`,
+
+	"version": `Usage: version [--json]
+
+Shows the client version, the git commit and build date it was built from, the Go version
+and platform, and optional features compiled in (sqlite needs cgo). Include it in bug
+reports. --json prints the same as a JSON object.
+`,
+
+	"exit": `Usage: exit
+
+Logs out of the FIX session and quits; live subscriptions end with the session.
+Ctrl-D does the same.
+`,
+}
+
+// printCommandHelp prints the help for one command, e.g. "md" or "book export"
+func printCommandHelp(w io.Writer, topic string) {
+	if fields := strings.Fields(topic); len(fields) > 0 {
+		topic = strings.ToLower(fields[0])
+	}
+	text, ok := helpTopics[topic]
+	if !ok {
+		fmt.Fprintf(w, "No help for %q. Help is available for: %s\n", topic, strings.Join(helpTopicNames(), ", "))
+		return
+	}
+	fmt.Fprint(w, text)
+}
+
+func helpTopicNames() []string {
+	names := make([]string, 0, len(helpTopics))
+	for name := range helpTopics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func helpCompletions() []readline.PrefixCompleterInterface {
+	items := make([]readline.PrefixCompleterInterface, 0, len(helpTopics))
+	for _, name := range helpTopicNames() {
+		items = append(items, readline.PcItem(name))
+	}
+	return items
+}
@@ -0,0 +1,47 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEveryCommandHasHelp(t *testing.T) {
+	commands := []string{"md", "unsubscribe", "template", "watchlist", "status", "stats", "top", "tail", "last", "candles", "book", "diff", "replay", "gaps",
+		"upload", "jobs", "output", "resync", "raw", "preview", "events", "sessions", "logon", "seq", "quiet", "aggregate", "clear", "reload", "help", "version", "exit"}
+	for _, cmd := range commands {
+		text, ok := helpTopics[cmd]
+		if !ok {
+			t.Fatalf("No help topic for %s", cmd)
+		}
+		if !strings.HasPrefix(text, "Usage: "+cmd) || !strings.HasSuffix(text, "\n") {
+			t.Fatalf("Help for %s should start with its usage line and end with a newline:\n%s", cmd, text)
+		}
+	}
+}
+
+func TestHelpCompletions(t *testing.T) {
+	if got := len(helpCompletions()); got != len(helpTopics) {
+		t.Fatalf("Expected %d help completions, got %d", len(helpTopics), got)
+	}
+	names := helpTopicNames()
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Fatalf("Help topics not sorted: %v", names)
+		}
+	}
+}
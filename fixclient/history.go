@@ -0,0 +1,105 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReplOptions configures the interactive prompt
+type ReplOptions struct {
+	HistoryFile  string // Empty uses DefaultHistoryFile
+	HistorySize  int    // Commands kept; negative disables history
+	HistoryDedup bool   // Keep only the latest copy of a repeated command
+}
+
+// DefaultHistoryFile is ~/.fixmd_history, or a per-user file in the temp dir when there is no home directory
+func DefaultHistoryFile() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".fixmd_history")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("fixmd_history_%d", os.Getuid()))
+}
+
+// historyPath expands a leading "~/" and fills in the default
+func historyPath(path string) string {
+	if path == "" {
+		return DefaultHistoryFile()
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// prepareHistory creates the history file readable only by its owner, and when dedup is set
+// rewrites it keeping the latest copy of each command, at most limit of them
+func prepareHistory(path string, limit int, dedup bool) error {
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if !dedup {
+		return nil
+	}
+
+	kept := dedupHistory(lines, limit)
+	if len(kept) == len(lines) {
+		return nil
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(kept, "\n")+"\n"), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// dedupHistory keeps the last occurrence of each line, in order, and then the newest limit lines
+func dedupHistory(lines []string, limit int) []string {
+	seen := make(map[string]bool, len(lines))
+	kept := make([]string, 0, len(lines))
+	for i := len(lines) - 1; i >= 0; i-- {
+		if seen[lines[i]] {
+			continue
+		}
+		seen[lines[i]] = true
+		kept = append(kept, lines[i])
+	}
+	if limit > 0 && len(kept) > limit {
+		kept = kept[:limit]
+	}
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	return kept
+}
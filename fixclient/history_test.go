@@ -0,0 +1,78 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDedupHistoryKeepsLatest(t *testing.T) {
+	lines := []string{"status", "md BTC-USD --snapshot --l1", "status", "top", "md ETH-USD --subscribe --trades", "top"}
+
+	got := dedupHistory(lines, 0)
+	want := []string{"md BTC-USD --snapshot --l1", "status", "md ETH-USD --subscribe --trades", "top"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+
+	got = dedupHistory(lines, 2)
+	want = []string{"md ETH-USD --subscribe --trades", "top"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected the newest 2 commands %v, got %v", want, got)
+	}
+}
+
+func TestPrepareHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	if err := prepareHistory(path, 100, true); err != nil {
+		t.Fatalf("Failed to create history: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("History file not created: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	os.WriteFile(path, []byte("status\ntop\n\nstatus\n"), 0600)
+	if err := prepareHistory(path, 100, true); err != nil {
+		t.Fatalf("Failed to compact history: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "top\nstatus\n" {
+		t.Fatalf("Unexpected compacted history %q", data)
+	}
+}
+
+func TestHistoryPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	if got := historyPath("~/fix/history"); got != filepath.Join(home, "fix", "history") {
+		t.Fatalf("Expected ~ expanded, got %s", got)
+	}
+	if got := historyPath(""); got != DefaultHistoryFile() {
+		t.Fatalf("Expected the default history file, got %s", got)
+	}
+	if got := historyPath("/var/tmp/h"); got != "/var/tmp/h" {
+		t.Fatalf("Expected absolute path unchanged, got %s", got)
+	}
+}
@@ -0,0 +1,326 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"prime-fix-md-go/database"
+	"prime-fix-md-go/export"
+)
+
+const (
+	JobExportTrades  = "trades"
+	JobExportCandles = "candles"
+	JobExportBook    = "book"
+)
+
+// ExportJob writes one export file per run. Runs are aligned to multiples of Every in UTC,
+// so an hourly job runs on the hour and exports the hour that just ended.
+type ExportJob struct {
+	Name     string
+	Export   string // JobExportTrades, JobExportCandles or JobExportBook
+	Symbol   string
+	Every    time.Duration
+	Window   time.Duration // Span exported by trades and candles runs; defaults to Every
+	Interval time.Duration // Bar size for candles
+	Dir      string
+	Format   string // csv, json or arrow (trades only); book exports are always json
+}
+
+func (j *ExportJob) normalize() error {
+	j.Symbol = strings.ToUpper(j.Symbol)
+	j.Format = strings.ToLower(j.Format)
+	if j.Window == 0 {
+		j.Window = j.Every
+	}
+	if j.Dir == "" {
+		j.Dir = "."
+	}
+
+	switch {
+	case j.Name == "":
+		return fmt.Errorf("export job needs a name")
+	case j.Symbol == "":
+		return fmt.Errorf("job %s: symbol is required", j.Name)
+	case j.Every < time.Minute:
+		return fmt.Errorf("job %s: every must be at least 1m", j.Name)
+	case j.Window <= 0:
+		return fmt.Errorf("job %s: invalid window", j.Name)
+	}
+
+	formats := map[string]bool{export.FormatCsv: true, export.FormatJson: true}
+	switch j.Export {
+	case JobExportTrades:
+		formats[export.FormatArrow] = true
+	case JobExportCandles:
+		if j.Interval <= 0 {
+			return fmt.Errorf("job %s: candles need an interval", j.Name)
+		}
+	case JobExportBook:
+		formats = map[string]bool{export.FormatJson: true}
+		if j.Format == "" {
+			j.Format = export.FormatJson
+		}
+	default:
+		return fmt.Errorf("job %s: unknown export %q (expected trades, candles or book)", j.Name, j.Export)
+	}
+	if j.Format == "" {
+		j.Format = export.FormatCsv
+	}
+	if j.Format == "parquet" {
+		return fmt.Errorf("job %s: parquet is not supported; use arrow, which pandas, polars and DuckDB read directly", j.Name)
+	}
+	if !formats[j.Format] {
+		return fmt.Errorf("job %s: format %q is not available for %s exports", j.Name, j.Format, j.Export)
+	}
+	return nil
+}
+
+// nextRun is the first scheduled time after now
+func (j *ExportJob) nextRun(now time.Time) time.Time {
+	return now.Truncate(j.Every).Add(j.Every)
+}
+
+// path is the file written by the run scheduled at runAt
+func (j *ExportJob) path(runAt time.Time) string {
+	name := fmt.Sprintf("%s-%s-%s.%s", j.Symbol, j.Export, runAt.UTC().Format("20060102T150405Z"), j.Format)
+	return filepath.Join(j.Dir, name)
+}
+
+type jobState struct {
+	job ExportJob
+
+	mu       sync.Mutex
+	running  bool
+	runs     int
+	failures int
+	nextRun  time.Time
+	lastRun  time.Time
+	lastFile string
+	lastRows int
+	lastErr  error
+}
+
+// StartJobs validates the jobs and schedules them until StopBackground is called
+func (a *FixApp) StartJobs(jobs []ExportJob) error {
+	names := make(map[string]bool)
+	states := make([]*jobState, 0, len(jobs))
+	for _, job := range jobs {
+		if err := job.normalize(); err != nil {
+			return err
+		}
+		if names[job.Name] {
+			return fmt.Errorf("duplicate job name %q", job.Name)
+		}
+		names[job.Name] = true
+		if err := os.MkdirAll(job.Dir, 0o755); err != nil {
+			return fmt.Errorf("job %s: %v", job.Name, err)
+		}
+		states = append(states, &jobState{job: job})
+	}
+
+	a.jobs = states
+	for _, s := range states {
+		go a.scheduleJob(s, a.done)
+	}
+	return nil
+}
+
+func (a *FixApp) scheduleJob(s *jobState, stop <-chan struct{}) {
+	for {
+		next := s.job.nextRun(time.Now())
+		s.mu.Lock()
+		s.nextRun = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			a.runJob(s, next)
+		}
+	}
+}
+
+// runJob exports the window ending at runAt. Overlapping runs of the same job are skipped.
+func (a *FixApp) runJob(s *jobState, runAt time.Time) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		log.Printf("Job %s: previous run still in progress, skipping %s", s.job.Name, runAt.UTC().Format(time.RFC3339))
+		return
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	path := s.job.path(runAt)
+	rows, err := a.exportJob(&s.job, runAt, path)
+
+	s.mu.Lock()
+	s.running = false
+	s.runs++
+	s.lastRun = runAt
+	s.lastErr = err
+	if err != nil {
+		s.failures++
+	} else {
+		s.lastFile, s.lastRows = path, rows
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		log.Printf("Job %s failed: %v", s.job.Name, err)
+		return
+	}
+	log.Printf("Job %s exported %d rows to %s", s.job.Name, rows, path)
+	a.exported(a.consoleOutput(), path)
+}
+
+func (a *FixApp) exportJob(job *ExportJob, runAt time.Time, path string) (int, error) {
+	window := database.TimeRange{From: runAt.Add(-job.Window), To: runAt}
+
+	switch job.Export {
+	case JobExportTrades:
+		if a.Db == nil {
+			return 0, fmt.Errorf("%w: no database for stored trades", ErrStorage)
+		}
+		stored, err := a.Db.QueryTrades(job.Symbol, window, 0)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrStorage, err)
+		}
+		trades := make([]export.TradeUpdate, 0, len(stored))
+		for _, t := range stored {
+			trades = append(trades, export.TradeUpdate{
+				Symbol:         t.Symbol,
+				TradeTime:      t.TradeTime,
+				ReceivedAt:     t.ReceivedAt,
+				Price:          t.Price,
+				Size:           t.Size,
+				AggressorSide:  t.AggressorSide,
+				TradeCondition: t.TradeCondition,
+				SeqNum:         int64(t.SeqNum),
+				MdReqId:        t.MdReqId,
+				IsSnapshot:     t.IsSnapshot,
+			})
+		}
+		return len(trades), export.WriteTradesFile(path, trades)
+
+	case JobExportCandles:
+		bars, err := a.storedCandles(job.Symbol, job.Interval, window)
+		if err != nil {
+			return 0, err
+		}
+		records := candleRecords(job.Symbol, job.Interval, bars, bars.Candles())
+		return len(records), export.WriteCandlesFile(path, records)
+
+	default:
+		snapshot, err := a.bookSnapshot(job.Symbol, time.Time{})
+		if err != nil {
+			return 0, err
+		}
+		return len(snapshot.Bids) + len(snapshot.Offers), export.WriteBookSnapshotFile(path, snapshot)
+	}
+}
+
+func (a *FixApp) findJob(name string) *jobState {
+	for _, s := range a.jobs {
+		if s.job.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (a *FixApp) completionJobs(string) []string {
+	names := make([]string, 0, len(a.jobs))
+	for _, s := range a.jobs {
+		names = append(names, s.job.Name)
+	}
+	return names
+}
+
+// handleJobsRequest lists scheduled exports or runs one immediately with "jobs run <name>"
+func (a *FixApp) handleJobsRequest(out output, parts []string) {
+	if len(parts) >= 2 && parts[1] == "run" {
+		if len(parts) != 3 {
+			fmt.Fprintln(out.Console(), "Usage: jobs run <name>")
+			return
+		}
+		s := a.findJob(parts[2])
+		if s == nil {
+			out.Error(invalidRequest("no job named %s", parts[2]))
+			return
+		}
+		out.Info("Running job %s", s.job.Name)
+		go a.runJob(s, time.Now().UTC().Truncate(time.Second))
+		return
+	}
+	if len(parts) != 1 {
+		fmt.Fprintln(out.Console(), "Usage: jobs [run <name>]")
+		return
+	}
+
+	if len(a.jobs) == 0 {
+		out.Info("No export jobs configured (see jobs in config.json)")
+		return
+	}
+
+	rows := make([][]string, 0, len(a.jobs))
+	for _, s := range a.jobs {
+		rows = append(rows, s.row())
+	}
+	out.Table("Export jobs:",
+		[]string{"Name", "Export", "Symbol", "Every", "Runs", "Failed", "Last Run", "Last Result", "Next Run"}, rows)
+}
+
+func (s *jobState) row() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kind := s.job.Export
+	if s.job.Export == JobExportCandles {
+		kind += " " + s.job.Interval.String()
+	}
+	result := "-"
+	switch {
+	case s.running:
+		result = "running"
+	case s.lastErr != nil:
+		result = "error: " + s.lastErr.Error()
+	case s.runs > 0:
+		result = fmt.Sprintf("%d rows -> %s", s.lastRows, s.lastFile)
+	}
+	lastRun := "-"
+	if !s.lastRun.IsZero() {
+		lastRun = s.lastRun.UTC().Format(time.DateTime)
+	}
+	nextRun := "-"
+	if !s.nextRun.IsZero() {
+		nextRun = s.nextRun.UTC().Format(time.DateTime)
+	}
+	return []string{s.job.Name, kind + " " + s.job.Format, s.job.Symbol, s.job.Every.String(),
+		strconv.Itoa(s.runs), strconv.Itoa(s.failures), lastRun, result, nextRun}
+}
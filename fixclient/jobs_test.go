@@ -0,0 +1,89 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+func TestExportJobValidation(t *testing.T) {
+	job := ExportJob{Name: "hourly", Export: JobExportTrades, Symbol: "btc-usd", Every: time.Hour}
+	if err := job.normalize(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if job.Symbol != "BTC-USD" || job.Window != time.Hour || job.Format != "csv" {
+		t.Fatalf("Expected defaults to be filled in, got %+v", job)
+	}
+
+	for _, bad := range []ExportJob{
+		{Name: "pq", Export: JobExportTrades, Symbol: "BTC-USD", Every: time.Hour, Format: "parquet"},
+		{Name: "bars", Export: JobExportCandles, Symbol: "BTC-USD", Every: time.Hour},
+		{Name: "book", Export: JobExportBook, Symbol: "BTC-USD", Every: time.Hour, Format: "csv"},
+		{Name: "fast", Export: JobExportTrades, Symbol: "BTC-USD", Every: time.Second},
+	} {
+		if err := bad.normalize(); err == nil {
+			t.Fatalf("Expected job %s to be rejected", bad.Name)
+		}
+	}
+
+	at := time.Date(2025, 1, 1, 12, 34, 56, 0, time.UTC)
+	if next := job.nextRun(at); !next.Equal(time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected the next run on the hour, got %v", next)
+	}
+}
+
+func TestRunTradesJob(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.StoreTrade("BTC-USD", "101", "1", "Buy", "20250101-12:00:02", 1, "req", false)
+	db.StoreTrade("BTC-USD", "99", "1", "Sell", "20250101-11:59:59", 2, "req", false)
+
+	app := createTestFixApp()
+	app.Db = db
+	dir := t.TempDir()
+	job := ExportJob{Name: "hourly", Export: JobExportTrades, Symbol: "BTC-USD", Every: time.Hour, Dir: dir}
+	if err := job.normalize(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s := &jobState{job: job}
+
+	runAt := time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)
+	app.runJob(s, runAt)
+	if s.runs != 1 || s.lastErr != nil || s.lastRows != 1 {
+		t.Fatalf("Expected one successful run with 1 row, got runs=%d rows=%d err=%v", s.runs, s.lastRows, s.lastErr)
+	}
+
+	path := filepath.Join(dir, "BTC-USD-trades-20250101T130000Z.csv")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected export file: %v", err)
+	}
+	if !strings.Contains(string(data), "BTC-USD,2025-01-01T12:00:02Z") || strings.Contains(string(data), "11:59:59") {
+		t.Fatalf("Expected only the trade inside the window, got:\n%s", data)
+	}
+	if row := s.row(); row[7] != "1 rows -> "+path {
+		t.Fatalf("Unexpected status row %v", row)
+	}
+}
@@ -0,0 +1,72 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ladder lays a book out the way a trading screen does: one row per price, highest first, with
+// offers above bids. Bid sizes sit left of the price and offer sizes right of it, each with the
+// size (and optionally notional) available at that price or better.
+func (a *FixApp) ladder(q bookViewQuery, bids, offers []BookLevel) ([]string, [][]string) {
+	headers := []string{"Bid Cum", "Bid Size", "Price", "Ask Size", "Ask Cum"}
+	if q.notional {
+		headers = append([]string{"Bid Notional"}, append(headers, "Ask Notional")...)
+	}
+	bidColumns := len(headers) / 2
+
+	var rows [][]string
+	askTotals := cumulativeLevels(offers, q.depth)
+	for i := len(askTotals) - 1; i >= 0; i-- {
+		row := make([]string, bidColumns)
+		row = append(row, a.formatPrice(q.symbol, offers[i].Price), a.formatSize(q.symbol, offers[i].Size),
+			a.formatSize(q.symbol, askTotals[i].size.String()))
+		if q.notional {
+			row = append(row, a.formatPrice(q.symbol, askTotals[i].notional.String()))
+		}
+		rows = append(rows, row)
+	}
+	for i, totals := range cumulativeLevels(bids, q.depth) {
+		var row []string
+		if q.notional {
+			row = append(row, a.formatPrice(q.symbol, totals.notional.String()))
+		}
+		row = append(row, a.formatSize(q.symbol, totals.size.String()), a.formatSize(q.symbol, bids[i].Size),
+			a.formatPrice(q.symbol, bids[i].Price))
+		rows = append(rows, append(row, make([]string, len(headers)-len(row))...))
+	}
+
+	if format := a.currentFormat(); format == "" || format == OutputTable {
+		alignRight(headers, rows, bidColumns)
+	}
+	return headers, rows
+}
+
+// alignRight pads the first n columns on the left so bid sizes line up against the price column
+func alignRight(headers []string, rows [][]string, n int) {
+	for col := 0; col < n; col++ {
+		width := utf8.RuneCountInString(headers[col])
+		for _, row := range rows {
+			width = max(width, utf8.RuneCountInString(row[col]))
+		}
+		headers[col] = fmt.Sprintf("%*s", width, headers[col])
+		for _, row := range rows {
+			row[col] = fmt.Sprintf("%*s", width, row[col])
+		}
+	}
+}
@@ -0,0 +1,176 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"prime-fix-md-go/analytics"
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/notify"
+
+	"github.com/shopspring/decimal"
+)
+
+const largePrintColor = "\x1b[1;33m"
+
+// Large prints are colored only on terminals that accept color; set by SetLargePrints
+var highlightLargePrints atomic.Bool
+
+// LargePrints flags trades at or above a size or notional (price x size) threshold, so block
+// prints stand out in a busy tape. Thresholds are decimal strings; empty turns that check off.
+type LargePrints struct {
+	Size     string
+	Notional string
+	Symbols  map[string]LargePrintThreshold // Per-symbol thresholds, replacing the defaults
+	Bell     bool                           // Ring the terminal bell on each batch with a large print
+}
+
+type LargePrintThreshold struct {
+	Size     string
+	Notional string
+}
+
+type largePrintRule struct {
+	size, notional decimal.Decimal // Zero is off
+}
+
+type largePrintRules struct {
+	defaults largePrintRule
+	symbols  map[string]largePrintRule
+	bell     bool
+}
+
+func parseLargePrintRule(t LargePrintThreshold) (largePrintRule, error) {
+	var rule largePrintRule
+	for _, field := range []struct {
+		name  string
+		value string
+		dest  *decimal.Decimal
+	}{{"size", t.Size, &rule.size}, {"notional", t.Notional, &rule.notional}} {
+		if field.value == "" {
+			continue
+		}
+		d, err := decimal.NewFromString(field.value)
+		if err != nil || !d.IsPositive() {
+			return rule, fmt.Errorf("invalid large print %s %q (expected a positive number)", field.name, field.value)
+		}
+		*field.dest = d
+	}
+	return rule, nil
+}
+
+// SetLargePrints sets the large print thresholds
+func (a *FixApp) SetLargePrints(lp LargePrints) error {
+	defaults, err := parseLargePrintRule(LargePrintThreshold{Size: lp.Size, Notional: lp.Notional})
+	if err != nil {
+		return err
+	}
+	rules := &largePrintRules{defaults: defaults, symbols: make(map[string]largePrintRule), bell: lp.Bell}
+	for symbol, t := range lp.Symbols {
+		rule, err := parseLargePrintRule(t)
+		if err != nil {
+			return fmt.Errorf("%s: %w", symbol, err)
+		}
+		rules.symbols[strings.ToUpper(symbol)] = rule
+	}
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	a.largePrints = rules
+	highlightLargePrints.Store(promptColor())
+	return nil
+}
+
+// currentLargePrints is the rules set by SetLargePrints, nil when large prints are off
+func (a *FixApp) currentLargePrints() *largePrintRules {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.largePrints
+}
+
+// isLargePrint checks a trade with its raw exchange price and size against the thresholds
+func (a *FixApp) isLargePrint(trade Trade) bool {
+	return a.currentLargePrints().match(trade)
+}
+
+func (r *largePrintRules) match(trade Trade) bool {
+	if r == nil || (trade.EntryType != constants.MdEntryTypeTrade && trade.EntryType != "") {
+		return false
+	}
+	rule, ok := r.symbols[trade.Symbol]
+	if !ok {
+		rule = r.defaults
+	}
+	size, err := analytics.ParseDecimal(trade.Size)
+	if err != nil {
+		return false
+	}
+	if !rule.size.IsZero() && size.GreaterThanOrEqual(rule.size) {
+		return true
+	}
+	if price, err := analytics.ParseDecimal(trade.Price); err == nil && !rule.notional.IsZero() {
+		return price.Mul(size).GreaterThanOrEqual(rule.notional)
+	}
+	return false
+}
+
+// markLargePrints flags large trades among incremental updates, sends an alert for each and rings
+// the bell once for the batch when it is printed
+func (a *FixApp) markLargePrints(trades []Trade, quiet bool) {
+	rules := a.currentLargePrints()
+	found := false
+	for i := range trades {
+		if !rules.match(trades[i]) {
+			continue
+		}
+		trades[i].Large = true
+		found = true
+		a.alertLargePrint(trades[i])
+	}
+	if found && rules.bell && !quiet {
+		fmt.Fprint(a.Console(), "\a")
+	}
+}
+
+func (a *FixApp) alertLargePrint(trade Trade) {
+	text := fmt.Sprintf("%s @ %s", trade.Size, trade.Price)
+	if price, err := analytics.ParseDecimal(trade.Price); err == nil {
+		if size, err := analytics.ParseDecimal(trade.Size); err == nil {
+			text += fmt.Sprintf(" (notional %s)", price.Mul(size).StringFixed(2))
+		}
+	}
+	if trade.Aggressor != "" {
+		text += ", aggressor " + trade.Aggressor
+	}
+	a.Alerts.Send(notify.Event{
+		Type:    notify.EventLargePrint,
+		Title:   fmt.Sprintf("%s large print", trade.Symbol),
+		Text:    text,
+		Symbol:  trade.Symbol,
+		MdReqId: trade.MdReqId,
+	})
+}
+
+// largePrintLine marks a streaming update line for a large trade
+func largePrintLine(line string) string {
+	line = ">> " + line + " | LARGE"
+	if highlightLargePrints.Load() {
+		return largePrintColor + line + colorReset
+	}
+	return line
+}
@@ -0,0 +1,69 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/notify"
+)
+
+func TestLargePrints(t *testing.T) {
+	rec := &recordingNotifier{}
+	app := createTestFixApp()
+	app.Alerts = notify.NewDispatcher([]notify.Notifier{rec}, map[string]bool{notify.EventLargePrint: true}, "")
+	err := app.SetLargePrints(LargePrints{
+		Notional: "100000",
+		Symbols:  map[string]LargePrintThreshold{"btc-usd": {Size: "10"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	trades := []Trade{
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeTrade, Price: "50000", Size: "9"},    // Below the BTC size threshold
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeTrade, Price: "50000", Size: "10"},   // At it
+		{Symbol: "ETH-USD", EntryType: constants.MdEntryTypeTrade, Price: "2000", Size: "50"},    // 100000 notional
+		{Symbol: "ETH-USD", EntryType: constants.MdEntryTypeBid, Price: "2000", Size: "500"},     // Not a trade
+		{Symbol: "SOL-USD", EntryType: constants.MdEntryTypeTrade, Price: "100", Size: "999.99"}, // Below notional
+	}
+	app.markLargePrints(trades, false)
+	app.Alerts.Close(5 * time.Second)
+
+	for i, want := range []bool{false, true, true, false, false} {
+		if trades[i].Large != want {
+			t.Fatalf("Trade %d: expected large=%v", i, want)
+		}
+	}
+	if len(rec.events) != 2 || rec.events[0].Symbol != "BTC-USD" || !strings.Contains(rec.events[1].Text, "notional 100000.00") {
+		t.Fatalf("Unexpected alerts %+v", rec.events)
+	}
+
+	var out bytes.Buffer
+	renderer, _ := NewRenderer(OutputPlain, &out)
+	renderer.Updates(trades[1:2])
+	if !strings.HasSuffix(out.String(), "| LARGE\n") {
+		t.Fatalf("Expected the plain line to be marked, got %q", out.String())
+	}
+
+	if err := app.SetLargePrints(LargePrints{Size: "-1"}); err == nil {
+		t.Fatalf("Expected an error for a negative threshold")
+	}
+}
@@ -0,0 +1,219 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// mdTemplateStore holds named md flag combinations, used as "md BTC-USD @name". Templates come
+// from md.templates in the config and from template save, which are kept in marketdata.db
+// when there is one; a saved template hides a configured one of the same name.
+type mdTemplateStore struct {
+	mu         sync.Mutex
+	configured map[string]string
+	saved      map[string]string
+	loadOnce   sync.Once
+}
+
+// SetMdTemplates sets the templates from the config: name -> md flags, e.g. "--depth 10 --book"
+func (a *FixApp) SetMdTemplates(templates map[string]string) error {
+	for name, flags := range templates {
+		if _, err := a.checkMdTemplate(name, strings.Fields(flags)); err != nil {
+			return fmt.Errorf("invalid md.templates.%s: %w", name, err)
+		}
+	}
+	s := &a.mdTemplates
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configured = templates
+	return nil
+}
+
+// checkMdTemplate validates a template and returns its flags as stored
+func (a *FixApp) checkMdTemplate(name string, flags []string) (string, error) {
+	if !templateNamePattern.MatchString(name) {
+		return "", invalidRequest("template name %q may only contain letters, digits, - and _", name)
+	}
+	if len(flags) == 0 || !strings.HasPrefix(flags[0], "--") {
+		return "", invalidRequest("a template holds md flags only, e.g. --depth 10 --book --subscribe")
+	}
+	for _, flag := range flags {
+		if strings.HasPrefix(flag, "@") {
+			return "", invalidRequest("a template cannot refer to another template")
+		}
+	}
+	if _, err := a.parseMdFlags(flags); err != nil {
+		return "", err
+	}
+	return strings.Join(flags, " "), nil
+}
+
+// loadSavedMdTemplates reads the saved templates from the database the first time they are needed
+func (a *FixApp) loadSavedMdTemplates() {
+	s := &a.mdTemplates
+	s.loadOnce.Do(func() {
+		saved := make(map[string]string)
+		if a.Db != nil {
+			rows, err := a.Db.QueryMdTemplates()
+			if err != nil {
+				log.Printf("%v", storageError("failed to load md templates", err))
+			}
+			for _, row := range rows {
+				saved[row.Name] = row.Flags
+			}
+		}
+		s.mu.Lock()
+		s.saved = saved
+		s.mu.Unlock()
+	})
+}
+
+// mdTemplate returns the flags of a template, saved first
+func (a *FixApp) mdTemplate(name string) (string, bool) {
+	a.loadSavedMdTemplates()
+	s := &a.mdTemplates
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if flags, ok := s.saved[name]; ok {
+		return flags, true
+	}
+	flags, ok := s.configured[name]
+	return flags, ok
+}
+
+// expandMdTemplates replaces each @name argument with the template's flags
+func (a *FixApp) expandMdTemplates(args []string) ([]string, error) {
+	var expanded []string
+	for _, arg := range args {
+		name, ok := strings.CutPrefix(arg, "@")
+		if !ok {
+			expanded = append(expanded, arg)
+			continue
+		}
+		flags, ok := a.mdTemplate(name)
+		if !ok {
+			return nil, invalidRequest("unknown template @%s (see template list)", name)
+		}
+		expanded = append(expanded, strings.Fields(flags)...)
+	}
+	return expanded, nil
+}
+
+// saveMdTemplate stores a template for this and later sessions
+func (a *FixApp) saveMdTemplate(name string, flags []string) error {
+	stored, err := a.checkMdTemplate(name, flags)
+	if err != nil {
+		return err
+	}
+	a.loadSavedMdTemplates()
+	if a.Db != nil {
+		if err := a.Db.StoreMdTemplate(name, stored, time.Now()); err != nil {
+			return storageError("failed to save md template", err)
+		}
+	}
+	s := &a.mdTemplates
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved[name] = stored
+	return nil
+}
+
+// deleteMdTemplate removes a saved template; configured ones can only be removed from the config
+func (a *FixApp) deleteMdTemplate(name string) error {
+	a.loadSavedMdTemplates()
+	s := &a.mdTemplates
+	s.mu.Lock()
+	_, saved := s.saved[name]
+	_, configured := s.configured[name]
+	s.mu.Unlock()
+	if !saved {
+		if configured {
+			return invalidRequest("template %s is defined in md.templates; remove it from the config", name)
+		}
+		return invalidRequest("unknown template %s", name)
+	}
+	if a.Db != nil {
+		if err := a.Db.DeleteMdTemplate(name); err != nil {
+			return storageError("failed to delete md template", err)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.saved, name)
+	return nil
+}
+
+// mdTemplateRows lists every template by name with where it comes from
+func (a *FixApp) mdTemplateRows() [][]string {
+	a.loadSavedMdTemplates()
+	s := &a.mdTemplates
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows [][]string
+	for name, flags := range s.saved {
+		rows = append(rows, []string{"@" + name, flags, "saved"})
+	}
+	for name, flags := range s.configured {
+		if _, hidden := s.saved[name]; !hidden {
+			rows = append(rows, []string{"@" + name, flags, "config"})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+	return rows
+}
+
+func (a *FixApp) handleTemplateRequest(out output, parts []string) {
+	if len(parts) < 2 {
+		printCommandHelp(out.Console(), "template")
+		return
+	}
+	switch {
+	case parts[1] == "list" && len(parts) == 2:
+		rows := a.mdTemplateRows()
+		if len(rows) == 0 {
+			out.Info("No templates; save one with: template save <name> <md flags...>")
+			return
+		}
+		out.Table("Md Templates:", []string{"Template", "Flags", "Source"}, rows)
+	case parts[1] == "save" && len(parts) >= 4:
+		if err := a.saveMdTemplate(parts[2], parts[3:]); err != nil {
+			out.Error(err)
+			return
+		}
+		if a.Db == nil {
+			out.Info("Template @%s saved for this session only (no database)", parts[2])
+			return
+		}
+		out.Info("Template @%s saved: md <symbol> @%s", parts[2], parts[2])
+	case parts[1] == "delete" && len(parts) == 3:
+		if err := a.deleteMdTemplate(parts[2]); err != nil {
+			out.Error(err)
+			return
+		}
+		out.Info("Template @%s deleted", parts[2])
+	default:
+		printCommandHelp(out.Console(), "template")
+	}
+}
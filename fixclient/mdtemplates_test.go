@@ -0,0 +1,91 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"prime-fix-md-go/database"
+)
+
+func TestMdTemplatesExpandAndPersist(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "templates.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	var buf bytes.Buffer
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), db)
+	app.Renderer, _ = NewRenderer(OutputPlain, &buf)
+	if err := app.SetMdTemplates(map[string]string{"tape": "--trades", "l10book": "--depth 5 --book"}); err != nil {
+		t.Fatalf("SetMdTemplates: %v", err)
+	}
+
+	app.handleTemplateRequest(app.consoleOutput(), []string{"template", "save", "l10book", "--depth", "10", "--bids", "--offers", "--subscribe"})
+	args, err := app.expandMdTemplates([]string{"md", "BTC-USD", "@l10book", "@tape"})
+	if err != nil {
+		t.Fatalf("expandMdTemplates: %v", err)
+	}
+	if got := strings.Join(args, " "); got != "md BTC-USD --depth 10 --bids --offers --subscribe --trades" {
+		t.Fatalf("Expected the saved template to hide the configured one, got %q", got)
+	}
+	if _, err := app.expandMdTemplates([]string{"md", "BTC-USD", "@missing"}); err == nil {
+		t.Fatal("Expected an error for an unknown template")
+	}
+
+	// A new app on the same database sees the saved template
+	again := NewFixApp(app.Config, db)
+	if flags, ok := again.mdTemplate("l10book"); !ok || flags != "--depth 10 --bids --offers --subscribe" {
+		t.Fatalf("Expected the saved template to persist, got %q %v", flags, ok)
+	}
+
+	if err := app.deleteMdTemplate("tape"); err == nil || !strings.Contains(err.Error(), "md.templates") {
+		t.Fatalf("Expected configured templates to be kept, got %v", err)
+	}
+	if err := app.deleteMdTemplate("l10book"); err != nil {
+		t.Fatalf("deleteMdTemplate: %v", err)
+	}
+	if flags, _ := app.mdTemplate("l10book"); flags != "--depth 5 --book" {
+		t.Fatalf("Expected the configured template back after delete, got %q", flags)
+	}
+}
+
+func TestMdTemplateValidation(t *testing.T) {
+	app := createTestFixApp()
+	for _, tt := range []struct {
+		name  string
+		flags []string
+	}{
+		{"bad name", []string{"--trades"}},
+		{"sym", []string{"BTC-USD", "--trades"}},
+		{"nested", []string{"--trades", "@tape"}},
+		{"unknown", []string{"--nope"}},
+	} {
+		if _, err := app.checkMdTemplate(tt.name, tt.flags); err == nil {
+			t.Fatalf("Expected %s %v to be rejected", tt.name, tt.flags)
+		}
+	}
+	if err := app.saveMdTemplate("tape", []string{"--trades"}); err != nil {
+		t.Fatalf("Expected a template without a database to be kept in memory, got %v", err)
+	}
+	if flags, ok := app.mdTemplate("tape"); !ok || flags != "--trades" {
+		t.Fatalf("Expected the in-memory template, got %q", flags)
+	}
+}
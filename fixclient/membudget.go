@@ -0,0 +1,263 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Books are never trimmed below this many levels a side, so top of book stays right
+const minBookDepth = 10
+
+// MemoryBudget caps the market data held in memory across the trade store, the books and the
+// message queue. Zero limits are unlimited.
+type MemoryBudget struct {
+	MaxEntries int
+	MaxBytes   int64
+}
+
+// memoryUsage is an entry count and estimated size
+type memoryUsage struct {
+	Entries int
+	Bytes   int64
+}
+
+func (u memoryUsage) plus(o memoryUsage) memoryUsage {
+	return memoryUsage{u.Entries + o.Entries, u.Bytes + o.Bytes}
+}
+
+// MemoryStats breaks down memory use and what the budget has shed
+type MemoryStats struct {
+	Trades memoryUsage
+	Book   memoryUsage
+	Queue  memoryUsage
+	Budget MemoryBudget
+
+	ShedTrades int64 // Oldest trades dropped from the trade store
+	ShedLevels int64 // Deep book levels trimmed
+	ShedQueue  int64 // Incoming messages dropped, so neither stored nor displayed
+}
+
+func (s MemoryStats) Total() memoryUsage {
+	return s.Trades.plus(s.Book).plus(s.Queue)
+}
+
+// budgetState is the budget and its shedding counters
+type budgetState struct {
+	MemoryBudget
+	shedTrades atomic.Int64
+	shedLevels atomic.Int64
+	lastWarn   time.Time // Only touched on the pipeline worker
+}
+
+// SetMemoryBudget limits the market data held in memory. When over, the client sheds in
+// order: the oldest trades in the trade store (only display and stats use them), then the
+// deepest book levels down to minBookDepth a side, then incoming messages while the queue
+// has a backlog. Each is counted and shown under stats.
+func (a *FixApp) SetMemoryBudget(b MemoryBudget) error {
+	if b.MaxEntries < 0 || b.MaxBytes < 0 {
+		return fmt.Errorf("memory limits cannot be negative")
+	}
+	if b.MaxEntries == 0 && b.MaxBytes == 0 {
+		a.budget = nil
+		return nil
+	}
+	a.budget = &budgetState{MemoryBudget: b}
+	return nil
+}
+
+// MemoryStats reports current memory use against the budget
+func (a *FixApp) MemoryStats() MemoryStats {
+	var s MemoryStats
+	s.Trades.Entries, s.Trades.Bytes = a.TradeStore.Usage()
+	if a.Books != nil {
+		s.Book.Entries, s.Book.Bytes = a.Books.Usage()
+	}
+	if p := a.pipeline; p != nil {
+		s.Queue = memoryUsage{int(max(p.queuedEntries.Load(), 0)), max(p.queuedBytes.Load(), 0)}
+		s.ShedQueue = p.shed.Load()
+	}
+	if b := a.budget; b != nil {
+		s.Budget = b.MemoryBudget
+		s.ShedTrades, s.ShedLevels = b.shedTrades.Load(), b.shedLevels.Load()
+	}
+	return s
+}
+
+// excess is how far u is over the budget; zero fields are within it
+func (b MemoryBudget) excess(u memoryUsage) memoryUsage {
+	var over memoryUsage
+	if b.MaxEntries > 0 && u.Entries > b.MaxEntries {
+		over.Entries = u.Entries - b.MaxEntries
+	}
+	if b.MaxBytes > 0 && u.Bytes > b.MaxBytes {
+		over.Bytes = u.Bytes - b.MaxBytes
+	}
+	return over
+}
+
+// enforceMemoryBudget sheds data until memory use is within the budget. It runs after each
+// market data message.
+func (a *FixApp) enforceMemoryBudget() {
+	b := a.budget
+	if b == nil {
+		return
+	}
+	s := a.MemoryStats()
+	over := b.excess(s.Total())
+	if over == (memoryUsage{}) {
+		a.setShedding(false)
+		return
+	}
+
+	trades := a.TradeStore.ShedOldest(over.Entries, over.Bytes)
+	b.shedTrades.Add(int64(trades))
+
+	levels := 0
+	if s = a.MemoryStats(); b.excess(s.Total()) != (memoryUsage{}) && a.Books != nil {
+		depth := fitDepth(a.Books.SideDepths(), b.levelsAllowed(s))
+		levels = a.Books.TrimDepth(depth)
+		b.shedLevels.Add(int64(levels))
+	}
+
+	s = a.MemoryStats()
+	shedding := b.excess(s.Total()) != (memoryUsage{})
+	a.setShedding(shedding)
+
+	if now := time.Now(); now.Sub(b.lastWarn) >= dropWarnEvery {
+		b.lastWarn = now
+		note := ""
+		if shedding {
+			note = "; dropping incoming market data while the queue is backed up"
+		}
+		log.Printf("Over the memory budget (%s): dropped %d oldest trades and %d book levels%s",
+			b.describe(), trades, levels, note)
+	}
+}
+
+// levelsAllowed is how many book levels fit in the budget next to the trades and queue in s
+func (b MemoryBudget) levelsAllowed(s MemoryStats) int {
+	allowed := -1
+	if b.MaxEntries > 0 {
+		allowed = max(b.MaxEntries-s.Trades.Entries-s.Queue.Entries, 0)
+	}
+	if b.MaxBytes > 0 && s.Book.Entries > 0 {
+		perLevel := max(s.Book.Bytes/int64(s.Book.Entries), 1)
+		byBytes := int(max(b.MaxBytes-s.Trades.Bytes-s.Queue.Bytes, 0) / perLevel)
+		if allowed < 0 || byBytes < allowed {
+			allowed = byBytes
+		}
+	}
+	return allowed
+}
+
+// fitDepth is the deepest per-side depth, at least minBookDepth, at which the sides hold no
+// more than allowed levels
+func fitDepth(sides []int, allowed int) int {
+	deepest := 0
+	for _, n := range sides {
+		deepest = max(deepest, n)
+	}
+	if allowed < 0 {
+		return deepest
+	}
+	for depth := deepest; depth > minBookDepth; depth-- {
+		total := 0
+		for _, n := range sides {
+			total += min(n, depth)
+		}
+		if total <= allowed {
+			return depth
+		}
+	}
+	return minBookDepth
+}
+
+func (a *FixApp) setShedding(on bool) {
+	if p := a.pipeline; p != nil {
+		p.shedding.Store(on)
+	}
+}
+
+func (b MemoryBudget) describe() string {
+	switch {
+	case b.MaxEntries > 0 && b.MaxBytes > 0:
+		return fmt.Sprintf("%d entries, %s", b.MaxEntries, formatBytes(b.MaxBytes))
+	case b.MaxEntries > 0:
+		return fmt.Sprintf("%d entries", b.MaxEntries)
+	}
+	return formatBytes(b.MaxBytes)
+}
+
+// displayMemoryStats shows what the trade store, books and message queue hold
+func (a *FixApp) displayMemoryStats(out output) {
+	s := a.MemoryStats()
+	row := func(name string, u memoryUsage) []string {
+		return []string{name, fmt.Sprint(u.Entries), formatBytes(u.Bytes)}
+	}
+	rows := [][]string{
+		row("Trades", s.Trades),
+		row("Book", s.Book),
+		row("Queue", s.Queue),
+		row("Total", s.Total()),
+	}
+	if a.budget != nil {
+		entries, bytes := "-", "-"
+		if s.Budget.MaxEntries > 0 {
+			entries = fmt.Sprint(s.Budget.MaxEntries)
+		}
+		if s.Budget.MaxBytes > 0 {
+			bytes = formatBytes(s.Budget.MaxBytes)
+		}
+		rows = append(rows, []string{"Budget", entries, bytes})
+	}
+	out.Table("Memory (estimated):", []string{"", "Entries", "Bytes"}, rows)
+	if a.budget != nil {
+		out.Info("Shed: %d oldest trades, %d book levels, %d incoming messages", s.ShedTrades, s.ShedLevels, s.ShedQueue)
+	}
+}
+
+// Sizes are estimates: struct sizes plus string lengths
+var (
+	tradeSize = int64(unsafe.Sizeof(Trade{}))
+	levelSize = int64(unsafe.Sizeof(BookLevel{}))
+)
+
+func tradeBytes(t Trade) int64 {
+	return tradeSize + int64(len(t.Symbol)+len(t.Price)+len(t.Size)+len(t.Time)+len(t.Date)+len(t.Aggressor)+
+		len(t.MdReqId)+len(t.EntryType)+len(t.Position)+len(t.NumOrders)+len(t.EntryId)+len(t.UpdateAction)+
+		len(t.TradeCondition)+len(t.QuoteCondition)+len(t.SecurityId)+len(t.SecurityIdSource)+len(t.RptSeq)+len(t.SeqNum))
+}
+
+func levelBytes(l *BookLevel) int64 {
+	return levelSize + int64(len(l.Price)+len(l.Size)+len(l.NumOrders)+len(l.EntryId))
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
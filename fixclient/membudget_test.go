@@ -0,0 +1,144 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"testing"
+	"time"
+)
+
+func bookEntriesForTest(symbol string, levels int) []Trade {
+	var entries []Trade
+	for i := 0; i < levels; i++ {
+		entries = append(entries,
+			Trade{Symbol: symbol, EntryType: "0", Price: fmt.Sprintf("%d", 1000-i), Size: "1"},
+			Trade{Symbol: symbol, EntryType: "1", Price: fmt.Sprintf("%d", 1001+i), Size: "1"})
+	}
+	return entries
+}
+
+func TestTradeStoreShedOldest(t *testing.T) {
+	ts := NewTradeStore(100, "")
+	ts.AddTrades("BTC-USD", []Trade{{Price: "1"}, {Price: "2"}, {Price: "3"}}, false, "req")
+	entries, bytes := ts.Usage()
+	if entries != 3 || bytes <= 0 {
+		t.Fatalf("Expected 3 trades with a size, got %d, %d", entries, bytes)
+	}
+
+	if n := ts.ShedOldest(2, 0); n != 2 {
+		t.Fatalf("Expected 2 shed, got %d", n)
+	}
+	left := ts.GetAllTrades()
+	if len(left) != 1 || left[0].Price != "3" {
+		t.Fatalf("Expected the newest trade kept, got %+v", left)
+	}
+	if _, after := ts.Usage(); after != tradeBytes(left[0]) {
+		t.Fatalf("Expected bytes to follow the remaining trade, got %d", after)
+	}
+}
+
+func TestBookTrimDepthKeepsBest(t *testing.T) {
+	m := NewBookManager()
+	m.ApplySnapshot("BTC-USD", bookEntriesForTest("BTC-USD", 30), time.Now())
+
+	if removed := m.TrimDepth(10); removed != 40 {
+		t.Fatalf("Expected 40 levels removed, got %d", removed)
+	}
+	book, _ := m.Get("BTC-USD")
+	bids, offers := book.Bids(), book.Offers()
+	if len(bids) != 10 || bids[0].Price != "1000" || bids[9].Price != "991" {
+		t.Fatalf("Expected the best 10 bids, got %d from %s", len(bids), bids[0].Price)
+	}
+	if len(offers) != 10 || offers[0].Price != "1001" {
+		t.Fatalf("Expected the best 10 offers, got %d", len(offers))
+	}
+	var want int64
+	for _, level := range append(bids, offers...) {
+		want += levelBytes(&level)
+	}
+	if levels, bytes := m.Usage(); levels != 20 || bytes != want {
+		t.Fatalf("Expected usage of 20 levels in %d bytes, got %d, %d", want, levels, bytes)
+	}
+}
+
+func TestFitDepth(t *testing.T) {
+	if d := fitDepth([]int{50, 50, 20, 20}, -1); d != 50 {
+		t.Fatalf("Expected no limit to keep full depth, got %d", d)
+	}
+	if d := fitDepth([]int{50, 50, 20, 20}, 100); d != 30 {
+		t.Fatalf("Expected depth 30, got %d", d)
+	}
+	if d := fitDepth([]int{50, 50}, 4); d != minBookDepth {
+		t.Fatalf("Expected the minimum depth, got %d", d)
+	}
+}
+
+func TestEnforceMemoryBudgetShedsTradesBeforeBook(t *testing.T) {
+	w := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(w)
+
+	app := createTestFixApp()
+	app.Books = NewBookManager()
+	app.Books.ApplySnapshot("BTC-USD", bookEntriesForTest("BTC-USD", 30), time.Now())
+	for i := 0; i < 50; i++ {
+		app.TradeStore.AddTrades("BTC-USD", []Trade{{EntryType: "2", Price: "1000", Size: "1"}}, false, "req")
+	}
+	if err := app.SetMemoryBudget(MemoryBudget{MaxEntries: 80}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 50 trades + 60 levels: 30 trades go before any level
+	app.enforceMemoryBudget()
+	s := app.MemoryStats()
+	if s.Trades.Entries != 20 || s.Book.Entries != 60 || s.ShedTrades != 30 || s.ShedLevels != 0 {
+		t.Fatalf("Expected only trades shed, got %+v", s)
+	}
+
+	// Now the book must give up levels too
+	app.SetMemoryBudget(MemoryBudget{MaxEntries: 40})
+	app.enforceMemoryBudget()
+	s = app.MemoryStats()
+	if s.Trades.Entries != 0 || s.Book.Entries != 40 || s.ShedLevels != 20 {
+		t.Fatalf("Expected all trades and 20 levels shed, got %+v", s)
+	}
+}
+
+func TestSetMemoryBudgetValidates(t *testing.T) {
+	app := createTestFixApp()
+	if err := app.SetMemoryBudget(MemoryBudget{MaxBytes: -1}); err == nil {
+		t.Fatalf("Expected an error for a negative limit")
+	}
+	if err := app.SetMemoryBudget(MemoryBudget{}); err != nil || app.budget != nil {
+		t.Fatalf("Expected no budget when both limits are zero")
+	}
+}
+
+func TestPipelineShedsWhileOverBudget(t *testing.T) {
+	p := &pipeline{queue: make(chan inbound, 10)}
+	p.shedding.Store(true)
+	md := inbound{msg: testAppMessage("X"), received: time.Now(), entries: 2, bytes: 100}
+
+	// The first message is queued: with no backlog, dropping it would not free anything
+	p.enqueue(md)
+	p.enqueue(md)
+	if len(p.queue) != 1 || p.shed.Load() != 1 || p.queuedEntries.Load() != 2 || p.queuedBytes.Load() != 100 {
+		t.Fatalf("Expected one queued and one shed, got %d queued, %d shed", len(p.queue), p.shed.Load())
+	}
+}
@@ -0,0 +1,189 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/formatter"
+	"prime-fix-md-go/utils"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// unexpectedAlertEvery spaces out alerts for the same unexpected message type
+const unexpectedAlertEvery = 10 * time.Minute
+
+// msgTypeCounter counts application messages by MsgType and watches for reject bursts
+type msgTypeCounter struct {
+	mu        sync.Mutex
+	counts    map[string]int64
+	lastAlert map[string]time.Time // Unexpected MsgType -> last alert
+
+	burstCount  int           // Rejects within burstWindow that make a burst; 0 disables
+	burstWindow time.Duration // Sliding window
+	rejects     []time.Time   // The latest rejects, at most burstCount
+	lastBurst   time.Time     // When the last burst was alerted
+}
+
+// SetRejectBurst alerts when count rejects of any kind arrive within window; 0 disables it
+func (a *FixApp) SetRejectBurst(count int, window time.Duration) {
+	c := &a.msgTypes
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if window <= 0 {
+		count = 0
+	}
+	c.burstCount = count
+	c.burstWindow = window
+	c.rejects = nil
+}
+
+// MessageCounts returns how many application messages of each MsgType arrived this session
+func (a *FixApp) MessageCounts() map[string]int64 {
+	c := &a.msgTypes
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make(map[string]int64, len(c.counts))
+	for t, n := range c.counts {
+		counts[t] = n
+	}
+	return counts
+}
+
+// countMessage adds one message of msgType and returns the count so far
+func (c *msgTypeCounter) countMessage(msgType string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]int64)
+	}
+	c.counts[msgType]++
+	return c.counts[msgType]
+}
+
+// shouldAlert reports whether msgType has not been alerted within unexpectedAlertEvery
+func (c *msgTypeCounter) shouldAlert(msgType string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.Sub(c.lastAlert[msgType]) < unexpectedAlertEvery {
+		return false
+	}
+	if c.lastAlert == nil {
+		c.lastAlert = make(map[string]time.Time)
+	}
+	c.lastAlert[msgType] = now
+	return true
+}
+
+// addReject records a reject and reports how many arrived within the window when that makes
+// a new burst; a burst is alerted at most once per window
+func (c *msgTypeCounter) addReject(now time.Time) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.burstCount <= 0 {
+		return 0, false
+	}
+	cutoff := now.Add(-c.burstWindow)
+	recent := c.rejects[:0]
+	for _, t := range c.rejects {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	recent = append(recent, now)
+	if len(recent) > c.burstCount {
+		recent = recent[len(recent)-c.burstCount:]
+	}
+	c.rejects = recent
+
+	if len(recent) < c.burstCount || now.Sub(c.lastBurst) < c.burstWindow {
+		return len(recent), false
+	}
+	c.lastBurst = now
+	return len(recent), true
+}
+
+// recordReject counts a market data, business or session reject towards a burst
+func (a *FixApp) recordReject(now time.Time, detail string) {
+	if n, burst := a.msgTypes.addReject(now); burst {
+		a.msgTypes.mu.Lock()
+		window := a.msgTypes.burstWindow
+		a.msgTypes.mu.Unlock()
+		log.Printf("Reject burst: %d rejects in the last %s, latest %s", n, window, detail)
+		a.alertRejectBurst(n, window, detail)
+	}
+}
+
+// handleBusinessReject logs a BusinessMessageReject (j), which the gateway sends for an
+// application message it could not process
+func (a *FixApp) handleBusinessReject(msg *quickfix.Message, received time.Time) {
+	refMsgType := utils.GetString(msg, constants.TagRefMsgType)
+	detail := fmt.Sprintf("business reject of %s, reason %s",
+		formatter.MsgTypeName(refMsgType), utils.GetString(msg, constants.TagBusinessRejectReason))
+	if text := utils.GetString(msg, constants.TagText); text != "" {
+		detail += ": " + text
+	}
+	a.consoleOutput().Error(errors.New(detail))
+	a.recordReject(received, detail)
+}
+
+// handleUnexpectedMessage reports an application message the client has no handler for
+func (a *FixApp) handleUnexpectedMessage(msgType string, count int64, received time.Time) {
+	log.Printf("Unexpected application message type %s (%s), %d received so far", msgType, formatter.MsgTypeName(msgType), count)
+	if a.msgTypes.shouldAlert(msgType, received) {
+		a.alertUnexpected(msgType, count)
+	}
+}
+
+func expectedMsgType(msgType string) bool {
+	switch msgType {
+	case constants.MsgTypeMarketDataSnapshot, constants.MsgTypeMarketDataIncremental,
+		constants.MsgTypeMarketDataReject, constants.MsgTypeBusinessMessageReject:
+		return true
+	}
+	return false
+}
+
+// displayMessageCounts shows the application messages received by type
+func (a *FixApp) displayMessageCounts(out output) {
+	counts := a.MessageCounts()
+	if len(counts) == 0 {
+		return
+	}
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	rows := make([][]string, 0, len(types))
+	for _, t := range types {
+		expected := "yes"
+		if !expectedMsgType(t) {
+			expected = "no"
+		}
+		rows = append(rows, []string{t, formatter.MsgTypeName(t), strconv.FormatInt(counts[t], 10), expected})
+	}
+	out.Table("Application Messages:", []string{"Type", "Name", "Count", "Expected"}, rows)
+}
@@ -0,0 +1,82 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/notify"
+)
+
+func TestRejectBurstAlertsOncePerWindow(t *testing.T) {
+	var c msgTypeCounter
+	c.burstCount, c.burstWindow = 3, time.Minute
+	start := time.Now()
+
+	var bursts []int
+	for i, offset := range []time.Duration{0, 10 * time.Second, 70 * time.Second, 80 * time.Second, 90 * time.Second, 100 * time.Second, 160 * time.Second, 161 * time.Second, 162 * time.Second} {
+		if n, burst := c.addReject(start.Add(offset)); burst {
+			bursts = append(bursts, i)
+			if n != 3 {
+				t.Fatalf("Expected 3 rejects in the window, got %d", n)
+			}
+		}
+	}
+	// The third reject falls outside the first two's window and the fifth completes a burst. The
+	// sixth is within a window of that alert, and the ninth completes a new burst.
+	if len(bursts) != 2 || bursts[0] != 4 || bursts[1] != 8 {
+		t.Fatalf("Expected bursts at the fifth and ninth rejects, got %v", bursts)
+	}
+	if len(c.rejects) > c.burstCount {
+		t.Fatalf("Expected at most %d rejects kept, got %d", c.burstCount, len(c.rejects))
+	}
+
+	c.burstCount = 0
+	if _, burst := c.addReject(start.Add(time.Hour)); burst {
+		t.Fatalf("Expected no burst when disabled")
+	}
+}
+
+func TestUnexpectedMessageTypeAlerts(t *testing.T) {
+	w := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(w)
+
+	rec := &recordingNotifier{}
+	app := createTestFixApp()
+	app.Renderer, _ = NewRenderer(OutputPlain, &bytes.Buffer{})
+	app.Alerts = notify.NewDispatcher([]notify.Notifier{rec}, map[string]bool{notify.EventUnexpected: true}, "")
+
+	now := time.Now()
+	app.handleApplicationMessage(rawMessage("35=B\x0134=2\x01"), now)
+	app.handleApplicationMessage(rawMessage("35=B\x0134=3\x01"), now.Add(time.Minute))
+	app.handleApplicationMessage(rawMessage("35=B\x0134=4\x01"), now.Add(unexpectedAlertEvery))
+	app.Alerts.Close(5 * time.Second)
+
+	if len(rec.events) != 2 {
+		t.Fatalf("Expected one alert per %s, got %+v", unexpectedAlertEvery, rec.events)
+	}
+	if rec.events[1].Text != "B has no handler; 3 received so far" {
+		t.Fatalf("Unexpected alert text %q", rec.events[1].Text)
+	}
+	if n := app.MessageCounts()["B"]; n != 3 {
+		t.Fatalf("Expected 3 messages of type B counted, got %d", n)
+	}
+}
@@ -0,0 +1,225 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"strings"
+
+	"prime-fix-md-go/analytics"
+	"prime-fix-md-go/constants"
+
+	"github.com/shopspring/decimal"
+)
+
+// Size notations
+const (
+	SizePlain   = "plain"   // At size precision, e.g. 1500.25
+	SizeCompact = "compact" // With a K/M/B suffix from a thousand up, e.g. 1.5K
+)
+
+// NumberFormat controls how prices and sizes are shown on the console. The zero value shows
+// them exactly as they arrived, unless product increments give a precision.
+type NumberFormat struct {
+	Thousands    bool                       // Group integer digits, e.g. 50,000.10
+	SizeNotation string                     // SizePlain or SizeCompact; empty is plain
+	Precision    map[string]SymbolPrecision // Symbol -> decimal places, overriding product increments
+}
+
+// SymbolPrecision fixes the decimal places for one symbol; nil leaves that value to the
+// product increment, or as received
+type SymbolPrecision struct {
+	Price *int32
+	Size  *int32
+}
+
+// SetNumberFormat validates and applies the console number format
+func (a *FixApp) SetNumberFormat(f NumberFormat) error {
+	switch f.SizeNotation {
+	case "", SizePlain, SizeCompact:
+	default:
+		return fmt.Errorf("unknown size notation %q (expected plain or compact)", f.SizeNotation)
+	}
+	precision := make(map[string]SymbolPrecision, len(f.Precision))
+	for symbol, p := range f.Precision {
+		for _, places := range []*int32{p.Price, p.Size} {
+			if places != nil && (*places < 0 || *places > 18) {
+				return fmt.Errorf("precision for %s must be between 0 and 18, got %d", symbol, *places)
+			}
+		}
+		precision[strings.ToUpper(symbol)] = p
+	}
+	f.Precision = precision
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	a.numbers = f
+	return nil
+}
+
+// numberFormat is the format set by SetNumberFormat. It is replaced whole, never changed in place.
+func (a *FixApp) numberFormat() NumberFormat {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.numbers
+}
+
+// pricePlaces is the configured price precision for symbol, else the product's quote increment
+func (a *FixApp) pricePlaces(symbol string) (int32, bool) {
+	if p, ok := a.numberFormat().Precision[symbol]; ok && p.Price != nil {
+		return *p.Price, true
+	}
+	if a.Products != nil {
+		if product, ok := a.Products.Get(symbol); ok && product.QuoteIncrement != "" {
+			return analytics.IncrementPlaces(product.QuoteIncrement), true
+		}
+	}
+	return 0, false
+}
+
+// sizePlaces is the configured size precision for symbol, else the product's base increment
+func (a *FixApp) sizePlaces(symbol string) (int32, bool) {
+	if p, ok := a.numberFormat().Precision[symbol]; ok && p.Size != nil {
+		return *p.Size, true
+	}
+	if a.Products != nil {
+		if product, ok := a.Products.Get(symbol); ok && product.BaseIncrement != "" {
+			return analytics.IncrementPlaces(product.BaseIncrement), true
+		}
+	}
+	return 0, false
+}
+
+// formatPrice renders a price for the console. JSON output keeps the exchange strings so
+// consumers can parse them.
+func (a *FixApp) formatPrice(symbol, raw string) string {
+	if a.currentFormat() == OutputJson {
+		return raw
+	}
+	places, ok := a.pricePlaces(symbol)
+	return formatNumber(raw, places, ok, a.numberFormat().Thousands, false)
+}
+
+func (a *FixApp) formatSize(symbol, raw string) string {
+	if a.currentFormat() == OutputJson {
+		return raw
+	}
+	places, ok := a.sizePlaces(symbol)
+	numbers := a.numberFormat()
+	return formatNumber(raw, places, ok, numbers.Thousands, numbers.SizeNotation == SizeCompact)
+}
+
+// formatNotional renders price x size in the quote currency, at price precision. It is empty
+// when either cannot be parsed.
+func (a *FixApp) formatNotional(symbol, price, size string) string {
+	p, err := analytics.ParseDecimal(price)
+	if err != nil {
+		return ""
+	}
+	s, err := analytics.ParseDecimal(size)
+	if err != nil {
+		return ""
+	}
+	return a.formatPrice(symbol, p.Mul(s).String())
+}
+
+// displayTrades returns copies of trades with prices, sizes and entry times formatted for the console,
+// and the notional of trades added
+func (a *FixApp) displayTrades(trades []Trade) []Trade {
+	if a.currentFormat() == OutputJson {
+		return trades
+	}
+	formatted := make([]Trade, len(trades))
+	copy(formatted, trades)
+	a.addCumulativeDepth(formatted)
+	for i, trade := range formatted {
+		if trade.EntryType == constants.MdEntryTypeTrade {
+			trade.Notional = a.formatNotional(trade.Symbol, trade.Price, trade.Size)
+		}
+		trade.Price = a.formatPrice(trade.Symbol, trade.Price)
+		trade.Size = a.formatSize(trade.Symbol, trade.Size)
+		trade.Time = displayEntryTime(trade)
+		formatted[i] = trade
+	}
+	return formatted
+}
+
+var compactUnits = []struct {
+	suffix string
+	scale  decimal.Decimal
+}{
+	{"B", decimal.New(1, 9)},
+	{"M", decimal.New(1, 6)},
+	{"K", decimal.New(1, 3)},
+}
+
+// formatNumber renders raw at places when fixed is set, with optional digit grouping and
+// K/M/B notation. Anything that is not a number is returned unchanged.
+func formatNumber(raw string, places int32, fixed, thousands, compact bool) string {
+	if raw == "" {
+		return raw
+	}
+	d, err := analytics.ParseDecimal(raw)
+	if err != nil {
+		return raw
+	}
+
+	if compact {
+		for _, unit := range compactUnits {
+			if d.Abs().GreaterThanOrEqual(unit.scale) {
+				scaled := d.Div(unit.scale).StringFixed(2)
+				scaled = strings.TrimRight(strings.TrimRight(scaled, "0"), ".")
+				return scaled + unit.suffix
+			}
+		}
+	}
+
+	s := strings.TrimSpace(raw)
+	if fixed {
+		s = d.StringFixed(places)
+	}
+	if thousands {
+		s = groupThousands(s)
+	}
+	return s
+}
+
+// groupThousands inserts commas into the integer part of a plain decimal string
+func groupThousands(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i != -1 {
+		intPart, frac = s[:i], s[i:]
+	}
+	if len(intPart) <= 3 || strings.ContainsAny(intPart, "eE") {
+		return sign + s
+	}
+
+	var sb strings.Builder
+	lead := len(intPart) % 3
+	if lead > 0 {
+		sb.WriteString(intPart[:lead])
+	}
+	for i := lead; i < len(intPart); i += 3 {
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(intPart[i : i+3])
+	}
+	return sign + sb.String() + frac
+}
@@ -0,0 +1,113 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"strings"
+	"testing"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/products"
+)
+
+func TestFormatNumber(t *testing.T) {
+	cases := []struct {
+		raw                       string
+		places                    int32
+		fixed, thousands, compact bool
+		want                      string
+	}{
+		{"50000.1", 0, false, false, false, "50000.1"},
+		{"50000.1", 2, true, false, false, "50000.10"},
+		{"1234567.891", 2, true, true, false, "1,234,567.89"},
+		{"-1234.5", 0, false, true, false, "-1,234.5"},
+		{"999", 0, false, true, false, "999"},
+		{"1500", 0, false, false, true, "1.5K"},
+		{"2345678", 0, false, false, true, "2.35M"},
+		{"0.25", 8, true, false, true, "0.25000000"},
+		{"", 2, true, true, false, ""},
+		{"n/a", 2, true, true, false, "n/a"},
+	}
+	for _, c := range cases {
+		if got := formatNumber(c.raw, c.places, c.fixed, c.thousands, c.compact); got != c.want {
+			t.Fatalf("formatNumber(%q, %d, %v, %v, %v) = %q, expected %q",
+				c.raw, c.places, c.fixed, c.thousands, c.compact, got, c.want)
+		}
+	}
+}
+
+func TestPrecisionFromProductsAndConfig(t *testing.T) {
+	app := createTestFixApp()
+	app.Products = products.NewCatalog()
+	app.Products.Replace([]products.Product{{Symbol: "BTC-USD", QuoteIncrement: "0.01", BaseIncrement: "0.00000001"}})
+
+	if got := app.formatPrice("BTC-USD", "50000.1"); got != "50000.10" {
+		t.Fatalf("Expected the quote increment's precision, got %q", got)
+	}
+	if got := app.formatSize("BTC-USD", "0.5"); got != "0.50000000" {
+		t.Fatalf("Expected the base increment's precision, got %q", got)
+	}
+	if got := app.formatPrice("ETH-USD", "3000.5"); got != "3000.5" {
+		t.Fatalf("Expected an unknown product shown as received, got %q", got)
+	}
+
+	places := int32(0)
+	if err := app.SetNumberFormat(NumberFormat{Thousands: true, Precision: map[string]SymbolPrecision{"btc-usd": {Price: &places}}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := app.formatPrice("BTC-USD", "50000.6"); got != "50,001" {
+		t.Fatalf("Expected the configured precision to win, got %q", got)
+	}
+
+	app.outputFormat = OutputJson
+	if got := app.formatPrice("BTC-USD", "50000.6"); got != "50000.6" {
+		t.Fatalf("Expected JSON output to keep the raw price, got %q", got)
+	}
+
+	if err := app.SetNumberFormat(NumberFormat{SizeNotation: "engineering"}); err == nil {
+		t.Fatal("Expected an unknown size notation to be rejected")
+	}
+}
+
+func TestDisplayTradesNotional(t *testing.T) {
+	app := createTestFixApp()
+	app.Products = products.NewCatalog()
+	app.Products.Replace([]products.Product{{Symbol: "BTC-USD", QuoteIncrement: "0.01", BaseIncrement: "0.00000001"}})
+	if err := app.SetNumberFormat(NumberFormat{Thousands: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	shown := app.displayTrades([]Trade{
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeTrade, Price: "50000.5", Size: "0.12345678"},
+		{Symbol: "ETH-USD", EntryType: constants.MdEntryTypeTrade, Price: "2000", Size: "1.5"},
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeBid, Price: "50000", Size: "1"},
+	})
+	for i, want := range []string{"6,172.90", "3,000", ""} {
+		if shown[i].Notional != want {
+			t.Fatalf("Entry %d: expected notional %q, got %q", i, want, shown[i].Notional)
+		}
+	}
+	if line := formatUpdateLine(shown[0]); !strings.Contains(line, "| Size: 0.12345678 | Notional: 6,172.90 |") {
+		t.Fatalf("Expected the notional after the size, got %q", line)
+	}
+
+	var out strings.Builder
+	renderer, _ := NewRenderer(OutputTable, &out)
+	renderer.Snapshot("BTC-USD", shown[:1])
+	if !strings.Contains(out.String(), "Notional") || !strings.Contains(out.String(), "6,172.90") {
+		t.Fatalf("Expected a notional column in the snapshot, got:\n%s", out.String())
+	}
+}
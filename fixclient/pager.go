@@ -0,0 +1,302 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"prime-fix-md-go/formatter"
+
+	"github.com/chzyer/readline"
+)
+
+const (
+	defaultPipeLines = 10
+	defaultPageLines = 24
+
+	// How long a piped md request waits for its snapshot before the output is shown
+	pipeResponseTimeout = 10 * time.Second
+)
+
+// outputPipe is a trailing "| head N", "| tail N" or "| less" on a REPL command
+type outputPipe struct {
+	kind  string // head, tail or less; empty when the command is not piped
+	lines int
+}
+
+// splitPipe separates a trailing pipe from the command. "|" inside raw FIX messages is not a
+// pipe, so only a final "|" followed by a known filter counts.
+func splitPipe(line string) (string, outputPipe, error) {
+	i := strings.LastIndex(line, "|")
+	if i < 0 {
+		return line, outputPipe{}, nil
+	}
+	fields := strings.Fields(line[i+1:])
+	if len(fields) == 0 {
+		return line, outputPipe{}, nil
+	}
+
+	pipe := outputPipe{kind: strings.ToLower(fields[0]), lines: defaultPipeLines}
+	switch pipe.kind {
+	case "head", "tail":
+		if len(fields) > 2 {
+			return "", outputPipe{}, invalidRequest("usage: | %s [N]", pipe.kind)
+		}
+		if len(fields) == 2 {
+			n, err := strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			if err != nil || n <= 0 {
+				return "", outputPipe{}, invalidRequest("%s needs a positive line count, got %q", pipe.kind, fields[1])
+			}
+			pipe.lines = n
+		}
+	case "less", "more":
+		if len(fields) > 1 {
+			return "", outputPipe{}, invalidRequest("usage: | %s", pipe.kind)
+		}
+		pipe.kind = "less"
+	default:
+		// Not a filter, e.g. the last field of a raw message
+		return line, outputPipe{}, nil
+	}
+	return strings.TrimSpace(line[:i]), pipe, nil
+}
+
+// filter applies head or tail to the captured lines
+func (p outputPipe) filter(lines []string) []string {
+	switch {
+	case p.kind == "head" && len(lines) > p.lines:
+		return lines[:p.lines]
+	case p.kind == "tail" && len(lines) > p.lines:
+		return lines[len(lines)-p.lines:]
+	}
+	return lines
+}
+
+// terminalHeight is the number of rows of the terminal on stdout, or a default when there is none
+func terminalHeight() int {
+	if _, height, err := readline.GetSize(int(os.Stdout.Fd())); err == nil && height > 1 {
+		return height
+	}
+	return defaultPageLines + 1
+}
+
+// lockedBuffer collects output written from the REPL and the session goroutine at once
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// captureOutput runs fn with an output, in the current format, that goes to a buffer and
+// returns the captured lines. The console is left alone, so market data arriving meanwhile is
+// printed as usual rather than mixed into the capture.
+func (a *FixApp) captureOutput(fn func(out output)) []string {
+	buf := &lockedBuffer{}
+	a.settingsMu.RLock()
+	format, templates := a.outputFormat, a.templates[rendererFormat(a.outputFormat)]
+	a.settingsMu.RUnlock()
+	renderer, err := NewRenderer(format, formatter.ASCII(buf))
+	if err != nil {
+		a.consoleOutput().Error(err)
+		return nil
+	}
+	setRendererTemplates(renderer, templates)
+
+	fn(output{Renderer: renderer, w: buf, capture: true})
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+// captureCommand runs one command line with its output captured, waiting for the responses to
+// any requests it sends so a snapshot is part of the output
+func (a *FixApp) captureCommand(line string) ([]string, bool) {
+	var quit bool
+	lines := a.captureOutput(func(out output) {
+		defer a.unrouteResponses(out)
+		before := a.pendingRequestIds()
+		quit = a.runCommand(out, line)
+		for reqId := range a.pendingRequestIds() {
+			if !before[reqId] {
+				if err := a.WaitForResponse(reqId, pipeResponseTimeout); err != nil {
+					out.Error(err)
+				}
+			}
+		}
+	})
+	return lines, quit
+}
+
+// responseRoutes maps the requests sent by captured commands to their capture. count lets
+// market data skip the lock while no command is being captured, which is nearly always.
+type responseRoutes struct {
+	mu        sync.Mutex
+	renderers map[string]Renderer // reqId -> capture of the command that sent it
+	count     atomic.Int32
+}
+
+// routeResponses sends the messages answering reqId to out while out is a command's capture,
+// so a piped or attached md command shows its own snapshot
+func (a *FixApp) routeResponses(reqId string, out output) {
+	if !out.capture {
+		return
+	}
+	r := &a.routes
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.renderers == nil {
+		r.renderers = make(map[string]Renderer)
+	}
+	r.renderers[reqId] = out.Renderer
+	r.count.Store(int32(len(r.renderers)))
+}
+
+// unrouteResponses sends responses routed to out back to the console once its capture ends
+func (a *FixApp) unrouteResponses(out output) {
+	r := &a.routes
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for reqId, renderer := range r.renderers {
+		if renderer == out.Renderer {
+			delete(r.renderers, reqId)
+		}
+	}
+	r.count.Store(int32(len(r.renderers)))
+}
+
+// responseRenderer is where the messages answering mdReqId are rendered
+func (a *FixApp) responseRenderer(mdReqId string) Renderer {
+	r := &a.routes
+	if r.count.Load() > 0 {
+		r.mu.Lock()
+		renderer, ok := r.renderers[mdReqId]
+		r.mu.Unlock()
+		if ok {
+			return renderer
+		}
+	}
+	return a.consoleOutput().Renderer
+}
+
+// pager shows lines a screen at a time. readLine asks for the next navigation command.
+type pager struct {
+	lines    []string
+	height   int
+	out      io.Writer
+	readLine func(prompt string) (string, error)
+}
+
+func (p *pager) run() {
+	height := p.height
+	if height <= 0 {
+		height = defaultPageLines
+	}
+	last := len(p.lines) - height
+	if last < 0 {
+		last = 0
+	}
+
+	top := 0
+	for {
+		end := top + height
+		if end > len(p.lines) {
+			end = len(p.lines)
+		}
+		for _, line := range p.lines[top:end] {
+			fmt.Fprintln(p.out, line)
+		}
+		if end == len(p.lines) {
+			return
+		}
+
+		prompt := fmt.Sprintf("-- lines %d-%d of %d (Enter: next page, b: back, /text: find, g/G: top/bottom, q: quit) ",
+			top+1, end, len(p.lines))
+		input, err := p.readLine(prompt)
+		if err != nil {
+			return
+		}
+		switch cmd := strings.TrimSpace(input); {
+		case cmd == "" || cmd == "f":
+			top = end
+		case cmd == "b":
+			top -= height
+		case cmd == "g":
+			top = 0
+		case cmd == "G":
+			top = last
+		case cmd == "q":
+			return
+		case strings.HasPrefix(cmd, "/") && len(cmd) > 1:
+			if i := p.find(cmd[1:], top+1); i >= 0 {
+				top = i
+			} else {
+				fmt.Fprintf(p.out, "Pattern not found: %s\n", cmd[1:])
+			}
+		default:
+			fmt.Fprintf(p.out, "Unknown pager command %q\n", cmd)
+		}
+		if top < 0 {
+			top = 0
+		}
+		if top > last {
+			top = last
+		}
+	}
+}
+
+// find returns the first line at or after from containing text, or -1
+func (p *pager) find(text string, from int) int {
+	for i := from; i < len(p.lines); i++ {
+		if strings.Contains(p.lines[i], text) {
+			return i
+		}
+	}
+	return -1
+}
+
+// runPiped runs a command with its output captured and passed through the pipe. Requests the
+// command sends are given time to answer first, so "md BTC-USD --snapshot --depth 0 | less"
+// pages the snapshot itself.
+func (a *FixApp) runPiped(line string, pipe outputPipe, readLine func(prompt string) (string, error)) bool {
+	lines, quit := a.captureCommand(line)
+	lines = pipe.filter(lines)
+	if pipe.kind != "less" {
+		for _, l := range lines {
+			fmt.Fprintln(a.Console(), l)
+		}
+		return quit
+	}
+	p := &pager{lines: lines, height: terminalHeight() - 1, out: a.Console(), readLine: readLine}
+	p.run()
+	return quit
+}
@@ -0,0 +1,113 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSplitPipe(t *testing.T) {
+	cases := []struct {
+		line, cmd string
+		pipe      outputPipe
+	}{
+		{"status", "status", outputPipe{}},
+		{"candles BTC-USD 1m | head 5", "candles BTC-USD 1m", outputPipe{kind: "head", lines: 5}},
+		{"help md | tail", "help md", outputPipe{kind: "tail", lines: defaultPipeLines}},
+		{"md BTC-USD --snapshot --depth 0 |more", "md BTC-USD --snapshot --depth 0", outputPipe{kind: "less", lines: defaultPipeLines}},
+		{"raw 35=V|262=test|55=BTC-USD", "raw 35=V|262=test|55=BTC-USD", outputPipe{}},
+	}
+	for _, c := range cases {
+		cmd, pipe, err := splitPipe(c.line)
+		if err != nil || cmd != c.cmd || pipe != c.pipe {
+			t.Fatalf("splitPipe(%q) = %q, %+v, %v; expected %q, %+v", c.line, cmd, pipe, err, c.cmd, c.pipe)
+		}
+	}
+
+	if _, _, err := splitPipe("stats | head zero"); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("Expected an invalid request for a bad line count, got %v", err)
+	}
+}
+
+func TestRunPipedHead(t *testing.T) {
+	var out bytes.Buffer
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	app.SetConsole(&out, &out)
+	defer app.SetConsole(nil, nil)
+
+	app.runPiped("help md", outputPipe{kind: "head", lines: 3}, nil)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "Usage: md") {
+		t.Fatalf("Expected the first 3 lines of help md, got:\n%s", out.String())
+	}
+}
+
+func TestCaptureKeepsLiveOutputOnConsole(t *testing.T) {
+	var console bytes.Buffer
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	app.SetConsole(&console, &console)
+	defer app.SetConsole(nil, nil)
+
+	lines := app.captureOutput(func(out output) {
+		app.routeResponses("md_1", out)
+		defer app.unrouteResponses(out)
+		out.Info("command")
+		app.responseRenderer("md_1").Info("response")
+		app.responseRenderer("md_2").Info("live")
+	})
+	if strings.Join(lines, "|") != "command|response" {
+		t.Fatalf("Expected the command output and its response captured, got %q", lines)
+	}
+	if console.String() != "live\n" {
+		t.Fatalf("Expected live output on the console, got %q", console.String())
+	}
+
+	console.Reset()
+	app.responseRenderer("md_1").Info("later")
+	if console.String() != "later\n" {
+		t.Fatalf("Expected responses on the console once the capture ended, got %q", console.String())
+	}
+}
+
+func TestPagerNavigation(t *testing.T) {
+	lines := make([]string, 10)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+
+	var out bytes.Buffer
+	inputs := []string{"", "b", "/line 9", "q"}
+	p := &pager{lines: lines, height: 3, out: &out, readLine: func(string) (string, error) {
+		input := inputs[0]
+		inputs = inputs[1:]
+		return input, nil
+	}}
+	p.run()
+
+	want := []string{
+		"line 1", "line 2", "line 3", // first page
+		"line 4", "line 5", "line 6", // Enter
+		"line 1", "line 2", "line 3", // b
+		"line 8", "line 9", "line 10", // /line 9 stops at the last full page
+	}
+	if got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("Unexpected pager output:\n%s", out.String())
+	}
+}
@@ -17,7 +17,8 @@
 package fixclient
 
 import (
-	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,55 +28,28 @@ import (
 	"github.com/quickfixgo/quickfix"
 )
 
+// extractTrades parses the message's MD entries into a new slice; see appendTrades
 func (a *FixApp) extractTrades(msg *quickfix.Message, symbol, mdReqId string, isSnapshot bool, seqNum string) []Trade {
-	return a.extractTradesImproved(msg, symbol, mdReqId, isSnapshot, seqNum)
-}
-
-func (a *FixApp) extractTradesImproved(msg *quickfix.Message, symbol, mdReqId string, isSnapshot bool, seqNum string) []Trade {
-	rawMsg := msg.String()
-
-	noMdEntriesStr := utils.GetString(msg, constants.TagNoMdEntries)
-	if noMdEntriesStr == "" || noMdEntriesStr == "0" {
+	trades := a.appendTrades(nil, msg, symbol, mdReqId, isSnapshot, seqNum)
+	if trades == nil {
 		return []Trade{}
 	}
-
-	entryStarts := a.findEntryBoundaries(rawMsg)
-
-	var trades []Trade
-	for i, startPos := range entryStarts {
-		endPos := a.getEntryEndPos(entryStarts, i, len(rawMsg))
-		entrySegment := rawMsg[startPos:endPos]
-
-		trade := a.parseTradeFromSegment(entrySegment, symbol, mdReqId, isSnapshot, seqNum, i)
-		trades = append(trades, trade)
-	}
-
 	return trades
 }
 
-func (a *FixApp) findEntryBoundaries(rawMsg string) []int {
-	var entryStarts []int
-	searchFrom := 0
-	for {
-		pos := strings.Index(rawMsg[searchFrom:], "269=")
-		if pos == -1 {
-			break
-		}
-		entryStarts = append(entryStarts, searchFrom+pos)
-		searchFrom += pos + 4
+// appendTrades parses the message's MD entries onto dst in one pass over its raw form. Values
+// are substrings of that single copy, and dst is grown to fit NoMDEntries (268) up front.
+func (a *FixApp) appendTrades(dst []Trade, msg *quickfix.Message, symbol, mdReqId string, isSnapshot bool, seqNum string) []Trade {
+	n, _ := strconv.Atoi(utils.GetString(msg, constants.TagNoMdEntries))
+	if n <= 0 {
+		return dst
 	}
-	return entryStarts
+	return appendEntries(slices.Grow(dst, n), msg.String(), newEntry(symbol, mdReqId, isSnapshot, seqNum))
 }
 
-func (a *FixApp) getEntryEndPos(entryStarts []int, currentIndex, msgLen int) int {
-	if currentIndex < len(entryStarts)-1 {
-		return entryStarts[currentIndex+1]
-	}
-	return msgLen
-}
-
-func (a *FixApp) parseTradeFromSegment(segment, symbol, mdReqId string, isSnapshot bool, seqNum string, entryIndex int) Trade {
-	trade := Trade{
+// newEntry is the part of a Trade shared by every entry in a message
+func newEntry(symbol, mdReqId string, isSnapshot bool, seqNum string) Trade {
+	return Trade{
 		Timestamp:  time.Now(),
 		Symbol:     symbol,
 		MdReqId:    mdReqId,
@@ -83,46 +57,146 @@ func (a *FixApp) parseTradeFromSegment(segment, symbol, mdReqId string, isSnapsh
 		IsUpdate:   !isSnapshot,
 		SeqNum:     seqNum,
 	}
+}
 
-	if entryType := extractSingleFieldValue(segment, "269="); entryType != "" {
-		trade.EntryType = entryType
+// appendEntries starts a new entry from base at each MdEntryType (269) in raw
+func appendEntries(dst []Trade, raw string, base Trade) []Trade {
+	first := len(dst)
+	for pos := 0; pos < len(raw); {
+		tag, value, next := nextField(raw, pos)
+		pos = next
+		if tag == constants.TagMdEntryType {
+			dst = append(dst, base)
+		}
+		if len(dst) > first {
+			setEntryField(&dst[len(dst)-1], tag, value)
+		}
 	}
-	if price := extractSingleFieldValue(segment, "270="); price != "" {
-		trade.Price = price
+	for i := first; i < len(dst); i++ {
+		finishEntry(&dst[i], i-first)
 	}
-	if size := extractSingleFieldValue(segment, "271="); size != "" {
-		trade.Size = size
+	return dst
+}
+
+// parseTradeFromSegment parses the fields of a single entry, e.g. "269=0\x01270=100\x01..."
+func (a *FixApp) parseTradeFromSegment(segment, symbol, mdReqId string, isSnapshot bool, seqNum string, entryIndex int) Trade {
+	trade := newEntry(symbol, mdReqId, isSnapshot, seqNum)
+	for pos := 0; pos < len(segment); {
+		tag, value, next := nextField(segment, pos)
+		setEntryField(&trade, tag, value)
+		pos = next
 	}
-	if timeVal := extractSingleFieldValue(segment, "273="); timeVal != "" {
-		trade.Time = timeVal
+	finishEntry(&trade, entryIndex)
+	return trade
+}
+
+// nextField reads the tag=value field starting at pos and returns where the next one starts.
+// A tag that is not a number comes back as -1.
+func nextField(raw string, pos int) (quickfix.Tag, string, int) {
+	eq := strings.IndexByte(raw[pos:], '=')
+	if eq == -1 {
+		return -1, "", len(raw)
 	}
+	eq += pos
 
-	if position := extractSingleFieldValue(segment, "290="); position != "" {
-		trade.Position = position
+	end := strings.IndexByte(raw[eq+1:], '\x01') // FIX field delimiter
+	if end == -1 {
+		end = len(raw)
 	} else {
-		if trade.EntryType == "0" || trade.EntryType == "1" { // Bids or Offers
-			trade.Position = fmt.Sprintf("%d", entryIndex+1)
+		end += eq + 1
+	}
+
+	tag := 0
+	for i := pos; i < eq; i++ {
+		c := raw[i]
+		if c < '0' || c > '9' {
+			return -1, raw[eq+1 : end], end + 1
 		}
+		tag = tag*10 + int(c-'0')
 	}
+	return quickfix.Tag(tag), raw[eq+1 : end], end + 1
+}
 
-	if aggressor := extractSingleFieldValue(segment, "2446="); aggressor != "" {
-		trade.Aggressor = getAggressorSideDesc(aggressor)
+func setEntryField(trade *Trade, tag quickfix.Tag, value string) {
+	switch tag {
+	case constants.TagMdEntryType:
+		trade.EntryType = value
+	case constants.TagMdEntryPx:
+		trade.Price = value
+	case constants.TagMdEntrySize:
+		trade.Size = value
+	case constants.TagMdEntryTime:
+		trade.Time = value
+	case constants.TagMdEntryDate:
+		trade.Date = value
+	case constants.TagMdEntryPositionNo:
+		trade.Position = value
+	case constants.TagMdEntryId:
+		trade.EntryId = value
+	case constants.TagMdUpdateAction:
+		trade.UpdateAction = value
+	case constants.TagTradeCondition:
+		trade.TradeCondition = value
+	case constants.TagQuoteCondition:
+		trade.QuoteCondition = value
+	case constants.TagNumberOfOrders:
+		trade.NumOrders = value
+	case constants.TagRptSeq:
+		trade.RptSeq = value
+	// Incremental entries may carry their own instrument; message-level values fill in otherwise
+	case constants.TagSecurityId:
+		trade.SecurityId = value
+	case constants.TagSecurityIdSource:
+		trade.SecurityIdSource = value
+	case constants.TagAggressorSide:
+		trade.Aggressor = getAggressorSideDesc(value)
 	}
+}
 
-	return trade
+// Positions are numbered from 1 by entry; a full-depth book would otherwise format one per level
+var positionStrings = func() []string {
+	p := make([]string, 1001)
+	for i := range p {
+		p[i] = strconv.Itoa(i)
+	}
+	return p
+}()
+
+func positionString(n int) string {
+	if n < len(positionStrings) {
+		return positionStrings[n]
+	}
+	return strconv.Itoa(n)
 }
 
-func extractSingleFieldValue(fixSegment, tagPrefix string) string {
-	start := strings.Index(fixSegment, tagPrefix)
-	if start == -1 {
-		return ""
+// finishEntry fills in what depends on the whole entry once its fields are read
+func finishEntry(trade *Trade, entryIndex int) {
+	trade.EntryTime = combineEntryDateTime(trade.Date, trade.Time, trade.Timestamp)
+
+	if trade.Position == "" && (trade.EntryType == "0" || trade.EntryType == "1") { // Bids or Offers
+		trade.Position = positionString(entryIndex + 1)
 	}
+	if trade.SecurityId == "" {
+		trade.SecurityIdSource = ""
+	}
+}
 
-	start += len(tagPrefix)
-	end := strings.Index(fixSegment[start:], "\x01") // FIX field delimiter
-	if end == -1 {
-		return fixSegment[start:]
+// combineEntryDateTime joins MdEntryDate (YYYYMMDD) and MdEntryTime into one UTC timestamp.
+// Without a date, time-only values are placed on the receive date.
+func combineEntryDateTime(date, timeVal string, received time.Time) time.Time {
+	if timeVal == "" {
+		if t, err := time.Parse("20060102", date); err == nil {
+			return t
+		}
+		return time.Time{}
 	}
 
-	return fixSegment[start : start+end]
+	// MdEntryTime may already carry the date
+	if date != "" && !strings.Contains(timeVal, "-") {
+		timeVal = date + "-" + timeVal
+	}
+	if t, ok := utils.ParseFixTime(timeVal, received); ok {
+		return t
+	}
+	return time.Time{}
 }
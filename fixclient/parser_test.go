@@ -17,10 +17,15 @@
 package fixclient
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
+
+	"prime-fix-md-go/builder"
+
+	"github.com/quickfixgo/quickfix"
 )
 
 func createTestFixApp() *FixApp {
@@ -29,34 +34,37 @@ func createTestFixApp() *FixApp {
 	}
 }
 
-func TestFindEntryBoundaries(t *testing.T) {
+func TestExtractTradesFullMessage(t *testing.T) {
 	app := createTestFixApp()
 
-	// Test message with multiple MD entries
-	rawMsg := "8=FIX.4.4|9=142|35=W|49=SENDER|56=TARGET|34=2|52=20250101-12:00:00|55=BTC-USD|268=2|269=0|270=50000.00|271=1.5|269=1|270=49999.00|271=2.0|10=123|"
-
-	boundaries := app.findEntryBoundaries(rawMsg)
+	trades := app.extractTrades(fullDepthSnapshot(3), "BTC-USD", "req", true, "2")
+	if len(trades) != 6 {
+		t.Fatalf("Expected 6 entries, got %d", len(trades))
+	}
+	if trades[0].EntryType != "0" || trades[0].Price != "50000.25" || trades[0].Size != "0.0001" || trades[0].Position != "1" {
+		t.Fatalf("Unexpected first bid %+v", trades[0])
+	}
+	if last := trades[5]; last.EntryType != "1" || last.Price != "50003.75" || last.Position != "6" || last.Time != "12:00:00.000" {
+		t.Fatalf("Unexpected last offer %+v", last)
+	}
 
-	if len(boundaries) != 2 {
-		t.Fatalf("Expected 2 entry boundaries, got %d", len(boundaries))
+	// The checksum after the last entry is not mistaken for one of its fields
+	if trades[5].SecurityId != "" || trades[5].RptSeq != "" {
+		t.Fatalf("Expected trailer fields ignored, got %+v", trades[5])
 	}
 }
 
-func TestGetEntryEndPos(t *testing.T) {
-	app := createTestFixApp()
-
-	entryStarts := []int{10, 50, 90}
-
-	// Test middle entry
-	endPos := app.getEntryEndPos(entryStarts, 1, 120)
-	if endPos != 90 {
-		t.Fatalf("Expected end position 90, got %d", endPos)
+func TestNextField(t *testing.T) {
+	raw := "269=0\x01270=100.5\x01ab=1\x01271=2"
+	var tags []quickfix.Tag
+	var values []string
+	for pos := 0; pos < len(raw); {
+		tag, value, next := nextField(raw, pos)
+		tags, values = append(tags, tag), append(values, value)
+		pos = next
 	}
-
-	// Test last entry
-	endPos = app.getEntryEndPos(entryStarts, 2, 120)
-	if endPos != 120 {
-		t.Fatalf("Expected end position 120 (message length), got %d", endPos)
+	if fmt.Sprint(tags) != "[269 270 -1 271]" || strings.Join(values, ",") != "0,100.5,1,2" {
+		t.Fatalf("Unexpected fields %v %v", tags, values)
 	}
 }
 
@@ -225,3 +233,191 @@ func mapAggressorSide(side string) string {
 		return "Unknown"
 	}
 }
+
+func TestParseEntryDateAndTime(t *testing.T) {
+	app := createTestFixApp()
+	received := time.Date(2025, 3, 2, 0, 0, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		segment  string
+		expected time.Time
+	}{
+		{
+			name:     "Separate date and time",
+			segment:  "269=2\x01270=100\x01271=1\x01272=20250301\x01273=23:59:58.250\x01",
+			expected: time.Date(2025, 3, 1, 23, 59, 58, 250000000, time.UTC),
+		},
+		{
+			name:     "Full timestamp in MdEntryTime",
+			segment:  "269=2\x01270=100\x01271=1\x01273=20250301-10:00:00.000\x01",
+			expected: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Time only uses receive date",
+			segment:  "269=2\x01270=100\x01271=1\x01273=00:00:01\x01",
+			expected: time.Date(2025, 3, 2, 0, 0, 1, 0, time.UTC),
+		},
+		{
+			name:     "Date only",
+			segment:  "269=4\x01270=100\x01272=20250301\x01",
+			expected: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trade := app.parseTradeFromSegment(tc.segment, "BTC-USD", "req", false, "1", 0)
+			trade.EntryTime = combineEntryDateTime(trade.Date, trade.Time, received)
+			if !trade.EntryTime.Equal(tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, trade.EntryTime)
+			}
+		})
+	}
+}
+
+func TestStoredTimeKeepsDate(t *testing.T) {
+	trade := Trade{Time: "10:00:00.123", Date: "20250301",
+		EntryTime: time.Date(2025, 3, 1, 10, 0, 0, 123000000, time.UTC)}
+	if got := trade.storedTime(); got != "20250301-10:00:00.123" {
+		t.Fatalf("Expected combined timestamp, got %s", got)
+	}
+
+	raw := Trade{Time: "garbage"}
+	if got := raw.storedTime(); got != "garbage" {
+		t.Fatalf("Expected raw time when unparseable, got %s", got)
+	}
+}
+
+func TestParseNumberOfOrders(t *testing.T) {
+	app := createTestFixApp()
+
+	bid := app.parseTradeFromSegment("269=0\x01270=49999.00\x01271=2.0\x01346=7\x01", "BTC-USD", "req", true, "1", 0)
+	if bid.NumOrders != "7" {
+		t.Fatalf("Expected 7 orders, got %q", bid.NumOrders)
+	}
+	if rec := bid.orderBookRecord("bid", 1, true); rec.NumOrders == nil || *rec.NumOrders != 7 {
+		t.Fatalf("Expected NumOrders 7 on book record, got %v", rec.NumOrders)
+	}
+
+	offer := app.parseTradeFromSegment("269=1\x01270=50001.00\x01271=1.0\x01", "BTC-USD", "req", true, "1", 0)
+	if rec := offer.orderBookRecord("offer", 1, true); rec.NumOrders != nil {
+		t.Fatalf("Expected nil NumOrders when tag is absent, got %d", *rec.NumOrders)
+	}
+}
+
+func TestParseEntryIdAndUpdateAction(t *testing.T) {
+	app := createTestFixApp()
+
+	segment := "279=2\x01269=0\x01278=bid-42\x01270=49999.00\x01271=0\x01"
+	entry := app.parseTradeFromSegment(segment, "BTC-USD", "req", false, "5", 0)
+	if entry.EntryId != "bid-42" || entry.UpdateAction != "2" {
+		t.Fatalf("Expected entry id bid-42 with delete action, got %q/%q", entry.EntryId, entry.UpdateAction)
+	}
+
+	line := formatUpdateLine(entry)
+	if !strings.HasSuffix(line, "| Delete | Id: bid-42") {
+		t.Fatalf("Expected action and id in update line, got %s", line)
+	}
+}
+
+func TestParseConditions(t *testing.T) {
+	app := createTestFixApp()
+
+	trade := app.parseTradeFromSegment("269=2\x01270=50000.00\x01271=0.1\x01277=R U\x012446=1\x01", "BTC-USD", "req", false, "1", 0)
+	if trade.TradeCondition != "R U" {
+		t.Fatalf("Expected trade condition 'R U', got %q", trade.TradeCondition)
+	}
+	if line := formatUpdateLine(trade); line != "BTC-USD Trade: 50000.00 | Size: 0.1 | Aggressor: Buy | Cond: R U" {
+		t.Fatalf("Unexpected trade line: %s", line)
+	}
+
+	quote := app.parseTradeFromSegment("269=1\x01270=50001.00\x01271=1\x01276=B\x01", "BTC-USD", "req", false, "1", 0)
+	if rec := quote.orderBookRecord("offer", 1, false); rec.QuoteCondition != "B" {
+		t.Fatalf("Expected quote condition B on book record, got %q", rec.QuoteCondition)
+	}
+}
+
+func TestParseSecurityId(t *testing.T) {
+	app := createTestFixApp()
+
+	trade := app.parseTradeFromSegment("269=2\x0148=BTC-USD\x0122=8\x01270=50000.00\x01271=0.1\x01", "BTC-USD", "req", false, "1", 0)
+	if trade.SecurityId != "BTC-USD" || trade.SecurityIdSource != "8" {
+		t.Fatalf("Expected SecurityID BTC-USD source 8, got %q/%q", trade.SecurityId, trade.SecurityIdSource)
+	}
+	if rec := trade.orderBookRecord("bid", 1, false); rec.SecurityId != "BTC-USD" || rec.SecurityIdSource != "8" {
+		t.Fatalf("Expected SecurityID on book record, got %q/%q", rec.SecurityId, rec.SecurityIdSource)
+	}
+}
+
+func TestSubscriptionInstrument(t *testing.T) {
+	store := NewTradeStore(10, "")
+	store.AddInstrumentSubscription(builder.Instrument{SecurityId: "ID-1", SecurityIdSource: "8"}, "1", "md_1", "0", nil, builder.MdRequestOptions{}, "")
+	store.AddSubscription("ETH-USD", "1", "md_2")
+
+	subs := store.GetSubscriptionStatus()
+	if got := subs["md_1"].Instrument(); got.SecurityId != "ID-1" || got.SecurityIdSource != "8" || got.Symbol != "" {
+		t.Fatalf("Expected SecurityID instrument, got %+v", got)
+	}
+	if got := subs["md_2"].Instrument(); got.Symbol != "ETH-USD" || got.SecurityId != "" {
+		t.Fatalf("Expected symbol instrument, got %+v", got)
+	}
+}
+
+// fullDepthSnapshot builds a raw snapshot with levels bids and levels offers, each with an entry time
+func fullDepthSnapshot(levels int) *quickfix.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "35=W\x0149=COIN\x0156=CLIENT\x0134=2\x0152=20250101-12:00:00.000\x01262=req\x0155=BTC-USD\x01268=%d\x01", 2*levels)
+	for i := 0; i < levels; i++ {
+		fmt.Fprintf(&body, "269=0\x01270=%d.25\x01271=0.%04d\x01273=12:00:00.000\x01", 50000-i, i+1)
+	}
+	for i := 0; i < levels; i++ {
+		fmt.Fprintf(&body, "269=1\x01270=%d.75\x01271=0.%04d\x01273=12:00:00.000\x01", 50001+i, i+1)
+	}
+	return rawMessage(body.String())
+}
+
+// rawMessage parses body, which starts at MsgType (35), with BeginString, BodyLength and CheckSum added
+func rawMessage(body string) *quickfix.Message {
+	raw := fmt.Sprintf("8=FIX.4.4\x019=%d\x01%s", len(body), body)
+	sum := 0
+	for i := 0; i < len(raw); i++ {
+		sum += int(raw[i])
+	}
+	raw += fmt.Sprintf("10=%03d\x01", sum%256)
+
+	msg := quickfix.NewMessage()
+	if err := quickfix.ParseMessage(msg, bytes.NewBufferString(raw)); err != nil {
+		panic(err)
+	}
+	return msg
+}
+
+func BenchmarkExtractTradesFullDepth(b *testing.B) {
+	app := createTestFixApp()
+	msg := fullDepthSnapshot(500)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if trades := app.extractTrades(msg, "BTC-USD", "req", true, "2"); len(trades) != 1000 {
+			b.Fatalf("Expected 1000 entries, got %d", len(trades))
+		}
+	}
+}
+
+func BenchmarkAppendTradesPooled(b *testing.B) {
+	app := createTestFixApp()
+	msg := fullDepthSnapshot(500)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch := getTradeBatch()
+		*batch = app.appendTrades(*batch, msg, "BTC-USD", "req", true, "2")
+		if len(*batch) != 1000 {
+			b.Fatalf("Expected 1000 entries, got %d", len(*batch))
+		}
+		putTradeBatch(batch)
+	}
+}
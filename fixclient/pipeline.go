@@ -0,0 +1,227 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"prime-fix-md-go/constants"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const (
+	OverflowBlock = "block" // FromApp waits for room, holding up the FIX session
+	OverflowDrop  = "drop"  // Market data arriving at a full queue is discarded and counted
+
+	DefaultPipelineQueueSize = 10000
+
+	dropWarnEvery = 10 * time.Second
+)
+
+// inbound is an application message waiting for the worker, with the time it arrived
+type inbound struct {
+	msg      *quickfix.Message
+	received time.Time
+	entries  int   // NoMDEntries (268), counted against the memory budget while queued
+	bytes    int64 // Raw message length
+}
+
+// pipeline moves market data handling (parsing, the book, storage and display) off the
+// QuickFIX callback goroutine, so slow console output cannot delay heartbeats. One worker
+// keeps messages in arrival order.
+type pipeline struct {
+	queue chan inbound
+	drop  bool
+
+	mu      sync.RWMutex // Held for reading while enqueueing; Stop takes it to close the queue
+	stopped bool
+	worker  sync.WaitGroup
+
+	processed atomic.Int64
+	dropped   atomic.Int64
+	maxDepth  atomic.Int64
+	lastWarn  atomic.Int64 // UnixNano of the last dropped-message warning
+
+	queuedEntries atomic.Int64
+	queuedBytes   atomic.Int64
+	shedding      atomic.Bool  // Over the memory budget; incoming market data is dropped while others wait
+	shed          atomic.Int64 // Messages dropped for the memory budget
+}
+
+// PipelineStats is a snapshot of the pipeline's counters
+type PipelineStats struct {
+	Depth     int
+	MaxDepth  int64
+	Capacity  int
+	Processed int64
+	Dropped   int64
+	Overflow  string
+}
+
+// StartPipeline hands market data to a worker through a queue of size messages. overflow
+// chooses what FromApp does when the queue is full: OverflowBlock or OverflowDrop. Until it
+// is called messages are handled on the QuickFIX goroutine.
+func (a *FixApp) StartPipeline(size int, overflow string) error {
+	if size <= 0 {
+		return fmt.Errorf("pipeline queue size must be positive, got %d", size)
+	}
+	if overflow != OverflowBlock && overflow != OverflowDrop {
+		return fmt.Errorf("unknown pipeline overflow %q (want %s or %s)", overflow, OverflowBlock, OverflowDrop)
+	}
+
+	p := &pipeline{queue: make(chan inbound, size), drop: overflow == OverflowDrop}
+	p.worker.Add(1)
+	go func() {
+		defer p.worker.Done()
+		for in := range p.queue {
+			p.queuedEntries.Add(-int64(in.entries))
+			p.queuedBytes.Add(-in.bytes)
+			a.handleApplicationMessage(in.msg, in.received)
+			p.processed.Add(1)
+		}
+	}()
+	a.pipeline = p
+	return nil
+}
+
+// StopPipeline handles everything already queued and stops the worker. Messages arriving
+// afterwards are handled on the caller's goroutine.
+func (a *FixApp) StopPipeline() {
+	p := a.pipeline
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+	p.worker.Wait()
+}
+
+// PipelineStats reports queue depth and drops; ok is false when the pipeline is not running
+func (a *FixApp) PipelineStats() (PipelineStats, bool) {
+	p := a.pipeline
+	if p == nil {
+		return PipelineStats{}, false
+	}
+	overflow := OverflowBlock
+	if p.drop {
+		overflow = OverflowDrop
+	}
+	return PipelineStats{
+		Depth:     len(p.queue),
+		MaxDepth:  p.maxDepth.Load(),
+		Capacity:  cap(p.queue),
+		Processed: p.processed.Load(),
+		Dropped:   p.dropped.Load(),
+		Overflow:  overflow,
+	}, true
+}
+
+// dispatch queues msg for the worker, or handles it here when there is no pipeline
+func (a *FixApp) dispatch(msg *quickfix.Message, received time.Time) {
+	p := a.pipeline
+	if p == nil {
+		a.handleApplicationMessage(msg, received)
+		return
+	}
+	entries, _ := msg.Body.GetInt(constants.TagNoMdEntries)
+	if !p.enqueue(inbound{msg: msg, received: received, entries: entries, bytes: int64(len(msg.Bytes()))}) {
+		a.handleApplicationMessage(msg, received)
+	}
+}
+
+// enqueue reports whether in was taken (queued or dropped); false means the pipeline is stopped
+func (p *pipeline) enqueue(in inbound) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped {
+		return false
+	}
+
+	// While over the memory budget, market data is dropped as long as the worker has a backlog
+	if p.shedding.Load() && isMarketData(in.msg) && len(p.queue) > 0 {
+		p.recordShed(in.received)
+		return true
+	}
+
+	// Only market data is dropped; a command may be waiting on a reject
+	if p.drop && isMarketData(in.msg) {
+		select {
+		case p.queue <- in:
+		default:
+			p.recordDrop(in.received)
+			return true
+		}
+	} else {
+		p.queue <- in
+	}
+
+	p.queuedEntries.Add(int64(in.entries))
+	p.queuedBytes.Add(in.bytes)
+	if depth := int64(len(p.queue)); depth > p.maxDepth.Load() {
+		p.maxDepth.Store(depth)
+	}
+	return true
+}
+
+// recordDrop counts a discarded message, warning at most every dropWarnEvery
+func (p *pipeline) recordDrop(now time.Time) {
+	dropped := p.dropped.Add(1)
+	last := p.lastWarn.Load()
+	if now.UnixNano()-last >= int64(dropWarnEvery) && p.lastWarn.CompareAndSwap(last, now.UnixNano()) {
+		log.Printf("Message queue full (%d): dropped %d market data messages so far", cap(p.queue), dropped)
+	}
+}
+
+// recordShed counts a message dropped for the memory budget, warning at most every dropWarnEvery
+func (p *pipeline) recordShed(now time.Time) {
+	shed := p.shed.Add(1)
+	last := p.lastWarn.Load()
+	if now.UnixNano()-last >= int64(dropWarnEvery) && p.lastWarn.CompareAndSwap(last, now.UnixNano()) {
+		log.Printf("Over the memory budget: dropped %d market data messages so far", shed)
+	}
+}
+
+func isMarketData(msg *quickfix.Message) bool {
+	t, _ := msg.Header.GetString(constants.TagMsgType)
+	return t == constants.MsgTypeMarketDataSnapshot || t == constants.MsgTypeMarketDataIncremental
+}
+
+// displayPipelineStats shows the message queue behind the FIX session
+func (a *FixApp) displayPipelineStats(out output) {
+	s, ok := a.PipelineStats()
+	if !ok {
+		return
+	}
+	out.Table("Message Pipeline:",
+		[]string{"Queue", "Max Queue", "Capacity", "Processed", "Dropped", "Overflow"},
+		[][]string{{
+			fmt.Sprint(s.Depth),
+			fmt.Sprint(s.MaxDepth),
+			fmt.Sprint(s.Capacity),
+			fmt.Sprint(s.Processed),
+			fmt.Sprint(s.Dropped),
+			s.Overflow,
+		}})
+}
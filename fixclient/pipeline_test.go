@@ -0,0 +1,101 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/constants"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func testAppMessage(msgType string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(constants.TagMsgType, msgType)
+	return msg
+}
+
+func TestStartPipelineValidates(t *testing.T) {
+	app := createTestFixApp()
+	if err := app.StartPipeline(0, OverflowBlock); err == nil {
+		t.Fatalf("Expected an error for an empty queue")
+	}
+	if err := app.StartPipeline(10, "spill"); err == nil {
+		t.Fatalf("Expected an error for an unknown overflow")
+	}
+	if _, ok := app.PipelineStats(); ok {
+		t.Fatalf("Expected no pipeline after failed starts")
+	}
+}
+
+func TestStopPipelineDrains(t *testing.T) {
+	w := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(w)
+
+	app := createTestFixApp()
+	if err := app.StartPipeline(100, OverflowBlock); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		app.dispatch(testAppMessage("B"), time.Now())
+	}
+	app.StopPipeline()
+
+	s, _ := app.PipelineStats()
+	if s.Processed != 50 || s.Depth != 0 || s.Dropped != 0 {
+		t.Fatalf("Expected 50 processed and an empty queue, got %+v", s)
+	}
+
+	// Once stopped, messages are handled inline instead of panicking on the closed queue
+	app.dispatch(testAppMessage("B"), time.Now())
+	app.StopPipeline()
+}
+
+func TestPipelineDropsMarketDataWhenFull(t *testing.T) {
+	p := &pipeline{queue: make(chan inbound, 1), drop: true}
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if !p.enqueue(inbound{msg: testAppMessage(constants.MsgTypeMarketDataIncremental), received: now}) {
+			t.Fatalf("Expected message %d to be taken", i)
+		}
+	}
+	if len(p.queue) != 1 || p.dropped.Load() != 2 || p.maxDepth.Load() != 1 {
+		t.Fatalf("Expected 1 queued and 2 dropped, got %d queued, %d dropped", len(p.queue), p.dropped.Load())
+	}
+
+	// Rejects wait for room instead of being dropped
+	taken := make(chan struct{})
+	go func() {
+		p.enqueue(inbound{msg: testAppMessage(constants.MsgTypeMarketDataReject), received: now})
+		close(taken)
+	}()
+	select {
+	case <-taken:
+		t.Fatalf("Expected the reject to wait for room")
+	case <-time.After(20 * time.Millisecond):
+	}
+	<-p.queue
+	<-taken
+	if in := <-p.queue; isMarketData(in.msg) || p.dropped.Load() != 2 {
+		t.Fatalf("Expected the reject to be queued without another drop")
+	}
+}
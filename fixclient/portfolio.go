@@ -0,0 +1,94 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"prime-fix-md-go/primeapi"
+	"prime-fix-md-go/products"
+)
+
+// showPortfolioDetails prints the portfolio the session logged on with, so users can confirm
+// they are capturing data under the intended PRIME_PORTFOLIO_ID
+func (a *FixApp) showPortfolioDetails() {
+	portfolio, err := a.Rest.GetPortfolio(context.Background(), a.Config.PortfolioFor(a.SessionId))
+	if err != nil {
+		a.consoleOutput().Info("Portfolio details unavailable: %v", err)
+		return
+	}
+
+	// Entitlements come from the product list when it was loaded over REST at startup
+	list, err := a.productsForEntitlements()
+	if err != nil {
+		a.consoleOutput().Info("Portfolio entitlements unavailable: %v", err)
+	}
+
+	a.consoleOutput().Table("Portfolio:", []string{"Field", "Value"}, portfolioRows(portfolio, list))
+}
+
+func (a *FixApp) productsForEntitlements() ([]products.Product, error) {
+	list := a.Products.List()
+	for _, p := range list {
+		if len(p.Permissions) > 0 {
+			return list, nil
+		}
+	}
+	return a.Rest.ListProducts(context.Background(), a.Config.PortfolioFor(a.SessionId))
+}
+
+func portfolioRows(portfolio primeapi.Portfolio, list []products.Product) [][]string {
+	rows := [][]string{
+		{"Id", portfolio.Id},
+		{"Name", portfolio.Name},
+		{"Entity", portfolio.EntityId},
+		{"Organization", portfolio.OrganizationId},
+	}
+	if len(list) > 0 {
+		rows = append(rows, []string{"Products", fmt.Sprintf("%d", len(list))})
+		rows = append(rows, []string{"Entitlements", summarizePermissions(list)})
+	}
+	return rows
+}
+
+// summarizePermissions counts products per permission, e.g. "READ: 120, TRADE: 98"
+func summarizePermissions(list []products.Product) string {
+	counts := make(map[string]int)
+	for _, p := range list {
+		for _, permission := range p.Permissions {
+			counts[strings.TrimPrefix(permission, "PERMISSION_")]++
+		}
+	}
+	if len(counts) == 0 {
+		return "none reported"
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %d", name, counts[name]))
+	}
+	return strings.Join(parts, ", ")
+}
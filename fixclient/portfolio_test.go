@@ -0,0 +1,52 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"testing"
+
+	"prime-fix-md-go/products"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestSummarizePermissions(t *testing.T) {
+	list := []products.Product{
+		{Symbol: "BTC-USD", Permissions: []string{"PERMISSION_READ", "PERMISSION_TRADE"}},
+		{Symbol: "ETH-USD", Permissions: []string{"PERMISSION_READ"}},
+	}
+	if got := summarizePermissions(list); got != "READ: 2, TRADE: 1" {
+		t.Fatalf("Unexpected summary %q", got)
+	}
+	if got := summarizePermissions([]products.Product{{Symbol: "BTC-USD"}}); got != "none reported" {
+		t.Fatalf("Unexpected summary %q", got)
+	}
+}
+
+func TestPortfolioForSession(t *testing.T) {
+	config := NewConfig("", "", "", "SENDER", "COIN", "pf-default")
+	hedge := quickfix.SessionID{BeginString: "FIXT.1.1", SenderCompID: "SENDER", TargetCompID: "COIN", Qualifier: "hedge"}
+	config.SessionPortfolios = map[quickfix.SessionID]string{hedge: "pf-hedge"}
+
+	if got := config.PortfolioFor(hedge); got != "pf-hedge" {
+		t.Fatalf("Expected session override, got %s", got)
+	}
+	main := quickfix.SessionID{BeginString: "FIXT.1.1", SenderCompID: "SENDER", TargetCompID: "COIN"}
+	if got := config.PortfolioFor(main); got != "pf-default" {
+		t.Fatalf("Expected default portfolio, got %s", got)
+	}
+}
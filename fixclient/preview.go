@@ -0,0 +1,69 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"fmt"
+
+	"prime-fix-md-go/builder"
+	"prime-fix-md-go/formatter"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Fields the session recomputes on send; showing preview values for them would be misleading
+var previewSkipTags = map[string]bool{"9": true, "10": true}
+
+// previewMessage renders an outgoing message as a tag/name/value table instead of sending it
+func (a *FixApp) previewMessage(out output, msg *quickfix.Message) {
+	raw := []byte(msg.String())
+
+	var rows [][]string
+	for _, row := range formatter.MessageRows(raw) {
+		if !previewSkipTags[row[0]] {
+			rows = append(rows, row)
+		}
+	}
+
+	title := fmt.Sprintf("Preview: %s (not sent; MsgSeqNum, BodyLength and CheckSum are set on send)", formatter.MessageTypeName(raw))
+	out.Table(title, []string{"Tag", "Name", "Value"}, rows)
+}
+
+func (a *FixApp) previewMarketDataRequest(out output, instruments []builder.Instrument, subscriptionType, marketDepth string, entryTypes []string, opts builder.MdRequestOptions) {
+	a.previewMessage(out, a.buildMarketDataRequest(newMdReqId(), instruments, subscriptionType, marketDepth, entryTypes, opts))
+}
+
+// previewUnsubscribeBySymbol shows the unsubscribe that would be sent for each active subscription
+func (a *FixApp) previewUnsubscribeBySymbol(out output, symbol string) error {
+	subs := a.TradeStore.GetSubscriptionsBySymbol()[symbol]
+	if len(subs) == 0 {
+		return fmt.Errorf("%w for %s", ErrNoSuchSubscription, symbol)
+	}
+	for _, sub := range subs {
+		a.previewMessage(out, a.buildUnsubscribe(sub))
+	}
+	return nil
+}
+
+func (a *FixApp) previewRawMessage(out output, raw string) error {
+	msg, err := a.buildRawMessage(raw)
+	if err != nil {
+		return err
+	}
+	a.previewMessage(out, msg)
+	return nil
+}
@@ -0,0 +1,74 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"prime-fix-md-go/builder"
+	"prime-fix-md-go/constants"
+)
+
+func newPreviewTestApp(buf *bytes.Buffer) *FixApp {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+	app.Renderer, _ = NewRenderer(OutputPlain, buf)
+	return app
+}
+
+func TestPreviewMarketDataRequest(t *testing.T) {
+	var buf bytes.Buffer
+	app := newPreviewTestApp(&buf)
+	app.Config.CustomTags = []builder.CustomTag{{Tag: 9999, Value: "foo"}}
+
+	// Not connected: a dry run must still work and must not register a subscription
+	app.handleDirectMdRequest(app.consoleOutput(), []string{"md", "BTC-USD", "--subscribe", "--depth", "5", "--dry-run"})
+
+	out := buf.String()
+	for _, want := range []string{"35\tMsgType\tV (MarketDataRequest)", "264\tMarketDepth\t5", "55\tSymbol\tBTC-USD", "9999\t"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Expected %q in preview, got:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "9\t") || strings.HasPrefix(line, "10\t") {
+			t.Fatalf("BodyLength/CheckSum should not be previewed: %s", line)
+		}
+	}
+	if len(app.TradeStore.GetSubscriptionsBySymbol()) != 0 {
+		t.Fatal("Dry run should not add a subscription")
+	}
+}
+
+func TestPreviewUnsubscribe(t *testing.T) {
+	var buf bytes.Buffer
+	app := newPreviewTestApp(&buf)
+
+	if err := app.previewUnsubscribeBySymbol(app.consoleOutput(), "BTC-USD"); !errors.Is(err, ErrNoSuchSubscription) {
+		t.Fatalf("Expected ErrNoSuchSubscription, got %v", err)
+	}
+
+	app.TradeStore.AddSubscription("BTC-USD", constants.SubscriptionRequestTypeSubscribe, "md_1")
+	if err := app.previewUnsubscribeBySymbol(app.consoleOutput(), "BTC-USD"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "263\tSubscriptionRequestType\t2") || !strings.Contains(buf.String(), "262\tMDReqID\tmd_1") {
+		t.Fatalf("Expected unsubscribe preview, got:\n%s", buf.String())
+	}
+}
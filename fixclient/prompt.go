@@ -0,0 +1,86 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/chzyer/readline"
+)
+
+const (
+	promptRefresh = time.Second
+
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorReset = "\x1b[0m"
+)
+
+// promptText shows the session state and live subscription count, e.g. FIX-MD[connected|3 subs]>,
+// followed by |quiet while live updates are not printed
+func promptText(connected bool, subs int, quiet, color bool) string {
+	state, stateColor := "connected", colorGreen
+	if !connected {
+		state, stateColor = "disconnected", colorRed
+	}
+	unit := "subs"
+	if subs == 1 {
+		unit = "sub"
+	}
+	if quiet {
+		unit += "|quiet"
+	}
+	if !color {
+		return fmt.Sprintf("FIX-MD[%s|%d %s]> ", state, subs, unit)
+	}
+	if !connected {
+		// The whole prompt turns red so a dropped session is hard to miss
+		return fmt.Sprintf("%sFIX-MD[%s|%d %s]>%s ", colorRed, state, subs, unit, colorReset)
+	}
+	return fmt.Sprintf("FIX-MD[%s%s%s|%d %s]> ", stateColor, state, colorReset, subs, unit)
+}
+
+func (a *FixApp) prompt(color bool) string {
+	return promptText(a.IsConnected(), len(a.TradeStore.GetSubscriptionStatus()), a.IsQuiet(), color)
+}
+
+// promptColor is off when stdout is not a terminal or NO_COLOR is set
+func promptColor() bool {
+	return readline.DefaultIsTerminal() && os.Getenv("NO_COLOR") == ""
+}
+
+// watchPrompt redraws the prompt when the connection or subscriptions change while waiting for input
+func (a *FixApp) watchPrompt(rl *readline.Instance, color bool, paused *atomic.Bool, stop <-chan struct{}) {
+	ticker := time.NewTicker(promptRefresh)
+	defer ticker.Stop()
+
+	current := a.prompt(color)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if p := a.prompt(color); p != current && !paused.Load() {
+				current = p
+				rl.SetPrompt(p)
+				rl.Refresh()
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPromptText(t *testing.T) {
+	if got := promptText(true, 3, false, false); got != "FIX-MD[connected|3 subs]> " {
+		t.Fatalf("Unexpected prompt %q", got)
+	}
+	if got := promptText(false, 1, false, false); got != "FIX-MD[disconnected|1 sub]> " {
+		t.Fatalf("Unexpected prompt %q", got)
+	}
+	if got := promptText(false, 0, false, true); !strings.HasPrefix(got, colorRed) || !strings.Contains(got, colorReset) {
+		t.Fatalf("Expected a red prompt while disconnected, got %q", got)
+	}
+}
+
+func TestPromptFollowsState(t *testing.T) {
+	app := createTestFixApp()
+	app.TradeStore.AddSubscription("BTC-USD", "1", "req-1")
+	app.TradeStore.AddSubscription("ETH-USD", "1", "req-2")
+	app.connected.Store(true)
+
+	if got := app.prompt(false); got != "FIX-MD[connected|2 subs]> " {
+		t.Fatalf("Unexpected prompt %q", got)
+	}
+}
@@ -0,0 +1,70 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetQuiet turns quiet mode on or off. While quiet, market data for live subscriptions is stored
+// and counted but not printed; requested snapshots and command output still are.
+func (a *FixApp) SetQuiet(on bool) {
+	a.quiet.Store(on)
+}
+
+// IsQuiet reports whether quiet mode is on
+func (a *FixApp) IsQuiet() bool {
+	return a.quiet.Load()
+}
+
+// quietMessage reports whether quiet mode suppresses the console output for one market data
+// message: incremental updates, and snapshots for live subscriptions or book resyncs. tail
+// prints regardless, since following a symbol is an explicit request for its trades.
+func (a *FixApp) quietMessage(mdReqId string, isIncremental bool) bool {
+	if !a.quiet.Load() {
+		return false
+	}
+	if _, tailing := a.consoleOutput().Renderer.(*tailRenderer); tailing {
+		return false
+	}
+	if isIncremental || a.TradeStore.IsSubscription(mdReqId) {
+		return true
+	}
+	a.resyncMu.Lock()
+	defer a.resyncMu.Unlock()
+	_, resync := a.resyncs[mdReqId]
+	return resync
+}
+
+func (a *FixApp) handleQuietRequest(out output, parts []string) {
+	if len(parts) > 1 {
+		switch strings.ToLower(parts[1]) {
+		case "on":
+			a.SetQuiet(true)
+		case "off":
+			a.SetQuiet(false)
+		default:
+			fmt.Fprintln(out.Console(), "Usage: quiet [on|off]")
+			return
+		}
+	}
+	if a.IsQuiet() {
+		out.Info("Quiet mode on: live updates are stored and counted but not printed (quiet off to show them)")
+	} else {
+		out.Info("Quiet mode off: live updates are printed")
+	}
+}
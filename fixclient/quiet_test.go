@@ -0,0 +1,46 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import "testing"
+
+func TestQuietMessage(t *testing.T) {
+	app := NewFixApp(nil, nil)
+	app.TradeStore.AddSubscription("BTC-USD", "1", "sub-1")
+	app.resyncs["resync-1"] = "BTC-USD"
+
+	if app.quietMessage("sub-1", true) {
+		t.Fatalf("Expected updates to print while quiet mode is off")
+	}
+
+	app.SetQuiet(true)
+	for _, reqId := range []string{"sub-1", "resync-1"} {
+		if !app.quietMessage(reqId, false) {
+			t.Fatalf("Expected the snapshot for %s to be suppressed", reqId)
+		}
+	}
+	if !app.quietMessage("other", true) {
+		t.Fatalf("Expected incremental updates to be suppressed")
+	}
+	if app.quietMessage("snapshot-1", false) {
+		t.Fatalf("Expected a requested snapshot to print")
+	}
+
+	app.Renderer = &tailRenderer{symbol: "BTC-USD", next: app.Renderer}
+	if app.quietMessage("sub-1", true) {
+		t.Fatalf("Expected tail to print while quiet")
+	}
+}
@@ -0,0 +1,30 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import "errors"
+
+// handleReloadRequest re-reads the config file without touching the FIX session. What is applied,
+// and what needs a restart, is logged by the ReloadConfig hook.
+func (a *FixApp) handleReloadRequest(out output) {
+	if a.ReloadConfig == nil {
+		out.Error(errors.New("reload is not available in this mode"))
+		return
+	}
+	if err := a.ReloadConfig(); err != nil {
+		out.Error(err)
+	}
+}
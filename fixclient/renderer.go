@@ -0,0 +1,833 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/formatter"
+)
+
+const (
+	OutputTable = "table"
+	OutputPlain = "plain"
+	OutputJson  = "json"
+	OutputQuiet = "quiet"
+)
+
+// Renderer is the single place console output is produced, so the format can be switched globally
+type Renderer interface {
+	MarketDataReceived(msgType, symbol, mdReqId, noMdEntries, seqNum string)
+	Snapshot(symbol string, entries []Trade)
+	Updates(entries []Trade)
+	Reject(rej *ErrRejected, hint string)
+	Status(status StatusView)
+	Table(title string, headers []string, rows [][]string)
+	Info(format string, args ...interface{})
+	Error(err error)
+}
+
+type StatusView struct {
+	SessionId     string
+	Connected     bool
+	Admin         *formatter.AdminStats // nil when the log factory does not track admin traffic
+	Clock         *ClockStats
+	Subscriptions map[string][]*Subscription // symbol -> subscriptions
+	Markets       map[string]MarketSummary   // symbol -> session trade summary, for symbols that traded
+}
+
+// MarketSummary is a symbol's session high, low, last price and volume from its streamed
+// trades, formatted for display
+type MarketSummary struct {
+	High   string `json:"high"`
+	Low    string `json:"low"`
+	Last   string `json:"last"`
+	Volume string `json:"volume"`
+}
+
+// marketCells is the session summary of symbol for the status table, "-" before it trades
+func (s StatusView) marketCells(symbol string) MarketSummary {
+	if m, ok := s.Markets[symbol]; ok {
+		return m
+	}
+	return MarketSummary{High: "-", Low: "-", Last: "-", Volume: "-"}
+}
+
+func adminSummary(admin *formatter.AdminStats) string {
+	summary := fmt.Sprintf("heartbeats in %d / out %d, test requests in %d / out %d",
+		admin.InHeartbeats, admin.OutHeartbeats, admin.InTestRequests, admin.OutTestRequests)
+	if !admin.LastInbound.IsZero() {
+		summary += fmt.Sprintf(", last inbound %s ago", time.Since(admin.LastInbound).Round(time.Second))
+	}
+	if !admin.LastInHeartbeat.IsZero() {
+		summary += fmt.Sprintf(", last heartbeat %s ago", time.Since(admin.LastInHeartbeat).Round(time.Second))
+	}
+	if admin.MissedHeartbeats > 0 {
+		summary += fmt.Sprintf(", missed heartbeats %d", admin.MissedHeartbeats)
+	}
+	if admin.PendingTestRequests > 0 {
+		summary += fmt.Sprintf(", unanswered test requests %d (oldest %s ago)",
+			admin.PendingTestRequests, time.Since(admin.OldestPendingTest).Round(time.Second))
+	}
+	if admin.LastTestRequestRtt > 0 {
+		summary += fmt.Sprintf(", test request rtt %s", admin.LastTestRequestRtt.Round(time.Millisecond))
+	}
+	return summary
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func NewRenderer(format string, w io.Writer) (Renderer, error) {
+	switch format {
+	case OutputTable, "":
+		return &tableRenderer{out: w, logger: log.New(w, "", log.LstdFlags)}, nil
+	case OutputPlain:
+		return &plainRenderer{out: w}, nil
+	case OutputJson:
+		return &jsonRenderer{enc: json.NewEncoder(w)}, nil
+	case OutputQuiet:
+		return &quietRenderer{plain: &plainRenderer{out: w}}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (expected table, plain, json or quiet)", format)
+	}
+}
+
+// rendererFormat maps "" to the default table format
+func rendererFormat(format string) string {
+	if format == "" {
+		return OutputTable
+	}
+	return format
+}
+
+// setRendererTemplates gives the table and plain renderers their user line templates
+func setRendererTemplates(r Renderer, templates *LineTemplates) {
+	switch r := r.(type) {
+	case *tableRenderer:
+		r.templates = templates
+	case *plainRenderer:
+		r.templates = templates
+	}
+}
+
+func sortedSymbols(subs map[string][]*Subscription) []string {
+	symbols := make([]string, 0, len(subs))
+	for symbol := range subs {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+func shortReqId(reqId string) string {
+	if len(reqId) > 16 {
+		return "..." + reqId[len(reqId)-13:]
+	}
+	return reqId
+}
+
+// shortLabel fits a label into the status table's Label column
+func shortLabel(label string) string {
+	if label == "" {
+		return "-"
+	}
+	if len(label) > 16 {
+		return label[:13] + "..."
+	}
+	return label
+}
+
+// tableRenderer draws box tables for snapshots and status, and log-style lines for updates
+type tableRenderer struct {
+	mu        sync.Mutex
+	out       io.Writer
+	logger    *log.Logger
+	templates *LineTemplates
+}
+
+func (r *tableRenderer) MarketDataReceived(msgType, symbol, mdReqId, noMdEntries, seqNum string) {
+	r.logger.Printf("Market Data %s for %s (ReqId: %s, Entries: %s, Seq: %s)",
+		getMarketDataTypeName(msgType), symbol, mdReqId, noMdEntries, seqNum)
+}
+
+func (r *tableRenderer) Snapshot(symbol string, trades []Trade) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logger.Printf("\n📋 Market Data Snapshot for %s:", symbol)
+
+	for _, group := range groupByEntryType(trades) {
+		entryType, entries := group.entryType, group.entries
+		typeName := getMdEntryTypeName(entryType)
+		r.logger.Printf("\n🔹 %s Entries (%d):", typeName, len(entries))
+
+		if entryType == constants.MdEntryTypeBid || entryType == constants.MdEntryTypeOffer {
+			// Display bid/offer book format, with the size available at or better than each level
+			notional := entries[0].CumNotional != ""
+			border, notionalHeader := "────────────────", ""
+			if notional {
+				border, notionalHeader = "────────────────┬──────────────────", " Cum Notional     │"
+			}
+			fmt.Fprintf(r.out, "┌─────┬───────────────┬────────────────┬%s┬────────┬───────────────┬──────────┐\n", border)
+			fmt.Fprintf(r.out, "│ Pos │ Price         │ Size           │ Cum Size       │%s Orders │ %-13s │ Type     │\n", notionalHeader, withZone("Time"))
+			fmt.Fprintf(r.out, "├─────┼───────────────┼────────────────┼%s┼────────┼───────────────┼──────────┤\n", strings.ReplaceAll(border, "┬", "┼"))
+
+			for _, entry := range entries {
+				pos := entry.Position
+				if pos == "" {
+					pos = "-"
+				}
+				orders := entry.NumOrders
+				if orders == "" {
+					orders = "-"
+				}
+				cumNotional := ""
+				if notional {
+					cumNotional = fmt.Sprintf(" %-16s │", entry.CumNotional)
+				}
+				fmt.Fprintf(r.out, "│ %-3s │ %-13s │ %-14s │ %-14s │%s %-6s │ %-13s │ %-8s │\n",
+					pos, entry.Price, entry.Size, entry.CumSize, cumNotional, orders, entry.Time, typeName)
+			}
+			fmt.Fprintf(r.out, "└─────┴───────────────┴────────────────┴%s┴────────┴───────────────┴──────────┘\n", strings.ReplaceAll(border, "┬", "┴"))
+
+		} else if entryType == constants.MdEntryTypeTrade {
+			// Display trade format
+			fmt.Fprintf(r.out, "┌─────┬───────────────┬────────────────┬──────────────────┬───────────────┬───────────┬──────┐\n")
+			fmt.Fprintf(r.out, "│ #   │ Price         │ Size           │ Notional         │ %-13s │ Aggressor │ Cond │\n", withZone("Time"))
+			fmt.Fprintf(r.out, "├─────┼───────────────┼────────────────┼──────────────────┼───────────────┼───────────┼──────┤\n")
+
+			for i, entry := range entries {
+				aggressor := entry.Aggressor
+				if aggressor == "" {
+					aggressor = "-"
+				}
+				cond := entry.TradeCondition
+				if cond == "" {
+					cond = "-"
+				}
+				notional := entry.Notional
+				if notional == "" {
+					notional = "-"
+				}
+				fmt.Fprintf(r.out, "│ %-3d │ %-13s │ %-14s │ %-16s │ %-13s │ %-9s │ %-4s │\n",
+					i+1, entry.Price, entry.Size, notional, entry.Time, aggressor, cond)
+			}
+			fmt.Fprintf(r.out, "└─────┴───────────────┴────────────────┴──────────────────┴───────────────┴───────────┴──────┘\n")
+
+		} else {
+			// Display OHLC/Volume format (no size column - not relevant for these data types)
+			fmt.Fprintf(r.out, "┌─────┬───────────────┬───────────────┐\n")
+			fmt.Fprintf(r.out, "│ #   │ Value         │ %-13s │\n", withZone("Time"))
+			fmt.Fprintf(r.out, "├─────┼───────────────┼───────────────┤\n")
+
+			for i, entry := range entries {
+				fmt.Fprintf(r.out, "│ %-3d │ %-13s │ %-13s │\n",
+					i+1, entryValue(entry), entry.Time)
+			}
+			fmt.Fprintf(r.out, "└─────┴───────────────┴───────────────┘\n")
+		}
+	}
+
+	r.logger.Printf("\nTotal Entries Displayed: %d", len(trades))
+}
+
+func (r *tableRenderer) Updates(trades []Trade) {
+	if len(trades) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, trade := range trades {
+		if line, ok := r.templates.line(trade); ok {
+			fmt.Fprintln(r.out, line)
+			continue
+		}
+		line := symbolStyle.updateLine(trade)
+		if trade.Large {
+			line = largePrintLine(line)
+		}
+		r.logger.Print(line)
+	}
+	// Add visual separator after each batch of incremental updates
+	r.logger.Println("────────────────────────────────────────────────")
+}
+
+func (r *tableRenderer) Reject(rej *ErrRejected, hint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logger.Printf("Market Data Request REJECTED")
+	r.logger.Printf("   MdReqId: %s", rej.MdReqId)
+	r.logger.Printf("   Reason: %s (%s)", rej.Reason, getMdReqRejReasonDesc(rej.Reason))
+	if rej.Text != "" {
+		r.logger.Printf("   Text: %s", rej.Text)
+	}
+	if hint != "" {
+		r.logger.Print(hint)
+	}
+}
+
+func (r *tableRenderer) Status(status StatusView) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fmt.Fprintf(r.out, "Session: %s ", status.SessionId)
+	if status.Connected {
+		fmt.Fprintln(r.out, "(Connected)")
+	} else {
+		fmt.Fprintln(r.out, "(Disconnected)")
+	}
+	if status.Admin != nil {
+		fmt.Fprintf(r.out, "Admin traffic: %s\n", adminSummary(status.Admin))
+	}
+	if status.Clock != nil {
+		fmt.Fprintf(r.out, "Clock: %s\n", status.Clock)
+	}
+
+	if len(status.Subscriptions) == 0 {
+		fmt.Fprintln(r.out, "No active subscriptions")
+		return
+	}
+
+	fmt.Fprintf(r.out, `
+Active Subscriptions:
+┌─────────────┬──────────────────┬──────────────┬─────────────┬─────────────┬─────────────────────────┬─────────────────┬───────────────┬───────────────┬───────────────┬───────────────┬──────────────────┬──────────────────┐
+│ Symbol      │ Type             │ Mode         │ Status      │ Updates     │ By Type                 │ %-15s │ Last          │ High          │ Low           │ Volume        │ ReqId            │ Label            │
+├─────────────┼──────────────────┼──────────────┼─────────────┼─────────────┼─────────────────────────┼─────────────────┼───────────────┼───────────────┼───────────────┼───────────────┼──────────────────┼──────────────────┤
+`, withZone("Updated"))
+
+	for _, symbol := range sortedSymbols(status.Subscriptions) {
+		for i, sub := range status.Subscriptions[symbol] {
+			// Show symbol and its session summary only on first line for multiple subscriptions
+			displaySymbol := symbol
+			market := status.marketCells(symbol)
+			if i > 0 {
+				displaySymbol, market = "", MarketSummary{}
+			}
+
+			fmt.Fprintf(r.out, "│ %-11s │ %-16s │ %-12s │ %-11s │ %-11d │ %-23s │ %-15s │ %-13s │ %-13s │ %-13s │ %-13s │ %-16s │ %-16s │\n",
+				displaySymbol, getSubscriptionTypeDesc(sub.SubscriptionType), subscriptionModeDesc(sub), subscriptionState(sub),
+				sub.TotalUpdates, updateCountsDesc(sub), lastUpdateDesc(sub.LastUpdate), market.Last, market.High, market.Low, market.Volume,
+				shortReqId(sub.MdReqId), shortLabel(sub.Label))
+		}
+	}
+
+	fmt.Fprintln(r.out, "└─────────────┴──────────────────┴──────────────┴─────────────┴─────────────┴─────────────────────────┴─────────────────┴───────────────┴───────────────┴───────────────┴───────────────┴──────────────────┴──────────────────┘")
+}
+
+func (r *tableRenderer) Table(title string, headers []string, rows [][]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if title != "" {
+		fmt.Fprintf(r.out, "%s\n", title)
+	}
+	drawTable(r.out, headers, rows)
+}
+
+func (r *tableRenderer) Info(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(r.out, format+"\n", args...)
+}
+
+func (r *tableRenderer) Error(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(r.out, "Error: %v\n", err)
+}
+
+// plainRenderer writes one unadorned line per entry, suitable for grep and pipes
+type plainRenderer struct {
+	mu        sync.Mutex
+	out       io.Writer
+	templates *LineTemplates
+}
+
+func (r *plainRenderer) MarketDataReceived(msgType, symbol, mdReqId, noMdEntries, seqNum string) {}
+
+func (r *plainRenderer) Snapshot(symbol string, trades []Trade) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, trade := range trades {
+		fmt.Fprintf(r.out, "snapshot %s\n", formatUpdateLine(trade))
+	}
+}
+
+func (r *plainRenderer) Updates(trades []Trade) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, trade := range trades {
+		if line, ok := r.templates.line(trade); ok {
+			fmt.Fprintln(r.out, line)
+			continue
+		}
+		if trade.Large {
+			fmt.Fprintf(r.out, "update %s | LARGE\n", formatUpdateLine(trade))
+			continue
+		}
+		fmt.Fprintf(r.out, "update %s\n", formatUpdateLine(trade))
+	}
+}
+
+func (r *plainRenderer) Reject(rej *ErrRejected, hint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(r.out, "reject %v\n", rej)
+}
+
+func (r *plainRenderer) Status(status StatusView) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state := "disconnected"
+	if status.Connected {
+		state = "connected"
+	}
+	fmt.Fprintf(r.out, "session %s %s\n", status.SessionId, state)
+	if status.Admin != nil {
+		fmt.Fprintf(r.out, "admin %s\n", adminSummary(status.Admin))
+	}
+	if status.Clock != nil {
+		fmt.Fprintf(r.out, "clock %s\n", status.Clock)
+	}
+
+	for _, symbol := range sortedSymbols(status.Subscriptions) {
+		for _, sub := range status.Subscriptions[symbol] {
+			label := ""
+			if sub.Label != "" {
+				label = " label=" + sub.Label
+			}
+			market := ""
+			if m, ok := status.Markets[symbol]; ok {
+				market = fmt.Sprintf(" price=%s high=%s low=%s volume=%s", m.Last, m.High, m.Low, m.Volume)
+			}
+			u := sub.Updates
+			fmt.Fprintf(r.out, "subscription %s %s %s updates=%d bids=%d offers=%d trades=%d ohlcv=%d last=%s mode=%s%s%s\n",
+				symbol, sub.MdReqId, subscriptionState(sub), sub.TotalUpdates, u.Bids, u.Offers, u.Trades, u.Ohlcv,
+				lastUpdateDesc(sub.LastUpdate), subscriptionModeDesc(sub), market, label)
+		}
+	}
+}
+
+func (r *plainRenderer) Table(title string, headers []string, rows [][]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fmt.Fprintln(r.out, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(r.out, strings.Join(row, "\t"))
+	}
+}
+
+func (r *plainRenderer) Info(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(r.out, format+"\n", args...)
+}
+
+func (r *plainRenderer) Error(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(r.out, "error %v\n", err)
+}
+
+// jsonRenderer emits one JSON object per line, tagged with a "type" field
+type jsonRenderer struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (r *jsonRenderer) emit(v interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(v); err != nil {
+		log.Printf("Failed to encode JSON output: %v", err)
+	}
+}
+
+func (r *jsonRenderer) MarketDataReceived(msgType, symbol, mdReqId, noMdEntries, seqNum string) {}
+
+func (r *jsonRenderer) Snapshot(symbol string, trades []Trade) {
+	r.emit(struct {
+		Type    string  `json:"type"`
+		Symbol  string  `json:"symbol"`
+		Entries []Trade `json:"entries"`
+	}{"snapshot", symbol, trades})
+}
+
+func (r *jsonRenderer) Updates(trades []Trade) {
+	for _, trade := range trades {
+		r.emit(struct {
+			Type string `json:"type"`
+			Trade
+		}{"update", trade})
+	}
+}
+
+func (r *jsonRenderer) Reject(rej *ErrRejected, hint string) {
+	r.emit(struct {
+		Type       string `json:"type"`
+		MdReqId    string `json:"mdReqId"`
+		Reason     string `json:"reason"`
+		ReasonDesc string `json:"reasonDesc"`
+		Text       string `json:"text,omitempty"`
+	}{"reject", rej.MdReqId, rej.Reason, getMdReqRejReasonDesc(rej.Reason), rej.Text})
+}
+
+// subscriptionJson is a subscription in JSON status output and the admin API
+type subscriptionJson struct {
+	Symbol           string    `json:"symbol"`
+	MdReqId          string    `json:"mdReqId"`
+	SubscriptionType string    `json:"subscriptionType"`
+	Active           bool      `json:"active"`
+	TotalUpdates     int64     `json:"totalUpdates"`
+	LastUpdate       time.Time `json:"lastUpdate"`
+	SnapshotReceived bool      `json:"snapshotReceived"`
+	FullRefresh      bool      `json:"fullRefresh,omitempty"`
+	AggregatedBook   string    `json:"aggregatedBook,omitempty"`
+	Label            string    `json:"label,omitempty"`
+
+	UpdatesByType UpdateCounts   `json:"updatesByType"`    // TotalUpdates by entry type
+	Market        *MarketSummary `json:"market,omitempty"` // Session summary of the symbol, once it trades
+}
+
+func newSubscriptionJson(sub *Subscription) subscriptionJson {
+	return subscriptionJson{
+		Symbol:           sub.Symbol,
+		MdReqId:          sub.MdReqId,
+		SubscriptionType: sub.SubscriptionType,
+		Active:           sub.Active,
+		TotalUpdates:     sub.TotalUpdates,
+		UpdatesByType:    sub.Updates,
+		LastUpdate:       sub.LastUpdate,
+		SnapshotReceived: sub.SnapshotReceived,
+		FullRefresh:      sub.Options.FullRefresh,
+		AggregatedBook:   sub.Options.AggregatedBook,
+		Label:            sub.Label,
+	}
+}
+
+func (r *jsonRenderer) Status(status StatusView) {
+	type clockJson struct {
+		Samples      int64   `json:"samples"`
+		SkewMs       float64 `json:"skewMs"`
+		LastOffsetMs float64 `json:"lastOffsetMs"`
+		AvgOffsetMs  float64 `json:"avgOffsetMs"`
+		MaxOffsetMs  float64 `json:"maxOffsetMs"`
+		Exceeded     bool    `json:"exceeded"`
+	}
+
+	var clock *clockJson
+	if c := status.Clock; c != nil {
+		clock = &clockJson{
+			Samples:      c.Samples,
+			SkewMs:       durationMs(c.Skew),
+			LastOffsetMs: durationMs(c.LastOffset),
+			AvgOffsetMs:  durationMs(c.AvgOffset),
+			MaxOffsetMs:  durationMs(c.MaxOffset),
+			Exceeded:     c.Exceeded(),
+		}
+	}
+
+	subs := []subscriptionJson{}
+	for _, symbol := range sortedSymbols(status.Subscriptions) {
+		for _, sub := range status.Subscriptions[symbol] {
+			s := newSubscriptionJson(sub)
+			if m, ok := status.Markets[symbol]; ok {
+				s.Market = &m
+			}
+			subs = append(subs, s)
+		}
+	}
+
+	r.emit(struct {
+		Type          string                `json:"type"`
+		SessionId     string                `json:"sessionId"`
+		Connected     bool                  `json:"connected"`
+		Admin         *formatter.AdminStats `json:"admin,omitempty"`
+		Clock         *clockJson            `json:"clock,omitempty"`
+		Subscriptions []subscriptionJson    `json:"subscriptions"`
+	}{"status", status.SessionId, status.Connected, status.Admin, clock, subs})
+}
+
+func (r *jsonRenderer) Table(title string, headers []string, rows [][]string) {
+	objects := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		obj := make(map[string]string, len(headers))
+		for i, header := range headers {
+			if i < len(row) {
+				obj[header] = row[i]
+			}
+		}
+		objects = append(objects, obj)
+	}
+
+	r.emit(struct {
+		Type  string              `json:"type"`
+		Title string              `json:"title,omitempty"`
+		Rows  []map[string]string `json:"rows"`
+	}{"table", title, objects})
+}
+
+func (r *jsonRenderer) Info(format string, args ...interface{}) {
+	r.emit(struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}{"info", fmt.Sprintf(format, args...)})
+}
+
+func (r *jsonRenderer) Error(err error) {
+	r.emit(struct {
+		Type  string `json:"type"`
+		Error string `json:"error"`
+	}{"error", err.Error()})
+}
+
+// quietRenderer drops market data output but still answers explicit commands and reports errors
+type quietRenderer struct {
+	plain *plainRenderer
+}
+
+func (r *quietRenderer) MarketDataReceived(msgType, symbol, mdReqId, noMdEntries, seqNum string) {}
+
+func (r *quietRenderer) Snapshot(symbol string, trades []Trade) {}
+
+func (r *quietRenderer) Updates(trades []Trade) {}
+
+func (r *quietRenderer) Info(format string, args ...interface{}) {}
+
+func (r *quietRenderer) Reject(rej *ErrRejected, hint string) {
+	r.plain.Error(rej)
+}
+
+func (r *quietRenderer) Status(status StatusView) {
+	r.plain.Status(status)
+}
+
+func (r *quietRenderer) Table(title string, headers []string, rows [][]string) {
+	r.plain.Table(title, headers, rows)
+}
+
+func (r *quietRenderer) Error(err error) {
+	r.plain.Error(err)
+}
+
+// drawTable renders a box table sized to its contents
+func drawTable(w io.Writer, headers []string, rows [][]string) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = utf8.RuneCountInString(header)
+	}
+	for _, row := range rows {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+		}
+	}
+
+	border := func(left, mid, right string) {
+		parts := make([]string, len(widths))
+		for i, width := range widths {
+			parts[i] = strings.Repeat("─", width+2)
+		}
+		fmt.Fprintln(w, left+strings.Join(parts, mid)+right)
+	}
+	line := func(cells []string) {
+		var sb strings.Builder
+		sb.WriteString("│")
+		for i, width := range widths {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			fmt.Fprintf(&sb, " %-*s │", width, cell)
+		}
+		fmt.Fprintln(w, sb.String())
+	}
+
+	border("┌", "┬", "┐")
+	line(headers)
+	border("├", "┼", "┤")
+	for _, row := range rows {
+		line(row)
+	}
+	border("└", "┴", "┘")
+}
+
+type entryGroup struct {
+	entryType string
+	entries   []Trade
+}
+
+// groupByEntryType keeps entry types in the order they first appear in the message
+func groupByEntryType(trades []Trade) []entryGroup {
+	var groups []entryGroup
+	index := make(map[string]int)
+	for _, trade := range trades {
+		entryType := trade.EntryType
+		if entryType == "" {
+			entryType = constants.MdEntryTypeTrade // Default to Trade if not specified
+		}
+		i, ok := index[entryType]
+		if !ok {
+			i = len(groups)
+			index[entryType] = i
+			groups = append(groups, entryGroup{entryType: entryType})
+		}
+		groups[i].entries = append(groups[i].entries, trade)
+	}
+	return groups
+}
+
+// entryValue returns the meaningful number for an entry; for volume the "size" field carries the volume
+func entryValue(trade Trade) string {
+	if trade.EntryType == constants.MdEntryTypeVolume {
+		return trade.Size
+	}
+	return trade.Price
+}
+
+func subscriptionState(sub *Subscription) string {
+	if !sub.Active {
+		return "Inactive"
+	}
+	return "Active"
+}
+
+// updateCountsDesc shows the entries received by type, e.g. "B 120 O 0 T 15". Types the
+// subscription requested are shown even at zero, so a missing side stands out.
+func updateCountsDesc(sub *Subscription) string {
+	requested := make(map[string]bool, len(sub.EntryTypes))
+	for _, entryType := range sub.EntryTypes {
+		requested[entryType] = true
+	}
+	u := sub.Updates
+	var parts []string
+	for _, c := range []struct {
+		label     string
+		count     int64
+		requested bool
+	}{
+		{"B", u.Bids, requested[constants.MdEntryTypeBid]},
+		{"O", u.Offers, requested[constants.MdEntryTypeOffer]},
+		{"T", u.Trades, requested[constants.MdEntryTypeTrade]},
+		{"OHLCV", u.Ohlcv, requested[constants.MdEntryTypeOpen] || requested[constants.MdEntryTypeClose] ||
+			requested[constants.MdEntryTypeHigh] || requested[constants.MdEntryTypeLow] || requested[constants.MdEntryTypeVolume]},
+		{"Other", u.Other, false},
+	} {
+		if c.count > 0 || c.requested {
+			parts = append(parts, fmt.Sprintf("%s %d", c.label, c.count))
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+// subscriptionModeDesc shows the update and aggregation options the subscription was requested with
+func subscriptionModeDesc(sub *Subscription) string {
+	var modes []string
+	if sub.Options.FullRefresh {
+		modes = append(modes, "full refresh")
+	}
+	if mode := sub.Options.BookMode(); mode != "" {
+		modes = append(modes, mode)
+	}
+	if len(modes) == 0 {
+		return "default"
+	}
+	return strings.Join(modes, ", ")
+}
+
+func lastUpdateDesc(t time.Time) string {
+	if t.IsZero() {
+		return "Never"
+	}
+	return displayTime(t, "15:04:05")
+}
+
+// formatUpdateLine renders a single streaming entry, e.g. "BTC-USD Trade: 50000 | Size: 0.1 | Aggressor: Buy"
+func formatUpdateLine(trade Trade) string {
+	entryType := trade.EntryType
+	if entryType == "" {
+		entryType = constants.MdEntryTypeTrade
+	}
+
+	switch entryType {
+	case constants.MdEntryTypeBid, constants.MdEntryTypeOffer:
+		line := fmt.Sprintf("%s %s: %s | Size: %s | Pos: %s",
+			trade.Symbol, getMdEntryTypeName(entryType), trade.Price, trade.Size, trade.Position)
+		if trade.NumOrders != "" {
+			line += " | Orders: " + trade.NumOrders
+		}
+		if trade.QuoteCondition != "" {
+			line += " | Cond: " + trade.QuoteCondition
+		}
+		return line + entryIdSuffix(trade)
+	case constants.MdEntryTypeTrade:
+		aggressor := trade.Aggressor
+		if aggressor == "" {
+			aggressor = "-"
+		} else if trade.AggressorInferred {
+			aggressor += " (inferred)"
+		}
+		size := trade.Size
+		if trade.Count > 1 {
+			size += fmt.Sprintf(" (%d trades)", trade.Count)
+		}
+		if trade.Notional != "" {
+			size += " | Notional: " + trade.Notional
+		}
+		line := fmt.Sprintf("%s Trade: %s | Size: %s | Aggressor: %s",
+			trade.Symbol, trade.Price, size, aggressor)
+		if trade.TradeCondition != "" {
+			line += " | Cond: " + trade.TradeCondition
+		}
+		return line + entryIdSuffix(trade)
+	case constants.MdEntryTypeOpen, constants.MdEntryTypeClose, constants.MdEntryTypeHigh,
+		constants.MdEntryTypeLow, constants.MdEntryTypeVolume:
+		return fmt.Sprintf("%s %s: %s", trade.Symbol, getMdEntryTypeName(entryType), entryValue(trade))
+	default: // Unknown
+		return fmt.Sprintf("%s [%s]: %s | Size: %s", trade.Symbol, entryType, trade.Price, trade.Size)
+	}
+}
+
+// entryIdSuffix shows the incremental action and entry id when the venue sends them
+func entryIdSuffix(trade Trade) string {
+	suffix := ""
+	if trade.UpdateAction != "" {
+		suffix += " | " + getMdUpdateActionName(trade.UpdateAction)
+	}
+	if trade.EntryId != "" {
+		suffix += " | Id: " + trade.EntryId
+	}
+	return suffix
+}
@@ -0,0 +1,169 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testEntries() []Trade {
+	return []Trade{
+		{Symbol: "BTC-USD", EntryType: "0", Price: "49999.00", Size: "1.5", Position: "1"},
+		{Symbol: "BTC-USD", EntryType: "2", Price: "50000.00", Size: "0.1", Aggressor: "Buy"},
+	}
+}
+
+func TestNewRendererUnknownFormat(t *testing.T) {
+	if _, err := NewRenderer("xml", &bytes.Buffer{}); err == nil {
+		t.Fatal("Expected error for unknown output format")
+	}
+}
+
+func TestTableRendererSnapshot(t *testing.T) {
+	var buf bytes.Buffer
+	r, _ := NewRenderer(OutputTable, &buf)
+
+	r.Snapshot("BTC-USD", testEntries())
+
+	out := buf.String()
+	if !strings.Contains(out, "Bid Entries (1)") || !strings.Contains(out, "Trade Entries (1)") {
+		t.Fatalf("Expected grouped bid and trade tables, got:\n%s", out)
+	}
+	if !strings.Contains(out, "49999.00") {
+		t.Fatalf("Expected bid price in output, got:\n%s", out)
+	}
+}
+
+func TestPlainRendererUpdates(t *testing.T) {
+	var buf bytes.Buffer
+	r, _ := NewRenderer(OutputPlain, &buf)
+
+	r.Updates(testEntries())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	if lines[1] != "update BTC-USD Trade: 50000.00 | Size: 0.1 | Aggressor: Buy" {
+		t.Fatalf("Unexpected trade line: %s", lines[1])
+	}
+}
+
+func TestJsonRendererUpdates(t *testing.T) {
+	var buf bytes.Buffer
+	r, _ := NewRenderer(OutputJson, &buf)
+
+	r.Updates(testEntries())
+
+	dec := json.NewDecoder(&buf)
+	count := 0
+	for dec.More() {
+		var obj map[string]interface{}
+		if err := dec.Decode(&obj); err != nil {
+			t.Fatalf("Invalid JSON output: %v", err)
+		}
+		if obj["type"] != "update" || obj["symbol"] != "BTC-USD" {
+			t.Fatalf("Unexpected JSON object: %v", obj)
+		}
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("Expected 2 JSON objects, got %d", count)
+	}
+}
+
+func TestQuietRendererSuppressesData(t *testing.T) {
+	var buf bytes.Buffer
+	r, _ := NewRenderer(OutputQuiet, &buf)
+
+	r.Snapshot("BTC-USD", testEntries())
+	r.Updates(testEntries())
+	r.Info("request sent")
+	if buf.Len() != 0 {
+		t.Fatalf("Expected no output, got %q", buf.String())
+	}
+
+	r.Error(ErrNotConnected)
+	if !strings.Contains(buf.String(), ErrNotConnected.Error()) {
+		t.Fatalf("Expected error to be shown, got %q", buf.String())
+	}
+}
+
+func TestStatusRendering(t *testing.T) {
+	var buf bytes.Buffer
+	r, _ := NewRenderer(OutputTable, &buf)
+
+	store := NewTradeStore(10, "")
+	store.AddSubscription("ETH-USD", "1", "md_2")
+	store.AddSubscription("BTC-USD", "1", "md_1")
+
+	store.AddTrades("BTC-USD", []Trade{{EntryType: "2", Price: "100", Size: "1"}}, true, "md_1") // Snapshot trades do not count
+	store.AddTrades("BTC-USD", []Trade{
+		{EntryType: "2", Price: "101", Size: "0.5"},
+		{EntryType: "0", Price: "90", Size: "9"},
+		{EntryType: "2", Price: "99.5", Size: "1.25"},
+	}, false, "md_1")
+
+	app := createTestFixApp()
+	app.TradeStore = store
+	r.Status(StatusView{SessionId: "FIXT.1.1:A->B", Connected: true, Subscriptions: store.GetSubscriptionsBySymbol(), Markets: app.marketSummaries()})
+
+	out := buf.String()
+	if !strings.Contains(out, "(Connected)") {
+		t.Fatalf("Expected connected state, got:\n%s", out)
+	}
+	if strings.Index(out, "BTC-USD") > strings.Index(out, "ETH-USD") {
+		t.Fatalf("Expected symbols sorted, got:\n%s", out)
+	}
+	if !strings.Contains(out, "│ B 1 T 3                 │") {
+		t.Fatalf("Expected BTC-USD updates by type, got:\n%s", out)
+	}
+	if !strings.Contains(out, "│ 99.5          │ 101           │ 99.5          │ 1.75          │") {
+		t.Fatalf("Expected BTC-USD last, high, low and volume, got:\n%s", out)
+	}
+
+	buf.Reset()
+	r, _ = NewRenderer(OutputPlain, &buf)
+	r.Status(StatusView{Subscriptions: store.GetSubscriptionsBySymbol(), Markets: app.marketSummaries()})
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		btc := strings.HasPrefix(line, "subscription BTC-USD")
+		if btc != strings.Contains(line, "price=99.5 high=101 low=99.5 volume=1.75") || (!btc && strings.Contains(line, "price=")) {
+			t.Fatalf("Unexpected plain status line %q", line)
+		}
+	}
+}
+
+func TestTableRendererGenericTable(t *testing.T) {
+	var buf bytes.Buffer
+	r, _ := NewRenderer(OutputTable, &buf)
+
+	r.Table("", []string{"Symbol", "Last"}, [][]string{{"BTC-USD", "50000.00"}, {"ETH-USD", "3000.5"}})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("Expected 6 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	width := len([]rune(lines[0]))
+	for _, line := range lines {
+		if len([]rune(line)) != width {
+			t.Fatalf("Misaligned row %q", line)
+		}
+	}
+}
@@ -17,42 +17,90 @@
 package fixclient
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 
+	"prime-fix-md-go/builder"
 	"prime-fix-md-go/constants"
 	"prime-fix-md-go/utils"
 
 	"github.com/chzyer/readline"
 )
 
-func Repl(app *FixApp) {
+func Repl(app *FixApp, opts ReplOptions) {
 	// Setup readline with command completion
 	completer := readline.NewPrefixCompleter(
 		readline.PcItem("md",
-			readline.PcItem("BTC-USD",
-				readline.PcItem("--snapshot", readline.PcItem("--trades"), readline.PcItem("--depth")),
-				readline.PcItem("--subscribe", readline.PcItem("--trades"), readline.PcItem("--depth")),
-			),
-			readline.PcItem("ETH-USD",
-				readline.PcItem("--snapshot", readline.PcItem("--trades"), readline.PcItem("--depth")),
-				readline.PcItem("--subscribe", readline.PcItem("--trades"), readline.PcItem("--depth")),
+			readline.PcItem("--all", readline.PcItem("--snapshot", readline.PcItem("--trades")), readline.PcItem("--subscribe", readline.PcItem("--trades"))),
+			readline.PcItemDynamic(app.completionSymbols,
+				readline.PcItem("--snapshot", readline.PcItem("--trades"), readline.PcItem("--depth"), readline.PcItem("--l1"), readline.PcItem("--book"), readline.PcItem("--ohlcv"), readline.PcItem("--all")),
+				readline.PcItem("--subscribe", readline.PcItem("--trades"), readline.PcItem("--depth"), readline.PcItem("--l1"), readline.PcItem("--book"), readline.PcItem("--ohlcv"), readline.PcItem("--all")),
 			),
 		),
-		readline.PcItem("unsubscribe", readline.PcItem("BTC-USD"), readline.PcItem("ETH-USD")),
-		readline.PcItem("status"),
-		readline.PcItem("help"),
-		readline.PcItem("version"),
+		readline.PcItem("unsubscribe", readline.PcItemDynamic(app.completionSymbols)),
+		readline.PcItem("template", readline.PcItem("save"), readline.PcItem("list"), readline.PcItem("delete")),
+		readline.PcItem("watchlist", readline.PcItem("import"), readline.PcItem("list"), readline.PcItem("subscribe")),
+		readline.PcItem("status", readline.PcItem("--watch"), readline.PcItemDynamic(app.completionSymbols, readline.PcItem("--watch"))),
+		readline.PcItem("stats"),
+		readline.PcItem("top"),
+		readline.PcItem("tail", readline.PcItemDynamic(app.completionSymbols)),
+		readline.PcItem("last", readline.PcItemDynamic(app.completionSymbols)),
+		readline.PcItem("candles", readline.PcItemDynamic(app.completionSymbols)),
+		readline.PcItem("book",
+			readline.PcItem("export", readline.PcItemDynamic(app.completionSymbols)),
+			readline.PcItemDynamic(app.completionSymbols, readline.PcItem("--depth"), readline.PcItem("--notional"), readline.PcItem("--tables"))),
+		readline.PcItem("diff", readline.PcItemDynamic(app.completionSymbols,
+			readline.PcItem("--from"), readline.PcItem("--to"), readline.PcItem("--ohlcv"))),
+		readline.PcItem("replay", readline.PcItemDynamic(app.completionSymbols,
+			readline.PcItem("--from"), readline.PcItem("--to"), readline.PcItem("--speed"), readline.PcItem("--sinks"))),
+		readline.PcItem("gaps", readline.PcItemDynamic(app.completionSymbols,
+			readline.PcItem("--since"), readline.PcItem("--from"), readline.PcItem("--to"), readline.PcItem("--silence"), readline.PcItem("--seq-tolerance"))),
+		readline.PcItem("upload"),
+		readline.PcItem("jobs", readline.PcItem("run", readline.PcItemDynamic(app.completionJobs))),
+		readline.PcItem("output",
+			readline.PcItem(OutputTable), readline.PcItem(OutputPlain), readline.PcItem(OutputJson), readline.PcItem(OutputQuiet),
+		),
+		readline.PcItem("resync", readline.PcItemDynamic(app.completionSymbols)),
+		readline.PcItem("raw"),
+		readline.PcItem("preview", readline.PcItem("md"), readline.PcItem("raw")),
+		readline.PcItem("events", readline.PcItem("--since")),
+		readline.PcItem("sessions", readline.PcItem("--label"), readline.PcItem("--limit")),
+		readline.PcItem("logon", readline.PcItem("--reset")),
+		readline.PcItem("seq", readline.PcItem("show"), readline.PcItem("set", readline.PcItem("--in"), readline.PcItem("--out"))),
+		readline.PcItem("quiet", readline.PcItem("on"), readline.PcItem("off")),
+		readline.PcItem("aggregate", readline.PcItem("on"), readline.PcItem("off")),
+		readline.PcItem("clear"),
+		readline.PcItem("reload"),
+		readline.PcItem("help", helpCompletions()...),
+		readline.PcItem("version", readline.PcItem("--json")),
 		readline.PcItem("exit"),
 	)
 
+	historyFile := ""
+	if opts.HistorySize >= 0 {
+		historyFile = historyPath(opts.HistoryFile)
+		if err := prepareHistory(historyFile, opts.HistorySize, opts.HistoryDedup); err != nil {
+			log.Printf("Command history disabled: %v", err)
+			historyFile = ""
+		}
+	}
+
+	// Ctrl-R / Ctrl-S search the history backwards / forwards
+	color := promptColor()
 	rl, err := readline.NewEx(&readline.Config{
-		Prompt:          "FIX-MD> ",
-		HistoryFile:     "/tmp/fixmd_history",
-		AutoComplete:    completer,
-		InterruptPrompt: "^C",
-		EOFPrompt:       "exit",
+		Prompt:            app.prompt(color),
+		HistoryFile:       historyFile,
+		HistoryLimit:      opts.HistorySize,
+		HistorySearchFold: true,
+		AutoComplete:      completer,
+		InterruptPrompt:   "^C",
+		EOFPrompt:         "exit",
 	})
 	if err != nil {
 		log.Printf("Failed to create readline: %v", err)
@@ -60,80 +108,186 @@ func Repl(app *FixApp) {
 	}
 	defer rl.Close()
 
+	// Async output goes through readline so the prompt and the line being typed are redrawn below it
+	app.SetConsole(rl.Stdout(), rl.Stderr())
+	defer app.SetConsole(nil, nil)
+
+	stopPrompt := make(chan struct{})
+	defer close(stopPrompt)
+	var paging atomic.Bool // The pager owns the prompt
+	go app.watchPrompt(rl, color, &paging, stopPrompt)
+
+	// Reads one answer from the user, for the pager and confirmations, without adding it to history
+	readAnswer := func(prompt string) (string, error) {
+		paging.Store(true)
+		defer paging.Store(false)
+		rl.SetPrompt(prompt)
+		rl.HistoryDisable()
+		defer rl.HistoryEnable()
+		return rl.Readline()
+	}
+	app.confirm = func(question string) bool {
+		answer, err := readAnswer(question + " [y/N] ")
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return err == nil && (answer == "y" || answer == "yes")
+	}
+	defer func() { app.confirm = nil }()
+
 	for {
 		if app.ShouldExit() {
 			fmt.Println("Exiting due to authentication failures. Please check your credentials.")
 			return
 		}
 
+		rl.SetPrompt(app.prompt(color))
 		line, err := rl.Readline()
 		if err != nil {
 			break
 		}
 
-		parts := strings.Fields(strings.TrimSpace(line))
-		if len(parts) == 0 {
+		line, pipe, err := splitPipe(line)
+		if err != nil {
+			app.consoleOutput().Error(err)
 			continue
 		}
 
-		cmd := strings.ToLower(parts[0])
-		switch cmd {
-		case "md":
-			app.handleDirectMdRequest(parts)
-		case "unsubscribe":
-			app.handleUnsubscribeRequest(parts)
-		case "status":
-			if !app.handleStatusRequest() {
-				return
-			}
-		case "help":
-			app.displayHelp()
-		case "version":
-			fmt.Println(utils.FullVersion())
-		case "exit":
+		var quit bool
+		if pipe.kind == "" {
+			quit = app.runCommand(app.consoleOutput(), line)
+		} else {
+			quit = app.runPiped(line, pipe, readAnswer)
+		}
+		if quit {
 			return
-		default:
-			fmt.Println("Unknown command. Type 'help' for available commands.")
 		}
 	}
 }
 
+// runCommand runs one REPL command line and reports whether the REPL should end
+func (a *FixApp) runCommand(out output, line string) bool {
+	parts := strings.Fields(strings.TrimSpace(line))
+	if len(parts) == 0 {
+		return false
+	}
+
+	cmd := strings.ToLower(parts[0])
+	switch cmd {
+	case "md":
+		a.handleDirectMdRequest(out, parts)
+	case "unsubscribe":
+		a.handleUnsubscribeRequest(out, parts)
+	case "template":
+		a.handleTemplateRequest(out, parts)
+	case "watchlist":
+		a.handleWatchlistRequest(out, parts)
+	case "status":
+		if !a.handleStatusRequest(out, parts) {
+			return true
+		}
+	case "stats":
+		a.handleStatsRequest(out, parts)
+	case "top":
+		a.handleTopRequest(out)
+	case "tail":
+		a.handleTailRequest(out, parts)
+	case "last":
+		a.handleLastRequest(out, parts)
+	case "candles":
+		a.handleCandlesRequest(out, parts)
+	case "book":
+		a.handleBookRequest(out, parts)
+	case "diff":
+		a.handleDiffRequest(out, parts)
+	case "replay":
+		a.handleReplayRequest(out, parts)
+	case "gaps":
+		a.handleGapsRequest(out, parts)
+	case "upload":
+		a.handleUploadRequest(out, parts)
+	case "jobs":
+		a.handleJobsRequest(out, parts)
+	case "output":
+		a.handleOutputRequest(out, parts)
+	case "resync":
+		a.handleResyncRequest(out, parts)
+	case "raw":
+		a.handleRawRequest(out, commandArgs(line), false)
+	case "preview":
+		a.handlePreviewRequest(out, line, parts)
+	case "events":
+		a.handleEventsRequest(out, parts)
+	case "sessions":
+		a.handleSessionsRequest(out, parts)
+	case "seq":
+		a.handleSeqRequest(out, parts)
+	case "logon":
+		a.handleLogonRequest(out, parts)
+	case "aggregate":
+		a.handleAggregateRequest(out, parts)
+	case "quiet":
+		a.handleQuietRequest(out, parts)
+	case "clear":
+		a.handleClearRequest(out)
+	case "reload":
+		a.handleReloadRequest(out)
+	case "help":
+		if len(parts) > 1 {
+			printCommandHelp(out.Console(), strings.Join(parts[1:], " "))
+		} else {
+			a.displayHelp(out)
+		}
+	case "version":
+		a.handleVersionRequest(out, parts)
+	case "exit":
+		return true
+	default:
+		fmt.Fprintln(out.Console(), "Unknown command. Type 'help' for available commands.")
+	}
+	return false
+}
+
 type MdRequestFlags struct {
 	subscriptionType string
 	marketDepth      string
 	entryTypes       []string
+	dryRun           bool // Print the request instead of sending it
+	force            bool // Skip the product catalog symbol check
+	securityIds      []string
+	securityIdSource string
+	options          builder.MdRequestOptions
+	topOfBook        bool   // --l1 was given
+	label            string // Stored with the request's session rows, e.g. fomc-day
+}
+
+// Symbols offered when the product catalog has not been loaded
+var defaultCompletionSymbols = []string{"BTC-USD", "ETH-USD"}
+
+// completionSymbols feeds tab completion from the product catalog, read on every keypress
+// so a catalog loaded after startup is picked up
+func (a *FixApp) completionSymbols(string) []string {
+	if a.Products.Len() == 0 {
+		return defaultCompletionSymbols
+	}
+	return a.Products.Symbols()
 }
 
-func (a *FixApp) handleDirectMdRequest(parts []string) {
+func (a *FixApp) handleDirectMdRequest(out output, parts []string) {
 	if len(parts) < 2 {
-		fmt.Print(`Usage: md <symbol1> [symbol2 symbol3 ...] [flags...]
-
-Subscription Flags:
-  --snapshot              - Snapshot only
-  --subscribe             - Snapshot + live updates
-  --unsubscribe           - Stop updates
-
-Depth Flag:
-  --depth N               - Market depth (0=full, 1=top, N=best N levels)
-                            Automatically includes both bids and offers
-
-Entry Type Flags:
-  --trades                - Executed trades
-  --o                     - Opening price
-  --c                     - Closing price
-  --h                     - High price
-  --l                     - Low price
-  --v                     - Trading volume
-
-Examples:
-  md BTC-USD --snapshot --trades
-  md BTC-USD ETH-USD --snapshot --depth 1
-  md BTC-USD ETH-USD SOL-USD --subscribe --depth 10
-  md ETH-USD --snapshot --o --c --h --l --v
-  md BTC-USD --unsubscribe
-`)
+		printCommandHelp(out.Console(), "md")
 		return
 	}
+	if err := a.mdRequest(out, parts); err != nil {
+		out.Error(err)
+	}
+}
+
+// mdRequest sends the request of an md command line; parts[0] is "md". Progress and warnings
+// are printed, failures returned.
+func (a *FixApp) mdRequest(out output, parts []string) error {
+	parts, err := a.expandMdTemplates(parts)
+	if err != nil {
+		return err
+	}
 
 	// Parse symbols and flags
 	var symbols []string
@@ -159,20 +313,50 @@ Examples:
 		flagArgs = parts[flagStart:]
 	}
 
-	flags := a.parseMdFlags(flagArgs)
+	allProducts, flagArgs := isAllProductsRequest(symbols, flagArgs)
+
+	flags, err := a.parseMdFlags(flagArgs)
+	if err != nil {
+		return err
+	}
+
+	a.applyMdDefaults(&flags)
 
 	// Validate we have a subscription type
 	if flags.subscriptionType == "" {
-		fmt.Println("Error: Must specify subscription type (--snapshot, --subscribe, or --unsubscribe)")
-		return
+		return invalidRequest("must specify subscription type (--snapshot, --subscribe, or --unsubscribe) or set md.subscriptionType in the config")
+	}
+
+	if allProducts {
+		if symbols, err = a.allProductSymbols(flags.subscriptionType); err != nil {
+			return err
+		}
+	}
+
+	instruments := append(builder.SymbolInstruments(symbols),
+		builder.SecurityIdInstruments(flags.securityIds, flags.securityIdSource)...)
+	if len(instruments) == 0 {
+		return invalidRequest("at least one symbol or --security-id is required")
 	}
 
 	// For unsubscribe, we don't need depth or entry types
 	if flags.subscriptionType == constants.SubscriptionRequestTypeUnsubscribe {
-		for _, symbol := range symbols {
-			a.sendUnsubscribeBySymbol(symbol)
+		var errs []error
+		for i, symbol := range instrumentKeys(instruments) {
+			if allProducts && !flags.dryRun && i > 0 && i%allProductsBatchSize == 0 && !a.waitBetweenBatches() {
+				break
+			}
+			var err error
+			if flags.dryRun {
+				err = a.previewUnsubscribeBySymbol(out, symbol)
+			} else {
+				err = a.sendUnsubscribeBySymbol(out, symbol)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
 		}
-		return
+		return errors.Join(errs...)
 	}
 
 	// Default depth to full if not specified
@@ -191,16 +375,40 @@ Examples:
 		}
 	}
 
+	flags.entryTypes = dedupeEntryTypes(flags.entryTypes)
+	warnings, err := validateMdRequest(instrumentKeys(instruments), flags.subscriptionType, flags.marketDepth, flags.entryTypes)
+	if err == nil && !flags.force {
+		err = a.validateSymbols(symbols)
+	}
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		out.Info("Warning: %s", warning)
+	}
+
 	// Determine description
 	description := "Snapshot"
 	if flags.subscriptionType == constants.SubscriptionRequestTypeSubscribe {
 		description = "Live Subscription"
 	}
 
-	a.sendMarketDataRequestWithOptions(symbols, flags.subscriptionType, flags.marketDepth, flags.entryTypes, description)
+	if allProducts {
+		a.sendAllProducts(out, instruments, flags, description)
+		return nil
+	}
+
+	if flags.dryRun {
+		a.previewMarketDataRequest(out, instruments, flags.subscriptionType, flags.marketDepth, flags.entryTypes, flags.options)
+		return nil
+	}
+
+	_, err = a.sendMarketDataRequestWithOptions(out, instruments, flags.subscriptionType, flags.marketDepth, flags.entryTypes,
+		flags.options, flags.label, description)
+	return err
 }
 
-func (a *FixApp) parseMdFlags(args []string) MdRequestFlags {
+func (a *FixApp) parseMdFlags(args []string) (MdRequestFlags, error) {
 	flags := MdRequestFlags{
 		entryTypes: []string{},
 	}
@@ -216,16 +424,52 @@ func (a *FixApp) parseMdFlags(args []string) MdRequestFlags {
 			flags.subscriptionType = constants.SubscriptionRequestTypeSubscribe
 		case "--unsubscribe":
 			flags.subscriptionType = constants.SubscriptionRequestTypeUnsubscribe
+		case "--dry-run":
+			flags.dryRun = true
+		case "--force":
+			flags.force = true
+		case "--full-refresh":
+			flags.options.FullRefresh = true
+		case "--aggregated":
+			flags.options.AggregatedBook = constants.AggregatedBookYes
+		case "--unaggregated":
+			flags.options.AggregatedBook = constants.AggregatedBookNo
 
 		// Depth flag (requires next argument)
 		case "--depth":
-			if i+1 < len(args) {
-				i++
-				flags.marketDepth = args[i]
+			if i+1 >= len(args) {
+				return flags, invalidRequest("--depth requires a value")
+			}
+			i++
+			flags.marketDepth = args[i]
+
+		case "--label":
+			if i+1 >= len(args) {
+				return flags, invalidRequest("--label requires a value")
+			}
+			i++
+			if !labelPattern.MatchString(args[i]) {
+				return flags, invalidRequest("label %q may only contain letters, digits, '.', ':', '-' and '_' (up to 64)", args[i])
+			}
+			flags.label = args[i]
+
+		case "--security-id", "--id-source":
+			if i+1 >= len(args) {
+				return flags, invalidRequest("%s requires a value", arg)
+			}
+			i++
+			if arg == "--security-id" {
+				flags.securityIds = append(flags.securityIds, args[i])
+			} else {
+				flags.securityIdSource = args[i]
 			}
 
 		case "--trades":
 			flags.entryTypes = append(flags.entryTypes, constants.MdEntryTypeTrade)
+		case "--bids":
+			flags.entryTypes = append(flags.entryTypes, constants.MdEntryTypeBid)
+		case "--offers":
+			flags.entryTypes = append(flags.entryTypes, constants.MdEntryTypeOffer)
 		case "--o":
 			flags.entryTypes = append(flags.entryTypes, constants.MdEntryTypeOpen)
 		case "--c":
@@ -236,96 +480,277 @@ func (a *FixApp) parseMdFlags(args []string) MdRequestFlags {
 			flags.entryTypes = append(flags.entryTypes, constants.MdEntryTypeLow)
 		case "--v":
 			flags.entryTypes = append(flags.entryTypes, constants.MdEntryTypeVolume)
+		case "--l1", "--book", "--ohlcv", "--all":
+			flags.entryTypes = append(flags.entryTypes, mdEntryPresets[arg]...)
+			flags.topOfBook = flags.topOfBook || arg == "--l1"
+
+		default:
+			return flags, invalidRequest("unknown flag %q", arg)
+		}
+	}
+
+	if flags.topOfBook {
+		if flags.marketDepth == "" {
+			flags.marketDepth = "1"
+		} else if flags.marketDepth != "1" {
+			return flags, invalidRequest("--l1 is top of book and cannot be combined with --depth %s", flags.marketDepth)
 		}
 	}
 
-	return flags
+	return flags, nil
+}
+
+// SetMdDefaults sets what md fills in when a request leaves out its subscription type, depth or
+// entry types. Entry types are md flag names without "--", e.g. "trades", "l1" or "ohlcv".
+func (a *FixApp) SetMdDefaults(subscriptionType string, depth *int, entryTypes []string) error {
+	var args []string
+	if subscriptionType != "" {
+		args = append(args, "--"+subscriptionType)
+	}
+	if depth != nil {
+		args = append(args, "--depth", strconv.Itoa(*depth))
+	}
+	for _, entryType := range entryTypes {
+		args = append(args, "--"+strings.TrimPrefix(entryType, "--"))
+	}
+
+	defaults, err := a.parseMdFlags(args)
+	if err != nil {
+		return fmt.Errorf("invalid md defaults: %w", err)
+	}
+	if defaults.subscriptionType == constants.SubscriptionRequestTypeUnsubscribe {
+		return fmt.Errorf("invalid md defaults: subscription type must be snapshot or subscribe")
+	}
+	if defaults.dryRun || defaults.force || len(defaults.securityIds) > 0 || defaults.options != (builder.MdRequestOptions{}) || defaults.label != "" {
+		return fmt.Errorf("invalid md defaults: only subscription type, depth and entry types can be defaulted")
+	}
+
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	a.mdDefaults = defaults
+	return nil
+}
+
+func (a *FixApp) applyMdDefaults(flags *MdRequestFlags) {
+	a.settingsMu.RLock()
+	defaults := a.mdDefaults
+	a.settingsMu.RUnlock()
+	if flags.subscriptionType == "" {
+		flags.subscriptionType = defaults.subscriptionType
+	}
+	if flags.marketDepth == "" {
+		flags.marketDepth = defaults.marketDepth
+	}
+	if len(flags.entryTypes) == 0 {
+		flags.entryTypes = defaults.entryTypes
+	}
 }
 
-func (a *FixApp) handleUnsubscribeRequest(parts []string) {
+func (a *FixApp) handleUnsubscribeRequest(out output, parts []string) {
 	if len(parts) < 2 {
-		fmt.Print(`Usage: unsubscribe <symbol|reqId>
-Examples: 
-  unsubscribe BTC-USD           - Cancel ALL BTC-USD subscriptions
-  unsubscribe md_1234567890     - Cancel specific subscription by reqId
-  unsubscribe --reqid md_123    - Cancel specific subscription (explicit)
-`)
+		printCommandHelp(out.Console(), "unsubscribe")
 		return
 	}
 
+	var err error
+
 	// Handle --reqid flag for explicit reqId targeting
 	if len(parts) >= 3 && parts[1] == "--reqid" {
-		a.sendUnsubscribeByReqId(parts[2])
-		return
+		err = a.sendUnsubscribeByReqId(out, parts[2])
+	} else if input := parts[1]; strings.HasPrefix(input, "md_") {
+		// Auto-detect: if input looks like reqId, treat as reqId; otherwise as symbol
+		err = a.sendUnsubscribeByReqId(out, input)
+	} else {
+		err = a.sendUnsubscribeBySymbol(out, strings.ToUpper(input))
 	}
 
-	input := parts[1]
-
-	// Auto-detect: if input looks like reqId, treat as reqId; otherwise as symbol
-	if strings.HasPrefix(input, "md_") {
-		a.sendUnsubscribeByReqId(input)
-	} else {
-		symbol := strings.ToUpper(input)
-		a.sendUnsubscribeBySymbol(symbol)
+	if err != nil {
+		out.Error(err)
 	}
 }
 
-func (a *FixApp) handleStatusRequest() bool {
+func (a *FixApp) handleStatusRequest(out output, parts []string) bool {
 	if a.ShouldExit() {
-		fmt.Println("Exiting due to authentication failures. Please check your credentials.")
+		fmt.Fprintln(out.Console(), "Exiting due to authentication failures. Please check your credentials.")
 		return false
 	}
 
-	fmt.Printf("Session: %s ", a.SessionId)
-	if a.SessionId.String() != "" {
-		fmt.Println("(Connected)")
-	} else {
-		fmt.Println("(Disconnected)")
+	symbol, every, err := parseStatusArgs(parts[1:])
+	if err != nil {
+		out.Error(err)
+		return true
 	}
-
-	subscriptionsBySymbol := a.TradeStore.GetSubscriptionsBySymbol()
-	if len(subscriptionsBySymbol) == 0 {
-		fmt.Println("No active subscriptions")
+	if every > 0 {
+		a.watchStatus(out, symbol, every)
+		return !a.ShouldExit()
+	}
+	if symbol != "" {
+		a.symbolStatus(out, symbol)
 		return true
 	}
+	out.Status(a.statusView())
+	return true
+}
 
-	fmt.Print(`
-Active Subscriptions:
-┌─────────────┬──────────────────┬─────────────┬─────────────┬──────────────┬──────────────────┐
-│ Symbol      │ Type             │ Status      │ Updates     │ Last Update  │ ReqId            │
-├─────────────┼──────────────────┼─────────────┼─────────────┼──────────────┼──────────────────┤
-`)
+func (a *FixApp) statusView() StatusView {
+	status := StatusView{
+		SessionId:     a.SessionId.String(),
+		Connected:     a.IsConnected(),
+		Subscriptions: a.TradeStore.GetSubscriptionsBySymbol(),
+		Markets:       a.marketSummaries(),
+	}
+	if a.AdminCounters != nil {
+		admin := a.AdminCounters.Stats()
+		status.Admin = &admin
+	}
+	if a.Clock != nil {
+		clock := a.Clock.Stats()
+		status.Clock = &clock
+	}
+	return status
+}
 
-	for symbol, subs := range subscriptionsBySymbol {
-		for i, sub := range subs {
-			status := "Active"
-			if !sub.Active {
-				status = "Inactive"
-			}
+// marketSummaries formats the session stats of each symbol that has traded for status
+func (a *FixApp) marketSummaries() map[string]MarketSummary {
+	markets := make(map[string]MarketSummary)
+	for symbol, stats := range a.TradeStore.SessionStats() {
+		if stats.Count == 0 {
+			continue
+		}
+		markets[symbol] = MarketSummary{
+			High:   a.formatPrice(symbol, stats.High.String()),
+			Low:    a.formatPrice(symbol, stats.Low.String()),
+			Last:   a.formatPrice(symbol, stats.Last.String()),
+			Volume: a.formatSize(symbol, stats.Volume.String()),
+		}
+	}
+	return markets
+}
 
-			lastUpdate := "Never"
-			if !sub.LastUpdate.IsZero() {
-				lastUpdate = sub.LastUpdate.Format("15:04:05")
-			}
+func (a *FixApp) handleVersionRequest(out output, parts []string) {
+	if len(parts) > 1 && parts[1] == "--json" {
+		enc := json.NewEncoder(out.Console())
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(utils.GetBuildInfo()); err != nil {
+			out.Error(err)
+		}
+		return
+	}
+	fmt.Fprint(out.Console(), utils.VersionDetails())
+}
 
-			// Show symbol only on first line for multiple subscriptions
-			displaySymbol := symbol
-			if i > 0 {
-				displaySymbol = ""
-			}
+func (a *FixApp) handleStatsRequest(out output, parts []string) {
+	statsBySymbol := a.TradeStore.GetTradeStats()
 
-			// Truncate reqId for display
-			shortReqId := sub.MdReqId
-			if len(shortReqId) > 16 {
-				shortReqId = "..." + shortReqId[len(shortReqId)-13:]
-			}
+	var symbols []string
+	if len(parts) > 1 {
+		for _, symbol := range parts[1:] {
+			symbols = append(symbols, strings.ToUpper(symbol))
+		}
+	} else {
+		for symbol := range statsBySymbol {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+	}
 
-			fmt.Printf("│ %-11s │ %-16s │ %-11s │ %-11d │ %-12s │ %-16s │\n",
-				displaySymbol, a.getSubscriptionTypeDesc(sub.SubscriptionType), status, sub.TotalUpdates, lastUpdate, shortReqId)
+	var rows [][]string
+	for _, symbol := range symbols {
+		stats, ok := statsBySymbol[symbol]
+		if !ok || stats.Count == 0 {
+			continue
+		}
+		if product, ok := a.Products.Get(symbol); ok {
+			stats.UseIncrements(product.QuoteIncrement, product.BaseIncrement)
 		}
+		rows = append(rows, []string{
+			symbol,
+			strconv.FormatInt(stats.Count, 10),
+			stats.FormatSize(stats.Volume),
+			stats.FormatNotional(stats.Notional),
+			stats.FormatPrice(stats.Vwap()),
+			stats.FormatPrice(stats.Low),
+			stats.FormatPrice(stats.High),
+			stats.FormatPrice(stats.Last),
+		})
 	}
 
-	fmt.Println("└─────────────┴──────────────────┴─────────────┴─────────────┴──────────────┴──────────────────┘")
+	if len(rows) == 0 {
+		out.Info("No trades received yet")
+	} else {
+		out.Table("Trade Statistics (in-memory trades):",
+			[]string{"Symbol", "Trades", "Volume", "Notional", "VWAP", "Low", "High", "Last"}, rows)
+	}
+	a.displayWriterStats(out)
+	a.displayPipelineStats(out)
+	a.displayValidationStats(out)
+	a.displayMessageCounts(out)
+	a.displayMemoryStats(out)
+}
 
-	return true
+func (a *FixApp) handleResyncRequest(out output, parts []string) {
+	if len(parts) < 2 {
+		fmt.Fprintln(out.Console(), "Usage: resync <symbol> [symbol...]  - Rebuild the in-memory book from a fresh snapshot")
+		return
+	}
+	for _, symbol := range parts[1:] {
+		if _, err := a.resyncBook(out, strings.ToUpper(symbol)); err != nil {
+			out.Error(err)
+		}
+	}
+}
+
+func (a *FixApp) handleOutputRequest(out output, parts []string) {
+	if len(parts) < 2 {
+		fmt.Fprintln(out.Console(), "Usage: output <table|plain|json|quiet>")
+		return
+	}
+
+	if err := a.SetOutputFormat(strings.ToLower(parts[1])); err != nil {
+		out.Error(err)
+		return
+	}
+	out.Info("Output format set to %s", strings.ToLower(parts[1]))
+}
+
+// commandArgs returns everything after the command word, with internal spacing preserved
+func commandArgs(line string) string {
+	line = strings.TrimSpace(line)
+	if i := strings.IndexAny(line, " \t"); i != -1 {
+		return strings.TrimSpace(line[i:])
+	}
+	return ""
+}
+
+func (a *FixApp) handlePreviewRequest(out output, line string, parts []string) {
+	if len(parts) < 2 {
+		printCommandHelp(out.Console(), "preview")
+		return
+	}
+
+	switch strings.ToLower(parts[1]) {
+	case "md":
+		a.handleDirectMdRequest(out, append(parts[1:], "--dry-run"))
+	case "raw":
+		a.handleRawRequest(out, commandArgs(commandArgs(line)), true)
+	default:
+		out.Error(fmt.Errorf("preview supports md and raw, not %q", parts[1]))
+	}
+}
+
+func (a *FixApp) handleRawRequest(out output, raw string, dryRun bool) {
+	if raw == "" {
+		printCommandHelp(out.Console(), "raw")
+		return
+	}
+
+	var err error
+	if dryRun {
+		err = a.previewRawMessage(out, raw)
+	} else {
+		err = a.sendRawMessage(out, raw)
+	}
+	if err != nil {
+		out.Error(err)
+	}
 }
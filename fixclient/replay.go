@@ -0,0 +1,304 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/database"
+	"prime-fix-md-go/formatter"
+)
+
+// Quiet stretches in a capture are shortened to this much wall time, whatever the speed
+const replayMaxGap = 5 * time.Second
+
+type replayQuery struct {
+	symbol string
+	from   time.Time
+	to     time.Time // Zero replays up to now
+	speed  float64   // Multiple of real time; 0 replays as fast as possible
+	sinks  bool      // Also write the replayed entries to the Arrow streams
+}
+
+// parseReplayTime accepts a clock time such as 09:30 or 09:30:15 (today, in the display zone)
+// as well as RFC 3339 and YYYY-MM-DD
+func parseReplayTime(value string, now time.Time) (time.Time, error) {
+	loc := zone()
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			y, m, d := now.In(loc).Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+		}
+	}
+	return parseExportTime(value)
+}
+
+func parseReplaySpeed(value string) (float64, error) {
+	if strings.EqualFold(value, "max") {
+		return 0, nil
+	}
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(value), "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("invalid --speed %q (e.g. 1x, 10x, 0.5x or max)", value)
+	}
+	return speed, nil
+}
+
+func parseReplayQuery(args []string, now time.Time) (replayQuery, error) {
+	if len(args) < 1 {
+		return replayQuery{}, errors.New("usage: replay <symbol> --from TIME [--to TIME] [--speed 10x|max] [--sinks]")
+	}
+
+	q := replayQuery{symbol: strings.ToUpper(args[0]), speed: 1}
+	for i := 1; i < len(args); i++ {
+		if args[i] == "--sinks" {
+			q.sinks = true
+			continue
+		}
+		if i+1 >= len(args) {
+			return q, fmt.Errorf("%s requires a value", args[i])
+		}
+		var err error
+		switch args[i] {
+		case "--from", "--to":
+			var at time.Time
+			if at, err = parseReplayTime(args[i+1], now); err != nil {
+				return q, fmt.Errorf("invalid %s %q (HH:MM, RFC 3339 or YYYY-MM-DD)", args[i], args[i+1])
+			}
+			if args[i] == "--from" {
+				q.from = at
+			} else {
+				q.to = at
+			}
+		case "--speed":
+			if q.speed, err = parseReplaySpeed(args[i+1]); err != nil {
+				return q, err
+			}
+		default:
+			return q, fmt.Errorf("unknown flag %q", args[i])
+		}
+		i++
+	}
+	if q.from.IsZero() {
+		return q, errors.New("--from is required")
+	}
+	if !q.to.IsZero() && !q.to.After(q.from) {
+		return q, errors.New("--to must be after --from")
+	}
+	return q, nil
+}
+
+// replayEvent is the entries of one stored message, replayed together
+type replayEvent struct {
+	at       time.Time // When the message was received
+	seqNum   int
+	snapshot bool
+	entries  []Trade
+}
+
+// loadReplay reads the stored trades and book updates for the query window, merged into
+// message order by receive time. Snapshot rows are included when snapshots is set.
+func (a *FixApp) loadReplay(q replayQuery, snapshots bool) ([]replayEvent, error) {
+	if a.Db == nil {
+		return nil, fmt.Errorf("%w: no database to replay from", ErrStorage)
+	}
+	r := database.TimeRange{From: q.from, To: q.to}
+
+	trades, err := a.Db.QueryTrades(q.symbol, r, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	queryBook := a.Db.QueryOrderBookUpdates
+	if snapshots {
+		queryBook = a.Db.QueryOrderBook
+	}
+	updates, err := queryBook(q.symbol, r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+
+	// Entries of one message share its MsgSeqNum and request id
+	var events []replayEvent
+	messages := make(map[string]int)
+	add := func(at time.Time, seqNum int, entry Trade) {
+		key := fmt.Sprintf("%s/%d/%t", entry.MdReqId, seqNum, entry.IsSnapshot)
+		if i, ok := messages[key]; ok && seqNum != 0 {
+			events[i].entries = append(events[i].entries, entry)
+			if at.Before(events[i].at) {
+				events[i].at = at
+			}
+			return
+		}
+		messages[key] = len(events)
+		events = append(events, replayEvent{at: at, seqNum: seqNum, snapshot: entry.IsSnapshot, entries: []Trade{entry}})
+	}
+	for _, row := range trades {
+		if row.IsSnapshot && !snapshots {
+			continue
+		}
+		add(row.ReceivedAt, row.SeqNum, replayedTrade(row))
+	}
+	for _, row := range updates {
+		entry := storedBookEntry(row)
+		entry.Price, entry.Size = normalized(entry.Price), normalized(entry.Size)
+		if row.Position > 0 {
+			entry.Position = strconv.Itoa(row.Position)
+		}
+		entry.MdReqId, entry.SeqNum = row.MdReqId, strconv.Itoa(row.SeqNum)
+		entry.IsSnapshot, entry.IsUpdate = row.IsSnapshot, !row.IsSnapshot
+		add(row.ReceivedAt, row.SeqNum, entry)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+	return events, nil
+}
+
+func replayedTrade(row database.TradeRow) Trade {
+	return Trade{
+		Symbol:           row.Symbol,
+		Price:            normalized(row.Price),
+		Size:             normalized(row.Size),
+		EntryTime:        row.TradeTime,
+		Aggressor:        row.AggressorSide,
+		MdReqId:          row.MdReqId,
+		IsSnapshot:       row.IsSnapshot,
+		IsUpdate:         !row.IsSnapshot,
+		EntryType:        constants.MdEntryTypeTrade,
+		TradeCondition:   row.TradeCondition,
+		SecurityId:       row.SecurityId,
+		SecurityIdSource: row.SecurityIdSource,
+		SeqNum:           strconv.Itoa(row.SeqNum),
+
+		AggressorInferred: row.AggressorInferred,
+	}
+}
+
+// replayDelay is the wall time to wait before an event, given the recorded gap since the last one
+func replayDelay(gap time.Duration, speed float64) time.Duration {
+	if speed == 0 || gap <= 0 {
+		return 0
+	}
+	return min(time.Duration(float64(gap)/speed), replayMaxGap)
+}
+
+// runReplay feeds events to out at speed and returns how many were shown before stop closed
+func (a *FixApp) runReplay(events []replayEvent, q replayQuery, out Renderer, stop <-chan struct{}) int {
+	for i, ev := range events {
+		if i > 0 {
+			timer := time.NewTimer(replayDelay(ev.at.Sub(events[i-1].at), q.speed))
+			select {
+			case <-stop:
+				timer.Stop()
+				return i
+			case <-timer.C:
+			}
+		}
+		out.Updates(a.displayTrades(ev.entries))
+		if q.sinks {
+			if err := a.Arrow.Write(ev.entries, strconv.Itoa(ev.seqNum), ev.snapshot, ev.at); err != nil {
+				out.Error(err)
+			}
+		}
+	}
+	return len(events)
+}
+
+// mutedRenderer drops everything; live output is held back while a replay is on screen
+type mutedRenderer struct{}
+
+func (mutedRenderer) MarketDataReceived(msgType, symbol, mdReqId, noMdEntries, seqNum string) {}
+func (mutedRenderer) Snapshot(symbol string, entries []Trade)                                 {}
+func (mutedRenderer) Updates(entries []Trade)                                                 {}
+func (mutedRenderer) Reject(rej *ErrRejected, hint string)                                    {}
+func (mutedRenderer) Status(status StatusView)                                                {}
+func (mutedRenderer) Table(title string, headers []string, rows [][]string)                   {}
+func (mutedRenderer) Info(format string, args ...interface{})                                 {}
+func (mutedRenderer) Error(err error)                                                         {}
+
+func (a *FixApp) handleReplayRequest(out output, parts []string) {
+	q, err := parseReplayQuery(parts[1:], time.Now())
+	if err != nil {
+		out.Error(err)
+		return
+	}
+	events, err := a.loadReplay(q, false)
+	if err != nil {
+		out.Error(err)
+		return
+	}
+	if len(events) == 0 {
+		out.Info("No stored updates for %s in that window", q.symbol)
+		return
+	}
+
+	speed := "as fast as possible"
+	if q.speed > 0 {
+		speed = strconv.FormatFloat(q.speed, 'f', -1, 64) + "x"
+	}
+	out.Info("Replaying %d stored messages for %s from %s, %s; press Ctrl-C to stop",
+		len(events), q.symbol, displayTime(events[0].at, diffTimeFormat), speed)
+	if book, err := a.reconstructBook(q.symbol, q.from); err == nil {
+		out.Snapshot(q.symbol, a.displayTrades(bookEntries(book)))
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	stop, done := make(chan struct{}), make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-interrupt:
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	// Live data keeps being stored but is not printed while the replay runs
+	live, logOutput := a.swapRenderer(mutedRenderer{}), log.Writer()
+	log.SetOutput(io.Discard)
+	formatter.SetMuted(true)
+	shown := a.runReplay(events, q, out, stop)
+	formatter.SetMuted(false)
+	log.SetOutput(logOutput)
+	a.swapRenderer(live)
+
+	out.Info("Replay of %s finished: %d of %d messages shown", q.symbol, shown, len(events))
+}
+
+// bookEntries lists a book's levels as bid and offer entries, best first
+func bookEntries(book *OrderBook) []Trade {
+	var entries []Trade
+	for _, side := range []struct {
+		entryType string
+		levels    []BookLevel
+	}{{constants.MdEntryTypeBid, book.Bids()}, {constants.MdEntryTypeOffer, book.Offers()}} {
+		for i, level := range side.levels {
+			entries = append(entries, Trade{Symbol: book.Symbol, EntryType: side.entryType, Price: normalized(level.Price),
+				Size: normalized(level.Size), NumOrders: level.NumOrders, Position: strconv.Itoa(i + 1)})
+		}
+	}
+	return entries
+}
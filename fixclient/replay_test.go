@@ -0,0 +1,101 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+func TestParseReplayQuery(t *testing.T) {
+	now := time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC)
+	q, err := parseReplayQuery([]string{"btc-usd", "--from", "09:30", "--to", "09:35", "--speed", "10x", "--sinks"}, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if q.symbol != "BTC-USD" || !q.from.Equal(time.Date(2025, 3, 4, 9, 30, 0, 0, time.UTC)) ||
+		q.to.Sub(q.from) != 5*time.Minute || q.speed != 10 || !q.sinks {
+		t.Fatalf("Unexpected query %+v", q)
+	}
+
+	if q, err := parseReplayQuery([]string{"BTC-USD", "--from", "2025-01-01", "--speed", "max"}, now); err != nil || q.speed != 0 {
+		t.Fatalf("Expected max speed, got %+v (%v)", q, err)
+	}
+	for _, args := range [][]string{
+		{"BTC-USD"},
+		{"BTC-USD", "--from", "09:35", "--to", "09:30"},
+		{"BTC-USD", "--from", "09:30", "--speed", "-2x"},
+	} {
+		if _, err := parseReplayQuery(args, now); err == nil {
+			t.Fatalf("Expected an error for %v", args)
+		}
+	}
+
+	if d := replayDelay(time.Minute, 2); d != replayMaxGap {
+		t.Fatalf("Expected long gaps to be capped, got %v", d)
+	}
+	if d := replayDelay(time.Second, 10); d != 100*time.Millisecond {
+		t.Fatalf("Expected a 100ms delay at 10x, got %v", d)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "replay.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	from := time.Now().Add(-time.Minute)
+	tradeTime := time.Now().UTC().Format("20060102-15:04:05.000")
+	db.StoreOrderBookRecord(database.OrderBookRecord{Symbol: "BTC-USD", Side: "bid", Price: "100", Size: "1", Position: 1, SeqNum: 2, MdReqId: "md_1", UpdateAction: "0"})
+	db.StoreTradeRecord(database.TradeRecord{Symbol: "BTC-USD", Price: "100.5", Size: "0.2", AggressorSide: "Buy", TradeTime: tradeTime, SeqNum: 3, MdReqId: "md_1"})
+	db.StoreTradeRecord(database.TradeRecord{Symbol: "BTC-USD", Price: "100.6", Size: "0.1", AggressorSide: "Sell", TradeTime: tradeTime, SeqNum: 3, MdReqId: "md_1"})
+	db.StoreTradeRecord(database.TradeRecord{Symbol: "ETH-USD", Price: "2000", Size: "1", TradeTime: tradeTime, SeqNum: 4, MdReqId: "md_2"})
+
+	app := createTestFixApp()
+	app.Db = db
+	q := replayQuery{symbol: "BTC-USD", from: from}
+	events, err := app.loadReplay(q, false)
+	if err != nil {
+		t.Fatalf("Failed to load replay: %v", err)
+	}
+	if len(events) != 2 || len(events[0].entries) != 1 || len(events[1].entries) != 2 {
+		t.Fatalf("Expected the book update, then both trades of message 3, got %+v", events)
+	}
+
+	var out bytes.Buffer
+	renderer, _ := NewRenderer(OutputPlain, &out)
+	if shown := app.runReplay(events, q, renderer, make(chan struct{})); shown != 2 {
+		t.Fatalf("Expected 2 messages shown, got %d", shown)
+	}
+	want := "update BTC-USD Bid: 100 | Size: 1 | Pos: 1 | New\nupdate BTC-USD Trade: 100.5 | Size: 0.2 | Notional: 20.1 | Aggressor: Buy\n"
+	if !strings.HasPrefix(out.String(), want) {
+		t.Fatalf("Unexpected replay output:\n%s", out.String())
+	}
+
+	// A closed stop channel ends the replay before the next delay
+	stop := make(chan struct{})
+	close(stop)
+	if shown := app.runReplay(events, replayQuery{speed: 1}, renderer, stop); shown != 1 {
+		t.Fatalf("Expected the replay to stop after the first message, got %d", shown)
+	}
+}
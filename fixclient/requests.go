@@ -17,17 +17,39 @@
 package fixclient
 
 import (
+	"container/list"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"prime-fix-md-go/builder"
 	"prime-fix-md-go/constants"
+	"prime-fix-md-go/database"
 
 	"github.com/quickfixgo/quickfix"
 )
 
-func (a *FixApp) sendUnsubscribeBySymbol(symbol string) {
+// Responses are only waited for by piped and attached commands, for up to pipeResponseTimeout
+// each, so a request tracked this long ago is one nobody is going to wait for
+const (
+	maxPendingRequests = 1000
+	pendingRequestTTL  = 5 * time.Minute
+)
+
+// pendingRequest is a request tracked until its first response or reject is waited for
+type pendingRequest struct {
+	result  chan error
+	tracked time.Time
+	order   *list.Element // In pendingOrder
+}
+
+func (a *FixApp) sendUnsubscribeBySymbol(out output, symbol string) error {
+	if !a.IsConnected() {
+		return ErrNotConnected
+	}
+
 	subscriptions := a.TradeStore.GetSubscriptionStatus()
 
 	var symbolSubs []*Subscription
@@ -38,109 +60,258 @@ func (a *FixApp) sendUnsubscribeBySymbol(symbol string) {
 	}
 
 	if len(symbolSubs) == 0 {
-		fmt.Printf("No active subscriptions found for %s\n", symbol)
-		return
+		return fmt.Errorf("%w for %s", ErrNoSuchSubscription, symbol)
 	}
 
 	if len(symbolSubs) > 1 {
-		fmt.Printf("Multiple active subscriptions for %s:\n", symbol)
+		out.Info("Multiple active subscriptions for %s:", symbol)
 		for i, sub := range symbolSubs {
-			fmt.Printf("  %d. ReqId: %s, Type: %s, Updates: %d\n",
+			out.Info("  %d. ReqId: %s, Type: %s, Updates: %d",
 				i+1, sub.MdReqId, a.getSubscriptionTypeDesc(sub.SubscriptionType), sub.TotalUpdates)
 		}
-		fmt.Printf("Unsubscribing from all %d subscriptions for %s\n", len(symbolSubs), symbol)
+		out.Info("Unsubscribing from all %d subscriptions for %s", len(symbolSubs), symbol)
 	}
 
+	var errs []error
 	for _, sub := range symbolSubs {
-		msg := builder.BuildMarketDataRequest(
-			sub.MdReqId,
-			[]string{symbol},
-			constants.SubscriptionRequestTypeUnsubscribe,
-			"0",
-			a.Config.SenderCompId,
-			a.Config.TargetCompId,
-			[]string{constants.MdEntryTypeTrade},
-		)
-
-		if err := quickfix.Send(msg); err != nil {
-			log.Printf("Error sending unsubscribe request for reqId %s: %v", sub.MdReqId, err)
-		} else {
-			fmt.Printf("Unsubscribe request sent for %s (reqId: %s)\n", symbol, sub.MdReqId)
-			a.TradeStore.RemoveSubscriptionByReqId(sub.MdReqId)
+		if err := a.sendUnsubscribe(out, sub); err != nil {
+			errs = append(errs, err)
 		}
 	}
+	return errors.Join(errs...)
 }
 
-func (a *FixApp) sendUnsubscribeByReqId(reqId string) {
+func (a *FixApp) sendUnsubscribeByReqId(out output, reqId string) error {
+	if !a.IsConnected() {
+		return ErrNotConnected
+	}
+
 	subscriptions := a.TradeStore.GetSubscriptionStatus()
 
 	sub, exists := subscriptions[reqId]
 	if !exists {
-		fmt.Printf("No active subscription found with reqId: %s\n", reqId)
-		return
+		return fmt.Errorf("%w with reqId: %s", ErrNoSuchSubscription, reqId)
 	}
 
-	msg := builder.BuildMarketDataRequest(
+	return a.sendUnsubscribe(out, sub)
+}
+
+func (a *FixApp) sendUnsubscribe(out output, sub *Subscription) error {
+	msg := a.buildUnsubscribe(sub)
+	if err := quickfix.SendToTarget(msg, a.SessionId); err != nil {
+		return fmt.Errorf("failed to send unsubscribe request for reqId %s: %w", sub.MdReqId, err)
+	}
+
+	out.Info("Unsubscribe request sent for %s (reqId: %s)", sub.Symbol, sub.MdReqId)
+	a.TradeStore.RemoveSubscriptionByReqId(sub.MdReqId)
+	a.recordEvent(sub.MdReqId, sub.Symbol, database.EventUnsubscribe, "")
+	a.endDatabaseSession(sub.MdReqId, database.EndReasonUnsubscribed, sub)
+	return nil
+}
+
+// instrumentKeys returns the local tracking key (symbol or SecurityID) of each instrument
+func instrumentKeys(instruments []builder.Instrument) []string {
+	keys := make([]string, 0, len(instruments))
+	for _, instrument := range instruments {
+		keys = append(keys, instrument.Key())
+	}
+	return keys
+}
+
+func newMdReqId() string {
+	return fmt.Sprintf("md_%d", time.Now().UnixNano())
+}
+
+// buildMarketDataRequest is shared by sending and preview so both produce the same message
+func (a *FixApp) buildMarketDataRequest(reqId string, instruments []builder.Instrument, subscriptionType, marketDepth string, entryTypes []string, opts builder.MdRequestOptions) *quickfix.Message {
+	msg := builder.BuildMarketDataRequestForInstruments(
 		reqId,
-		[]string{sub.Symbol},
-		constants.SubscriptionRequestTypeUnsubscribe,
-		"0",
+		instruments,
+		subscriptionType,
+		marketDepth,
 		a.Config.SenderCompId,
 		a.Config.TargetCompId,
-		[]string{constants.MdEntryTypeTrade},
+		entryTypes,
 	)
+	builder.ApplyMdRequestOptions(msg, opts)
+	builder.ApplyCustomTags(&msg.Body, a.Config.CustomTags)
+	a.Config.Dialect.Apply(msg)
+	return msg
+}
 
-	if err := quickfix.Send(msg); err != nil {
-		log.Printf("Error sending unsubscribe request for reqId %s: %v", reqId, err)
-		fmt.Printf("Failed to send unsubscribe request for reqId: %s\n", reqId)
-	} else {
-		fmt.Printf("Unsubscribe request sent for %s (reqId: %s)\n", sub.Symbol, reqId)
-		a.TradeStore.RemoveSubscriptionByReqId(reqId)
-	}
+func (a *FixApp) buildUnsubscribe(sub *Subscription) *quickfix.Message {
+	return a.buildMarketDataRequest(sub.MdReqId, []builder.Instrument{sub.Instrument()}, constants.SubscriptionRequestTypeUnsubscribe,
+		"0", []string{constants.MdEntryTypeTrade}, builder.MdRequestOptions{})
 }
 
-func (a *FixApp) sendMarketDataRequest(symbols []string, subscriptionType, description string) {
-	a.sendMarketDataRequestWithOptions(symbols, subscriptionType, "0", []string{constants.MdEntryTypeTrade}, description)
+func (a *FixApp) sendMarketDataRequest(out output, symbols []string, subscriptionType, description string) (string, error) {
+	return a.sendMarketDataRequestWithOptions(out, builder.SymbolInstruments(symbols), subscriptionType, "0", []string{constants.MdEntryTypeTrade},
+		builder.MdRequestOptions{}, "", description)
 }
 
-func (a *FixApp) sendMarketDataRequestWithOptions(symbols []string, subscriptionType, marketDepth string, entryTypes []string, description string) {
-	reqId := fmt.Sprintf("md_%d", time.Now().UnixNano())
+func (a *FixApp) sendMarketDataRequestWithOptions(out output, instruments []builder.Instrument, subscriptionType, marketDepth string, entryTypes []string,
+	opts builder.MdRequestOptions, label, description string) (string, error) {
+	symbols := instrumentKeys(instruments)
+	if _, err := validateMdRequest(symbols, subscriptionType, marketDepth, entryTypes); err != nil {
+		return "", err
+	}
+	if !a.IsConnected() {
+		return "", ErrNotConnected
+	}
+
+	reqId := newMdReqId()
 
 	if subscriptionType == constants.SubscriptionRequestTypeSubscribe {
-		for _, symbol := range symbols {
-			a.TradeStore.AddSubscription(symbol, subscriptionType, reqId)
+		for _, instrument := range instruments {
+			a.TradeStore.AddInstrumentSubscription(instrument, subscriptionType, reqId, marketDepth, entryTypes, opts, label)
 		}
 	}
 
 	for _, symbol := range symbols {
-		a.createDatabaseSession(symbol, subscriptionType, marketDepth, entryTypes, reqId)
+		if err := a.createDatabaseSession(symbol, subscriptionType, marketDepth, entryTypes, reqId, label); err != nil {
+			log.Printf("%v", err)
+		}
 	}
 
-	msg := builder.BuildMarketDataRequest(
-		reqId,
-		symbols,
-		subscriptionType,
-		marketDepth,
-		a.Config.SenderCompId,
-		a.Config.TargetCompId,
-		entryTypes,
-	)
+	msg := a.buildMarketDataRequest(reqId, instruments, subscriptionType, marketDepth, entryTypes, opts)
 
-	if err := quickfix.Send(msg); err != nil {
-		log.Printf("Error sending market data request: %v", err)
-		fmt.Printf("Failed to send %s request for %v\n", description, symbols)
+	a.trackRequest(reqId)
+	a.routeResponses(reqId, out)
+	if err := quickfix.SendToTarget(msg, a.SessionId); err != nil {
+		a.resolveRequest(reqId, err)
 		for _, symbol := range symbols {
 			a.TradeStore.RemoveSubscription(symbol)
 		}
-	} else {
-		entryTypesStr := ""
-		for i, et := range entryTypes {
-			if i > 0 {
-				entryTypesStr += ", "
-			}
-			entryTypesStr += getMdEntryTypeName(et)
+		return "", fmt.Errorf("failed to send %s request for %v: %w", description, symbols, err)
+	}
+
+	entryTypesStr := ""
+	for i, et := range entryTypes {
+		if i > 0 {
+			entryTypesStr += ", "
+		}
+		entryTypesStr += getMdEntryTypeName(et)
+	}
+	bookMode := ""
+	if mode := opts.BookMode(); mode != "" {
+		bookMode = ", book=" + mode
+	}
+	if label != "" {
+		bookMode += ", label=" + label
+	}
+	out.Info("%s request sent for %v (depth=%s, types=[%s]%s, reqId=%s)",
+		description, instruments, marketDepth, entryTypesStr, bookMode, reqId)
+	if subscriptionType == constants.SubscriptionRequestTypeSubscribe {
+		for _, key := range instrumentKeys(instruments) {
+			a.recordEvent(reqId, key, database.EventSubscribe, fmt.Sprintf("depth=%s", marketDepth))
+		}
+	}
+
+	return reqId, nil
+}
+
+// trackRequest registers reqId so the first response or reject can be observed by WaitForResponse
+func (a *FixApp) trackRequest(reqId string) {
+	a.pendingMu.Lock()
+	defer a.pendingMu.Unlock()
+
+	// Drop requests nobody waited for so long sessions don't accumulate them: expired ones, and
+	// the oldest while the map is still full. Requests are tracked in order, so only the front
+	// of pendingOrder needs looking at.
+	now := time.Now()
+	for front := a.pendingOrder.Front(); front != nil; front = a.pendingOrder.Front() {
+		id := front.Value.(string)
+		if now.Sub(a.pending[id].tracked) <= pendingRequestTTL && len(a.pending) < maxPendingRequests {
+			break
+		}
+		a.untrackRequest(id)
+	}
+	a.untrackRequest(reqId)
+	a.pending[reqId] = pendingRequest{result: make(chan error, 1), tracked: now, order: a.pendingOrder.PushBack(reqId)}
+}
+
+// untrackRequest forgets reqId; the caller holds pendingMu
+func (a *FixApp) untrackRequest(reqId string) {
+	if p, ok := a.pending[reqId]; ok {
+		a.pendingOrder.Remove(p.order)
+		delete(a.pending, reqId)
+	}
+}
+
+// pendingRequestIds returns the requests tracked and not yet waited for
+func (a *FixApp) pendingRequestIds() map[string]bool {
+	a.pendingMu.Lock()
+	defer a.pendingMu.Unlock()
+	ids := make(map[string]bool, len(a.pending))
+	for reqId := range a.pending {
+		ids[reqId] = true
+	}
+	return ids
+}
+
+func (a *FixApp) resolveRequest(reqId string, err error) {
+	a.pendingMu.Lock()
+	defer a.pendingMu.Unlock()
+	if p, ok := a.pending[reqId]; ok {
+		select {
+		case p.result <- err:
+		default: // already resolved
 		}
-		fmt.Printf("%s request sent for %v (depth=%s, types=[%s], reqId=%s)\n",
-			description, symbols, marketDepth, entryTypesStr, reqId)
 	}
 }
+
+// WaitForResponse blocks until the first market data message or reject arrives for reqId.
+// It returns nil on data, *ErrRejected on a 35=Y, or ErrTimeout.
+func (a *FixApp) WaitForResponse(reqId string, timeout time.Duration) error {
+	a.pendingMu.Lock()
+	p, ok := a.pending[reqId]
+	a.pendingMu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoSuchSubscription, reqId)
+	}
+
+	defer func() {
+		a.pendingMu.Lock()
+		a.untrackRequest(reqId)
+		a.pendingMu.Unlock()
+	}()
+
+	select {
+	case err := <-p.result:
+		return err
+	case <-time.After(timeout):
+		return ErrTimeout
+	}
+}
+
+// buildRawMessage is shared by sending and preview so both produce the same message
+func (a *FixApp) buildRawMessage(raw string) (*quickfix.Message, error) {
+	msg, err := builder.BuildRawMessage(raw, a.Config.SenderCompId, a.Config.TargetCompId)
+	if err != nil {
+		return nil, err
+	}
+	a.Config.Dialect.Apply(msg)
+	return msg, nil
+}
+
+// sendRawMessage sends a hand-built message for debugging gateway behavior. Responses are handled
+// like any other inbound message.
+func (a *FixApp) sendRawMessage(out output, raw string) error {
+	if !a.IsConnected() {
+		return ErrNotConnected
+	}
+
+	msg, err := a.buildRawMessage(raw)
+	if err != nil {
+		return err
+	}
+
+	// Render before sending; the session fills in the remaining header fields concurrently
+	fields := strings.ReplaceAll(msg.String(), "\x01", "|")
+	if err := quickfix.SendToTarget(msg, a.SessionId); err != nil {
+		return fmt.Errorf("failed to send raw message: %w", err)
+	}
+
+	out.Info("Raw message sent: %s", fields)
+	return nil
+}
@@ -0,0 +1,128 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRequestsRequireConnection(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+
+	if _, err := app.sendMarketDataRequest(app.consoleOutput(), []string{"BTC-USD"}, "0", "Snapshot"); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("Expected ErrNotConnected, got %v", err)
+	}
+
+	if err := app.sendUnsubscribeBySymbol(app.consoleOutput(), "BTC-USD"); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("Expected ErrNotConnected, got %v", err)
+	}
+}
+
+func TestUnsubscribeUnknownSubscription(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+	app.connected.Store(true)
+
+	if err := app.sendUnsubscribeBySymbol(app.consoleOutput(), "BTC-USD"); !errors.Is(err, ErrNoSuchSubscription) {
+		t.Fatalf("Expected ErrNoSuchSubscription, got %v", err)
+	}
+
+	if err := app.sendUnsubscribeByReqId(app.consoleOutput(), "md_123"); !errors.Is(err, ErrNoSuchSubscription) {
+		t.Fatalf("Expected ErrNoSuchSubscription, got %v", err)
+	}
+}
+
+func TestWaitForResponseRejected(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+
+	app.trackRequest("md_1")
+	app.resolveRequest("md_1", &ErrRejected{MdReqId: "md_1", Reason: "0", Text: "bad symbol"})
+
+	err := app.WaitForResponse("md_1", time.Second)
+	var rejected *ErrRejected
+	if !errors.As(err, &rejected) {
+		t.Fatalf("Expected ErrRejected, got %v", err)
+	}
+	if rejected.Reason != "0" {
+		t.Fatalf("Expected reason 0, got %s", rejected.Reason)
+	}
+
+	app.trackRequest("md_2")
+	if err := app.WaitForResponse("md_2", 10*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestPendingRequestsExpire(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+
+	// Never answered, and never waited for
+	app.trackRequest("md_old")
+	p := app.pending["md_old"]
+	p.tracked = time.Now().Add(-pendingRequestTTL - time.Second)
+	app.pending["md_old"] = p
+	app.trackRequest("md_new")
+	if ids := app.pendingRequestIds(); ids["md_old"] || !ids["md_new"] {
+		t.Fatalf("Expected the expired request to be dropped, got %v", ids)
+	}
+
+	for i := 0; i < maxPendingRequests+10; i++ {
+		app.trackRequest(fmt.Sprintf("md_%d", i))
+	}
+	ids := app.pendingRequestIds()
+	if len(ids) != maxPendingRequests || ids["md_new"] || ids["md_0"] || !ids[fmt.Sprintf("md_%d", maxPendingRequests+9)] {
+		t.Fatalf("Expected the oldest requests to be dropped at %d, got %d", maxPendingRequests, len(ids))
+	}
+}
+
+func TestPendingRequestsEvictOldestAtCap(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+
+	for i := 0; i < maxPendingRequests; i++ {
+		app.trackRequest(fmt.Sprintf("md_%d", i))
+	}
+	app.trackRequest("md_next")
+	ids := app.pendingRequestIds()
+	if len(ids) != maxPendingRequests || ids["md_0"] || !ids["md_1"] || !ids["md_next"] {
+		t.Fatalf("Expected only md_0 evicted at %d, got %d tracked, md_0=%v md_1=%v md_next=%v",
+			maxPendingRequests, len(ids), ids["md_0"], ids["md_1"], ids["md_next"])
+	}
+
+	// Waiting for a request forgets it, so the next one evicts nothing
+	app.resolveRequest("md_1", nil)
+	if err := app.WaitForResponse("md_1", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	app.trackRequest("md_last")
+	if ids := app.pendingRequestIds(); len(ids) != maxPendingRequests || !ids["md_2"] || app.pendingOrder.Len() != len(ids) {
+		t.Fatalf("Expected md_2 kept after md_1 was waited for, got %d tracked, md_2=%v", len(ids), ids["md_2"])
+	}
+}
+
+func TestSendRawMessageValidation(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+	if err := app.sendRawMessage(app.consoleOutput(), "35=V|262=test"); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("Expected ErrNotConnected, got %v", err)
+	}
+
+	app.connected.Store(true)
+	if err := app.sendRawMessage(app.consoleOutput(), "262=test"); err == nil {
+		t.Fatal("Expected error for message without MsgType")
+	}
+}
@@ -0,0 +1,123 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"prime-fix-md-go/builder"
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/database"
+)
+
+// Automatic resyncs of one symbol are spaced out so a persistently crossed feed cannot flood the gateway
+const minAutoResyncInterval = 10 * time.Second
+
+// SetAutoResync selects whether a book that crosses or skips a RptSeq is resynced automatically
+func (a *FixApp) SetAutoResync(on bool) {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	a.autoResync = on
+}
+
+func (a *FixApp) autoResyncEnabled() bool {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.autoResync
+}
+
+// bookSubscription returns the live subscription whose book should be resynced for symbol
+func (a *FixApp) bookSubscription(symbol string) (*Subscription, bool) {
+	for _, sub := range a.TradeStore.GetSubscriptionStatus() {
+		if sub.Symbol == symbol && sub.Active && sub.HasBook() &&
+			sub.SubscriptionType == constants.SubscriptionRequestTypeSubscribe {
+			return sub, true
+		}
+	}
+	return nil, false
+}
+
+// resyncBook requests a fresh snapshot at the live subscription's depth. The book is swapped
+// for the rebuilt one when the snapshot arrives; incremental updates keep flowing meanwhile.
+func (a *FixApp) resyncBook(out output, symbol string) (string, error) {
+	sub, ok := a.bookSubscription(symbol)
+	if !ok {
+		return "", fmt.Errorf("%w: no live book subscription for %s", ErrNoSuchSubscription, symbol)
+	}
+
+	depth := sub.MarketDepth
+	if depth == "" {
+		depth = "0"
+	}
+	reqId, err := a.sendMarketDataRequestWithOptions(out, []builder.Instrument{sub.Instrument()},
+		constants.SubscriptionRequestTypeSnapshot, depth,
+		[]string{constants.MdEntryTypeBid, constants.MdEntryTypeOffer}, sub.Options, sub.Label, "Resync")
+	if err != nil {
+		return "", err
+	}
+
+	a.resyncMu.Lock()
+	a.resyncs[reqId] = symbol
+	a.lastResync[symbol] = time.Now()
+	a.resyncMu.Unlock()
+	a.recordEvent(sub.MdReqId, symbol, database.EventResubscribe, "snapshot "+reqId)
+	return reqId, nil
+}
+
+// handleBookProblems reports books that went out of sync and, when enabled, resyncs them
+func (a *FixApp) handleBookProblems(problems []BookProblem) {
+	for _, problem := range problems {
+		log.Printf("Warning: %s", problem)
+		if !a.autoResyncEnabled() {
+			a.consoleOutput().Info("Warning: %s (run 'resync %s' to rebuild it)", problem, problem.Symbol)
+			continue
+		}
+
+		a.resyncMu.Lock()
+		recent := time.Since(a.lastResync[problem.Symbol]) < minAutoResyncInterval
+		a.resyncMu.Unlock()
+		if recent {
+			continue
+		}
+
+		a.consoleOutput().Info("Warning: %s, resyncing", problem)
+		if _, err := a.resyncBook(a.consoleOutput(), problem.Symbol); err != nil {
+			a.consoleOutput().Error(err)
+		}
+	}
+}
+
+// completeResync reports a finished resync when the snapshot for one of its requests arrives
+func (a *FixApp) completeResync(mdReqId string) {
+	a.resyncMu.Lock()
+	symbol, ok := a.resyncs[mdReqId]
+	delete(a.resyncs, mdReqId)
+	a.resyncMu.Unlock()
+	if !ok {
+		return
+	}
+
+	if sub, ok := a.bookSubscription(symbol); ok {
+		a.recordEvent(sub.MdReqId, symbol, database.EventSnapshotReceived, "resync "+mdReqId)
+	}
+	if book, ok := a.Books.Get(symbol); ok {
+		a.consoleOutput().Info("Book for %s rebuilt from snapshot: %d bids, %d offers",
+			symbol, len(book.Bids()), len(book.Offers()))
+	}
+}
@@ -0,0 +1,145 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"prime-fix-md-go/database"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// SeqNums are the session's next sequence numbers as held in the message store
+type SeqNums struct {
+	In  int // Next MsgSeqNum expected from the gateway
+	Out int // MsgSeqNum of the next message sent
+}
+
+// SeqNums reads the session's sequence numbers from the message store
+func (a *FixApp) SeqNums() (SeqNums, error) {
+	if a.SessionId == (quickfix.SessionID{}) {
+		return SeqNums{}, errors.New("no FIX session has been created yet")
+	}
+	in, err := quickfix.GetExpectedTargetNum(a.SessionId)
+	if err != nil {
+		return SeqNums{}, fmt.Errorf("failed to read sequence numbers for %s: %v", a.SessionId, err)
+	}
+	out, err := quickfix.GetExpectedSenderNum(a.SessionId)
+	if err != nil {
+		return SeqNums{}, fmt.Errorf("failed to read sequence numbers for %s: %v", a.SessionId, err)
+	}
+	return SeqNums{In: in, Out: out}, nil
+}
+
+// SetSeqNums overwrites the session's sequence numbers in the message store; zero leaves one unchanged
+func (a *FixApp) SetSeqNums(n SeqNums) error {
+	if a.SessionId == (quickfix.SessionID{}) {
+		return errors.New("no FIX session has been created yet")
+	}
+	if n.In > 0 {
+		if err := quickfix.SetNextTargetMsgSeqNum(a.SessionId, n.In); err != nil {
+			return fmt.Errorf("failed to set the inbound sequence number: %v", err)
+		}
+	}
+	if n.Out > 0 {
+		if err := quickfix.SetNextSenderMsgSeqNum(a.SessionId, n.Out); err != nil {
+			return fmt.Errorf("failed to set the outbound sequence number: %v", err)
+		}
+	}
+	var set []string
+	if n.In > 0 {
+		set = append(set, fmt.Sprintf("in %d", n.In))
+	}
+	if n.Out > 0 {
+		set = append(set, fmt.Sprintf("out %d", n.Out))
+	}
+	a.recordSessionEvent(a.SessionId, database.FixEventSeqSet, strings.Join(set, ", "))
+	return nil
+}
+
+// parseSeqSetArgs reads "--in N --out M" (either or both) from the arguments after "seq set"
+func parseSeqSetArgs(args []string) (SeqNums, error) {
+	var n SeqNums
+	for i := 0; i < len(args); i++ {
+		var dst *int
+		switch args[i] {
+		case "--in":
+			dst = &n.In
+		case "--out":
+			dst = &n.Out
+		default:
+			return SeqNums{}, invalidRequest("unknown seq set flag %s", args[i])
+		}
+		if i+1 >= len(args) {
+			return SeqNums{}, invalidRequest("%s needs a sequence number", args[i])
+		}
+		i++
+		v, err := strconv.Atoi(args[i])
+		if err != nil || v < 1 {
+			return SeqNums{}, invalidRequest("invalid sequence number %q (expected a whole number from 1)", args[i])
+		}
+		*dst = v
+	}
+	if n.In == 0 && n.Out == 0 {
+		return SeqNums{}, invalidRequest("seq set needs --in, --out or both")
+	}
+	return n, nil
+}
+
+func (a *FixApp) handleSeqRequest(out output, parts []string) {
+	if len(parts) < 2 || (parts[1] != "show" && parts[1] != "set") || (parts[1] == "show" && len(parts) > 2) {
+		fmt.Fprintln(out.Console(), "Usage: seq show | seq set [--in N] [--out M]")
+		return
+	}
+	current, err := a.SeqNums()
+	if err != nil {
+		out.Error(err)
+		return
+	}
+	if parts[1] == "show" {
+		out.Table(fmt.Sprintf("Sequence numbers for %s:", a.SessionId), []string{"Direction", "Next MsgSeqNum"}, [][]string{
+			{"In (expected from gateway)", strconv.Itoa(current.In)},
+			{"Out (next sent)", strconv.Itoa(current.Out)},
+		})
+		return
+	}
+
+	next, err := parseSeqSetArgs(parts[2:])
+	if err != nil {
+		out.Error(err)
+		return
+	}
+	change := ""
+	if next.In > 0 {
+		change += fmt.Sprintf(" in %d -> %d", current.In, next.In)
+	}
+	if next.Out > 0 {
+		change += fmt.Sprintf(" out %d -> %d", current.Out, next.Out)
+	}
+	if a.confirm == nil || !a.confirm(fmt.Sprintf("Set %s sequence numbers:%s?", a.SessionId, change)) {
+		out.Info("Sequence numbers unchanged")
+		return
+	}
+	if err := a.SetSeqNums(next); err != nil {
+		out.Error(err)
+		return
+	}
+	out.Info(fmt.Sprintf("Sequence numbers set:%s", change))
+}
@@ -0,0 +1,55 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseSeqSetArgs(t *testing.T) {
+	n, err := parseSeqSetArgs([]string{"--in", "1042", "--out", "377"})
+	if err != nil || n != (SeqNums{In: 1042, Out: 377}) {
+		t.Fatalf("Expected in 1042 out 377, got %+v, %v", n, err)
+	}
+	if n, err := parseSeqSetArgs([]string{"--out", "5"}); err != nil || n != (SeqNums{Out: 5}) {
+		t.Fatalf("Expected only out 5, got %+v, %v", n, err)
+	}
+	for _, args := range [][]string{nil, {"--in"}, {"--in", "0"}, {"--in", "x"}, {"--next", "3"}} {
+		if _, err := parseSeqSetArgs(args); err == nil {
+			t.Fatalf("Expected an error for %v", args)
+		}
+	}
+}
+
+func TestSeqRequestErrors(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	var out bytes.Buffer
+	app.SetConsole(&out, &out)
+	app.SetOutputFormat(OutputPlain)
+
+	app.handleSeqRequest(app.consoleOutput(), []string{"seq", "set", "--in", "5"})
+	if !strings.Contains(out.String(), "no FIX session") {
+		t.Fatalf("Expected an error without a session, got %q", out.String())
+	}
+
+	out.Reset()
+	app.handleSeqRequest(app.consoleOutput(), []string{"seq", "bogus"})
+	if !strings.Contains(out.String(), "Usage: seq") {
+		t.Fatalf("Expected usage, got %q", out.String())
+	}
+}
@@ -0,0 +1,71 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"fmt"
+
+	"prime-fix-md-go/database"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// SetResetSeqNumOnLogon makes every logon ask the gateway to start both sequence numbers at 1
+func (a *FixApp) SetResetSeqNumOnLogon(on bool) {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	a.resetOnLogon = on
+}
+
+func (a *FixApp) resetSeqNumOnLogon() bool {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.resetOnLogon
+}
+
+// ResetSequence recovers a session whose sequence numbers no longer match the gateway's: it logs
+// out, resets both sequence numbers to 1, and the reconnect logs on with ResetSeqNumFlag(141)=Y.
+// When not logged on, the next logon carries the flag.
+func (a *FixApp) ResetSequence() error {
+	if a.SessionId == (quickfix.SessionID{}) {
+		return errors.New("no FIX session has been created yet")
+	}
+	a.resetPending.Store(true)
+	if err := quickfix.ResetSession(a.SessionId); err != nil {
+		a.resetPending.Store(false)
+		return fmt.Errorf("failed to reset session %s: %v", a.SessionId, err)
+	}
+	a.recordSessionEvent(a.SessionId, database.FixEventSeqSet, "in 1, out 1 (logon --reset)")
+	return nil
+}
+
+func (a *FixApp) handleLogonRequest(out output, parts []string) {
+	if len(parts) != 2 || parts[1] != "--reset" {
+		fmt.Fprintln(out.Console(), "Usage: logon --reset")
+		return
+	}
+	connected := a.IsConnected()
+	if err := a.ResetSequence(); err != nil {
+		out.Error(err)
+		return
+	}
+	if connected {
+		out.Info("Logging out to reset sequence numbers; the session reconnects with ResetSeqNumFlag=Y and re-sends configured subscriptions")
+	} else {
+		out.Info("Sequence numbers reset; the next logon sends ResetSeqNumFlag=Y")
+	}
+}
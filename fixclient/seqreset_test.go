@@ -0,0 +1,62 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"testing"
+
+	"prime-fix-md-go/constants"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func logonFor(app *FixApp) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetField(constants.TagMsgType, quickfix.FIXString(constants.MsgTypeLogon))
+	app.ToAdmin(msg, app.SessionId)
+	return msg
+}
+
+func TestLogonResetSeqNumFlag(t *testing.T) {
+	app := NewFixApp(NewConfig("key", "secret", "pass", "sender", "COIN", "portfolio"), nil)
+	if logonFor(app).Body.Has(constants.TagResetSeqNumFlag) {
+		t.Fatal("Expected no ResetSeqNumFlag by default")
+	}
+
+	app.resetPending.Store(true)
+	if v, _ := logonFor(app).Body.GetString(constants.TagResetSeqNumFlag); v != "Y" {
+		t.Fatalf("Expected ResetSeqNumFlag=Y while a reset is pending, got %q", v)
+	}
+	app.OnLogon(app.SessionId)
+	if logonFor(app).Body.Has(constants.TagResetSeqNumFlag) {
+		t.Fatal("Expected the pending reset to clear after logon")
+	}
+
+	app.SetResetSeqNumOnLogon(true)
+	if v, _ := logonFor(app).Body.GetString(constants.TagResetSeqNumFlag); v != "Y" {
+		t.Fatalf("Expected ResetSeqNumFlag=Y on every logon, got %q", v)
+	}
+}
+
+func TestResetSequenceWithoutSession(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	if err := app.ResetSequence(); err == nil {
+		t.Fatal("Expected an error before a session exists")
+	}
+	if app.resetPending.Load() {
+		t.Fatal("Expected no pending reset after a failed reset")
+	}
+}
@@ -0,0 +1,174 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/database"
+	"prime-fix-md-go/utils"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Session events kept in memory for the events command when there is no database
+const maxSessionEvents = 1000
+
+// SessionEvent is one entry of the FIX session timeline; Event is one of the database.FixEvent* names
+type SessionEvent struct {
+	At        time.Time
+	SessionId string
+	Event     string
+	Detail    string
+}
+
+// sessionTimeline holds the latest session events, oldest first
+type sessionTimeline struct {
+	mu     sync.Mutex
+	events []SessionEvent
+}
+
+func (t *sessionTimeline) add(e SessionEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.events) >= maxSessionEvents {
+		t.events = append(t.events[:0], t.events[len(t.events)-maxSessionEvents+1:]...)
+	}
+	t.events = append(t.events, e)
+}
+
+func (t *sessionTimeline) since(from time.Time) []SessionEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var events []SessionEvent
+	for _, e := range t.events {
+		if !e.At.Before(from) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// recordSessionEvent adds an event to the timeline and stores it
+func (a *FixApp) recordSessionEvent(sid quickfix.SessionID, event, detail string) {
+	e := SessionEvent{At: time.Now(), SessionId: sid.String(), Event: event, Detail: detail}
+	a.timeline.add(e)
+	a.alertSessionEvent(e)
+	if a.Db == nil {
+		return
+	}
+	// Off the session goroutine, so a busy database cannot hold up heartbeats
+	go func() {
+		if err := a.Db.StoreFixSessionEvent(e.SessionId, e.Event, e.Detail, e.At); err != nil {
+			log.Printf("%v", storageError("failed to store session event", err))
+		}
+	}()
+}
+
+// observeSessionMessage records logouts, resend requests, sequence resets and session rejects
+// sent or received; rejects received also count towards a reject burst
+func (a *FixApp) observeSessionMessage(msg *quickfix.Message, sid quickfix.SessionID, inbound bool) {
+	direction := "sent"
+	if inbound {
+		direction = "received"
+	}
+	msgType, _ := msg.Header.GetString(constants.TagMsgType)
+	var event string
+	details := []string{direction}
+	switch msgType {
+	case constants.MsgTypeLogout:
+		event = database.FixEventLogout
+	case constants.MsgTypeResendRequest:
+		event = database.FixEventResend
+		end := utils.GetString(msg, constants.TagEndSeqNo)
+		if end == "0" {
+			end = "end"
+		}
+		details = append(details, fmt.Sprintf("seq %s-%s", utils.GetString(msg, constants.TagBeginSeqNo), end))
+	case constants.MsgTypeSequenceReset:
+		event = database.FixEventSequenceReset
+		detail := "new seq " + utils.GetString(msg, constants.TagNewSeqNo)
+		if utils.GetString(msg, constants.TagGapFillFlag) == "Y" {
+			detail += " (gap fill)"
+		}
+		details = append(details, detail)
+	case constants.MsgTypeReject:
+		event = database.FixEventReject
+		details = append(details, "ref seq "+utils.GetString(msg, constants.TagRefSeqNum))
+	default:
+		return
+	}
+	if text := utils.GetString(msg, constants.TagText); text != "" {
+		details = append(details, text)
+	}
+	a.recordSessionEvent(sid, event, strings.Join(details, ": "))
+	if event == database.FixEventReject && inbound {
+		a.recordReject(time.Now(), "session reject, "+strings.Join(details[1:], ": "))
+	}
+}
+
+// parseEventsArgs reads [--since D]; without it the timeline starts when the client did
+func parseEventsArgs(args []string, now, started time.Time) (time.Time, error) {
+	switch {
+	case len(args) == 0:
+		return started, nil
+	case len(args) == 2 && args[0] == "--since":
+		since, err := time.ParseDuration(args[1])
+		if err != nil || since <= 0 {
+			return time.Time{}, invalidRequest("invalid --since %q (e.g. 2h, 30m)", args[1])
+		}
+		return now.Add(-since), nil
+	}
+	return time.Time{}, invalidRequest("usage: events [--since 1h]")
+}
+
+func (a *FixApp) handleEventsRequest(out output, parts []string) {
+	from, err := parseEventsArgs(parts[1:], time.Now(), a.startedAt)
+	if err != nil {
+		out.Error(err)
+		return
+	}
+
+	// The database also has events from earlier runs; memory only this one
+	events := a.timeline.since(from)
+	if a.Db != nil {
+		rows, err := a.Db.QueryFixSessionEvents(from)
+		if err != nil {
+			out.Error(fmt.Errorf("%w: %v", ErrStorage, err))
+			return
+		}
+		events = events[:0]
+		for _, r := range rows {
+			events = append(events, SessionEvent{At: r.At, SessionId: r.SessionId, Event: r.Event, Detail: r.Detail})
+		}
+	}
+	if len(events) == 0 {
+		out.Info("No session events since %s", displayTime(from, diffTimeFormat))
+		return
+	}
+
+	rows := make([][]string, 0, len(events))
+	for _, e := range events {
+		rows = append(rows, []string{displayTime(e.At, diffTimeFormat), e.Event, e.SessionId, dashIfEmpty(e.Detail)})
+	}
+	out.Table(fmt.Sprintf("Session events since %s (%d):", displayTime(from, diffTimeFormat), len(events)),
+		[]string{withZone("Time"), "Event", "Session", "Detail"}, rows)
+}
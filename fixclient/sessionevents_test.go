@@ -0,0 +1,99 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/database"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestSessionTimelineKeepsLatest(t *testing.T) {
+	var tl sessionTimeline
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < maxSessionEvents+5; i++ {
+		tl.add(SessionEvent{At: start.Add(time.Duration(i) * time.Second), Event: database.FixEventResend})
+	}
+	all := tl.since(start)
+	if len(all) != maxSessionEvents || !all[0].At.Equal(start.Add(5*time.Second)) {
+		t.Fatalf("Expected the latest %d events from 5s, got %d from %v", maxSessionEvents, len(all), all[0].At)
+	}
+	if recent := tl.since(start.Add(time.Duration(maxSessionEvents+3) * time.Second)); len(recent) != 2 {
+		t.Fatalf("Expected 2 events in the window, got %d", len(recent))
+	}
+}
+
+func TestObserveSessionMessage(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	sid := quickfix.SessionID{BeginString: "FIXT.1.1", SenderCompID: "A", TargetCompID: "COIN"}
+
+	resend := quickfix.NewMessage()
+	resend.Header.SetField(constants.TagMsgType, quickfix.FIXString(constants.MsgTypeResendRequest))
+	resend.Body.SetField(constants.TagBeginSeqNo, quickfix.FIXInt(10))
+	resend.Body.SetField(constants.TagEndSeqNo, quickfix.FIXInt(0))
+	app.observeSessionMessage(resend, sid, true)
+
+	logout := quickfix.NewMessage()
+	logout.Header.SetField(constants.TagMsgType, quickfix.FIXString(constants.MsgTypeLogout))
+	logout.Body.SetField(constants.TagText, quickfix.FIXString("MsgSeqNum too low"))
+	app.observeSessionMessage(logout, sid, false)
+
+	heartbeat := quickfix.NewMessage()
+	heartbeat.Header.SetField(constants.TagMsgType, quickfix.FIXString("0"))
+	app.observeSessionMessage(heartbeat, sid, true)
+
+	events := app.timeline.since(time.Time{})
+	if len(events) != 2 {
+		t.Fatalf("Expected a resend and a logout, got %+v", events)
+	}
+	if events[0].Event != database.FixEventResend || events[0].Detail != "received: seq 10-end" {
+		t.Fatalf("Unexpected resend event %+v", events[0])
+	}
+	if events[1].Event != database.FixEventLogout || events[1].Detail != "sent: MsgSeqNum too low" || events[1].SessionId != sid.String() {
+		t.Fatalf("Unexpected logout event %+v", events[1])
+	}
+}
+
+func TestEventsCommand(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	var out bytes.Buffer
+	app.SetConsole(&out, &out)
+	app.SetOutputFormat(OutputPlain)
+
+	app.handleEventsRequest(app.consoleOutput(), []string{"events"})
+	if !strings.Contains(out.String(), "No session events") {
+		t.Fatalf("Expected no events yet, got %q", out.String())
+	}
+
+	out.Reset()
+	app.OnCreate(quickfix.SessionID{BeginString: "FIXT.1.1", SenderCompID: "A", TargetCompID: "COIN"})
+	app.handleEventsRequest(app.consoleOutput(), []string{"events", "--since", "1h"})
+	if !strings.Contains(out.String(), database.FixEventCreated) {
+		t.Fatalf("Expected the created event, got %q", out.String())
+	}
+
+	for _, args := range [][]string{{"--since"}, {"--since", "-1h"}, {"--from", "1h"}} {
+		if _, err := parseEventsArgs(args, time.Now(), time.Now()); err == nil {
+			t.Fatalf("Expected an error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// labelPattern is what md --label accepts, so labels stay usable as query filters
+var labelPattern = regexp.MustCompile(`^[A-Za-z0-9.:_-]{1,64}$`)
+
+const defaultSessionsLimit = 50
+
+type sessionsQuery struct {
+	label string // Empty lists every request
+	limit int
+}
+
+// parseSessionsQuery reads [--label L] [--limit N]
+func parseSessionsQuery(args []string) (sessionsQuery, error) {
+	q := sessionsQuery{limit: defaultSessionsLimit}
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return q, invalidRequest("%s requires a value", args[i])
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "--label":
+			if !labelPattern.MatchString(value) {
+				return q, invalidRequest("invalid --label %q", value)
+			}
+			q.label = value
+		case "--limit":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return q, invalidRequest("invalid --limit %q", value)
+			}
+			q.limit = n
+		default:
+			return q, invalidRequest("usage: sessions [--label <label>] [--limit N]")
+		}
+		i++
+	}
+	return q, nil
+}
+
+func (a *FixApp) handleSessionsRequest(out output, parts []string) {
+	q, err := parseSessionsQuery(parts[1:])
+	if err != nil {
+		out.Error(err)
+		return
+	}
+	if a.Db == nil {
+		out.Error(fmt.Errorf("%w: no database to list requests from", ErrStorage))
+		return
+	}
+
+	sessions, err := a.Db.QuerySessions(q.label, q.limit)
+	if err != nil {
+		out.Error(fmt.Errorf("%w: %v", ErrStorage, err))
+		return
+	}
+	if len(sessions) == 0 {
+		if q.label != "" {
+			out.Info("No stored requests labelled %s", q.label)
+		} else {
+			out.Info("No stored requests")
+		}
+		return
+	}
+
+	rows := make([][]string, 0, len(sessions))
+	for _, s := range sessions {
+		ended, updates := "-", "-"
+		if !s.EndedAt.IsZero() {
+			ended = displayTime(s.EndedAt, diffTimeFormat) + " (" + s.EndReason + ")"
+		}
+		if s.TotalUpdates != nil {
+			updates = strconv.FormatInt(*s.TotalUpdates, 10)
+		}
+		rows = append(rows, []string{displayTime(s.CreatedAt, diffTimeFormat), ended, s.Symbol, s.RequestType,
+			s.DataTypes, updates, s.MdReqId, dashIfEmpty(s.Label)})
+	}
+	title := fmt.Sprintf("Stored requests (%d):", len(sessions))
+	if q.label != "" {
+		title = fmt.Sprintf("Stored requests labelled %s (%d):", q.label, len(sessions))
+	}
+	out.Table(title, []string{withZone("Started"), "Ended", "Symbol", "Type", "Data", "Updates", "ReqId", "Label"}, rows)
+}
@@ -0,0 +1,80 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"prime-fix-md-go/database"
+)
+
+func TestParseMdLabel(t *testing.T) {
+	app := createTestFixApp()
+	flags, err := app.parseMdFlags([]string{"--subscribe", "--trades", "--label", "fomc-day"})
+	if err != nil || flags.label != "fomc-day" {
+		t.Fatalf("Expected label fomc-day, got %q (%v)", flags.label, err)
+	}
+	for _, args := range [][]string{{"--label"}, {"--label", "fomc day"}, {"--label", strings.Repeat("x", 65)}} {
+		if _, err := app.parseMdFlags(args); !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("Expected invalid request for %q, got %v", args, err)
+		}
+	}
+}
+
+func TestParseSessionsQuery(t *testing.T) {
+	q, err := parseSessionsQuery(nil)
+	if err != nil || q.label != "" || q.limit != defaultSessionsLimit {
+		t.Fatalf("Unexpected defaults %+v (%v)", q, err)
+	}
+	q, err = parseSessionsQuery([]string{"--label", "fomc-day", "--limit", "5"})
+	if err != nil || q.label != "fomc-day" || q.limit != 5 {
+		t.Fatalf("Unexpected query %+v (%v)", q, err)
+	}
+	for _, args := range [][]string{{"--label"}, {"--limit", "0"}, {"--symbol", "BTC-USD"}} {
+		if _, err := parseSessionsQuery(args); err == nil {
+			t.Fatalf("Expected error for %q", args)
+		}
+	}
+}
+
+func TestSessionsListsLabelledRequests(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	var out bytes.Buffer
+	app := createTestFixApp()
+	app.Db = db
+	app.Renderer, _ = NewRenderer(OutputPlain, &out)
+
+	if err := app.createDatabaseSession("BTC-USD", "1", "0", []string{"2"}, "md_1", "fomc-day"); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if err := app.createDatabaseSession("ETH-USD", "1", "0", []string{"2"}, "md_2", ""); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	app.handleSessionsRequest(app.consoleOutput(), []string{"sessions", "--label", "fomc-day"})
+	if !strings.Contains(out.String(), "md_1") || strings.Contains(out.String(), "md_2") {
+		t.Fatalf("Expected only the labelled request, got:\n%s", out.String())
+	}
+}
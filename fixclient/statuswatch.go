@@ -0,0 +1,95 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"prime-fix-md-go/formatter"
+)
+
+const defaultStatusWatch = 2 * time.Second
+
+// parseStatusArgs reads "[symbol] [--watch [seconds]]"; an empty symbol shows every
+// subscription and zero means show status once
+func parseStatusArgs(args []string) (string, time.Duration, error) {
+	symbol := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		symbol, args = args[0], args[1:]
+	}
+	if len(args) == 0 {
+		return symbol, 0, nil
+	}
+	if args[0] != "--watch" || len(args) > 2 {
+		return "", 0, fmt.Errorf("usage: status [symbol] [--watch [seconds]]")
+	}
+	if len(args) == 1 {
+		return symbol, defaultStatusWatch, nil
+	}
+	seconds, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || seconds <= 0 {
+		return "", 0, fmt.Errorf("--watch needs a positive number of seconds, got %q", args[1])
+	}
+	return symbol, time.Duration(seconds * float64(time.Second)), nil
+}
+
+// watchStatus redraws the status, of one symbol when given, every interval until Ctrl-C. Live
+// output is held back so it does not scroll the table away; table output clears the screen
+// before each redraw.
+func (a *FixApp) watchStatus(out output, symbol string, every time.Duration) {
+	live, logOutput := a.swapRenderer(mutedRenderer{}), log.Writer()
+	log.SetOutput(io.Discard)
+	formatter.SetMuted(true)
+	defer func() {
+		formatter.SetMuted(false)
+		log.SetOutput(logOutput)
+		a.swapRenderer(live)
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for !a.ShouldExit() {
+		a.drawStatus(out, symbol, every)
+		select {
+		case <-interrupt:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *FixApp) drawStatus(out output, symbol string, every time.Duration) {
+	if format := a.currentFormat(); format == OutputTable || format == "" {
+		fmt.Fprint(out.Console(), clearScreenSeq)
+	}
+	if symbol != "" {
+		a.symbolStatus(out, symbol)
+	} else {
+		out.Status(a.statusView())
+	}
+	out.Info("Refreshing every %s at %s, press Ctrl-C to stop", every, displayTime(time.Now(), "15:04:05"))
+}
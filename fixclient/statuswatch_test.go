@@ -0,0 +1,103 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/builder"
+	"prime-fix-md-go/constants"
+)
+
+func TestParseStatusArgs(t *testing.T) {
+	cases := []struct {
+		args   []string
+		symbol string
+		want   time.Duration
+		ok     bool
+	}{
+		{nil, "", 0, true},
+		{[]string{"--watch"}, "", defaultStatusWatch, true},
+		{[]string{"--watch", "10"}, "", 10 * time.Second, true},
+		{[]string{"--watch", "0.5"}, "", 500 * time.Millisecond, true},
+		{[]string{"--watch", "0"}, "", 0, false},
+		{[]string{"--watch", "soon"}, "", 0, false},
+		{[]string{"--refresh"}, "", 0, false},
+		{[]string{"BTC-USD"}, "BTC-USD", 0, true},
+		{[]string{"BTC-USD", "--watch", "5"}, "BTC-USD", 5 * time.Second, true},
+		{[]string{"BTC-USD", "ETH-USD"}, "", 0, false},
+	}
+	for _, c := range cases {
+		symbol, got, err := parseStatusArgs(c.args)
+		if (err == nil) != c.ok || symbol != c.symbol || got != c.want {
+			t.Fatalf("parseStatusArgs(%v) = %q, %v, %v; want %q, %v, ok=%v", c.args, symbol, got, err, c.symbol, c.want, c.ok)
+		}
+	}
+}
+
+func TestDrawStatusClearsForTables(t *testing.T) {
+	var out bytes.Buffer
+	app := createTestFixApp()
+	app.console = &out
+	if err := app.SetOutputFormat(OutputTable); err != nil {
+		t.Fatal(err)
+	}
+
+	app.drawStatus(app.consoleOutput(), "", 5*time.Second)
+	if !strings.HasPrefix(out.String(), clearScreenSeq) || !strings.Contains(out.String(), "Refreshing every 5s") {
+		t.Fatalf("Expected a cleared screen and the refresh note, got %q", out.String())
+	}
+
+	out.Reset()
+	app.SetOutputFormat(OutputJson)
+	app.drawStatus(app.consoleOutput(), "", 5*time.Second)
+	if strings.Contains(out.String(), clearScreenSeq) {
+		t.Fatalf("Expected JSON output not to clear the screen, got %q", out.String())
+	}
+}
+
+func TestSymbolStatus(t *testing.T) {
+	var out bytes.Buffer
+	app := createTestFixApp()
+	app.console = &out
+	if err := app.SetOutputFormat(OutputPlain); err != nil {
+		t.Fatal(err)
+	}
+	app.TradeStore.AddInstrumentSubscription(builder.Instrument{Symbol: "BTC-USD"}, "1", "req-1", "10",
+		[]string{constants.MdEntryTypeBid, constants.MdEntryTypeTrade}, builder.MdRequestOptions{}, "")
+	app.TradeStore.AddTrades("BTC-USD", []Trade{
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeTrade, Price: "100", Size: "1", Aggressor: "Buy", MdReqId: "req-1"},
+	}, false, "req-1")
+	app.TradeStore.RecordReject("BTC-USD", SubscriptionReject{MdReqId: "req-0", Detail: "Unknown symbol", At: time.Now()})
+
+	app.symbolStatus(app.consoleOutput(), "btc-usd")
+	got := out.String()
+	for _, want := range []string{"ReqId\treq-1", "Depth\t10", "Snapshot\tnot received", "Updates\t1 (B 0 T 1)",
+		"Rate\t0.1/s over 10s", "Trade\t100\t1\tBuy", "req-0\tUnknown symbol"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("Expected %q in the symbol status, got:\n%s", want, got)
+		}
+	}
+
+	out.Reset()
+	app.symbolStatus(app.consoleOutput(), "ETH-USD")
+	if !strings.Contains(out.String(), "No subscriptions or rejects for ETH-USD") {
+		t.Fatalf("Expected a note for an unknown symbol, got %q", out.String())
+	}
+}
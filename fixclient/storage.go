@@ -17,72 +17,176 @@
 package fixclient
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"time"
 
 	"prime-fix-md-go/constants"
+	"prime-fix-md-go/database"
+	"prime-fix-md-go/utils"
 )
 
-func (a *FixApp) storeTradesToDatabase(trades []Trade, seqNum string, isSnapshot bool) {
+func (a *FixApp) storeTradesToDatabase(trades []Trade, seqNum string, isSnapshot bool) error {
 	if a.Db == nil {
-		return
+		return nil
 	}
 
 	seqNumInt, _ := strconv.Atoi(seqNum)
 
-	tx, err := a.Db.BeginTransaction()
-	if err != nil {
-		log.Printf("Failed to begin database transaction: %v", err)
-		return
+	err := a.Db.WriteBatch(func(tx *sql.Tx) error {
+		var err error
+		for _, trade := range trades {
+			entryTime := trade.storedTime()
+
+			switch trade.EntryType {
+			case constants.MdEntryTypeBid: // "0"
+				err = a.Db.StoreOrderBookRecordBatch(tx, trade.orderBookRecord("bid", seqNumInt, isSnapshot))
+			case constants.MdEntryTypeOffer: // "1"
+				err = a.Db.StoreOrderBookRecordBatch(tx, trade.orderBookRecord("offer", seqNumInt, isSnapshot))
+			case constants.MdEntryTypeTrade: // "2"
+				err = a.Db.StoreTradeRecordBatch(tx, database.TradeRecord{
+					Symbol:         trade.Symbol,
+					Price:          trade.Price,
+					Size:           trade.Size,
+					AggressorSide:  trade.Aggressor,
+					TradeTime:      entryTime,
+					SeqNum:         seqNumInt,
+					MdReqId:        trade.MdReqId,
+					IsSnapshot:     isSnapshot,
+					MdEntryId:      trade.EntryId,
+					UpdateAction:   trade.UpdateAction,
+					TradeCondition: trade.TradeCondition,
+
+					SecurityId:       trade.SecurityId,
+					SecurityIdSource: trade.SecurityIdSource,
+
+					AggressorInferred: trade.AggressorInferred,
+				})
+			case constants.MdEntryTypeOpen: // "4"
+				err = a.Db.StoreOhlcvBatch(tx, trade.Symbol, "open", trade.Price, entryTime,
+					seqNumInt, trade.MdReqId)
+			case constants.MdEntryTypeClose: // "5"
+				err = a.Db.StoreOhlcvBatch(tx, trade.Symbol, "close", trade.Price, entryTime,
+					seqNumInt, trade.MdReqId)
+			case constants.MdEntryTypeHigh: // "7"
+				err = a.Db.StoreOhlcvBatch(tx, trade.Symbol, "high", trade.Price, entryTime,
+					seqNumInt, trade.MdReqId)
+			case constants.MdEntryTypeLow: // "8"
+				err = a.Db.StoreOhlcvBatch(tx, trade.Symbol, "low", trade.Price, entryTime,
+					seqNumInt, trade.MdReqId)
+			case constants.MdEntryTypeVolume: // "B"
+				err = a.Db.StoreOhlcvBatch(tx, trade.Symbol, "volume", trade.Size, entryTime,
+					seqNumInt, trade.MdReqId)
+			}
+
+			if err != nil {
+				return storageError(fmt.Sprintf("failed to store %s data to database", getMdEntryTypeName(trade.EntryType)), err)
+			}
+		}
+		if err := a.storeBookState(tx, trades); err != nil {
+			return err
+		}
+		return a.storeSummary(tx, trades)
+	})
+	if err != nil && !errors.Is(err, ErrStorage) {
+		return storageError("failed to write to database", err)
+	}
+	return err
+}
+
+// storeBookState brings order_book_state in line with the in-memory book of every symbol the entries touched
+func (a *FixApp) storeBookState(tx *sql.Tx, entries []Trade) error {
+	if a.Books == nil {
+		return nil
+	}
+
+	done := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.EntryType != constants.MdEntryTypeBid && entry.EntryType != constants.MdEntryTypeOffer || done[entry.Symbol] {
+			continue
+		}
+		done[entry.Symbol] = true
+
+		book, ok := a.Books.Get(entry.Symbol)
+		if !ok {
+			continue
+		}
+		if err := a.Db.StoreBookStateBatch(tx, book.Symbol, bookStateLevels(book.Bids()), bookStateLevels(book.Offers()), book.LastUpdate); err != nil {
+			return storageError("failed to store order book state", err)
+		}
 	}
-	defer tx.Rollback()
-
-	for _, trade := range trades {
-		switch trade.EntryType {
-		case constants.MdEntryTypeBid: // "0"
-			posInt, _ := strconv.Atoi(trade.Position)
-			err = a.Db.StoreOrderBookBatch(tx, trade.Symbol, "bid", trade.Price, trade.Size,
-				posInt, seqNumInt, trade.MdReqId, isSnapshot)
-		case constants.MdEntryTypeOffer: // "1"
-			posInt, _ := strconv.Atoi(trade.Position)
-			err = a.Db.StoreOrderBookBatch(tx, trade.Symbol, "offer", trade.Price, trade.Size,
-				posInt, seqNumInt, trade.MdReqId, isSnapshot)
-		case constants.MdEntryTypeTrade: // "2"
-			err = a.Db.StoreTradeBatch(tx, trade.Symbol, trade.Price, trade.Size,
-				trade.Aggressor, trade.Time, seqNumInt, trade.MdReqId, isSnapshot)
-		case constants.MdEntryTypeOpen: // "4"
-			err = a.Db.StoreOhlcvBatch(tx, trade.Symbol, "open", trade.Price, trade.Time,
-				seqNumInt, trade.MdReqId)
-		case constants.MdEntryTypeClose: // "5"
-			err = a.Db.StoreOhlcvBatch(tx, trade.Symbol, "close", trade.Price, trade.Time,
-				seqNumInt, trade.MdReqId)
-		case constants.MdEntryTypeHigh: // "7"
-			err = a.Db.StoreOhlcvBatch(tx, trade.Symbol, "high", trade.Price, trade.Time,
-				seqNumInt, trade.MdReqId)
-		case constants.MdEntryTypeLow: // "8"
-			err = a.Db.StoreOhlcvBatch(tx, trade.Symbol, "low", trade.Price, trade.Time,
-				seqNumInt, trade.MdReqId)
-		case constants.MdEntryTypeVolume: // "B"
-			err = a.Db.StoreOhlcvBatch(tx, trade.Symbol, "volume", trade.Size, trade.Time,
-				seqNumInt, trade.MdReqId)
+	return nil
+}
+
+// storeSummary updates market_summary with the latest trade, current quote and entry counts per symbol
+func (a *FixApp) storeSummary(tx *sql.Tx, entries []Trade) error {
+	updates := make(map[string]*database.SummaryUpdate)
+	var order []string
+	for _, entry := range entries {
+		isTrade := entry.EntryType == constants.MdEntryTypeTrade
+		isBook := entry.EntryType == constants.MdEntryTypeBid || entry.EntryType == constants.MdEntryTypeOffer
+		if !isTrade && !isBook {
+			continue
 		}
 
-		if err != nil {
-			log.Printf("Failed to store %s data to database: %v", getMdEntryTypeName(trade.EntryType), err)
-			return
+		u, ok := updates[entry.Symbol]
+		if !ok {
+			u = &database.SummaryUpdate{Symbol: entry.Symbol}
+			updates[entry.Symbol] = u
+			order = append(order, entry.Symbol)
+		}
+		if isBook {
+			u.BookUpdates++
+			continue
+		}
+		// Snapshots can list trades in any order, so keep the one with the latest exchange time.
+		// A trade without MDEntryTime only counts while no trade with one was seen.
+		u.Trades++
+		if u.LastTradeTime.IsZero() || !entry.EntryTime.IsZero() && !entry.EntryTime.Before(u.LastTradeTime) {
+			u.LastPrice, u.LastSize, u.LastAggressor, u.LastTradeTime = entry.Price, entry.Size, entry.Aggressor, entry.EntryTime
 		}
 	}
 
-	if err = tx.Commit(); err != nil {
-		log.Printf("Failed to commit database transaction: %v", err)
+	now := time.Now()
+	for _, symbol := range order {
+		u := updates[symbol]
+		u.Updated = now
+		if u.BookUpdates > 0 && a.Books != nil {
+			if book, ok := a.Books.Get(symbol); ok {
+				u.HasQuote = true
+				if bid, ok := book.BestBid(); ok {
+					u.BidPrice, u.BidSize = bid.Price, bid.Size
+				}
+				if offer, ok := book.BestOffer(); ok {
+					u.OfferPrice, u.OfferSize = offer.Price, offer.Size
+				}
+			}
+		}
+		if err := a.Db.StoreSummaryBatch(tx, *u); err != nil {
+			return storageError("failed to store market summary", err)
+		}
+	}
+	return nil
+}
+
+func bookStateLevels(levels []BookLevel) []database.BookStateLevel {
+	state := make([]database.BookStateLevel, 0, len(levels))
+	for _, level := range levels {
+		s := database.BookStateLevel{Price: level.Price, Size: level.Size, EntryId: level.EntryId}
+		if n, err := strconv.Atoi(level.NumOrders); err == nil {
+			s.NumOrders = &n
+		}
+		state = append(state, s)
 	}
+	return state
 }
 
-func (a *FixApp) createDatabaseSession(symbol, subscriptionType, marketDepth string, entryTypes []string, reqId string) {
+func (a *FixApp) createDatabaseSession(symbol, subscriptionType, marketDepth string, entryTypes []string, reqId, label string) error {
 	if a.Db == nil {
-		return
+		return nil
 	}
 
 	requestType := "snapshot"
@@ -120,8 +224,81 @@ func (a *FixApp) createDatabaseSession(symbol, subscriptionType, marketDepth str
 	}
 
 	sessionId := fmt.Sprintf("%s_%s_%d", symbol, requestType, time.Now().Unix())
-	err := a.Db.CreateSession(sessionId, symbol, requestType, dataTypes, reqId, depth)
-	if err != nil {
-		log.Printf("Failed to create session record: %v", err)
+	if err := a.Db.CreateSessionWithLabel(sessionId, symbol, requestType, dataTypes, reqId, depth, label); err != nil {
+		return storageError("failed to create session record", err)
+	}
+	return nil
+}
+
+// endDatabaseSession closes the session rows of reqId. sub is the tracked subscription, if any,
+// and supplies the update count.
+func (a *FixApp) endDatabaseSession(reqId, reason string, sub *Subscription) {
+	if a.Db == nil {
+		return
+	}
+	var totalUpdates *int64
+	if sub != nil {
+		n := sub.TotalUpdates
+		totalUpdates = &n
+	}
+	if err := a.Db.EndSession(reqId, reason, totalUpdates, time.Now()); err != nil {
+		log.Printf("%v", storageError("failed to end session record", err))
+	}
+}
+
+// storeReject records a 35=Y so recurring entitlement or symbol problems can be analyzed later
+func (a *FixApp) storeReject(rej *ErrRejected, symbol string) {
+	if a.Db == nil {
+		return
+	}
+	if err := a.Db.StoreReject(rej.MdReqId, symbol, rej.Reason, rej.Text, time.Now()); err != nil {
+		log.Printf("%v", storageError("failed to store reject", err))
+	}
+}
+
+// EndDatabaseSessions closes every open session row on exit, recording update counts
+// for subscriptions that are still tracked
+func (a *FixApp) EndDatabaseSessions() {
+	if a.Db == nil {
+		return
+	}
+	for reqId, sub := range a.TradeStore.GetSubscriptionStatus() {
+		a.endDatabaseSession(reqId, database.EndReasonExit, sub)
+	}
+	if err := a.Db.EndOpenSessions(database.EndReasonExit, time.Now()); err != nil {
+		log.Printf("%v", storageError("failed to end session records", err))
+	}
+}
+
+// storedTime is the entry timestamp written to the database: the combined date and time
+// when it parsed, otherwise the raw MdEntryTime
+func (t Trade) storedTime() string {
+	if t.EntryTime.IsZero() {
+		return t.Time
+	}
+	return utils.FormatFixTime(t.EntryTime)
+}
+
+func (t Trade) orderBookRecord(side string, seqNum int, isSnapshot bool) database.OrderBookRecord {
+	posInt, _ := strconv.Atoi(t.Position)
+	rec := database.OrderBookRecord{
+		Symbol:         t.Symbol,
+		Side:           side,
+		Price:          t.Price,
+		Size:           t.Size,
+		Position:       posInt,
+		SeqNum:         seqNum,
+		MdReqId:        t.MdReqId,
+		IsSnapshot:     isSnapshot,
+		MdEntryId:      t.EntryId,
+		UpdateAction:   t.UpdateAction,
+		QuoteCondition: t.QuoteCondition,
+
+		SecurityId:       t.SecurityId,
+		SecurityIdSource: t.SecurityIdSource,
+	}
+	if n, err := strconv.Atoi(t.NumOrders); err == nil {
+		rec.NumOrders = &n
 	}
+	return rec
 }
@@ -0,0 +1,117 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/dictionary"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// ValidationStats counts inbound messages checked against the FIX dictionary in strict mode
+type ValidationStats struct {
+	Checked  int64  // Market data messages validated
+	Failed   int64  // Messages with at least one discrepancy
+	Problems int64  // Discrepancies across all messages
+	Last     string // Most recent discrepancy, with its message type and sequence number
+}
+
+type validationCounters struct {
+	checked  atomic.Int64
+	failed   atomic.Int64
+	problems atomic.Int64
+	last     atomic.Pointer[string]
+	lastWarn atomic.Int64 // UnixNano of the last warning logged
+}
+
+// SetStrictValidation checks every inbound market data message against the embedded
+// FIX 5.0 SP2 / Prime dictionary when on. Discrepancies are warnings: the message is still
+// handled, so a gateway or parser change shows up without losing data.
+func (a *FixApp) SetStrictValidation(on bool) error {
+	if !on {
+		a.strict.Store(nil)
+		return nil
+	}
+	v, err := dictionary.Prime()
+	if err != nil {
+		return err
+	}
+	a.strict.Store(v)
+	return nil
+}
+
+// ValidationStats returns the strict mode counters; ok is false when nothing was ever checked
+func (a *FixApp) ValidationStats() (ValidationStats, bool) {
+	c := &a.validation
+	s := ValidationStats{
+		Checked:  c.checked.Load(),
+		Failed:   c.failed.Load(),
+		Problems: c.problems.Load(),
+	}
+	if last := c.last.Load(); last != nil {
+		s.Last = *last
+	}
+	return s, s.Checked > 0 || a.strict.Load() != nil
+}
+
+// validateInbound records the message's discrepancies, warning at most every dropWarnEvery
+func (a *FixApp) validateInbound(v *dictionary.Validator, msg *quickfix.Message, now time.Time) {
+	msgType, _ := msg.Header.GetString(constants.TagMsgType)
+	if !v.Knows(msgType) {
+		return
+	}
+	c := &a.validation
+	c.checked.Add(1)
+	problems := v.Validate(msg.String())
+	if len(problems) == 0 {
+		return
+	}
+
+	failed := c.failed.Add(1)
+	c.problems.Add(int64(len(problems)))
+	seqNum, _ := msg.Header.GetString(constants.TagMsgSeqNum)
+	summary := fmt.Sprintf("%s seq %s: %s", msgType, seqNum, strings.Join(problems, "; "))
+	c.last.Store(&summary)
+
+	last := c.lastWarn.Load()
+	if now.UnixNano()-last >= int64(dropWarnEvery) && c.lastWarn.CompareAndSwap(last, now.UnixNano()) {
+		log.Printf("Strict validation: %s (%d messages with discrepancies so far)", summary, failed)
+	}
+}
+
+// displayValidationStats shows how many inbound messages failed strict validation
+func (a *FixApp) displayValidationStats(out output) {
+	s, ok := a.ValidationStats()
+	if !ok {
+		return
+	}
+	out.Table("Inbound Validation:",
+		[]string{"Checked", "Failed", "Problems", "Last Problem"},
+		[][]string{{
+			strconv.FormatInt(s.Checked, 10),
+			strconv.FormatInt(s.Failed, 10),
+			strconv.FormatInt(s.Problems, 10),
+			dashIfEmpty(s.Last),
+		}})
+}
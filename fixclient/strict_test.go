@@ -0,0 +1,59 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStrictValidationCountsDiscrepancies(t *testing.T) {
+	w := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(w)
+
+	app := createTestFixApp()
+	if _, ok := app.ValidationStats(); ok {
+		t.Fatalf("Expected no validation stats while strict mode is off")
+	}
+	if err := app.SetStrictValidation(true); err != nil {
+		t.Fatalf("SetStrictValidation: %v", err)
+	}
+
+	v := app.strict.Load()
+	now := time.Now()
+	app.validateInbound(v, fullDepthSnapshot(2), now)
+	app.validateInbound(v, rawMessage("35=X\x0134=3\x01262=req\x01268=2\x01279=0\x01269=2\x01270=50000\x01271=1\x01"), now)
+	app.validateInbound(v, rawMessage("35=0\x0134=4\x01"), now)
+
+	s, ok := app.ValidationStats()
+	if !ok || s.Checked != 2 || s.Failed != 1 || s.Problems != 1 {
+		t.Fatalf("Expected 2 checked with 1 failure and 1 problem, got %+v", s)
+	}
+	if !strings.HasPrefix(s.Last, "X seq 3: NoMDEntries (268) is 2 but 1 entries follow") {
+		t.Fatalf("Unexpected last problem: %q", s.Last)
+	}
+
+	if err := app.SetStrictValidation(false); err != nil || app.strict.Load() != nil {
+		t.Fatalf("Expected strict mode off, got %v", err)
+	}
+	if _, ok := app.ValidationStats(); !ok {
+		t.Fatalf("Expected counters to stay visible after strict mode is turned off")
+	}
+}
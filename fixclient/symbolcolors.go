@@ -0,0 +1,109 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+// Colors for symbol prefixes. Red and green are left out since they already mean a dropped or
+// live session in the prompt.
+var symbolPalette = []string{
+	"\x1b[36m", "\x1b[35m", "\x1b[33m", "\x1b[34m",
+	"\x1b[96m", "\x1b[95m", "\x1b[93m", "\x1b[94m",
+}
+
+// symbolStyles gives each symbol in the update stream a color and pads symbols to a common
+// width, so interleaved updates for several symbols are easy to tell apart
+type symbolStyles struct {
+	mu      sync.Mutex
+	enabled bool
+	colors  map[string]string
+	width   int
+}
+
+var symbolStyle = &symbolStyles{}
+
+// SetSymbolColors selects whether streaming updates get a colored symbol prefix: "auto" (on for
+// terminals unless NO_COLOR is set), "on" or "off".
+func SetSymbolColors(mode string) error {
+	var enabled bool
+	switch strings.ToLower(mode) {
+	case "", "auto":
+		enabled = promptColor()
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		return fmt.Errorf("invalid display.symbolColors %q (expected auto, on or off)", mode)
+	}
+	symbolStyle.mu.Lock()
+	defer symbolStyle.mu.Unlock()
+	symbolStyle.enabled = enabled
+	return nil
+}
+
+// color returns the symbol's color. The first choice comes from a hash of the symbol so it is
+// the same from run to run; when another symbol already has it, the next free color is used.
+func (s *symbolStyles) color(symbol string) string {
+	if c, ok := s.colors[symbol]; ok {
+		return c
+	}
+	if s.colors == nil {
+		s.colors = make(map[string]string)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(symbol))
+	start := int(h.Sum32() % uint32(len(symbolPalette)))
+
+	taken := make(map[string]bool, len(s.colors))
+	for _, c := range s.colors {
+		taken[c] = true
+	}
+	c := symbolPalette[start]
+	for i := range symbolPalette {
+		if candidate := symbolPalette[(start+i)%len(symbolPalette)]; !taken[candidate] {
+			c = candidate
+			break
+		}
+	}
+	s.colors[symbol] = c
+	return c
+}
+
+// updateLine is formatUpdateLine with the leading symbol padded and colored. Lines are unchanged
+// when symbol colors are off.
+func (s *symbolStyles) updateLine(trade Trade) string {
+	line := formatUpdateLine(trade)
+	if trade.Symbol == "" {
+		return line
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return line
+	}
+	if len(trade.Symbol) > s.width {
+		s.width = len(trade.Symbol)
+	}
+	prefix := fmt.Sprintf("%s%-*s%s", s.color(trade.Symbol), s.width, trade.Symbol, colorReset)
+	return prefix + strings.TrimPrefix(line, trade.Symbol)
+}
@@ -0,0 +1,60 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSymbolColorsDistinctAndStable(t *testing.T) {
+	s := &symbolStyles{enabled: true}
+	seen := make(map[string]string)
+	for _, symbol := range []string{"BTC-USD", "ETH-USD", "SOL-USD", "DOGE-USD"} {
+		c := s.color(symbol)
+		for other, oc := range seen {
+			if oc == c {
+				t.Fatalf("%s and %s share color %q", symbol, other, c)
+			}
+		}
+		seen[symbol] = c
+	}
+	if s.color("ETH-USD") != seen["ETH-USD"] {
+		t.Fatalf("Expected ETH-USD to keep its color")
+	}
+
+	// A symbol seen first gets its preferred color in every run
+	if first := (&symbolStyles{}).color("BTC-USD"); first != seen["BTC-USD"] {
+		t.Fatalf("Expected BTC-USD to get %q again, got %q", seen["BTC-USD"], first)
+	}
+}
+
+func TestSymbolStyleUpdateLine(t *testing.T) {
+	trade := Trade{Symbol: "BTC-USD", EntryType: "2", Price: "50000", Size: "0.1", Aggressor: "Buy"}
+
+	off := &symbolStyles{}
+	if got := off.updateLine(trade); got != formatUpdateLine(trade) {
+		t.Fatalf("Expected an unchanged line with colors off, got %q", got)
+	}
+
+	on := &symbolStyles{enabled: true}
+	on.updateLine(Trade{Symbol: "DOGE-USD", EntryType: "2", Price: "0.1", Size: "10"})
+	got := on.updateLine(trade)
+	want := on.color("BTC-USD") + "BTC-USD " + colorReset + " Trade: 50000"
+	if !strings.HasPrefix(got, want) {
+		t.Fatalf("Expected a padded, colored prefix %q, got %q", want, got)
+	}
+}
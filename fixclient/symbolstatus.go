@@ -0,0 +1,120 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// symbolStatusEntries is how many of a subscription's latest entries status <symbol> shows
+const symbolStatusEntries = 5
+
+// symbolStatus shows everything needed to debug one symbol's streams: each subscription's
+// request, snapshot and update rates, its latest entries, the session summary and any rejects
+func (a *FixApp) symbolStatus(out output, symbol string) {
+	subsBySymbol := a.TradeStore.GetSubscriptionsBySymbol()
+	subs, ok := subsBySymbol[symbol]
+	if !ok {
+		symbol = strings.ToUpper(symbol)
+		subs = subsBySymbol[symbol]
+	}
+	rejects := a.TradeStore.Rejects(symbol)
+	if len(subs) == 0 && len(rejects) == 0 {
+		out.Info("No subscriptions or rejects for %s", symbol)
+		return
+	}
+
+	now := time.Now()
+	for _, sub := range subs {
+		out.Table(fmt.Sprintf("%s subscription %s:", symbol, sub.MdReqId), []string{"Field", "Value"}, subscriptionDetailRows(sub, now))
+
+		entries := a.displayTrades(a.TradeStore.RecentEntries(sub.MdReqId, symbolStatusEntries))
+		if len(entries) == 0 {
+			continue
+		}
+		rows := make([][]string, 0, len(entries))
+		for _, e := range entries {
+			rows = append(rows, []string{lastUpdateDesc(e.Timestamp), getMdEntryTypeName(e.EntryType), e.Price, e.Size, entryDetail(e)})
+		}
+		out.Table(fmt.Sprintf("Last %d entries:", len(entries)), []string{withZone("Received"), "Type", "Price", "Size", "Detail"}, rows)
+	}
+
+	if m, ok := a.marketSummaries()[symbol]; ok {
+		out.Table("Session:", []string{"Last", "High", "Low", "Volume"}, [][]string{{m.Last, m.High, m.Low, m.Volume}})
+	}
+
+	if len(rejects) > 0 {
+		rows := make([][]string, 0, len(rejects))
+		for _, rej := range rejects {
+			rows = append(rows, []string{displayTime(rej.At, lastTimeFormat), rej.MdReqId, rej.Detail})
+		}
+		out.Table(fmt.Sprintf("Rejects (%d):", len(rejects)), []string{withZone("At"), "ReqId", "Detail"}, rows)
+	}
+}
+
+func subscriptionDetailRows(sub *Subscription, now time.Time) [][]string {
+	depth := sub.MarketDepth
+	if depth == "0" || depth == "" {
+		depth = "0 (full book)"
+	}
+	entryTypes := make([]string, 0, len(sub.EntryTypes))
+	for _, entryType := range sub.EntryTypes {
+		entryTypes = append(entryTypes, getMdEntryTypeName(entryType))
+	}
+	snapshot := "not received"
+	if !sub.SnapshotAt.IsZero() {
+		snapshot = displayTime(sub.SnapshotAt, lastTimeFormat)
+	}
+	label := sub.Label
+	if label == "" {
+		label = "-"
+	}
+
+	return [][]string{
+		{"ReqId", sub.MdReqId},
+		{"Type", getSubscriptionTypeDesc(sub.SubscriptionType)},
+		{"Status", subscriptionState(sub)},
+		{"Mode", subscriptionModeDesc(sub)},
+		{"Label", label},
+		{"Depth", depth},
+		{"Entry types", strings.Join(entryTypes, ", ")},
+		{"Subscribed", displayTime(sub.SubscribedAt, lastTimeFormat)},
+		{"Snapshot", snapshot},
+		{"Last update", lastUpdateDesc(sub.LastUpdate)},
+		{"Updates", fmt.Sprintf("%d (%s)", sub.TotalUpdates, updateCountsDesc(sub))},
+		{"Rate", fmt.Sprintf("%.1f/s over %ds, %.1f/s average", sub.RecentRate(now), updateRateWindow, sub.AverageRate(now))},
+	}
+}
+
+// entryDetail is the aggressor of a trade or the position and action of a book entry
+func entryDetail(e Trade) string {
+	switch {
+	case e.Aggressor != "":
+		if e.AggressorInferred {
+			return e.Aggressor + " (inferred)"
+		}
+		return e.Aggressor
+	case e.Position != "" && e.UpdateAction != "":
+		return fmt.Sprintf("pos %s, %s", e.Position, getMdUpdateActionName(e.UpdateAction))
+	case e.Position != "":
+		return "pos " + e.Position
+	case e.UpdateAction != "":
+		return getMdUpdateActionName(e.UpdateAction)
+	}
+	return "-"
+}
@@ -0,0 +1,121 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/formatter"
+)
+
+// tailRenderer passes one symbol's trades through to the wrapped renderer and drops everything else
+type tailRenderer struct {
+	symbol string
+	next   Renderer
+}
+
+func (r *tailRenderer) trades(entries []Trade) []Trade {
+	var result []Trade
+	for _, entry := range entries {
+		if entry.Symbol == r.symbol && (entry.EntryType == "" || entry.EntryType == constants.MdEntryTypeTrade) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func (r *tailRenderer) MarketDataReceived(msgType, symbol, mdReqId, noMdEntries, seqNum string) {}
+
+func (r *tailRenderer) Snapshot(symbol string, entries []Trade) {
+	if trades := r.trades(entries); len(trades) > 0 {
+		r.next.Updates(trades)
+	}
+}
+
+func (r *tailRenderer) Updates(entries []Trade) {
+	if trades := r.trades(entries); len(trades) > 0 {
+		r.next.Updates(trades)
+	}
+}
+
+func (r *tailRenderer) Reject(rej *ErrRejected, hint string) {}
+
+func (r *tailRenderer) Status(status StatusView) {}
+
+func (r *tailRenderer) Table(title string, headers []string, rows [][]string) {}
+
+func (r *tailRenderer) Info(format string, args ...interface{}) {}
+
+func (r *tailRenderer) Error(err error) {}
+
+// startTail routes console output to symbol's trades only and returns a function that restores it
+func (a *FixApp) startTail(symbol string) func() {
+	a.settingsMu.Lock()
+	previous := a.Renderer
+	a.Renderer = &tailRenderer{symbol: symbol, next: previous}
+	a.settingsMu.Unlock()
+
+	logOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	formatter.SetMuted(true)
+
+	return func() {
+		formatter.SetMuted(false)
+		log.SetOutput(logOutput)
+		a.swapRenderer(previous)
+	}
+}
+
+func (a *FixApp) hasTradeSubscription(symbol string) bool {
+	for _, sub := range a.TradeStore.GetSubscriptionsBySymbol()[symbol] {
+		for _, entryType := range sub.EntryTypes {
+			if entryType == constants.MdEntryTypeTrade {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (a *FixApp) handleTailRequest(out output, parts []string) {
+	if len(parts) < 2 {
+		fmt.Fprintln(out.Console(), "Usage: tail <symbol>")
+		return
+	}
+	symbol := strings.ToUpper(parts[1])
+
+	if !a.hasTradeSubscription(symbol) {
+		out.Info("No live trade subscription for %s; start one with: md %s --subscribe --trades", symbol, symbol)
+	}
+	out.Info("Following %s trades, press Ctrl-C to stop", symbol)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	stop := a.startTail(symbol)
+	<-interrupt
+	stop()
+
+	out.Info("Stopped following %s", symbol)
+}
@@ -0,0 +1,51 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTailShowsOnlyOneSymbolsTrades(t *testing.T) {
+	var buf bytes.Buffer
+	app := createTestFixApp()
+	app.Renderer, _ = NewRenderer(OutputPlain, &buf)
+
+	stop := app.startTail("BTC-USD")
+	app.Renderer.Updates([]Trade{
+		{Symbol: "BTC-USD", EntryType: "2", Price: "100.5", Size: "1"},
+		{Symbol: "BTC-USD", EntryType: "0", Price: "99", Size: "2"},
+		{Symbol: "ETH-USD", EntryType: "2", Price: "3000", Size: "1"},
+	})
+	app.Renderer.Info("hidden")
+	app.Renderer.Error(errors.New("hidden"))
+	stop()
+
+	out := buf.String()
+	if !strings.Contains(out, "100.5") || strings.Contains(out, "3000") || strings.Contains(out, "99") ||
+		strings.Contains(out, "hidden") {
+		t.Fatalf("Expected only BTC-USD trades, got %q", out)
+	}
+
+	app.Renderer.Info("restored")
+	if !strings.Contains(buf.String(), "restored") {
+		t.Fatalf("Expected output restored after tail, got %q", buf.String())
+	}
+}
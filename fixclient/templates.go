@@ -0,0 +1,109 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sync"
+	"text/template"
+
+	"prime-fix-md-go/constants"
+)
+
+// LineTemplates replaces the built-in streaming update lines with user templates, e.g.
+// "{{.Symbol}},{{.Price}},{{.Size}}". Each template receives a Trade. A nil template keeps the
+// built-in line for that kind of entry.
+type LineTemplates struct {
+	Trade *template.Template // Trade entries
+	Book  *template.Template // Bid and offer entries
+
+	failOnce sync.Once
+}
+
+var templateFuncs = template.FuncMap{
+	"entryType": getMdEntryTypeName,   // "Bid", "Offer", "Trade", ...
+	"aggressor": getAggressorSideDesc, // "Buy" or "Sell"
+}
+
+// ParseLineTemplates parses the trade and book templates; empty strings keep the built-in lines.
+// Each template is tried on a sample entry so a misspelled field is reported at startup.
+func ParseLineTemplates(trade, book string) (*LineTemplates, error) {
+	t := &LineTemplates{}
+	for _, spec := range []struct {
+		name, text string
+		dst        **template.Template
+		sample     Trade
+	}{
+		{"trade", trade, &t.Trade, Trade{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeTrade, Price: "1", Size: "1"}},
+		{"book", book, &t.Book, Trade{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeBid, Price: "1", Size: "1"}},
+	} {
+		if spec.text == "" {
+			continue
+		}
+		tmpl, err := template.New(spec.name).Funcs(templateFuncs).Parse(spec.text)
+		if err != nil {
+			return nil, fmt.Errorf("%s template: %v", spec.name, err)
+		}
+		if err := tmpl.Execute(&bytes.Buffer{}, spec.sample); err != nil {
+			return nil, fmt.Errorf("%s template: %v", spec.name, err)
+		}
+		*spec.dst = tmpl
+	}
+	return t, nil
+}
+
+// line renders trade with the matching template, or reports false to use the built-in line
+func (t *LineTemplates) line(trade Trade) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	tmpl := t.Trade
+	switch trade.EntryType {
+	case constants.MdEntryTypeBid, constants.MdEntryTypeOffer:
+		tmpl = t.Book
+	case constants.MdEntryTypeTrade, "":
+	default:
+		return "", false
+	}
+	if tmpl == nil {
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, trade); err != nil {
+		t.failOnce.Do(func() { log.Printf("Output template %s failed, using the built-in line: %v", tmpl.Name(), err) })
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// SetLineTemplates sets the templates used for streaming updates in one output format (table or plain)
+func (a *FixApp) SetLineTemplates(format string, templates *LineTemplates) error {
+	switch format {
+	case OutputTable, OutputPlain:
+	default:
+		return fmt.Errorf("templates apply to the table and plain output formats, not %q", format)
+	}
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	if a.templates == nil {
+		a.templates = make(map[string]*LineTemplates)
+	}
+	a.templates[format] = templates
+	return a.applyOutputFormat(a.outputFormat)
+}
@@ -0,0 +1,66 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"prime-fix-md-go/constants"
+)
+
+func TestLineTemplates(t *testing.T) {
+	templates, err := ParseLineTemplates("{{.Symbol}},{{.Price}},{{.Size}},{{aggressor .Aggressor}}", "{{.Symbol}} {{entryType .EntryType}} {{.Price}}")
+	if err != nil {
+		t.Fatalf("Failed to parse templates: %v", err)
+	}
+
+	var out bytes.Buffer
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	app.SetConsole(&out, &out)
+	defer app.SetConsole(nil, nil)
+	if err := app.SetOutputFormat(OutputPlain); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := app.SetLineTemplates(OutputPlain, templates); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	app.Renderer.Updates([]Trade{
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeTrade, Price: "50000.10", Size: "0.5", Aggressor: "1"},
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeOffer, Price: "50001", Size: "2"},
+		{Symbol: "BTC-USD", EntryType: constants.MdEntryTypeHigh, Price: "51000"},
+	})
+	want := "BTC-USD,50000.10,0.5,Buy\nBTC-USD Offer 50001\nupdate BTC-USD High: 51000\n"
+	if out.String() != want {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", want, out.String())
+	}
+}
+
+func TestLineTemplateErrors(t *testing.T) {
+	if _, err := ParseLineTemplates("{{.Symbol", ""); err == nil {
+		t.Fatal("Expected a syntax error")
+	}
+	if _, err := ParseLineTemplates("{{.Sym}}", ""); err == nil || !strings.Contains(err.Error(), "trade template") {
+		t.Fatalf("Expected a misspelled field to be reported, got %v", err)
+	}
+
+	app := NewFixApp(NewConfig("", "", "", "", "", ""), nil)
+	if err := app.SetLineTemplates(OutputJson, &LineTemplates{}); err == nil {
+		t.Fatal("Expected templates for json output to be rejected")
+	}
+}
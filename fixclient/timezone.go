@@ -0,0 +1,80 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Entry times and last-update times are shown in this zone; nil is UTC, the zone of FIX times.
+// A reload can replace it while updates are rendered.
+var displayZone atomic.Pointer[time.Location]
+
+const entryTimeFormat = "15:04:05.000"
+
+// SetTimeZone selects the zone times are displayed in: "UTC", "Local" or an IANA name such as
+// "America/New_York"
+func SetTimeZone(name string) error {
+	switch strings.ToLower(name) {
+	case "", "utc":
+		displayZone.Store(time.UTC)
+		return nil
+	case "local":
+		displayZone.Store(time.Local)
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("unknown time zone %q (expected UTC, Local or a name such as America/New_York)", name)
+	}
+	displayZone.Store(loc)
+	return nil
+}
+
+// zone is the display zone
+func zone() *time.Location {
+	if loc := displayZone.Load(); loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// displayTime formats t in the display zone
+func displayTime(t time.Time, layout string) string {
+	return t.In(zone()).Format(layout)
+}
+
+// zoneLabel is the display zone's abbreviation now, e.g. UTC or EDT
+func zoneLabel() string {
+	return time.Now().In(zone()).Format("MST")
+}
+
+// withZone adds the display zone to a column header, e.g. "Time (UTC)"
+func withZone(header string) string {
+	return header + " (" + zoneLabel() + ")"
+}
+
+// displayEntryTime is the entry's MDEntryTime in the display zone, or as received when it
+// could not be parsed
+func displayEntryTime(trade Trade) string {
+	if trade.EntryTime.IsZero() {
+		return trade.Time
+	}
+	return displayTime(trade.EntryTime, entryTimeFormat)
+}
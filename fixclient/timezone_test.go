@@ -0,0 +1,47 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDisplayTimeZone(t *testing.T) {
+	defer SetTimeZone("UTC")
+
+	entry := Trade{Time: "14:30:00.250", EntryTime: time.Date(2025, 7, 1, 14, 30, 0, 250e6, time.UTC)}
+	if got := displayEntryTime(entry); got != "14:30:00.250" {
+		t.Fatalf("Expected the UTC entry time, got %s", got)
+	}
+	if got := withZone("Time"); got != "Time (UTC)" {
+		t.Fatalf("Unexpected header %q", got)
+	}
+
+	if err := SetTimeZone("America/New_York"); err != nil {
+		t.Skipf("No zoneinfo available: %v", err)
+	}
+	if got := displayEntryTime(entry); got != "10:30:00.250" {
+		t.Fatalf("Expected the entry time in New York (EDT), got %s", got)
+	}
+	if got := displayEntryTime(Trade{Time: "garbled"}); got != "garbled" {
+		t.Fatalf("Expected an unparsed time shown as received, got %s", got)
+	}
+
+	if err := SetTimeZone("Mars/Olympus_Mons"); err == nil {
+		t.Fatal("Expected an unknown zone to be rejected")
+	}
+}
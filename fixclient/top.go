@@ -0,0 +1,189 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"prime-fix-md-go/analytics"
+	"prime-fix-md-go/database"
+)
+
+const lastTimeFormat = "2006-01-02 15:04:05.000"
+
+// TopOfBook is the best bid/offer and last trade for one symbol, from in-memory state
+type TopOfBook struct {
+	Symbol     string
+	Bid        BookLevel
+	Offer      BookLevel
+	LastTrade  Trade
+	LastUpdate time.Time
+
+	HasBid, HasOffer, HasTrade bool
+}
+
+// Spread returns offer minus bid, or "" without both sides
+func (t TopOfBook) Spread() string {
+	if !t.HasBid || !t.HasOffer {
+		return ""
+	}
+	bid, errBid := analytics.ParseDecimal(t.Bid.Price)
+	offer, errOffer := analytics.ParseDecimal(t.Offer.Price)
+	if errBid != nil || errOffer != nil {
+		return ""
+	}
+	return offer.Sub(bid).String()
+}
+
+func (a *FixApp) topOfBook(symbol string) TopOfBook {
+	top := TopOfBook{Symbol: symbol}
+	if a.Books != nil {
+		if book, ok := a.Books.Get(symbol); ok {
+			top.Bid, top.HasBid = book.BestBid()
+			top.Offer, top.HasOffer = book.BestOffer()
+			top.LastUpdate = book.LastUpdate
+		}
+	}
+	if trade, ok := a.TradeStore.LastTrade(symbol); ok {
+		top.LastTrade, top.HasTrade = trade, true
+		if trade.Timestamp.After(top.LastUpdate) {
+			top.LastUpdate = trade.Timestamp
+		}
+	}
+	return top
+}
+
+func (a *FixApp) handleTopRequest(out output) {
+	subs := a.TradeStore.GetSubscriptionsBySymbol()
+	if len(subs) == 0 {
+		out.Info("No active subscriptions")
+		return
+	}
+
+	var rows [][]string
+	for _, symbol := range sortedSymbols(subs) {
+		top := a.topOfBook(symbol)
+		for _, sub := range subs[symbol] {
+			if sub.LastUpdate.After(top.LastUpdate) {
+				top.LastUpdate = sub.LastUpdate
+			}
+		}
+		rows = append(rows, a.topOfBookRow(top))
+	}
+
+	out.Table("Top of Book (in-memory):",
+		[]string{"Symbol", "Bid", "Bid Size", "Ask", "Ask Size", "Spread", "Last", "Last Size", withZone("Updated")}, rows)
+}
+
+// handleLastRequest prints the latest trade and best bid/ask for one symbol from memory, falling back
+// to the database for whatever has not been received this session
+func (a *FixApp) handleLastRequest(out output, parts []string) {
+	if len(parts) < 2 {
+		fmt.Fprintln(out.Console(), "Usage: last <symbol>")
+		return
+	}
+	symbol := strings.ToUpper(parts[1])
+	top := a.topOfBook(symbol)
+
+	price := func(p string) string { return a.formatPrice(symbol, p) }
+	size := func(s string) string { return a.formatSize(symbol, s) }
+
+	rows := make([][]string, 0, 3)
+	if top.HasTrade {
+		rows = append(rows, []string{"Last trade", price(top.LastTrade.Price), size(top.LastTrade.Size),
+			entryTimeDesc(top.LastTrade), "memory"})
+	} else if trade := a.latestStoredTrade(symbol); trade != nil {
+		rows = append(rows, []string{"Last trade", price(trade.Price), size(trade.Size),
+			displayTime(trade.TradeTime, lastTimeFormat), "database"})
+	}
+
+	if top.HasBid || top.HasOffer {
+		updated := lastUpdateDesc(top.LastUpdate)
+		if top.HasBid {
+			rows = append(rows, []string{"Best bid", price(top.Bid.Price), size(top.Bid.Size), updated, "memory"})
+		}
+		if top.HasOffer {
+			rows = append(rows, []string{"Best ask", price(top.Offer.Price), size(top.Offer.Size), updated, "memory"})
+		}
+	} else if a.Db != nil {
+		bid, offer, err := a.Db.LatestSnapshotTop(symbol)
+		if err != nil {
+			log.Printf("Failed to read stored book for %s: %v", symbol, err)
+		}
+		for _, level := range []struct {
+			name string
+			row  *database.OrderBookRow
+		}{{"Best bid", bid}, {"Best ask", offer}} {
+			if level.row != nil {
+				rows = append(rows, []string{level.name, price(level.row.Price), size(level.row.Size),
+					displayTime(level.row.ReceivedAt, lastTimeFormat), "database snapshot"})
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		out.Info("No trades or book data for %s", symbol)
+		return
+	}
+	out.Table(fmt.Sprintf("%s:", symbol), []string{"", "Price", "Size", withZone("Time"), "Source"}, rows)
+}
+
+func (a *FixApp) latestStoredTrade(symbol string) *database.TradeRow {
+	if a.Db == nil {
+		return nil
+	}
+	trade, err := a.Db.LatestTrade(symbol)
+	if err != nil {
+		log.Printf("Failed to read stored trades for %s: %v", symbol, err)
+	}
+	return trade
+}
+
+// entryTimeDesc prefers the exchange time of an entry over when it was received
+func entryTimeDesc(trade Trade) string {
+	if !trade.EntryTime.IsZero() {
+		return displayTime(trade.EntryTime, lastTimeFormat)
+	}
+	return lastUpdateDesc(trade.Timestamp)
+}
+
+func (a *FixApp) topOfBookRow(top TopOfBook) []string {
+	price := func(p string) string { return a.formatPrice(top.Symbol, p) }
+	size := func(s string) string { return a.formatSize(top.Symbol, s) }
+
+	row := []string{top.Symbol, "-", "-", "-", "-", orDash(price(top.Spread())), "-", "-", lastUpdateDesc(top.LastUpdate)}
+	if top.HasBid {
+		row[1], row[2] = price(top.Bid.Price), size(top.Bid.Size)
+	}
+	if top.HasOffer {
+		row[3], row[4] = price(top.Offer.Price), size(top.Offer.Size)
+	}
+	if top.HasTrade {
+		row[6], row[7] = price(top.LastTrade.Price), size(top.LastTrade.Size)
+	}
+	return row
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,86 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+func TestTopOfBook(t *testing.T) {
+	app := createTestFixApp()
+	app.Books = NewBookManager()
+	app.Books.ApplySnapshot("BTC-USD", []Trade{
+		bookEntry("0", "99.5", "1", "b1", ""),
+		bookEntry("0", "100", "2", "b2", ""),
+		bookEntry("1", "100.25", "3", "o1", ""),
+	}, time.Now())
+	app.TradeStore.AddTrades("BTC-USD", []Trade{
+		{EntryType: "2", Price: "100.1", Size: "0.5"},
+		{EntryType: "0", Price: "100", Size: "2"},
+	}, false, "md_1")
+
+	top := app.topOfBook("BTC-USD")
+	if top.Bid.Price != "100" || top.Offer.Price != "100.25" || top.Spread() != "0.25" {
+		t.Fatalf("Unexpected top of book %+v spread %s", top, top.Spread())
+	}
+	if !top.HasTrade || top.LastTrade.Price != "100.1" {
+		t.Fatalf("Expected last trade 100.1, got %+v", top.LastTrade)
+	}
+
+	if empty := app.topOfBook("ETH-USD"); empty.HasBid || empty.HasTrade || empty.Spread() != "" {
+		t.Fatalf("Expected empty top of book, got %+v", empty)
+	}
+}
+
+func TestTopCommandRendersSubscribedSymbols(t *testing.T) {
+	var buf bytes.Buffer
+	app := createTestFixApp()
+	app.Books = NewBookManager()
+	app.Renderer, _ = NewRenderer(OutputPlain, &buf)
+	app.TradeStore.AddSubscription("ETH-USD", "1", "md_1")
+
+	app.handleTopRequest(app.consoleOutput())
+	if !strings.Contains(buf.String(), "ETH-USD") {
+		t.Fatalf("Expected a row for ETH-USD, got %q", buf.String())
+	}
+}
+
+func TestLastFallsBackToDatabase(t *testing.T) {
+	db, err := database.NewMarketDataDb(filepath.Join(t.TempDir(), "last.db"))
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.StoreTrade("BTC-USD", "101", "1", "Buy", "20250101-12:00:02", 1, "req", false)
+
+	var buf bytes.Buffer
+	app := createTestFixApp()
+	app.Books = NewBookManager()
+	app.Db = db
+	app.Renderer, _ = NewRenderer(OutputPlain, &buf)
+
+	app.handleLastRequest(app.consoleOutput(), []string{"last", "btc-usd"})
+	if out := buf.String(); !strings.Contains(out, "101") || !strings.Contains(out, "database") {
+		t.Fatalf("Expected the stored trade, got %q", out)
+	}
+}
@@ -0,0 +1,47 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import "sync"
+
+// Batches that grew beyond this are left to the GC rather than pinned in the pool
+const maxPooledBatch = 10000
+
+// tradeBatches recycles the entry slices parsed from market data messages, so a busy
+// subscription does not allocate a fresh slice for every message.
+//
+// A batch belongs to handleMarketDataMessage until it returns. Everything it hands the
+// entries to uses them synchronously and copies what it keeps: the trade store appends
+// Trade values, books copy levels, the database and Arrow writers finish before returning,
+// renderers format copies and trade aggregation holds copies. Code that needs entries later must copy them as well.
+var tradeBatches = sync.Pool{
+	New: func() any { return new([]Trade) },
+}
+
+func getTradeBatch() *[]Trade {
+	return tradeBatches.Get().(*[]Trade)
+}
+
+// putTradeBatch empties b and returns it to the pool. Entries are zeroed so a pooled batch
+// does not keep the strings of an old message alive.
+func putTradeBatch(b *[]Trade) {
+	if cap(*b) > maxPooledBatch {
+		return
+	}
+	clear(*b)
+	*b = (*b)[:0]
+	tradeBatches.Put(b)
+}
@@ -0,0 +1,58 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import "testing"
+
+func TestPutTradeBatchClearsEntries(t *testing.T) {
+	batch := getTradeBatch()
+	*batch = append(*batch, Trade{Symbol: "BTC-USD", Price: "100"}, Trade{Symbol: "ETH-USD"})
+	entries := (*batch)[:2]
+	putTradeBatch(batch)
+
+	if len(*batch) != 0 {
+		t.Fatalf("Expected an empty batch, got %d entries", len(*batch))
+	}
+	if entries[0].Symbol != "" || entries[1].Symbol != "" {
+		t.Fatalf("Expected pooled entries to be zeroed, got %+v", entries)
+	}
+}
+
+func TestAppendTradesReusesBatch(t *testing.T) {
+	app := createTestFixApp()
+	msg := fullDepthSnapshot(2)
+
+	batch := make([]Trade, 0, 8)
+	trades := app.appendTrades(batch, msg, "BTC-USD", "req", true, "2")
+	if len(trades) != 4 || &trades[0] != &batch[:1][0] {
+		t.Fatalf("Expected 4 entries in the given batch, got %d", len(trades))
+	}
+
+	// Positions restart for each message appended to a batch
+	trades = app.appendTrades(trades, msg, "BTC-USD", "req", true, "3")
+	if len(trades) != 8 || trades[4].Position != "1" || trades[4].SeqNum != "3" {
+		t.Fatalf("Unexpected second message entries %+v", trades[4])
+	}
+}
+
+func TestPutTradeBatchSkipsLargeBatches(t *testing.T) {
+	batch := make([]Trade, 1, maxPooledBatch+1)
+	batch[0].Symbol = "BTC-USD"
+	putTradeBatch(&batch)
+	if len(batch) != 1 || batch[0].Symbol != "BTC-USD" {
+		t.Fatalf("Expected an oversized batch to be left alone")
+	}
+}
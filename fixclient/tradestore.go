@@ -18,23 +18,48 @@ package fixclient
 
 import (
 	"log"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
+
+	"prime-fix-md-go/analytics"
+	"prime-fix-md-go/builder"
+	"prime-fix-md-go/constants"
 )
 
 type Trade struct {
-	Timestamp  time.Time `json:"timestamp"`
-	Symbol     string    `json:"symbol"`
-	Price      string    `json:"price"`
-	Size       string    `json:"size"`
-	Time       string    `json:"time"`
-	Aggressor  string    `json:"aggressor"`
-	MdReqId    string    `json:"mdReqId"`
-	IsSnapshot bool      `json:"isSnapshot"`
-	IsUpdate   bool      `json:"isUpdate"`
-	EntryType  string    `json:"entryType"` // MdEntryType (0=Bid, 1=Offer, 2=Trade, 4=Open, 5=Close, 7=High, 8=Low, B=Volume)
-	Position   string    `json:"position"`  // Position in book (for bids/offers)
-	SeqNum     string    `json:"seqNum"`    // FIX MsgSeqNum for ordering
+	Timestamp        time.Time `json:"timestamp"`
+	Symbol           string    `json:"symbol"`
+	Price            string    `json:"price"`
+	Size             string    `json:"size"`
+	Time             string    `json:"time"`      // MdEntryTime (273) as sent
+	Date             string    `json:"date"`      // MdEntryDate (272) as sent, when separate from the time
+	EntryTime        time.Time `json:"entryTime"` // Date and time combined into a UTC timestamp; zero if unparseable
+	Aggressor        string    `json:"aggressor"`
+	MdReqId          string    `json:"mdReqId"`
+	IsSnapshot       bool      `json:"isSnapshot"`
+	IsUpdate         bool      `json:"isUpdate"`
+	EntryType        string    `json:"entryType"`                  // MdEntryType (0=Bid, 1=Offer, 2=Trade, 4=Open, 5=Close, 7=High, 8=Low, B=Volume)
+	Position         string    `json:"position"`                   // Position in book (for bids/offers)
+	NumOrders        string    `json:"numOrders,omitempty"`        // NumberOfOrders (346) at this level, when sent
+	EntryId          string    `json:"entryId,omitempty"`          // MdEntryId (278), used to match changes and deletes to the original entry
+	UpdateAction     string    `json:"updateAction,omitempty"`     // MdUpdateAction (279) on incremental entries: 0=New, 1=Change, 2=Delete
+	TradeCondition   string    `json:"tradeCondition,omitempty"`   // TradeCondition (277), space-separated codes
+	QuoteCondition   string    `json:"quoteCondition,omitempty"`   // QuoteCondition (276), space-separated codes
+	SecurityId       string    `json:"securityId,omitempty"`       // SecurityID (48), when sent
+	SecurityIdSource string    `json:"securityIdSource,omitempty"` // SecurityIDSource (22), when sent
+	RptSeq           string    `json:"rptSeq,omitempty"`           // RptSeq (83), per-instrument update sequence when sent
+	SeqNum           string    `json:"seqNum"`                     // FIX MsgSeqNum for ordering
+	Count            int       `json:"count,omitempty"`            // Trades coalesced into this print by trade aggregation
+
+	AggressorInferred bool `json:"aggressorInferred,omitempty"` // Aggressor came from md.inferAggressor, not AggressorSide (2446)
+
+	// Running size and notional from the best level, filled in on book entries for display only
+	CumSize     string `json:"-"`
+	CumNotional string `json:"-"`
+	Notional    string `json:"-"` // Price x size of a trade, filled in for display only
+	Large       bool   `json:"-"` // At or above the large print threshold
 }
 
 type TradeStore struct {
@@ -43,16 +68,126 @@ type TradeStore struct {
 	subscriptions map[string]*Subscription // reqId -> subscription info
 	updateCount   int64
 	maxSize       int
+	bytes         int64 // Estimated size of trades; see tradeBytes
+	dropped       int64 // Trades dropped from the front, so cursors stay valid; see TradesPage
+
+	session map[string]*analytics.TradeStats // Symbol -> streamed trades since start, kept as trades are dropped
+	rejects map[string][]SubscriptionReject  // Symbol -> latest rejects, at most maxSymbolRejects
+}
+
+// maxSymbolRejects is how many rejects are kept per symbol for status <symbol>
+const maxSymbolRejects = 5
+
+// SubscriptionReject is a rejected market data request, kept so status <symbol> can show it
+type SubscriptionReject struct {
+	MdReqId string
+	Detail  string
+	At      time.Time
 }
 
 type Subscription struct {
-	Symbol           string
+	Symbol           string // Symbol, or the SecurityID when subscribed by ID
+	SecurityIdSource string // Set when Symbol holds a SecurityID (48)
+	MarketDepth      string
+	EntryTypes       []string
+	Options          builder.MdRequestOptions
 	SubscriptionType string // "0"=snapshot, "1"=subscribe, "2"=unsubscribe
+	Label            string // md --label, naming what the capture is for
 	MdReqId          string
 	Active           bool
 	LastUpdate       time.Time
 	TotalUpdates     int64
+	Updates          UpdateCounts // TotalUpdates by entry type
 	SnapshotReceived bool
+	SubscribedAt     time.Time
+	SnapshotAt       time.Time // Latest snapshot, including resyncs; zero until one arrives
+
+	rates updateRates
+}
+
+// updateRateWindow is how many seconds RecentRate is averaged over
+const updateRateWindow = 10
+
+type rateBucket struct {
+	second int64
+	count  int64
+}
+
+// updateRates counts entries per second over the last updateRateWindow seconds
+type updateRates struct {
+	buckets [updateRateWindow]rateBucket
+}
+
+func (r *updateRates) add(n int64, now time.Time) {
+	second := now.Unix()
+	b := &r.buckets[second%updateRateWindow]
+	if b.second != second {
+		*b = rateBucket{second: second}
+	}
+	b.count += n
+}
+
+// RecentRate is entries per second over the last 10 seconds
+func (s *Subscription) RecentRate(now time.Time) float64 {
+	var recent int64
+	for _, b := range s.rates.buckets {
+		if b.second > now.Unix()-updateRateWindow {
+			recent += b.count
+		}
+	}
+	return float64(recent) / updateRateWindow
+}
+
+// AverageRate is entries per second since the subscription was made
+func (s *Subscription) AverageRate(now time.Time) float64 {
+	elapsed := now.Sub(s.SubscribedAt).Seconds()
+	if s.SubscribedAt.IsZero() || elapsed <= 0 {
+		return 0
+	}
+	return float64(s.TotalUpdates) / elapsed
+}
+
+// UpdateCounts breaks a subscription's entries down by entry type
+type UpdateCounts struct {
+	Bids   int64 `json:"bids"`
+	Offers int64 `json:"offers"`
+	Trades int64 `json:"trades"`
+	Ohlcv  int64 `json:"ohlcv"` // Open, close, high, low and volume
+	Other  int64 `json:"other,omitempty"`
+}
+
+func (c *UpdateCounts) add(entryType string) {
+	switch entryType {
+	case constants.MdEntryTypeBid:
+		c.Bids++
+	case constants.MdEntryTypeOffer:
+		c.Offers++
+	case constants.MdEntryTypeTrade:
+		c.Trades++
+	case constants.MdEntryTypeOpen, constants.MdEntryTypeClose, constants.MdEntryTypeHigh,
+		constants.MdEntryTypeLow, constants.MdEntryTypeVolume:
+		c.Ohlcv++
+	default:
+		c.Other++
+	}
+}
+
+// HasBook reports whether the subscription streams bids or offers
+func (s *Subscription) HasBook() bool {
+	for _, entryType := range s.EntryTypes {
+		if entryType == constants.MdEntryTypeBid || entryType == constants.MdEntryTypeOffer {
+			return true
+		}
+	}
+	return false
+}
+
+// Instrument rebuilds the identifier the subscription was requested with
+func (s *Subscription) Instrument() builder.Instrument {
+	if s.SecurityIdSource != "" {
+		return builder.Instrument{SecurityId: s.Symbol, SecurityIdSource: s.SecurityIdSource}
+	}
+	return builder.Instrument{Symbol: s.Symbol}
 }
 
 func NewTradeStore(maxSize int, persistenceFile string) *TradeStore {
@@ -60,18 +195,27 @@ func NewTradeStore(maxSize int, persistenceFile string) *TradeStore {
 		trades:        make([]Trade, 0),
 		subscriptions: make(map[string]*Subscription),
 		maxSize:       maxSize,
+		session:       make(map[string]*analytics.TradeStats),
+		rejects:       make(map[string][]SubscriptionReject),
 	}
 }
 
-func (ts *TradeStore) AddTrades(symbol string, trades []Trade, isSnapshot bool, mdReqId string) {
+// AddTrades stores the entries of one message and reports whether it was the first snapshot
+// of a tracked subscription
+func (ts *TradeStore) AddTrades(symbol string, trades []Trade, isSnapshot bool, mdReqId string) (firstSnapshot bool) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 
-	if sub, exists := ts.subscriptions[mdReqId]; exists {
-		sub.LastUpdate = time.Now()
+	sub := ts.subscriptions[mdReqId]
+	if sub != nil {
+		now := time.Now()
+		sub.LastUpdate = now
 		sub.TotalUpdates += int64(len(trades))
+		sub.rates.add(int64(len(trades)), now)
 		if isSnapshot {
+			firstSnapshot = !sub.SnapshotReceived
 			sub.SnapshotReceived = true
+			sub.SnapshotAt = now
 		}
 	}
 
@@ -81,13 +225,104 @@ func (ts *TradeStore) AddTrades(symbol string, trades []Trade, isSnapshot bool,
 		trade.MdReqId = mdReqId
 		trade.IsSnapshot = isSnapshot
 		trade.IsUpdate = !isSnapshot
+		if sub != nil {
+			sub.Updates.add(trade.EntryType)
+		}
 
 		if len(ts.trades) >= ts.maxSize {
+			ts.bytes -= tradeBytes(ts.trades[0])
 			ts.trades = ts.trades[1:]
+			ts.dropped++
 		}
 		ts.trades = append(ts.trades, trade)
+		ts.bytes += tradeBytes(trade)
 		ts.updateCount++
+
+		// Snapshots can repeat recent trades, so only the stream counts
+		if !isSnapshot && trade.EntryType == constants.MdEntryTypeTrade {
+			stats, ok := ts.session[symbol]
+			if !ok {
+				stats = &analytics.TradeStats{}
+				ts.session[symbol] = stats
+			}
+			stats.Add(trade.Price, trade.Size)
+		}
+	}
+	return firstSnapshot
+}
+
+// RecordReject keeps a rejected request of symbol, dropping the oldest beyond maxSymbolRejects
+func (ts *TradeStore) RecordReject(symbol string, rej SubscriptionReject) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	rejects := append(ts.rejects[symbol], rej)
+	if len(rejects) > maxSymbolRejects {
+		rejects = rejects[len(rejects)-maxSymbolRejects:]
 	}
+	ts.rejects[symbol] = rejects
+}
+
+// Rejects returns the latest rejected requests of symbol, oldest first
+func (ts *TradeStore) Rejects(symbol string) []SubscriptionReject {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	return append([]SubscriptionReject(nil), ts.rejects[symbol]...)
+}
+
+// RecentEntries returns up to limit of the latest entries received for mdReqId, oldest first
+func (ts *TradeStore) RecentEntries(mdReqId string, limit int) []Trade {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	var recent []Trade
+	for i := len(ts.trades) - 1; i >= 0 && len(recent) < limit; i-- {
+		if ts.trades[i].MdReqId == mdReqId {
+			recent = append(recent, ts.trades[i])
+		}
+	}
+	slices.Reverse(recent)
+	return recent
+}
+
+// SessionStats returns copies of the running stats of each symbol's streamed trades since start
+func (ts *TradeStore) SessionStats() map[string]analytics.TradeStats {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	result := make(map[string]analytics.TradeStats, len(ts.session))
+	for symbol, stats := range ts.session {
+		result[symbol] = *stats
+	}
+	return result
+}
+
+// Usage returns how many trades are held and their estimated size
+func (ts *TradeStore) Usage() (int, int64) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	return len(ts.trades), ts.bytes
+}
+
+// ShedOldest drops the oldest trades until at least entries and bytes have been freed or the
+// store is empty, and returns how many were dropped
+func (ts *TradeStore) ShedOldest(entries int, bytes int64) int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	n := 0
+	var freed int64
+	for n < len(ts.trades) && (n < entries || freed < bytes) {
+		freed += tradeBytes(ts.trades[n])
+		n++
+	}
+	if n > 0 {
+		// A fresh slice, so the dropped trades are not kept alive by the old backing array
+		ts.trades = slices.Clone(ts.trades[n:])
+		ts.bytes -= freed
+		ts.dropped += int64(n)
+	}
+	return n
 }
 
 func (ts *TradeStore) GetRecentTrades(symbol string, limit int) []Trade {
@@ -108,6 +343,51 @@ func (ts *TradeStore) GetRecentTrades(symbol string, limit int) []Trade {
 	return recent
 }
 
+// TradesPage returns up to limit entries for symbol (every symbol when empty), oldest first,
+// starting at cursor (empty for the oldest held) and the cursor of the next page, which is empty
+// when nothing more is held. Cursors count entries since the store was created, so they stay
+// valid while old entries are dropped; one pointing at dropped entries continues at the oldest.
+func (ts *TradeStore) TradesPage(symbol, cursor string, limit int) ([]Trade, string, error) {
+	if limit <= 0 {
+		return nil, "", invalidRequest("page size must be positive, got %d", limit)
+	}
+	var position int64
+	if cursor != "" {
+		var err error
+		if position, err = strconv.ParseInt(cursor, 10, 64); err != nil || position < 0 {
+			return nil, "", invalidRequest("invalid cursor %q", cursor)
+		}
+	}
+
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	page := []Trade{}
+	for i := max(position-ts.dropped, 0); i < int64(len(ts.trades)); i++ {
+		if symbol != "" && ts.trades[i].Symbol != symbol {
+			continue
+		}
+		if len(page) == limit {
+			return page, strconv.FormatInt(ts.dropped+i, 10), nil
+		}
+		page = append(page, ts.trades[i])
+	}
+	return page, "", nil
+}
+
+// LastTrade returns the most recent trade entry (not book or OHLCV) for symbol
+func (ts *TradeStore) LastTrade(symbol string) (Trade, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	for i := len(ts.trades) - 1; i >= 0; i-- {
+		trade := ts.trades[i]
+		if trade.Symbol == symbol && (trade.EntryType == "" || trade.EntryType == constants.MdEntryTypeTrade) {
+			return trade, true
+		}
+	}
+	return Trade{}, false
+}
+
 func (ts *TradeStore) GetAllTrades() []Trade {
 	ts.mu.RLock()
 	defer ts.mu.RUnlock()
@@ -117,18 +397,59 @@ func (ts *TradeStore) GetAllTrades() []Trade {
 	return result
 }
 
+// GetTradeStats aggregates the in-memory trade entries per symbol (volume, notional, VWAP, range)
+func (ts *TradeStore) GetTradeStats() map[string]*analytics.TradeStats {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	result := make(map[string]*analytics.TradeStats)
+	for _, trade := range ts.trades {
+		if trade.EntryType != constants.MdEntryTypeTrade && trade.EntryType != "" {
+			continue
+		}
+		stats, ok := result[trade.Symbol]
+		if !ok {
+			stats = &analytics.TradeStats{}
+			result[trade.Symbol] = stats
+		}
+		if err := stats.Add(trade.Price, trade.Size); err != nil {
+			continue // Skip malformed entries rather than poisoning the aggregate
+		}
+	}
+	return result
+}
+
 func (ts *TradeStore) AddSubscription(symbol, subscriptionType, mdReqId string) {
+	ts.AddInstrumentSubscription(builder.Instrument{Symbol: symbol}, subscriptionType, mdReqId, "", nil, builder.MdRequestOptions{}, "")
+}
+
+// AddInstrumentSubscription tracks a subscription keyed by the instrument's symbol or SecurityID.
+// Depth and entry types are kept so the request can be repeated, e.g. to resync the book.
+func (ts *TradeStore) AddInstrumentSubscription(instrument builder.Instrument, subscriptionType, mdReqId, marketDepth string,
+	entryTypes []string, options builder.MdRequestOptions, label string) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 
+	symbol := instrument.Key()
+	securityIdSource := ""
+	if instrument.Symbol == "" {
+		securityIdSource = instrument.SecurityIdSource
+	}
+
 	ts.subscriptions[mdReqId] = &Subscription{
 		Symbol:           symbol,
+		SecurityIdSource: securityIdSource,
+		MarketDepth:      marketDepth,
+		EntryTypes:       entryTypes,
+		Options:          options,
 		SubscriptionType: subscriptionType,
+		Label:            label,
 		MdReqId:          mdReqId,
 		Active:           true,
 		LastUpdate:       time.Now(),
 		TotalUpdates:     0,
 		SnapshotReceived: false,
+		SubscribedAt:     time.Now(),
 	}
 
 	log.Printf("Added subscription: %s (type=%s, reqId=%s)", symbol, getSubscriptionTypeDesc(subscriptionType), mdReqId)
@@ -156,6 +477,14 @@ func (ts *TradeStore) RemoveSubscriptionByReqId(reqId string) {
 	}
 }
 
+// IsSubscription reports whether reqId belongs to a live subscription
+func (ts *TradeStore) IsSubscription(reqId string) bool {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	_, ok := ts.subscriptions[reqId]
+	return ok
+}
+
 func (ts *TradeStore) GetSubscriptionStatus() map[string]*Subscription {
 	ts.mu.RLock()
 	defer ts.mu.RUnlock()
@@ -194,40 +523,3 @@ func getSubscriptionTypeDesc(subType string) string {
 		return "Unknown"
 	}
 }
-
-// DisplayRealtimeUpdate shows a single line update for streaming mode
-func (ts *TradeStore) DisplayRealtimeUpdate(trade Trade) {
-	entryType := trade.EntryType
-	if entryType == "" {
-		entryType = "2" // Default to Trade
-	}
-
-	switch entryType {
-	case "0": // Bid
-		log.Printf("%s Bid: %s | Size: %s | Pos: %s",
-			trade.Symbol, trade.Price, trade.Size, trade.Position)
-	case "1": // Offer
-		log.Printf("%s Offer: %s | Size: %s | Pos: %s",
-			trade.Symbol, trade.Price, trade.Size, trade.Position)
-	case "2": // Trade
-		aggressor := trade.Aggressor
-		if aggressor == "" {
-			aggressor = "-"
-		}
-		log.Printf("%s Trade: %s | Size: %s | Aggressor: %s",
-			trade.Symbol, trade.Price, trade.Size, aggressor)
-	case "4": // Open
-		log.Printf("%s Open: %s", trade.Symbol, trade.Price)
-	case "5": // Close
-		log.Printf("%s Close: %s", trade.Symbol, trade.Price)
-	case "7": // High
-		log.Printf("%s High: %s", trade.Symbol, trade.Price)
-	case "8": // Low
-		log.Printf("%s Low: %s", trade.Symbol, trade.Price)
-	case "B": // Volume
-		log.Printf("%s Volume: %s", trade.Symbol, trade.Size)
-	default: // Unknown
-		log.Printf("%s [%s]: %s | Size: %s",
-			trade.Symbol, entryType, trade.Price, trade.Size)
-	}
-}
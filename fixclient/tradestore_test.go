@@ -21,6 +21,9 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"prime-fix-md-go/builder"
+	"prime-fix-md-go/constants"
 )
 
 func TestNewTradeStore(t *testing.T) {
@@ -262,3 +265,118 @@ func TestMultipleSymbols(t *testing.T) {
 		t.Fatalf("Expected ETH-USD, got %s", ethRecent[0].Symbol)
 	}
 }
+
+func TestGetTradeStats(t *testing.T) {
+	store := NewTradeStore(1000, "")
+
+	store.AddTrades("BTC-USD", []Trade{
+		{EntryType: "2", Price: "50000.00", Size: "0.5"},
+		{EntryType: "2", Price: "50100.00", Size: "0.5"},
+		{EntryType: "0", Price: "49000.00", Size: "10"}, // Bids are not trades
+	}, false, "req-123")
+
+	stats := store.GetTradeStats()["BTC-USD"]
+	if stats == nil || stats.Count != 2 {
+		t.Fatalf("Expected 2 trades in stats, got %+v", stats)
+	}
+	if got := stats.FormatPrice(stats.Vwap()); got != "50050.00" {
+		t.Fatalf("Expected VWAP 50050.00, got %s", got)
+	}
+}
+
+func TestUpdateCountsByEntryType(t *testing.T) {
+	store := NewTradeStore(2, "")
+	store.AddInstrumentSubscription(builder.Instrument{Symbol: "BTC-USD"}, "1", "req-1", "10",
+		[]string{constants.MdEntryTypeBid, constants.MdEntryTypeOffer}, builder.MdRequestOptions{}, "")
+
+	store.AddTrades("BTC-USD", []Trade{
+		{EntryType: constants.MdEntryTypeOffer, Price: "101", Size: "1"},
+		{EntryType: constants.MdEntryTypeOffer, Price: "102", Size: "1"},
+		{EntryType: constants.MdEntryTypeTrade, Price: "101", Size: "1"},
+		{EntryType: constants.MdEntryTypeVolume, Price: "100"},
+	}, false, "req-1")
+
+	sub := store.GetSubscriptionsBySymbol()["BTC-USD"][0]
+	if sub.Updates != (UpdateCounts{Offers: 2, Trades: 1, Ohlcv: 1}) || sub.TotalUpdates != 4 {
+		t.Fatalf("Unexpected counts %+v of %d", sub.Updates, sub.TotalUpdates)
+	}
+	if got := updateCountsDesc(sub); got != "B 0 O 2 T 1 OHLCV 1" {
+		t.Fatalf("Expected the missing bids to show, got %q", got)
+	}
+}
+
+func TestTradesPageSurvivesDroppedTrades(t *testing.T) {
+	store := NewTradeStore(4, "")
+	add := func(symbol string, prices ...int) {
+		var trades []Trade
+		for _, p := range prices {
+			trades = append(trades, Trade{Price: strconv.Itoa(p)})
+		}
+		store.AddTrades(symbol, trades, false, "req-1")
+	}
+	add("BTC-USD", 1, 2)
+	add("ETH-USD", 3)
+	add("BTC-USD", 4)
+
+	page, next, err := store.TradesPage("BTC-USD", "", 2)
+	if err != nil || len(page) != 2 || page[0].Price != "1" || page[1].Price != "2" || next == "" {
+		t.Fatalf("Unexpected first page %v %q %v", page, next, err)
+	}
+
+	// Trades 1 and 2 are pushed out; the cursor still points after them
+	add("BTC-USD", 5, 6)
+	page, next, err = store.TradesPage("BTC-USD", next, 2)
+	if err != nil || len(page) != 2 || page[0].Price != "4" || page[1].Price != "5" || next == "" {
+		t.Fatalf("Unexpected second page %v %q %v", page, next, err)
+	}
+	page, next, err = store.TradesPage("BTC-USD", next, 2)
+	if err != nil || len(page) != 1 || page[0].Price != "6" || next != "" {
+		t.Fatalf("Unexpected last page %v %q %v", page, next, err)
+	}
+
+	if _, _, err := store.TradesPage("", "abc", 2); err == nil {
+		t.Fatal("Expected an invalid cursor to be rejected")
+	}
+}
+
+func TestRejectsKeepLatestPerSymbol(t *testing.T) {
+	store := NewTradeStore(10, "")
+	for i := 1; i <= maxSymbolRejects+2; i++ {
+		store.RecordReject("BTC-USD", SubscriptionReject{MdReqId: "req-" + strconv.Itoa(i), Detail: "unknown symbol"})
+	}
+
+	rejects := store.Rejects("BTC-USD")
+	if len(rejects) != maxSymbolRejects || rejects[0].MdReqId != "req-3" || rejects[len(rejects)-1].MdReqId != "req-7" {
+		t.Fatalf("Expected the latest %d rejects, oldest first, got %+v", maxSymbolRejects, rejects)
+	}
+	if len(store.Rejects("ETH-USD")) != 0 {
+		t.Fatal("Expected no rejects for another symbol")
+	}
+}
+
+func TestRecentEntriesAndRates(t *testing.T) {
+	store := NewTradeStore(100, "")
+	store.AddInstrumentSubscription(builder.Instrument{Symbol: "BTC-USD"}, "1", "req-1", "0",
+		[]string{constants.MdEntryTypeTrade}, builder.MdRequestOptions{}, "")
+
+	store.AddTrades("BTC-USD", []Trade{{Price: "1", MdReqId: "req-1"}, {Price: "2", MdReqId: "req-1"}}, true, "req-1")
+	store.AddTrades("ETH-USD", []Trade{{Price: "9", MdReqId: "req-2"}}, false, "req-2")
+	store.AddTrades("BTC-USD", []Trade{{Price: "3", MdReqId: "req-1"}}, false, "req-1")
+
+	recent := store.RecentEntries("req-1", 2)
+	if len(recent) != 2 || recent[0].Price != "2" || recent[1].Price != "3" {
+		t.Fatalf("Expected the last 2 entries of req-1, oldest first, got %+v", recent)
+	}
+
+	sub := store.GetSubscriptionsBySymbol()["BTC-USD"][0]
+	if sub.SnapshotAt.IsZero() || sub.SubscribedAt.IsZero() {
+		t.Fatalf("Expected subscribe and snapshot times, got %+v", sub)
+	}
+	now := time.Now()
+	if got := sub.RecentRate(now); got != 0.3 {
+		t.Fatalf("Expected 3 entries over 10s, got %v/s", got)
+	}
+	if got := sub.RecentRate(now.Add(updateRateWindow * time.Second)); got != 0 {
+		t.Fatalf("Expected the rate to fall to 0 once the window passes, got %v/s", got)
+	}
+}
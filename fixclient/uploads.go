@@ -0,0 +1,106 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+var errUploadsDisabled = errors.New("uploads are not configured (set upload.provider and upload.bucket)")
+
+// Uploader copies local files to object storage; *upload.Client is the implementation
+type Uploader interface {
+	Key(localPath string) string
+	UploadFile(ctx context.Context, localPath, key string) error
+	Location(key string) string
+}
+
+// SetUploader sets the object storage for the upload command; nil disables uploads. With
+// exports set, every export file is uploaded after it is written, and with archives every
+// archive file.
+func (a *FixApp) SetUploader(uploader Uploader, exports, archives bool) {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	a.uploader = uploader
+	a.uploadExport = exports
+	a.uploadArchive = archives
+}
+
+// uploads returns the uploader and whether exports and archives are uploaded
+func (a *FixApp) uploads() (Uploader, bool, bool) {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.uploader, a.uploadExport, a.uploadArchive
+}
+
+func (a *FixApp) uploadFile(out output, localPath, key string) error {
+	uploader, _, _ := a.uploads()
+	if uploader == nil {
+		return errUploadsDisabled
+	}
+	if key == "" {
+		key = uploader.Key(localPath)
+	}
+	if err := uploader.UploadFile(context.Background(), localPath, key); err != nil {
+		return err
+	}
+	out.Info("Uploaded %s to %s", localPath, uploader.Location(key))
+	return nil
+}
+
+// exported is called after an export file is written and uploads it when upload.exports is set
+func (a *FixApp) exported(out output, path string) {
+	if uploader, exports, _ := a.uploads(); uploader == nil || !exports {
+		return
+	}
+	if err := a.uploadFile(out, path, ""); err != nil {
+		out.Error(fmt.Errorf("export written to %s but not uploaded: %w", path, err))
+	}
+}
+
+// archived is called after archiving writes a compressed database and uploads it when
+// upload.archives is set. Archiving runs in the background, so the outcome is logged.
+func (a *FixApp) archived(path string) {
+	uploader, _, archives := a.uploads()
+	if uploader == nil || !archives {
+		return
+	}
+	key := uploader.Key(path)
+	if err := uploader.UploadFile(context.Background(), path, key); err != nil {
+		log.Printf("Archive written to %s but not uploaded: %v", path, err)
+		return
+	}
+	log.Printf("Uploaded %s to %s", path, uploader.Location(key))
+}
+
+func (a *FixApp) handleUploadRequest(out output, parts []string) {
+	if len(parts) < 2 || len(parts) > 3 {
+		fmt.Fprintln(out.Console(), "Usage: upload <file> [key]")
+		return
+	}
+
+	key := ""
+	if len(parts) == 3 {
+		key = parts[2]
+	}
+	if err := a.uploadFile(out, parts[1], key); err != nil {
+		out.Error(err)
+	}
+}
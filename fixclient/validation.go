@@ -0,0 +1,123 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"strconv"
+
+	"prime-fix-md-go/constants"
+)
+
+var validEntryTypes = map[string]bool{
+	constants.MdEntryTypeBid:    true,
+	constants.MdEntryTypeOffer:  true,
+	constants.MdEntryTypeTrade:  true,
+	constants.MdEntryTypeOpen:   true,
+	constants.MdEntryTypeClose:  true,
+	constants.MdEntryTypeHigh:   true,
+	constants.MdEntryTypeLow:    true,
+	constants.MdEntryTypeVolume: true,
+}
+
+var (
+	bookEntryTypes  = []string{constants.MdEntryTypeBid, constants.MdEntryTypeOffer}
+	ohlcvEntryTypes = []string{constants.MdEntryTypeOpen, constants.MdEntryTypeClose, constants.MdEntryTypeHigh,
+		constants.MdEntryTypeLow, constants.MdEntryTypeVolume}
+)
+
+// Entry type presets for md; they add to any individually given entry types
+var mdEntryPresets = map[string][]string{
+	"--l1":    bookEntryTypes, // also defaults --depth to 1
+	"--book":  bookEntryTypes,
+	"--ohlcv": ohlcvEntryTypes,
+	"--all":   append(append([]string{constants.MdEntryTypeTrade}, bookEntryTypes...), ohlcvEntryTypes...),
+}
+
+// validateMdRequest checks a request against the options the gateway supports before it is sent,
+// so mistakes surface as clear errors instead of a 35=Y. Combinations that are accepted but
+// probably not what was meant come back as warnings.
+func validateMdRequest(symbols []string, subscriptionType, marketDepth string, entryTypes []string) ([]string, error) {
+	var warnings []string
+
+	if len(symbols) == 0 {
+		return nil, invalidRequest("at least one symbol is required")
+	}
+
+	switch subscriptionType {
+	case constants.SubscriptionRequestTypeSnapshot, constants.SubscriptionRequestTypeSubscribe,
+		constants.SubscriptionRequestTypeUnsubscribe:
+	default:
+		return nil, invalidRequest("unknown subscription type %q", subscriptionType)
+	}
+
+	depth, err := strconv.Atoi(marketDepth)
+	if err != nil || depth < 0 {
+		return nil, invalidRequest("market depth %q must be a non-negative integer (0=full book, 1=top of book, N=best N levels)", marketDepth)
+	}
+
+	if len(entryTypes) == 0 {
+		return nil, invalidRequest("at least one entry type is required")
+	}
+
+	hasBook := false
+	for _, entryType := range entryTypes {
+		if !validEntryTypes[entryType] {
+			return nil, invalidRequest("unsupported entry type %q", entryType)
+		}
+		if entryType == constants.MdEntryTypeBid || entryType == constants.MdEntryTypeOffer {
+			hasBook = true
+		}
+	}
+
+	if depth > 0 && !hasBook {
+		warnings = append(warnings, "--depth only applies to bids and offers; it has no effect on trades or OHLCV")
+	}
+
+	return warnings, nil
+}
+
+// dedupeEntryTypes drops repeated entry types while keeping the order they were given in
+func dedupeEntryTypes(entryTypes []string) []string {
+	seen := make(map[string]bool, len(entryTypes))
+	result := make([]string, 0, len(entryTypes))
+	for _, entryType := range entryTypes {
+		if !seen[entryType] {
+			seen[entryType] = true
+			result = append(result, entryType)
+		}
+	}
+	return result
+}
+
+// validateSymbols checks symbols against the product catalog. With an empty catalog there is
+// nothing to check against, so every symbol is allowed.
+func (a *FixApp) validateSymbols(symbols []string) error {
+	if a.Products == nil || a.Products.Len() == 0 {
+		return nil
+	}
+
+	for _, symbol := range symbols {
+		if _, ok := a.Products.Get(symbol); ok {
+			continue
+		}
+		if suggestion, ok := a.Products.Suggest(symbol); ok {
+			return invalidRequest("unknown symbol %s (did you mean %s?)", symbol, suggestion)
+		}
+		return invalidRequest("unknown symbol %s", symbol)
+	}
+	return nil
+}
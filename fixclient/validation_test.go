@@ -0,0 +1,157 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fixclient
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"prime-fix-md-go/constants"
+	"prime-fix-md-go/products"
+)
+
+func TestValidateMdRequest(t *testing.T) {
+	book := []string{constants.MdEntryTypeBid, constants.MdEntryTypeOffer}
+	trades := []string{constants.MdEntryTypeTrade}
+
+	testCases := []struct {
+		name       string
+		symbols    []string
+		depth      string
+		entryTypes []string
+		wantErr    bool
+		wantWarn   bool
+	}{
+		{"Full book", []string{"BTC-USD"}, "0", book, false, false},
+		{"L10 book", []string{"BTC-USD"}, "10", book, false, false},
+		{"Negative depth", []string{"BTC-USD"}, "-1", book, true, false},
+		{"Non-numeric depth", []string{"BTC-USD"}, "ten", book, true, false},
+		{"No symbols", nil, "0", trades, true, false},
+		{"No entry types", []string{"BTC-USD"}, "0", nil, true, false},
+		{"Unknown entry type", []string{"BTC-USD"}, "0", []string{"Z"}, true, false},
+		{"Depth with trades only", []string{"BTC-USD"}, "5", trades, false, true},
+		{"Depth with OHLCV only", []string{"BTC-USD"}, "1", []string{constants.MdEntryTypeOpen}, false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := validateMdRequest(tc.symbols, constants.SubscriptionRequestTypeSnapshot, tc.depth, tc.entryTypes)
+			if tc.wantErr {
+				if !errors.Is(err, ErrInvalidRequest) {
+					t.Fatalf("Expected ErrInvalidRequest, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if (len(warnings) > 0) != tc.wantWarn {
+				t.Fatalf("Expected warning=%v, got %v", tc.wantWarn, warnings)
+			}
+		})
+	}
+}
+
+func TestParseMdFlagsErrors(t *testing.T) {
+	app := createTestFixApp()
+
+	if _, err := app.parseMdFlags([]string{"--snapshot", "--depth"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("Expected error for --depth without value, got %v", err)
+	}
+	if _, err := app.parseMdFlags([]string{"--snapshot", "--trade"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("Expected error for unknown flag, got %v", err)
+	}
+
+	flags, err := app.parseMdFlags([]string{"--subscribe", "--trades", "--trades", "--depth", "1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := dedupeEntryTypes(flags.entryTypes); len(got) != 1 {
+		t.Fatalf("Expected duplicate entry types removed, got %v", got)
+	}
+}
+
+func TestParseMdFlagsPresets(t *testing.T) {
+	app := createTestFixApp()
+
+	flags, err := app.parseMdFlags([]string{"--subscribe", "--l1", "--trades"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if flags.marketDepth != "1" || len(flags.entryTypes) != 3 {
+		t.Fatalf("Expected depth 1 with bids, offers and trades, got depth=%q types=%v", flags.marketDepth, flags.entryTypes)
+	}
+
+	flags, err = app.parseMdFlags([]string{"--snapshot", "--all", "--book"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := dedupeEntryTypes(flags.entryTypes); len(got) != len(validEntryTypes) {
+		t.Fatalf("Expected --all to cover every entry type, got %v", got)
+	}
+
+	if _, err := app.parseMdFlags([]string{"--snapshot", "--l1", "--depth", "10"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("Expected error for --l1 with --depth 10, got %v", err)
+	}
+}
+
+func TestValidateSymbols(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+
+	// Nothing to validate against yet
+	if err := app.validateSymbols([]string{"ANYTHING"}); err != nil {
+		t.Fatalf("Expected no error with empty catalog, got %v", err)
+	}
+
+	app.Products.Replace([]products.Product{{Symbol: "BTC-USD"}, {Symbol: "ETH-USD"}})
+	if err := app.validateSymbols([]string{"BTC-USD", "ETH-USD"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err := app.validateSymbols([]string{"BTC-USD", "BTCUSD"})
+	if !errors.Is(err, ErrInvalidRequest) || !strings.Contains(err.Error(), "did you mean BTC-USD?") {
+		t.Fatalf("Expected suggestion, got %v", err)
+	}
+}
+
+func TestMdDefaults(t *testing.T) {
+	app := createTestFixApp()
+	depth := 5
+	if err := app.SetMdDefaults("subscribe", &depth, []string{"book"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var flags MdRequestFlags
+	app.applyMdDefaults(&flags)
+	if flags.subscriptionType != constants.SubscriptionRequestTypeSubscribe || flags.marketDepth != "5" || len(flags.entryTypes) != 2 {
+		t.Fatalf("Expected defaults to fill the request, got %+v", flags)
+	}
+
+	flags, _ = app.parseMdFlags([]string{"--snapshot", "--trades"})
+	app.applyMdDefaults(&flags)
+	if flags.subscriptionType != constants.SubscriptionRequestTypeSnapshot || len(flags.entryTypes) != 1 || flags.marketDepth != "5" {
+		t.Fatalf("Expected given flags to take precedence, got %+v", flags)
+	}
+
+	if err := app.SetMdDefaults("unsubscribe", nil, nil); err == nil {
+		t.Fatalf("Expected error for unsubscribe default")
+	}
+	if err := app.SetMdDefaults("", nil, []string{"bogus"}); err == nil {
+		t.Fatalf("Expected error for unknown entry type")
+	}
+}
@@ -0,0 +1,306 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// watchlistEntry is one symbol of an imported watchlist, with optional overrides of the md flags
+type watchlistEntry struct {
+	Symbol     string   `json:"symbol"`
+	Depth      *int     `json:"depth,omitempty"`
+	EntryTypes []string `json:"entryTypes,omitempty"` // md flag names without "--", e.g. "trades", "l1"
+}
+
+// overrideArgs is the md flags for the entry's overrides
+func (e watchlistEntry) overrideArgs() []string {
+	var args []string
+	if e.Depth != nil {
+		args = append(args, "--depth", strconv.Itoa(*e.Depth))
+	}
+	for _, entryType := range e.EntryTypes {
+		args = append(args, "--"+entryType)
+	}
+	return args
+}
+
+// md flags replaced by a per-symbol entry type override
+var entryTypeFlags = []string{"--trades", "--bids", "--offers", "--o", "--c", "--h", "--l", "--v", "--l1", "--book", "--ohlcv", "--all"}
+
+// parseWatchlist reads a symbol list. JSON files (by extension) hold an array of symbols or of
+// {"symbol", "depth", "entryTypes"} objects. Anything else is read as CSV with the columns
+// symbol, depth and entryTypes, the last two optional and an optional header row; entry types
+// within a cell are separated by spaces, ; or |.
+func (a *FixApp) parseWatchlist(path string, data []byte) ([]watchlistEntry, error) {
+	var entries []watchlistEntry
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		entries, err = parseWatchlistJson(data)
+	} else {
+		entries, err = parseWatchlistCsv(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, invalidRequest("%s has no symbols", path)
+	}
+
+	seen := make(map[string]bool)
+	for i := range entries {
+		e := &entries[i]
+		e.Symbol = strings.ToUpper(strings.TrimSpace(e.Symbol))
+		if e.Symbol == "" {
+			return nil, invalidRequest("entry %d has no symbol", i+1)
+		}
+		if seen[e.Symbol] {
+			return nil, invalidRequest("%s is listed twice", e.Symbol)
+		}
+		seen[e.Symbol] = true
+		for j, entryType := range e.EntryTypes {
+			e.EntryTypes[j] = strings.TrimPrefix(strings.ToLower(entryType), "--")
+		}
+		if _, err := a.parseMdFlags(e.overrideArgs()); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Symbol, err)
+		}
+		if e.Depth != nil && *e.Depth < 0 {
+			return nil, invalidRequest("%s: depth must be 0 (full book) or more", e.Symbol)
+		}
+	}
+	return entries, nil
+}
+
+func parseWatchlistJson(data []byte) ([]watchlistEntry, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, invalidRequest("expected a JSON array of symbols or objects: %v", err)
+	}
+	entries := make([]watchlistEntry, 0, len(items))
+	for i, item := range items {
+		var e watchlistEntry
+		if err := json.Unmarshal(item, &e.Symbol); err != nil {
+			dec := json.NewDecoder(bytes.NewReader(item))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&e); err != nil {
+				return nil, invalidRequest("entry %d: %v", i+1, err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func parseWatchlistCsv(data []byte) ([]watchlistEntry, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var entries []watchlistEntry
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, invalidRequest("%v", err)
+		}
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "symbol") {
+			continue
+		}
+		line, _ := r.FieldPos(0)
+		if len(record) > 3 {
+			return nil, invalidRequest("line %d: expected symbol, depth, entryTypes", line)
+		}
+		e := watchlistEntry{Symbol: record[0]}
+		if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+			depth, err := strconv.Atoi(strings.TrimSpace(record[1]))
+			if err != nil {
+				return nil, invalidRequest("line %d: invalid depth %q", line, record[1])
+			}
+			e.Depth = &depth
+		}
+		if len(record) > 2 {
+			e.EntryTypes = strings.FieldsFunc(record[2], func(r rune) bool { return r == ' ' || r == ';' || r == '|' })
+		}
+		entries = append(entries, e)
+	}
+}
+
+// watchlistRequests groups the watchlist by override into md requests of at most
+// allProductsBatchSize symbols each. flags are the md flags given on the command line; a
+// symbol's depth or entry types replace the ones among them.
+func watchlistRequests(entries []watchlistEntry, flags []string) [][]string {
+	var order []string
+	groups := make(map[string][]watchlistEntry)
+	for _, e := range entries {
+		key := strings.Join(e.overrideArgs(), " ")
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], e)
+	}
+
+	var requests [][]string
+	for _, key := range order {
+		group := groups[key]
+		args := watchlistFlags(flags, group[0])
+		for len(group) > 0 {
+			n := min(len(group), allProductsBatchSize)
+			request := []string{"md"}
+			for _, e := range group[:n] {
+				request = append(request, e.Symbol)
+			}
+			requests = append(requests, append(request, args...))
+			group = group[n:]
+		}
+	}
+	return requests
+}
+
+// watchlistFlags is flags with the entry's overrides in place of the flags they replace
+func watchlistFlags(flags []string, e watchlistEntry) []string {
+	var args []string
+	for i := 0; i < len(flags); i++ {
+		switch {
+		case e.Depth != nil && flags[i] == "--depth":
+			i++
+		case len(e.EntryTypes) > 0 && slices.Contains(entryTypeFlags, flags[i]):
+		default:
+			args = append(args, flags[i])
+		}
+	}
+	return append(args, e.overrideArgs()...)
+}
+
+// requestWatchlist sends md requests for the whole watchlist, pacing them as md --all does.
+// Requests that fail are reported and the rest still sent.
+func (a *FixApp) requestWatchlist(out output, flags []string) error {
+	a.watchlistMu.Lock()
+	entries := a.watchlist
+	a.watchlistMu.Unlock()
+	if len(entries) == 0 {
+		return invalidRequest("the watchlist is empty; load one with watchlist import <file>")
+	}
+
+	requests := watchlistRequests(entries, flags)
+	out.Info("Requesting %d symbols in %d requests", len(entries), len(requests))
+	var errs []error
+	for i, request := range requests {
+		if i > 0 && !a.waitBetweenBatches() {
+			break
+		}
+		if err := a.mdRequest(out, request); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// importWatchlist replaces the watchlist with the symbols in path. Unless force is set, every
+// symbol must be in the product catalog when one is loaded.
+func (a *FixApp) importWatchlist(path string, force bool) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, invalidRequest("%v", err)
+	}
+	entries, err := a.parseWatchlist(path, data)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+	if !force {
+		symbols := make([]string, len(entries))
+		for i, e := range entries {
+			symbols[i] = e.Symbol
+		}
+		if err := a.validateSymbols(symbols); err != nil {
+			return 0, fmt.Errorf("%s: %w (use --force to import anyway)", path, err)
+		}
+	}
+	a.watchlistMu.Lock()
+	defer a.watchlistMu.Unlock()
+	a.watchlist = entries
+	return len(entries), nil
+}
+
+func (a *FixApp) watchlistRows() [][]string {
+	a.watchlistMu.Lock()
+	defer a.watchlistMu.Unlock()
+	rows := make([][]string, 0, len(a.watchlist))
+	for _, e := range a.watchlist {
+		depth := "-"
+		if e.Depth != nil {
+			depth = strconv.Itoa(*e.Depth)
+		}
+		entryTypes := "-"
+		if len(e.EntryTypes) > 0 {
+			entryTypes = strings.Join(e.EntryTypes, " ")
+		}
+		rows = append(rows, []string{e.Symbol, depth, entryTypes})
+	}
+	return rows
+}
+
+func (a *FixApp) handleWatchlistRequest(out output, parts []string) {
+	if len(parts) == 1 || (parts[1] == "list" && len(parts) == 2) {
+		rows := a.watchlistRows()
+		if len(rows) == 0 {
+			out.Info("The watchlist is empty; load one with: watchlist import <file>")
+			return
+		}
+		out.Table("Watchlist:", []string{"Symbol", "Depth", "Entry Types"}, rows)
+		return
+	}
+
+	var err error
+	switch {
+	case parts[1] == "import" && len(parts) >= 3:
+		flags := parts[3:]
+		force := slices.Contains(flags, "--force")
+		request := slices.Contains(flags, "--subscribe") || slices.Contains(flags, "--snapshot")
+		if len(slices.DeleteFunc(slices.Clone(flags), func(f string) bool { return f == "--force" })) > 0 && !request {
+			err = invalidRequest("md flags after the file need --subscribe or --snapshot")
+			break
+		}
+		var n int
+		if n, err = a.importWatchlist(parts[2], force); err != nil {
+			break
+		}
+		out.Info("Imported %d symbols from %s", n, parts[2])
+		if request {
+			err = a.requestWatchlist(out, flags)
+		}
+	case parts[1] == "subscribe":
+		err = a.requestWatchlist(out, append([]string{"--subscribe"}, parts[2:]...))
+	default:
+		printCommandHelp(out.Console(), "watchlist")
+		return
+	}
+	if err != nil {
+		out.Error(err)
+	}
+}
@@ -0,0 +1,114 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"prime-fix-md-go/products"
+)
+
+func TestParseWatchlistCsvAndJson(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+
+	csvData := "symbol,depth,entryTypes\n# majors\nbtc-usd,10,bids offers\nETH-USD,,trades|--v\nSOL-USD\n"
+	entries, err := app.parseWatchlist("symbols.csv", []byte(csvData))
+	if err != nil {
+		t.Fatalf("parseWatchlist csv: %v", err)
+	}
+	rows := app.watchlistRows()
+	if len(rows) != 0 {
+		t.Fatalf("Expected parsing to leave the watchlist alone, got %v", rows)
+	}
+	if len(entries) != 3 || entries[0].Symbol != "BTC-USD" || *entries[0].Depth != 10 || strings.Join(entries[1].EntryTypes, " ") != "trades v" || entries[2].Depth != nil {
+		t.Fatalf("Unexpected csv entries %+v", entries)
+	}
+
+	jsonData := `["BTC-USD", {"symbol": "ETH-USD", "depth": 1, "entryTypes": ["l1"]}]`
+	entries, err = app.parseWatchlist("symbols.JSON", []byte(jsonData))
+	if err != nil {
+		t.Fatalf("parseWatchlist json: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Symbol != "BTC-USD" || entries[1].EntryTypes[0] != "l1" {
+		t.Fatalf("Unexpected json entries %+v", entries)
+	}
+
+	for name, bad := range map[string]string{
+		"dup.csv":   "BTC-USD\nbtc-usd\n",
+		"depth.csv": "BTC-USD,ten\n",
+		"entry.csv": "BTC-USD,,bogus\n",
+		"empty.csv": "symbol\n",
+		"bad.json":  `[{"symbol": "BTC-USD", "size": 1}]`,
+	} {
+		if _, err := app.parseWatchlist(name, []byte(bad)); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("%s: expected an invalid request, got %v", name, err)
+		}
+	}
+}
+
+func TestWatchlistRequestsGroupOverrides(t *testing.T) {
+	ten := 10
+	entries := []watchlistEntry{
+		{Symbol: "BTC-USD", Depth: &ten},
+		{Symbol: "ETH-USD"},
+		{Symbol: "SOL-USD", Depth: &ten},
+		{Symbol: "DOGE-USD", EntryTypes: []string{"trades"}},
+	}
+	requests := watchlistRequests(entries, []string{"--subscribe", "--depth", "1", "--bids", "--offers"})
+	var got []string
+	for _, r := range requests {
+		got = append(got, strings.Join(r, " "))
+	}
+	want := []string{
+		"md BTC-USD SOL-USD --subscribe --bids --offers --depth 10",
+		"md ETH-USD --subscribe --depth 1 --bids --offers",
+		"md DOGE-USD --subscribe --depth 1 --trades",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Expected\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	var many []watchlistEntry
+	for i := 0; i < allProductsBatchSize+1; i++ {
+		many = append(many, watchlistEntry{Symbol: "S" + string(rune('A'+i%26)) + string(rune('A'+i/26))})
+	}
+	if requests := watchlistRequests(many, []string{"--subscribe"}); len(requests) != 2 || len(requests[1]) != 3 {
+		t.Fatalf("Expected requests of at most %d symbols, got %d", allProductsBatchSize, len(requests))
+	}
+}
+
+func TestImportWatchlistChecksCatalog(t *testing.T) {
+	app := NewFixApp(NewConfig("", "", "", "SENDER", "TARGET", ""), nil)
+	app.Products.Replace([]products.Product{{Symbol: "BTC-USD"}})
+	path := filepath.Join(t.TempDir(), "symbols.csv")
+	if err := os.WriteFile(path, []byte("BTC-USD\nBTX-USD\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.importWatchlist(path, false); !errors.Is(err, ErrInvalidRequest) || !strings.Contains(err.Error(), "BTX-USD") {
+		t.Fatalf("Expected the unknown symbol to be reported, got %v", err)
+	}
+	if n, err := app.importWatchlist(path, true); err != nil || n != 2 {
+		t.Fatalf("Expected --force to import both symbols, got %d %v", n, err)
+	}
+	if rows := app.watchlistRows(); len(rows) != 2 || rows[1][0] != "BTX-USD" || rows[1][1] != "-" {
+		t.Fatalf("Unexpected watchlist %v", rows)
+	}
+}
@@ -0,0 +1,89 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package fixclient
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"prime-fix-md-go/database"
+)
+
+// displayWriterStats shows how busy the database writer is, so storage saturation is visible
+// before data backs up
+func (a *FixApp) displayWriterStats(out output) {
+	if a.Db == nil {
+		return
+	}
+	s := a.Db.WriterStats()
+	out.Table("Database Writer:",
+		[]string{"Rows/s", "Rows", "Writes", "Failed", "Avg Batch", "Max Batch", "Queue", "Avg Latency", "Max Latency"},
+		[][]string{{
+			fmt.Sprintf("%.1f", s.RowsPerSec),
+			strconv.FormatInt(s.Rows, 10),
+			strconv.FormatInt(s.Writes, 10),
+			strconv.FormatInt(s.Failed, 10),
+			fmt.Sprintf("%.1f", s.AvgBatch),
+			strconv.FormatInt(s.MaxBatch, 10),
+			strconv.FormatInt(s.QueueDepth, 10),
+			roundLatency(s.AvgLatency).String(),
+			roundLatency(s.MaxLatency).String(),
+		}})
+}
+
+// StartWriterStatsLog logs the database writer's throughput and latency every interval
+// while it is writing, until StopBackground is called
+func (a *FixApp) StartWriterStatsLog(every time.Duration) {
+	if a.Db == nil || every <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		var last database.WriterStats
+		for {
+			select {
+			case <-a.done:
+				return
+			case <-ticker.C:
+				s := a.Db.WriterStats()
+				if s.Writes != last.Writes {
+					log.Printf("%s", writerStatsLine(s, last))
+				}
+				last = s
+			}
+		}
+	}()
+}
+
+// writerStatsLine summarizes the writes made since last
+func writerStatsLine(s, last database.WriterStats) string {
+	writes := s.Writes - last.Writes
+	rows := s.Rows - last.Rows
+	return fmt.Sprintf("DB writer: %d rows in %d writes (%.1f rows/write, %.1f rows/s now), queue %d, latency avg %s max %s, %d failed",
+		rows, writes, float64(rows)/float64(writes), s.RowsPerSec, s.QueueDepth,
+		roundLatency(s.AvgLatency), roundLatency(s.MaxLatency), s.Failed-last.Failed)
+}
+
+func roundLatency(d time.Duration) time.Duration {
+	if d >= time.Millisecond {
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
@@ -0,0 +1,228 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AdminStats is a point-in-time copy of the session-level traffic counters
+type AdminStats struct {
+	InHeartbeats     int64
+	OutHeartbeats    int64
+	InTestRequests   int64
+	OutTestRequests  int64
+	OtherAdmin       int64 // Logon, logout, resend, sequence reset and session reject messages
+	RoutineEvents    int64 // "Sending"/"Received" engine events that are not printed individually
+	LastInbound      time.Time
+	LastOutbound     time.Time
+	LastRoutineEvent string
+
+	LastInHeartbeat     time.Time
+	HeartbeatInterval   time.Duration // HeartBtInt (108) from our Logon; 0 until logged on
+	MissedHeartbeats    int64         // Heartbeat intervals that passed with no inbound traffic at all
+	PendingTestRequests int           // TestRequests we sent that no Heartbeat has answered yet
+	OldestPendingTest   time.Time     // When the oldest unanswered TestRequest was sent
+	LastTestRequestRtt  time.Duration // TestRequest -> Heartbeat(112) round trip
+}
+
+// HalfDead reports whether a TestRequest has gone unanswered for longer than after
+func (s AdminStats) HalfDead(now time.Time, after time.Duration) bool {
+	return after > 0 && s.PendingTestRequests > 0 && now.Sub(s.OldestPendingTest) > after
+}
+
+// AdminCounters aggregates admin traffic so it can be summarized instead of printed message by message
+type AdminCounters struct {
+	mu              sync.Mutex
+	stats           AdminStats
+	summaryInterval time.Duration
+	lastSummary     time.Time
+	lastSummarized  AdminStats
+
+	pendingTests   map[string]time.Time // TestReqID (112) -> sent time
+	halfDeadAfter  time.Duration
+	halfDeadWarned bool
+}
+
+func NewAdminCounters(summaryInterval time.Duration) *AdminCounters {
+	return &AdminCounters{
+		summaryInterval: summaryInterval,
+		lastSummary:     time.Now(),
+		pendingTests:    make(map[string]time.Time),
+	}
+}
+
+// SetHalfDeadAlert enables a warning when a TestRequest stays unanswered for longer than after (0 disables)
+func (c *AdminCounters) SetHalfDeadAlert(after time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.halfDeadAfter = after
+}
+
+// Stats returns the running totals since startup
+func (c *AdminCounters) Stats() AdminStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *AdminCounters) recordMessage(msgType string, inbound bool) {
+	c.record(msgType, "", "", inbound, time.Now())
+}
+
+// observe records a raw message, picking out the fields needed for heartbeat health
+func (c *AdminCounters) observe(msg []byte, inbound bool, now time.Time) {
+	msgType := fieldOf(msg, "35")
+	var testReqId, heartBtInt string
+	switch msgType {
+	case "0", "1":
+		testReqId = fieldOf(msg, "112")
+	case "A":
+		heartBtInt = fieldOf(msg, "108")
+	}
+	c.record(msgType, testReqId, heartBtInt, inbound, now)
+}
+
+func (c *AdminCounters) record(msgType, testReqId, heartBtInt string, inbound bool, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if inbound {
+		c.countMissedHeartbeats(now)
+		c.stats.LastInbound = now
+	} else {
+		c.stats.LastOutbound = now
+	}
+
+	switch msgType {
+	case "0":
+		if inbound {
+			c.stats.InHeartbeats++
+			c.stats.LastInHeartbeat = now
+			if sent, ok := c.pendingTests[testReqId]; ok && testReqId != "" {
+				c.stats.LastTestRequestRtt = now.Sub(sent)
+				delete(c.pendingTests, testReqId)
+				c.updatePendingTests()
+			}
+		} else {
+			c.stats.OutHeartbeats++
+		}
+	case "1":
+		if inbound {
+			c.stats.InTestRequests++
+		} else {
+			c.stats.OutTestRequests++
+			if testReqId != "" {
+				c.pendingTests[testReqId] = now
+				c.updatePendingTests()
+			}
+		}
+	case "A":
+		c.stats.OtherAdmin++
+		if !inbound {
+			// New session: earlier TestRequests can no longer be answered
+			clear(c.pendingTests)
+			c.updatePendingTests()
+			if seconds, err := strconv.Atoi(heartBtInt); err == nil {
+				c.stats.HeartbeatInterval = time.Duration(seconds) * time.Second
+			}
+		}
+	case "2", "3", "4", "5":
+		c.stats.OtherAdmin++
+	}
+}
+
+// countMissedHeartbeats counts whole heartbeat intervals in the gap before this inbound message,
+// using the same 20% grace the engine allows before it sends a TestRequest
+func (c *AdminCounters) countMissedHeartbeats(now time.Time) {
+	interval := c.stats.HeartbeatInterval
+	if interval <= 0 || c.stats.LastInbound.IsZero() {
+		return
+	}
+	if gap := now.Sub(c.stats.LastInbound); gap > interval+interval/5 {
+		c.stats.MissedHeartbeats += int64(gap / interval)
+	}
+}
+
+func (c *AdminCounters) updatePendingTests() {
+	c.stats.PendingTestRequests = len(c.pendingTests)
+	c.stats.OldestPendingTest = time.Time{}
+	for _, sent := range c.pendingTests {
+		if c.stats.OldestPendingTest.IsZero() || sent.Before(c.stats.OldestPendingTest) {
+			c.stats.OldestPendingTest = sent
+		}
+	}
+}
+
+// dueAlert returns a warning the first time the session looks half-dead (we send, nothing answers),
+// and a recovery line once it answers again; "" otherwise
+func (c *AdminCounters) dueAlert(now time.Time) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	halfDead := c.stats.HalfDead(now, c.halfDeadAfter)
+	switch {
+	case halfDead && !c.halfDeadWarned:
+		c.halfDeadWarned = true
+		return fmt.Sprintf("WARNING: session looks half-dead: %d TestRequest(s) unanswered for %s, last inbound %s ago",
+			c.stats.PendingTestRequests, now.Sub(c.stats.OldestPendingTest).Round(time.Second),
+			now.Sub(c.stats.LastInbound).Round(time.Second))
+	case !halfDead && c.halfDeadWarned && c.stats.PendingTestRequests == 0:
+		c.halfDeadWarned = false
+		return "Session responding again"
+	}
+	return ""
+}
+
+func (c *AdminCounters) recordEvent(msg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.RoutineEvents++
+	c.stats.LastRoutineEvent = msg
+}
+
+// dueSummary returns the summary line for the window that just elapsed, or "" if it is not time yet
+func (c *AdminCounters) dueSummary(now time.Time) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.summaryInterval <= 0 || now.Sub(c.lastSummary) < c.summaryInterval {
+		return ""
+	}
+
+	window := now.Sub(c.lastSummary).Round(time.Second)
+	prev, cur := c.lastSummarized, c.stats
+	c.lastSummary = now
+	c.lastSummarized = cur
+
+	line := fmt.Sprintf("Session health (last %s): heartbeats in %d / out %d, test requests in %d / out %d, other admin %d",
+		window,
+		cur.InHeartbeats-prev.InHeartbeats, cur.OutHeartbeats-prev.OutHeartbeats,
+		cur.InTestRequests-prev.InTestRequests, cur.OutTestRequests-prev.OutTestRequests,
+		cur.OtherAdmin-prev.OtherAdmin)
+
+	if !cur.LastInbound.IsZero() {
+		line += fmt.Sprintf(", last inbound %s ago", now.Sub(cur.LastInbound).Round(time.Second))
+	}
+	if events := cur.RoutineEvents - prev.RoutineEvents; events > 0 {
+		line += fmt.Sprintf(", %d events (last: %s)", events, cur.LastRoutineEvent)
+	}
+	return line
+}
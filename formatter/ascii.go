@@ -0,0 +1,80 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package formatter
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// asciiMode replaces box drawing and emoji in console output, for terminals that cannot show them
+var asciiMode atomic.Bool
+
+// Each box drawing character becomes one ASCII character, so tables stay aligned
+var asciiReplacer = strings.NewReplacer(
+	"─", "-", "│", "|",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+",
+	"├", "+", "┤", "+", "┬", "+", "┴", "+", "┼", "+",
+	"📋 ", "", "🔹 ", "* ", "✓", "OK",
+)
+
+// SetASCII turns ASCII-only console output on or off. Writers returned by ASCII check it when created.
+func SetASCII(on bool) {
+	asciiMode.Store(on)
+}
+
+// ASCIIOnly reports whether console output is limited to ASCII
+func ASCIIOnly() bool {
+	return asciiMode.Load()
+}
+
+// NeedsASCII reports whether the terminal is unlikely to show unicode: TERM=dumb, or a C/POSIX locale
+func NeedsASCII() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return true
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			return locale == "C" || locale == "POSIX"
+		}
+	}
+	return false
+}
+
+type asciiWriter struct {
+	w io.Writer
+}
+
+// Write assumes each call carries whole characters, which holds for the formatted lines written here
+func (a asciiWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(a.w, asciiReplacer.Replace(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ASCII wraps w so box drawing and emoji are replaced when ASCII mode is on; otherwise w is returned as is
+func ASCII(w io.Writer) io.Writer {
+	if !asciiMode.Load() {
+		return w
+	}
+	if _, ok := w.(asciiWriter); ok {
+		return w
+	}
+	return asciiWriter{w}
+}
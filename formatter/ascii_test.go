@@ -0,0 +1,69 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package formatter
+
+import (
+	"bytes"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestASCIIWriter(t *testing.T) {
+	defer SetASCII(false)
+
+	var buf bytes.Buffer
+	if w := ASCII(&buf); w != &buf {
+		t.Fatal("Expected the writer unchanged while ASCII mode is off")
+	}
+
+	SetASCII(true)
+	w := ASCII(&buf)
+	if ASCII(w) != w {
+		t.Fatal("Expected an ASCII writer not to be wrapped twice")
+	}
+
+	line := "┌─────┬──────┐\n│ Pos │ Type │\n└─────┴──────┘\n📋 Snapshot ✓\n"
+	n, err := w.Write([]byte(line))
+	if err != nil || n != len(line) {
+		t.Fatalf("Write returned %d, %v; expected %d", n, err, len(line))
+	}
+	want := "+-----+------+\n| Pos | Type |\n+-----+------+\nSnapshot OK\n"
+	if buf.String() != want {
+		t.Fatalf("Unexpected ASCII output:\n%s", buf.String())
+	}
+	if utf8.RuneCountInString("┌─────┬──────┐") != len("+-----+------+") {
+		t.Fatal("Box drawing must map one to one to keep tables aligned")
+	}
+}
+
+func TestNeedsASCII(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_CTYPE", "")
+	t.Setenv("LANG", "en_US.UTF-8")
+	if NeedsASCII() {
+		t.Fatal("Expected unicode for a UTF-8 locale")
+	}
+	t.Setenv("LANG", "C")
+	if !NeedsASCII() {
+		t.Fatal("Expected ASCII for the C locale")
+	}
+	t.Setenv("LANG", "en_US.UTF-8")
+	t.Setenv("TERM", "dumb")
+	if !NeedsASCII() {
+		t.Fatal("Expected ASCII for TERM=dumb")
+	}
+}
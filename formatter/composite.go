@@ -0,0 +1,145 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// CompositeLogFactory sends every session log line to several destinations at once, e.g. the
+// console TableLog and a raw FileLog capture
+type CompositeLogFactory struct {
+	factories []quickfix.LogFactory
+}
+
+func NewCompositeLogFactory(factories ...quickfix.LogFactory) *CompositeLogFactory {
+	return &CompositeLogFactory{factories: factories}
+}
+
+func (f *CompositeLogFactory) Create() (quickfix.Log, error) {
+	return f.create(func(lf quickfix.LogFactory) (quickfix.Log, error) { return lf.Create() })
+}
+
+func (f *CompositeLogFactory) CreateSessionLog(sessionId quickfix.SessionID) (quickfix.Log, error) {
+	return f.create(func(lf quickfix.LogFactory) (quickfix.Log, error) { return lf.CreateSessionLog(sessionId) })
+}
+
+func (f *CompositeLogFactory) create(newLog func(quickfix.LogFactory) (quickfix.Log, error)) (quickfix.Log, error) {
+	logs := make(compositeLog, 0, len(f.factories))
+	for _, lf := range f.factories {
+		l, err := newLog(lf)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
+
+// Close closes every destination that holds a file open; call it after the initiator has stopped
+func (f *CompositeLogFactory) Close() error {
+	var errs []error
+	for _, lf := range f.factories {
+		if c, ok := lf.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type compositeLog []quickfix.Log
+
+func (c compositeLog) OnIncoming(msg []byte) {
+	for _, l := range c {
+		l.OnIncoming(msg)
+	}
+}
+
+func (c compositeLog) OnOutgoing(msg []byte) {
+	for _, l := range c {
+		l.OnOutgoing(msg)
+	}
+}
+
+func (c compositeLog) OnEvent(msg string) {
+	for _, l := range c {
+		l.OnEvent(msg)
+	}
+}
+
+func (c compositeLog) OnEventf(format string, args ...interface{}) {
+	for _, l := range c {
+		l.OnEventf(format, args...)
+	}
+}
+
+// RedactedLogFactory masks credentials in the raw messages passed to logs from f, for
+// destinations that write messages as they are (file capture)
+func RedactedLogFactory(f quickfix.LogFactory) quickfix.LogFactory {
+	return redactedLogFactory{f}
+}
+
+type redactedLogFactory struct{ quickfix.LogFactory }
+
+func (f redactedLogFactory) Create() (quickfix.Log, error) {
+	l, err := f.LogFactory.Create()
+	return redactedLog{l}, err
+}
+
+func (f redactedLogFactory) CreateSessionLog(sessionId quickfix.SessionID) (quickfix.Log, error) {
+	l, err := f.LogFactory.CreateSessionLog(sessionId)
+	return redactedLog{l}, err
+}
+
+type redactedLog struct{ quickfix.Log }
+
+func (l redactedLog) OnIncoming(msg []byte) { l.Log.OnIncoming(Redact(msg)) }
+func (l redactedLog) OnOutgoing(msg []byte) { l.Log.OnOutgoing(Redact(msg)) }
+
+// Redact returns msg with the values of sensitive tags (password, logon signature) masked. The
+// body length and checksum are left as they were. msg itself is never modified.
+func Redact(msg []byte) []byte {
+	var out []byte
+	for tag := range sensitiveTags {
+		prefix := []byte("\x01" + strconv.Itoa(tag) + "=")
+		src := msg
+		if out != nil {
+			src = out
+		}
+		start := bytes.Index(src, prefix)
+		if start == -1 {
+			continue
+		}
+		start += len(prefix)
+		end := bytes.IndexByte(src[start:], 0x01)
+		if end == -1 {
+			end = len(src) - start
+		}
+		redacted := make([]byte, 0, len(src))
+		redacted = append(redacted, src[:start]...)
+		redacted = append(redacted, "********"...)
+		out = append(redacted, src[start+end:]...)
+	}
+	if out == nil {
+		return msg
+	}
+	return out
+}
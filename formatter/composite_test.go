@@ -0,0 +1,103 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type recordingLog struct{ lines *[]string }
+
+func (l recordingLog) OnIncoming(msg []byte) { *l.lines = append(*l.lines, "in "+string(msg)) }
+func (l recordingLog) OnOutgoing(msg []byte) { *l.lines = append(*l.lines, "out "+string(msg)) }
+func (l recordingLog) OnEvent(msg string)    { *l.lines = append(*l.lines, "event "+msg) }
+func (l recordingLog) OnEventf(format string, args ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, args...))
+}
+
+type recordingFactory struct {
+	lines *[]string
+	err   error
+}
+
+func (f recordingFactory) Create() (quickfix.Log, error) { return recordingLog{f.lines}, f.err }
+func (f recordingFactory) CreateSessionLog(quickfix.SessionID) (quickfix.Log, error) {
+	return recordingLog{f.lines}, f.err
+}
+
+func TestCompositeLogFansOut(t *testing.T) {
+	var a, b []string
+	factory := NewCompositeLogFactory(recordingFactory{lines: &a}, RedactedLogFactory(recordingFactory{lines: &b}))
+	l, err := factory.CreateSessionLog(quickfix.SessionID{BeginString: "FIXT.1.1"})
+	if err != nil {
+		t.Fatalf("CreateSessionLog failed: %v", err)
+	}
+
+	logon := "8=FIXT.1.1\x0135=A\x0196=sig\x01554=secret\x0110=000\x01"
+	l.OnOutgoing([]byte(logon))
+	l.OnEventf("Connected to %s", "gateway")
+
+	if len(a) != 2 || a[0] != "out "+logon || a[1] != "event Connected to gateway" {
+		t.Fatalf("Unexpected lines on the first log: %q", a)
+	}
+	if len(b) != 2 || strings.Contains(b[0], "secret") || strings.Contains(b[0], "sig\x01") {
+		t.Fatalf("Expected credentials masked on the redacted log: %q", b)
+	}
+}
+
+func TestCompositeLogFactoryError(t *testing.T) {
+	var a []string
+	factory := NewCompositeLogFactory(recordingFactory{lines: &a}, recordingFactory{lines: &a, err: errors.New("no path")})
+	if _, err := factory.Create(); err == nil {
+		t.Fatal("Expected the failing destination's error")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	msg := []byte("8=FIXT.1.1\x0135=A\x01554=secret\x0196=c2ln\x0110=000\x01")
+	got := string(Redact(msg))
+	want := "8=FIXT.1.1\x0135=A\x01554=********\x0196=********\x0110=000\x01"
+	if got != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+	if !strings.Contains(string(msg), "secret") {
+		t.Fatal("Expected the original message to be left alone")
+	}
+
+	heartbeat := []byte("8=FIXT.1.1\x0135=0\x0110=000\x01")
+	if got := Redact(heartbeat); &got[0] != &heartbeat[0] {
+		t.Fatal("Expected a message without credentials to be returned as is")
+	}
+}
+
+type closeRecorder struct{ closed *bool }
+
+func (c closeRecorder) Write(p []byte) (int, error) { return len(p), nil }
+func (c closeRecorder) Close() error                { *c.closed = true; return nil }
+
+func TestCompositeLogFactoryClose(t *testing.T) {
+	var lines []string
+	closed := false
+	factory := NewCompositeLogFactory(recordingFactory{lines: &lines}, newJsonLogFactory(closeRecorder{&closed}, false))
+	if err := factory.Close(); err != nil || !closed {
+		t.Fatalf("Expected the JSON log file to be closed, got closed=%v err=%v", closed, err)
+	}
+}
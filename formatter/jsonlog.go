@@ -0,0 +1,121 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// JsonLogEntry is one line of the JSON session log
+type JsonLogEntry struct {
+	Time      string `json:"time"`                // RFC 3339 UTC with nanoseconds
+	Session   string `json:"session,omitempty"`   // Empty for events outside a session
+	Kind      string `json:"kind"`                // "event" or "message"
+	Direction string `json:"direction,omitempty"` // "in" or "out" for messages
+	MsgType   string `json:"msgType,omitempty"`
+	MsgName   string `json:"msgName,omitempty"`
+	SeqNum    int    `json:"seqNum,omitempty"`
+	Text      string `json:"text,omitempty"`    // The event
+	Message   string `json:"message,omitempty"` // Raw message with '|' for SOH and credentials masked
+}
+
+// JsonLogFactory writes session events, and optionally every message, to one file as JSON
+// lines for log shippers such as Filebeat or Promtail
+type JsonLogFactory struct {
+	Messages bool // Log every inbound and outbound message, not only events
+
+	mu  sync.Mutex
+	out io.WriteCloser
+	enc *json.Encoder
+}
+
+// NewJsonLogFactory appends to the file at path
+func NewJsonLogFactory(path string, messages bool) (*JsonLogFactory, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSON log: %v", err)
+	}
+	return newJsonLogFactory(f, messages), nil
+}
+
+func newJsonLogFactory(out io.WriteCloser, messages bool) *JsonLogFactory {
+	return &JsonLogFactory{Messages: messages, out: out, enc: json.NewEncoder(out)}
+}
+
+func (f *JsonLogFactory) Create() (quickfix.Log, error) {
+	return &jsonLog{factory: f}, nil
+}
+
+func (f *JsonLogFactory) CreateSessionLog(sessionId quickfix.SessionID) (quickfix.Log, error) {
+	return &jsonLog{factory: f, session: sessionId.String()}, nil
+}
+
+// Close closes the log file; call it after the initiator has stopped
+func (f *JsonLogFactory) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.out.Close()
+}
+
+func (f *JsonLogFactory) write(e JsonLogEntry) {
+	e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// A failed write has nowhere better to be reported than the log it failed to reach
+	_ = f.enc.Encode(e)
+}
+
+type jsonLog struct {
+	factory *JsonLogFactory
+	session string
+}
+
+func (l *jsonLog) OnIncoming(msg []byte) { l.message("in", msg) }
+func (l *jsonLog) OnOutgoing(msg []byte) { l.message("out", msg) }
+
+func (l *jsonLog) OnEvent(msg string) {
+	l.factory.write(JsonLogEntry{Session: l.session, Kind: "event", Text: msg})
+}
+
+func (l *jsonLog) OnEventf(format string, args ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, args...))
+}
+
+func (l *jsonLog) message(direction string, msg []byte) {
+	if !l.factory.Messages {
+		return
+	}
+	msgType := msgTypeOf(msg)
+	seqNum, _ := strconv.Atoi(fieldOf(msg, "34"))
+	l.factory.write(JsonLogEntry{
+		Session:   l.session,
+		Kind:      "message",
+		Direction: direction,
+		MsgType:   msgType,
+		MsgName:   MsgTypeName(msgType),
+		SeqNum:    seqNum,
+		Message:   string(bytes.ReplaceAll(Redact(msg), []byte{0x01}, []byte{'|'})),
+	})
+}
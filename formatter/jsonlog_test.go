@@ -0,0 +1,82 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func jsonLogLines(t *testing.T, out *bytes.Buffer) []JsonLogEntry {
+	var entries []JsonLogEntry
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var e JsonLogEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestJsonLogMessages(t *testing.T) {
+	var out bytes.Buffer
+	factory := newJsonLogFactory(nopCloser{&out}, true)
+	sid := quickfix.SessionID{BeginString: "FIXT.1.1", SenderCompID: "A", TargetCompID: "COIN"}
+	l, _ := factory.CreateSessionLog(sid)
+
+	l.OnOutgoing([]byte("8=FIXT.1.1\x0135=A\x0134=1\x01554=secret\x0110=000\x01"))
+	l.OnEvent("Logon accepted")
+
+	entries := jsonLogLines(t, &out)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %s", len(entries), out.String())
+	}
+	m := entries[0]
+	if m.Kind != "message" || m.Direction != "out" || m.MsgType != "A" || m.MsgName != "Logon" || m.SeqNum != 1 || m.Session != sid.String() {
+		t.Fatalf("Unexpected message entry %+v", m)
+	}
+	if strings.Contains(m.Message, "secret") || !strings.HasPrefix(m.Message, "8=FIXT.1.1|35=A|") {
+		t.Fatalf("Expected a masked, '|' delimited message, got %q", m.Message)
+	}
+	if e := entries[1]; e.Kind != "event" || e.Text != "Logon accepted" || e.Time == "" {
+		t.Fatalf("Unexpected event entry %+v", e)
+	}
+}
+
+func TestJsonLogEventsOnly(t *testing.T) {
+	var out bytes.Buffer
+	l, _ := newJsonLogFactory(nopCloser{&out}, false).Create()
+	l.OnIncoming([]byte("8=FIXT.1.1\x0135=0\x0134=7\x0110=000\x01"))
+	l.OnEventf("Reconnecting in %ds", 10)
+
+	entries := jsonLogLines(t, &out)
+	if len(entries) != 1 || entries[0].Text != "Reconnecting in 10s" || entries[0].Session != "" {
+		t.Fatalf("Expected only the event, got %+v", entries)
+	}
+}
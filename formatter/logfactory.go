@@ -17,47 +17,261 @@
 package formatter
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 
 	"github.com/quickfixgo/quickfix"
 )
 
-type TableLogFactory struct{}
+// outputMu keeps tables from different session logs from interleaving
+var outputMu sync.Mutex
+
+// muted suppresses console output from session logs, e.g. while the REPL follows one symbol
+var muted atomic.Bool
+
+// console overrides os.Stdout for session log output; see SetConsole
+var console atomic.Value
+
+type consoleWriter struct{ w io.Writer }
+
+// SetConsole sends console output from session logs to w, e.g. a REPL writer that redraws
+// the prompt after each line. nil goes back to os.Stdout.
+func SetConsole(w io.Writer) {
+	console.Store(consoleWriter{w})
+}
+
+func consoleOut() io.Writer {
+	if c, ok := console.Load().(consoleWriter); ok && c.w != nil {
+		return ASCII(c.w)
+	}
+	return ASCII(os.Stdout)
+}
+
+// SetMuted turns console output from session logs off or back on. Admin traffic is still counted.
+func SetMuted(m bool) {
+	muted.Store(m)
+}
+
+type TableLogFactory struct {
+	Verbose bool // Render inbound/outbound messages as tag tables
+
+	counters *AdminCounters
+	verbose  atomic.Bool // Shared with every log created, so SetVerbose reaches running sessions
+}
 
 func NewTableLogFactory() *TableLogFactory {
-	return &TableLogFactory{}
+	return NewTableLogFactoryWithSummary(time.Minute)
+}
+
+// NewTableLogFactoryWithSummary prints an admin traffic summary every interval (0 disables the line)
+func NewTableLogFactoryWithSummary(interval time.Duration) *TableLogFactory {
+	return &TableLogFactory{counters: NewAdminCounters(interval)}
+}
+
+// AdminCounters exposes the heartbeat/admin counters shared by all logs from this factory
+func (f *TableLogFactory) AdminCounters() *AdminCounters {
+	return f.counters
+}
+
+// SetVerbose turns tag tables on or off for logs already created as well as new ones
+func (f *TableLogFactory) SetVerbose(v bool) {
+	f.Verbose = v
+	f.verbose.Store(v)
 }
 
 func (f *TableLogFactory) Create() (quickfix.Log, error) {
-	return &TableLog{}, nil
+	f.verbose.Store(f.Verbose)
+	return &TableLog{Verbose: f.Verbose, counters: f.counters, live: &f.verbose}, nil
 }
 
 func (f *TableLogFactory) CreateSessionLog(sessionId quickfix.SessionID) (quickfix.Log, error) {
-	return &TableLog{SessionId: sessionId}, nil
+	f.verbose.Store(f.Verbose)
+	return &TableLog{SessionId: sessionId, Verbose: f.Verbose, counters: f.counters, live: &f.verbose}, nil
 }
 
 type TableLog struct {
 	SessionId quickfix.SessionID
+	Verbose   bool
+
+	counters *AdminCounters
+	live     *atomic.Bool // The factory's setting, when created by one; overrides Verbose
+}
+
+func (l *TableLog) verbose() bool {
+	if l.live != nil {
+		return l.live.Load()
+	}
+	return l.Verbose
 }
 
 func (l *TableLog) OnIncoming(msg []byte) {
-	// Raw FIX data logging disabled - data is processed in application layer
+	l.recordMessage(msg, true)
+	// Raw FIX data is processed in the application layer; only rendered in verbose mode
+	if l.verbose() {
+		printMessageTable("<<", "Incoming", msg)
+	}
 }
 
 func (l *TableLog) OnOutgoing(msg []byte) {
-	// Raw FIX data logging disabled - data is processed in application layer
+	l.recordMessage(msg, false)
+	if l.verbose() {
+		printMessageTable(">>", "Outgoing", msg)
+	}
 }
 
 func (l *TableLog) OnEvent(msg string) {
-	if !strings.Contains(msg, "Sending") && !strings.Contains(msg, "Received") {
-		fmt.Printf("Event: %s\n", msg)
+	// Routine traffic events are folded into the periodic summary instead of printed one by one
+	if strings.Contains(msg, "Sending") || strings.Contains(msg, "Received") {
+		if l.counters != nil {
+			l.counters.recordEvent(msg)
+			l.printDueSummary()
+		}
+		return
+	}
+	if muted.Load() {
+		return
 	}
+	fmt.Fprintf(consoleOut(), "Event: %s\n", msg)
 }
 
 func (l *TableLog) OnEventf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	if !strings.Contains(msg, "Sending") && !strings.Contains(msg, "Received") {
-		fmt.Printf("Event: %s\n", msg)
+	l.OnEvent(fmt.Sprintf(format, args...))
+}
+
+func (l *TableLog) recordMessage(msg []byte, inbound bool) {
+	if l.counters == nil {
+		return
+	}
+	l.counters.observe(msg, inbound, time.Now())
+	l.printDueSummary()
+}
+
+func (l *TableLog) printDueSummary() {
+	now := time.Now()
+	for _, line := range []string{l.counters.dueAlert(now), l.counters.dueSummary(now)} {
+		if line != "" && !muted.Load() {
+			outputMu.Lock()
+			fmt.Fprintf(consoleOut(), "Event: %s\n", line)
+			outputMu.Unlock()
+		}
 	}
 }
+
+// msgTypeOf extracts tag 35 without parsing the whole message
+func msgTypeOf(msg []byte) string {
+	return fieldOf(msg, "35")
+}
+
+// fieldOf extracts the first occurrence of a tag without parsing the whole message
+func fieldOf(msg []byte, tag string) string {
+	for _, prefix := range [][]byte{[]byte("\x01" + tag + "="), []byte("|" + tag + "=")} {
+		start := bytes.Index(msg, prefix)
+		if start == -1 {
+			continue
+		}
+		start += len(prefix)
+		end := bytes.IndexAny(msg[start:], "\x01|")
+		if end == -1 {
+			return string(msg[start:])
+		}
+		return string(msg[start : start+end])
+	}
+	return ""
+}
+
+type tagValue struct {
+	tag   int
+	value string
+}
+
+// parseTagValues splits a raw FIX message into tag/value pairs. SOH is the field delimiter;
+// '|' is accepted as well so pretty-printed messages from logs can be rendered too.
+func parseTagValues(msg []byte) []tagValue {
+	delim := byte(0x01)
+	if bytes.IndexByte(msg, delim) == -1 {
+		delim = '|'
+	}
+
+	var fields []tagValue
+	for _, field := range bytes.Split(msg, []byte{delim}) {
+		eq := bytes.IndexByte(field, '=')
+		if eq <= 0 {
+			continue
+		}
+		tag, err := strconv.Atoi(string(field[:eq]))
+		if err != nil {
+			continue
+		}
+		fields = append(fields, tagValue{tag: tag, value: string(field[eq+1:])})
+	}
+	return fields
+}
+
+// MessageRows returns one tag/name/value row per field, with sensitive values masked
+// and MsgType annotated with its name
+func MessageRows(msg []byte) [][]string {
+	fields := parseTagValues(msg)
+	rows := make([][]string, 0, len(fields))
+	for _, f := range fields {
+		value := f.value
+		if sensitiveTags[f.tag] {
+			value = "********"
+		} else if f.tag == 35 {
+			value = f.value + " (" + MsgTypeName(f.value) + ")"
+		}
+		rows = append(rows, []string{strconv.Itoa(f.tag), tagName(f.tag), value})
+	}
+	return rows
+}
+
+// MessageTypeName returns the human name for the MsgType (35) of a raw message
+func MessageTypeName(msg []byte) string {
+	return MsgTypeName(msgTypeOf(msg))
+}
+
+func formatMessageTable(arrow, direction string, msg []byte) string {
+	rows := MessageRows(msg)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	nameWidth, valueWidth := len("Name"), len("Value")
+	for _, row := range rows {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(row[1]))
+		valueWidth = max(valueWidth, utf8.RuneCountInString(row[2]))
+	}
+
+	line := func(left, mid, right string) string {
+		return left + strings.Repeat("─", 7) + mid + strings.Repeat("─", nameWidth+2) + mid + strings.Repeat("─", valueWidth+2) + right + "\n"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s %s\n", arrow, direction, MessageTypeName(msg))
+	sb.WriteString(line("┌", "┬", "┐"))
+	fmt.Fprintf(&sb, "│ %-5s │ %-*s │ %-*s │\n", "Tag", nameWidth, "Name", valueWidth, "Value")
+	sb.WriteString(line("├", "┼", "┤"))
+	for _, row := range rows {
+		fmt.Fprintf(&sb, "│ %-5s │ %-*s │ %-*s │\n", row[0], nameWidth, row[1], valueWidth, row[2])
+	}
+	sb.WriteString(line("└", "┴", "┘"))
+	return sb.String()
+}
+
+func printMessageTable(arrow, direction string, msg []byte) {
+	table := formatMessageTable(arrow, direction, msg)
+	if table == "" || muted.Load() {
+		return
+	}
+
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	fmt.Fprint(consoleOut(), table)
+}
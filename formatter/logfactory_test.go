@@ -17,7 +17,9 @@
 package formatter
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/quickfixgo/quickfix"
 )
@@ -227,3 +229,156 @@ func TestLogFactoryMultipleInstances(t *testing.T) {
 		t.Fatal("Expected different session IDs")
 	}
 }
+
+func TestVerboseFactoryPropagates(t *testing.T) {
+	factory := NewTableLogFactory()
+	factory.Verbose = true
+
+	log, _ := factory.CreateSessionLog(quickfix.SessionID{BeginString: "FIXT.1.1"})
+	if !log.(*TableLog).Verbose {
+		t.Fatal("Expected session log to inherit verbose mode")
+	}
+}
+
+func TestSetVerboseReachesExistingLogs(t *testing.T) {
+	factory := NewTableLogFactory()
+	log, _ := factory.CreateSessionLog(quickfix.SessionID{BeginString: "FIXT.1.1"})
+
+	factory.SetVerbose(true)
+	if !log.(*TableLog).verbose() {
+		t.Fatal("Expected SetVerbose to turn on an existing session log")
+	}
+	factory.SetVerbose(false)
+	if log.(*TableLog).verbose() {
+		t.Fatal("Expected SetVerbose to turn off an existing session log")
+	}
+}
+
+func TestFormatMessageTable(t *testing.T) {
+	msg := "8=FIXT.1.1\x019=50\x0135=W\x0155=BTC-USD\x01268=1\x01269=0\x01270=50000.00\x01554=secret\x0110=123\x01"
+
+	table := formatMessageTable("<<", "Incoming", []byte(msg))
+
+	if !strings.Contains(table, "MarketDataSnapshotFullRefresh") {
+		t.Fatalf("Expected message type name in header, got:\n%s", table)
+	}
+	if !strings.Contains(table, "MDEntryPx") || !strings.Contains(table, "50000.00") {
+		t.Fatalf("Expected tag name and value rows, got:\n%s", table)
+	}
+	if strings.Contains(table, "secret") {
+		t.Fatalf("Expected password to be masked, got:\n%s", table)
+	}
+
+	// Every row must be the same width so columns line up
+	lines := strings.Split(strings.TrimSpace(table), "\n")[1:]
+	width := len([]rune(lines[0]))
+	for _, line := range lines {
+		if len([]rune(line)) != width {
+			t.Fatalf("Misaligned row %q (expected width %d)", line, width)
+		}
+	}
+}
+
+func TestFormatMessageTablePipeDelimited(t *testing.T) {
+	table := formatMessageTable(">>", "Outgoing", []byte("8=FIX.4.4|35=V|262=md_1|10=123|"))
+	if !strings.Contains(table, "MDReqID") {
+		t.Fatalf("Expected pipe-delimited message to be parsed, got:\n%s", table)
+	}
+
+	if formatMessageTable(">>", "Outgoing", []byte("not-a-fix-message")) != "" {
+		t.Fatal("Expected empty output for malformed message")
+	}
+}
+
+func TestAdminCountersFromSessionLog(t *testing.T) {
+	factory := NewTableLogFactoryWithSummary(0)
+
+	log, _ := factory.CreateSessionLog(quickfix.SessionID{BeginString: "FIXT.1.1"})
+	log.OnIncoming([]byte("8=FIXT.1.1\x0135=0\x0134=2\x0110=000\x01"))
+	log.OnIncoming([]byte("8=FIXT.1.1\x0135=1\x0134=3\x01112=TEST\x0110=000\x01"))
+	log.OnOutgoing([]byte("8=FIXT.1.1\x0135=0\x0134=2\x0110=000\x01"))
+	log.OnIncoming([]byte("8=FIXT.1.1\x0135=W\x0134=4\x0110=000\x01"))
+	log.OnEvent("Received logout request")
+
+	stats := factory.AdminCounters().Stats()
+	if stats.InHeartbeats != 1 || stats.OutHeartbeats != 1 || stats.InTestRequests != 1 {
+		t.Fatalf("Unexpected admin counters: %+v", stats)
+	}
+	if stats.RoutineEvents != 1 || stats.LastRoutineEvent != "Received logout request" {
+		t.Fatalf("Expected routine event to be counted, got %+v", stats)
+	}
+	if stats.LastInbound.IsZero() {
+		t.Fatal("Expected last inbound time to be recorded")
+	}
+}
+
+func TestAdminCountersSummary(t *testing.T) {
+	counters := NewAdminCounters(time.Minute)
+	counters.recordMessage("0", true)
+	counters.recordMessage("0", false)
+
+	if line := counters.dueSummary(time.Now()); line != "" {
+		t.Fatalf("Expected no summary before the interval elapses, got %q", line)
+	}
+
+	line := counters.dueSummary(time.Now().Add(2 * time.Minute))
+	if !strings.Contains(line, "heartbeats in 1 / out 1") {
+		t.Fatalf("Unexpected summary line: %q", line)
+	}
+
+	// Next window starts from zero
+	counters.recordMessage("0", true)
+	line = counters.dueSummary(time.Now().Add(5 * time.Minute))
+	if !strings.Contains(line, "heartbeats in 1 / out 0") {
+		t.Fatalf("Expected per-window counts, got %q", line)
+	}
+}
+
+func TestMsgTypeOf(t *testing.T) {
+	if msgTypeOf([]byte("8=FIXT.1.1\x019=10\x0135=A\x0134=1\x01")) != "A" {
+		t.Fatal("Expected msg type A")
+	}
+	if msgTypeOf([]byte("8=FIX.4.4|35=V|10=123|")) != "V" {
+		t.Fatal("Expected msg type V from pipe-delimited message")
+	}
+	if msgTypeOf(nil) != "" {
+		t.Fatal("Expected empty msg type for nil message")
+	}
+}
+
+func TestAdminCountersHeartbeatHealth(t *testing.T) {
+	counters := NewAdminCounters(0)
+	counters.SetHalfDeadAlert(30 * time.Second)
+	start := time.Now()
+
+	counters.observe([]byte("8=FIXT.1.1|35=A|108=30|"), false, start)
+	counters.observe([]byte("8=FIXT.1.1|35=A|108=30|"), true, start)
+
+	// 70s of silence is two missed heartbeat intervals
+	counters.observe([]byte("8=FIXT.1.1|35=0|"), true, start.Add(70*time.Second))
+	stats := counters.Stats()
+	if stats.HeartbeatInterval != 30*time.Second || stats.MissedHeartbeats != 2 {
+		t.Fatalf("Expected interval 30s and 2 missed heartbeats, got %s and %d", stats.HeartbeatInterval, stats.MissedHeartbeats)
+	}
+
+	sent := start.Add(80 * time.Second)
+	counters.observe([]byte("8=FIXT.1.1|35=1|112=TEST1|"), false, sent)
+	if line := counters.dueAlert(sent.Add(10 * time.Second)); line != "" {
+		t.Fatalf("Expected no alert before the threshold, got %q", line)
+	}
+	if line := counters.dueAlert(sent.Add(31 * time.Second)); !strings.Contains(line, "half-dead") {
+		t.Fatalf("Expected half-dead alert, got %q", line)
+	}
+	if line := counters.dueAlert(sent.Add(40 * time.Second)); line != "" {
+		t.Fatalf("Expected the alert only once, got %q", line)
+	}
+
+	counters.observe([]byte("8=FIXT.1.1|35=0|112=TEST1|"), true, sent.Add(41*time.Second))
+	stats = counters.Stats()
+	if stats.PendingTestRequests != 0 || stats.LastTestRequestRtt != 41*time.Second {
+		t.Fatalf("Expected answered test request with 41s rtt, got %d pending, rtt %s", stats.PendingTestRequests, stats.LastTestRequestRtt)
+	}
+	if line := counters.dueAlert(sent.Add(42 * time.Second)); line != "Session responding again" {
+		t.Fatalf("Expected recovery line, got %q", line)
+	}
+}
@@ -0,0 +1,116 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package formatter
+
+import "strconv"
+
+// tagNames covers the header, session and market data tags used by Prime FIX MD
+var tagNames = map[int]string{
+	1:    "Account",
+	7:    "BeginSeqNo",
+	8:    "BeginString",
+	9:    "BodyLength",
+	10:   "CheckSum",
+	16:   "EndSeqNo",
+	22:   "SecurityIDSource",
+	34:   "MsgSeqNum",
+	35:   "MsgType",
+	36:   "NewSeqNo",
+	43:   "PossDupFlag",
+	45:   "RefSeqNum",
+	48:   "SecurityID",
+	49:   "SenderCompID",
+	52:   "SendingTime",
+	55:   "Symbol",
+	56:   "TargetCompID",
+	58:   "Text",
+	96:   "RawData",
+	97:   "PossResend",
+	98:   "EncryptMethod",
+	108:  "HeartBtInt",
+	112:  "TestReqID",
+	122:  "OrigSendingTime",
+	123:  "GapFillFlag",
+	141:  "ResetSeqNumFlag",
+	146:  "NoRelatedSym",
+	262:  "MDReqID",
+	263:  "SubscriptionRequestType",
+	264:  "MarketDepth",
+	265:  "MDUpdateType",
+	266:  "AggregatedBook",
+	267:  "NoMDEntryTypes",
+	268:  "NoMDEntries",
+	269:  "MDEntryType",
+	270:  "MDEntryPx",
+	271:  "MDEntrySize",
+	272:  "MDEntryDate",
+	273:  "MDEntryTime",
+	276:  "QuoteCondition",
+	277:  "TradeCondition",
+	278:  "MDEntryID",
+	279:  "MDUpdateAction",
+	281:  "MDReqRejReason",
+	290:  "MDEntryPositionNo",
+	346:  "NumberOfOrders",
+	371:  "RefTagID",
+	372:  "RefMsgType",
+	373:  "SessionRejectReason",
+	380:  "BusinessRejectReason",
+	553:  "Username",
+	554:  "Password",
+	1128: "ApplVerID",
+	1137: "DefaultApplVerID",
+	2446: "AggressorSide",
+	9406: "DropCopyFlag",
+	9407: "AccessKey",
+}
+
+var msgTypeNames = map[string]string{
+	"0": "Heartbeat",
+	"1": "TestRequest",
+	"2": "ResendRequest",
+	"3": "Reject",
+	"4": "SequenceReset",
+	"5": "Logout",
+	"A": "Logon",
+	"V": "MarketDataRequest",
+	"W": "MarketDataSnapshotFullRefresh",
+	"X": "MarketDataIncrementalRefresh",
+	"Y": "MarketDataRequestReject",
+	"j": "BusinessMessageReject",
+}
+
+// sensitiveTags are masked in verbose output so credentials never reach the console
+var sensitiveTags = map[int]bool{
+	96:  true, // RawData (logon signature)
+	554: true, // Password
+}
+
+func tagName(tag int) string {
+	if name, ok := tagNames[tag]; ok {
+		return name
+	}
+	return strconv.Itoa(tag)
+}
+
+// MsgTypeName returns the FIX name of a MsgType (35), or the type itself when it is not known
+func MsgTypeName(msgType string) string {
+	if name, ok := msgTypeNames[msgType]; ok {
+		return name
+	}
+	return msgType
+}
@@ -6,12 +6,12 @@ require (
 	github.com/chzyer/readline v1.5.1
 	github.com/mattn/go-sqlite3 v1.14.22
 	github.com/quickfixgo/quickfix v0.9.6
+	github.com/shopspring/decimal v1.4.0
 )
 
 require (
 	github.com/pires/go-proxyproto v0.7.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/shopspring/decimal v1.4.0 // indirect
 	golang.org/x/net v0.24.0 // indirect
 	golang.org/x/sys v0.19.0 // indirect
 )
@@ -0,0 +1,136 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	DefaultEmailSubject = "[{{.Severity}}] {{.Title}} ({{.Host}})"
+	DefaultEmailBody    = `{{.Title}}
+{{if .Text}}
+{{.Text}}
+{{end}}
+Type:     {{.Type}}
+Severity: {{.Severity}}
+Host:     {{.Host}}
+Time:     {{.Time.Format "2006-01-02 15:04:05 MST"}}
+{{- if .Symbol}}
+Symbol:   {{.Symbol}}{{end}}
+{{- if .MdReqId}}
+ReqId:    {{.MdReqId}}{{end}}
+`
+)
+
+// Email sends events of at least MinSeverity over SMTP, with subject and body rendered from
+// templates over the Event, for environments where chat webhooks are not allowed
+type Email struct {
+	Addr        string // SMTP server host:port; STARTTLS is used when the server offers it
+	Username    string // Empty sends without authentication
+	Password    string
+	From        string
+	To          []string
+	MinSeverity string
+
+	subject *template.Template
+	body    *template.Template
+	send    func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmail checks the settings and parses the subject and body templates; empty ones use the defaults
+func NewEmail(addr, username, password, from string, to []string, minSeverity, subject, body string) (*Email, error) {
+	if addr == "" || from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("email alerts need a server, a from address and at least one recipient")
+	}
+	if minSeverity == "" {
+		minSeverity = SeverityWarning
+	}
+	if !ValidSeverity(minSeverity) {
+		return nil, fmt.Errorf("invalid minimum severity %q (expected info, warning or critical)", minSeverity)
+	}
+	if subject == "" {
+		subject = DefaultEmailSubject
+	}
+	if body == "" {
+		body = DefaultEmailBody
+	}
+	e := &Email{Addr: addr, Username: username, Password: password, From: from, To: to, MinSeverity: minSeverity, send: smtp.SendMail}
+	var err error
+	if e.subject, err = template.New("subject").Parse(subject); err != nil {
+		return nil, fmt.Errorf("invalid subject template: %v", err)
+	}
+	if e.body, err = template.New("body").Parse(body); err != nil {
+		return nil, fmt.Errorf("invalid body template: %v", err)
+	}
+	return e, nil
+}
+
+func (e *Email) Name() string {
+	return "email"
+}
+
+func (e *Email) Notify(ctx context.Context, ev Event) error {
+	if !ev.AtLeast(e.MinSeverity) {
+		return nil
+	}
+	msg, err := e.message(ev)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if e.Username != "" {
+		host, _, _ := strings.Cut(e.Addr, ":")
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+
+	// net/smtp has no context, so a server that stops answering is abandoned at the deadline
+	done := make(chan error, 1)
+	go func() { done <- e.send(e.Addr, auth, e.From, e.To, msg) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// message renders ev as a plain text RFC 5322 message
+func (e *Email) message(ev Event) ([]byte, error) {
+	var subject, body strings.Builder
+	if err := e.subject.Execute(&subject, ev); err != nil {
+		return nil, fmt.Errorf("subject template: %v", err)
+	}
+	if err := e.body.Execute(&body, ev); err != nil {
+		return nil, fmt.Errorf("body template: %v", err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(subject.String()), " ")))
+	fmt.Fprintf(&msg, "Date: %s\r\n", ev.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+	return msg.Bytes(), nil
+}
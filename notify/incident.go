@@ -0,0 +1,257 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IncidentApi opens and resolves incidents on a paging service. Key identifies the incident, so
+// opening it again while it is open does not page twice.
+type IncidentApi interface {
+	Name() string
+	Open(ctx context.Context, key string, ev Event) error
+	Resolve(ctx context.Context, key string, ev Event) error
+}
+
+// Incidents pages through an IncidentApi for problems that need a person, as opposed to the
+// informational chat channels: a logon failure, a prolonged disconnect, and a subscription that
+// stays stale for StaleAfter after its stale alert. Other events are ignored. Each incident is
+// resolved when the matching resolve event arrives.
+type Incidents struct {
+	Api        IncidentApi
+	StaleAfter time.Duration // How long a stale subscription must stay stale to open an incident; 0 opens one straight away
+
+	mu    sync.Mutex
+	stale map[string]*time.Timer // Stale subscriptions waiting out StaleAfter, by incident key
+	open  map[string]bool        // Stale incidents opened
+}
+
+func (n *Incidents) Name() string {
+	return n.Api.Name()
+}
+
+func (n *Incidents) Notify(ctx context.Context, ev Event) error {
+	switch ev.Type {
+	case EventLogonFailure, EventProlongedDisconnect:
+		// One incident for the session, so a logon failure is resolved by the next successful logon
+		key := incidentKey(ev.Host, "session")
+		if ev.Resolve {
+			return n.Api.Resolve(ctx, key, ev)
+		}
+		return n.Api.Open(ctx, key, ev)
+	case EventStale:
+		return n.notifyStale(ctx, ev)
+	}
+	return nil
+}
+
+func (n *Incidents) notifyStale(ctx context.Context, ev Event) error {
+	key := incidentKey(ev.Host, "stale", ev.Symbol, ev.MdReqId)
+	n.mu.Lock()
+	if n.stale == nil {
+		n.stale = make(map[string]*time.Timer)
+		n.open = make(map[string]bool)
+	}
+	if ev.Resolve {
+		if t, ok := n.stale[key]; ok {
+			t.Stop()
+			delete(n.stale, key)
+		}
+		wasOpen := n.open[key]
+		delete(n.open, key)
+		n.mu.Unlock()
+		if !wasOpen {
+			return nil
+		}
+		return n.Api.Resolve(ctx, key, ev)
+	}
+	if n.StaleAfter <= 0 {
+		n.open[key] = true
+		n.mu.Unlock()
+		return n.Api.Open(ctx, key, ev)
+	}
+	if _, waiting := n.stale[key]; !waiting && !n.open[key] {
+		n.stale[key] = time.AfterFunc(n.StaleAfter, func() { n.openStale(key, ev) })
+	}
+	n.mu.Unlock()
+	return nil
+}
+
+// openStale opens the incident for a subscription still stale after StaleAfter
+func (n *Incidents) openStale(key string, ev Event) {
+	n.mu.Lock()
+	if _, waiting := n.stale[key]; !waiting {
+		n.mu.Unlock()
+		return // Recovered meanwhile
+	}
+	delete(n.stale, key)
+	n.open[key] = true
+	n.mu.Unlock()
+
+	ev.Text += fmt.Sprintf("; still stale after a further %s", n.StaleAfter)
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	if err := n.Api.Open(ctx, key, ev); err != nil {
+		log.Printf("Failed to open stale incident via %s: %v", n.Api.Name(), err)
+	}
+}
+
+// incidentKey is the deduplication key for an incident, distinct per host
+func incidentKey(host string, parts ...string) string {
+	key := "prime-fix-md/" + host
+	for _, p := range parts {
+		if p != "" {
+			key += "/" + p
+		}
+	}
+	return key
+}
+
+const (
+	pagerDutyEventsUrl = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieApiUrl     = "https://api.opsgenie.com"
+)
+
+// PagerDuty opens and resolves incidents with the PagerDuty Events API v2
+type PagerDuty struct {
+	RoutingKey string // Integration key of an Events API v2 integration on the service
+	Url        string // Events API endpoint; empty uses events.pagerduty.com
+	HttpClient *http.Client
+}
+
+func (p *PagerDuty) Name() string {
+	return "pagerduty"
+}
+
+func (p *PagerDuty) Open(ctx context.Context, key string, ev Event) error {
+	return p.send(ctx, map[string]interface{}{
+		"routing_key":  p.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    key,
+		"payload": map[string]interface{}{
+			"summary":   truncate("["+ev.Host+"] "+ev.Summary(), 1024),
+			"source":    ev.Host,
+			"severity":  "critical",
+			"timestamp": ev.Time.Format(time.RFC3339),
+			"class":     ev.Type,
+			"custom_details": map[string]string{
+				"symbol":  ev.Symbol,
+				"mdReqId": ev.MdReqId,
+			},
+		},
+	})
+}
+
+func (p *PagerDuty) Resolve(ctx context.Context, key string, _ Event) error {
+	return p.send(ctx, map[string]interface{}{
+		"routing_key":  p.RoutingKey,
+		"event_action": "resolve",
+		"dedup_key":    key,
+	})
+}
+
+func (p *PagerDuty) send(ctx context.Context, body interface{}) error {
+	endpoint := p.Url
+	if endpoint == "" {
+		endpoint = pagerDutyEventsUrl
+	}
+	return postIncident(ctx, p.HttpClient, "pagerduty", endpoint, "", body)
+}
+
+// Opsgenie creates and closes alerts with the Opsgenie Alert API; the incident key is the alias
+type Opsgenie struct {
+	ApiKey     string // Key of an API integration
+	Url        string // API root; empty uses api.opsgenie.com, set https://api.eu.opsgenie.com for the EU instance
+	HttpClient *http.Client
+}
+
+func (o *Opsgenie) Name() string {
+	return "opsgenie"
+}
+
+func (o *Opsgenie) Open(ctx context.Context, key string, ev Event) error {
+	return o.send(ctx, "/v2/alerts", map[string]interface{}{
+		"message":     truncate("["+ev.Host+"] "+ev.Title, 130),
+		"alias":       key,
+		"description": ev.Text,
+		"source":      ev.Host,
+		"priority":    "P1",
+		"tags":        []string{"prime-fix-md", ev.Type},
+		"details": map[string]string{
+			"symbol":  ev.Symbol,
+			"mdReqId": ev.MdReqId,
+		},
+	})
+}
+
+func (o *Opsgenie) Resolve(ctx context.Context, key string, ev Event) error {
+	return o.send(ctx, "/v2/alerts/"+url.PathEscape(key)+"/close?identifierType=alias", map[string]string{
+		"source": ev.Host,
+		"note":   ev.Summary(),
+	})
+}
+
+func (o *Opsgenie) send(ctx context.Context, path string, body interface{}) error {
+	base := o.Url
+	if base == "" {
+		base = opsgenieApiUrl
+	}
+	return postIncident(ctx, o.HttpClient, "opsgenie", strings.TrimSuffix(base, "/")+path, "GenieKey "+o.ApiKey, body)
+}
+
+func postIncident(ctx context.Context, client *http.Client, name, endpoint, authorization string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", name, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
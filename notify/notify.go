@@ -0,0 +1,202 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package notify delivers operational alerts (disconnects, rejects, stalled feeds) to
+// external channels such as a webhook.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types; each can be enabled separately
+const (
+	EventDisconnect   = "disconnect"
+	EventLogonFailure = "logon_failure"
+	EventReject       = "reject"
+	EventStale        = "stale"
+	EventLargePrint   = "large_print"
+	EventUnexpected   = "unexpected_message"
+	EventRejectBurst  = "reject_burst"
+	EventSession      = "session_event" // FIX session timeline entries: logon, logout, resend, ...
+
+	EventProlongedDisconnect = "prolonged_disconnect"
+)
+
+// Severities, lowest first; channels such as email can take only the more severe events
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+var severityRank = map[string]int{SeverityInfo: 0, SeverityWarning: 1, SeverityCritical: 2}
+
+var eventSeverity = map[string]string{
+	EventDisconnect:          SeverityWarning,
+	EventLogonFailure:        SeverityCritical,
+	EventReject:              SeverityWarning,
+	EventStale:               SeverityWarning,
+	EventLargePrint:          SeverityInfo,
+	EventUnexpected:          SeverityWarning,
+	EventRejectBurst:         SeverityCritical,
+	EventSession:             SeverityInfo,
+	EventProlongedDisconnect: SeverityCritical,
+}
+
+// ValidSeverity reports whether s is info, warning or critical
+func ValidSeverity(s string) bool {
+	_, ok := severityRank[s]
+	return ok
+}
+
+// Event is one alert
+type Event struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Title   string    `json:"title"`
+	Text    string    `json:"text"`
+	Symbol  string    `json:"symbol,omitempty"`
+	MdReqId string    `json:"mdReqId,omitempty"`
+	Host    string    `json:"host,omitempty"`
+	Resolve bool      `json:"resolve,omitempty"` // The problem reported earlier for the same type and symbol is over
+}
+
+// Notifier sends events to one channel
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, ev Event) error
+}
+
+const (
+	queueSize   = 256
+	sendTimeout = 15 * time.Second
+)
+
+// Dispatcher sends enabled events to every notifier in the background, so a slow channel
+// never blocks the FIX session. A nil *Dispatcher drops everything.
+type Dispatcher struct {
+	mu        sync.RWMutex
+	notifiers []Notifier
+	enabled   map[string]bool
+	host      string
+
+	queue     chan Event
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewDispatcher sends the event types marked true in enabled to notifiers
+func NewDispatcher(notifiers []Notifier, enabled map[string]bool, host string) *Dispatcher {
+	d := &Dispatcher{
+		notifiers: notifiers,
+		enabled:   enabled,
+		host:      host,
+		queue:     make(chan Event, queueSize),
+		done:      make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Send queues ev if its type is enabled. Events are dropped, with a log line, when the queue is full.
+func (d *Dispatcher) Send(ev Event) {
+	if d == nil {
+		return
+	}
+	d.mu.RLock()
+	enabled := d.enabled[ev.Type]
+	d.mu.RUnlock()
+	if !enabled {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+	if ev.Host == "" {
+		ev.Host = d.host
+	}
+	select {
+	case d.queue <- ev:
+	default:
+		log.Printf("Alert queue full, dropped %s alert: %s", ev.Type, ev.Title)
+	}
+}
+
+// Reconfigure replaces the notifiers and enabled event types, e.g. after a config reload. Events
+// already queued go to the new notifiers.
+func (d *Dispatcher) Reconfigure(notifiers []Notifier, enabled map[string]bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.notifiers = notifiers
+	d.enabled = enabled
+}
+
+// Close delivers the queued events, waiting at most timeout, and stops the dispatcher
+func (d *Dispatcher) Close(timeout time.Duration) {
+	if d == nil {
+		return
+	}
+	d.closeOnce.Do(func() { close(d.queue) })
+	select {
+	case <-d.done:
+	case <-time.After(timeout):
+		log.Printf("Gave up delivering alerts after %s", timeout)
+	}
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	for ev := range d.queue {
+		d.mu.RLock()
+		notifiers := d.notifiers
+		d.mu.RUnlock()
+		for _, n := range notifiers {
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			if err := n.Notify(ctx, ev); err != nil {
+				log.Printf("Failed to send %s alert via %s: %v", ev.Type, n.Name(), err)
+			}
+			cancel()
+		}
+	}
+}
+
+// Severity is how serious ev is; an event reporting that a problem is over is info
+func (ev Event) Severity() string {
+	if ev.Resolve {
+		return SeverityInfo
+	}
+	if s, ok := eventSeverity[ev.Type]; ok {
+		return s
+	}
+	return SeverityWarning
+}
+
+// AtLeast reports whether ev is at least as severe as severity
+func (ev Event) AtLeast(severity string) bool {
+	return severityRank[ev.Severity()] >= severityRank[severity]
+}
+
+// Summary is a one-line rendering of ev for plain-text channels
+func (ev Event) Summary() string {
+	if ev.Text == "" {
+		return ev.Title
+	}
+	return fmt.Sprintf("%s: %s", ev.Title, ev.Text)
+}
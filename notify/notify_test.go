@@ -0,0 +1,298 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatcherPostsEnabledEvents(t *testing.T) {
+	var got []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid alert body: %v", err)
+		}
+		got = append(got, body)
+	}))
+	defer server.Close()
+
+	webhook := &Webhook{Url: server.URL, HttpClient: server.Client()}
+	d := NewDispatcher([]Notifier{webhook}, map[string]bool{EventReject: true}, "host-1")
+	d.Send(Event{Type: EventStale, Title: "BTC-USD market data stalled"})
+	d.Send(Event{Type: EventReject, Title: "BTC-USD subscription rejected", Text: "Unknown symbol", Symbol: "BTC-USD"})
+	d.Close(5 * time.Second)
+
+	if len(got) != 1 {
+		t.Fatalf("Expected only the enabled reject alert, got %v", got)
+	}
+	if got[0]["type"] != EventReject || got[0]["symbol"] != "BTC-USD" || got[0]["host"] != "host-1" {
+		t.Fatalf("Unexpected alert %v", got[0])
+	}
+	if got[0]["text"] != "[host-1] BTC-USD subscription rejected: Unknown symbol" {
+		t.Fatalf("Unexpected summary text %q", got[0]["text"])
+	}
+}
+
+func TestNilDispatcher(t *testing.T) {
+	var d *Dispatcher
+	d.Send(Event{Type: EventDisconnect})
+	d.Close(time.Second)
+}
+
+func TestDispatcherReconfigure(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Path)
+	}))
+	defer server.Close()
+
+	old := &Webhook{Url: server.URL + "/old", HttpClient: server.Client()}
+	d := NewDispatcher([]Notifier{old}, map[string]bool{EventReject: true}, "host-1")
+	d.Reconfigure([]Notifier{&Webhook{Url: server.URL + "/new", HttpClient: server.Client()}}, map[string]bool{EventStale: true})
+	d.Send(Event{Type: EventReject, Title: "now disabled"})
+	d.Send(Event{Type: EventStale, Title: "now enabled"})
+	d.Close(5 * time.Second)
+
+	if len(got) != 1 || got[0] != "/new" {
+		t.Fatalf("Expected one stale alert on the new webhook, got %v", got)
+	}
+}
+
+func TestTelegramSendsMessage(t *testing.T) {
+	var path string
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid message body: %v", err)
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	telegram := &Telegram{BotToken: "123:abc", ChatId: "-10042", BaseUrl: server.URL, HttpClient: server.Client()}
+	d := NewDispatcher([]Notifier{telegram}, map[string]bool{EventSession: true}, "host-1")
+	d.Send(Event{Type: EventSession, Title: "FIX session logon", Text: "FIXT.1.1:A->B"})
+	d.Close(5 * time.Second)
+
+	if path != "/bot123:abc/sendMessage" {
+		t.Fatalf("Unexpected Bot API path %q", path)
+	}
+	if body["chat_id"] != "-10042" {
+		t.Fatalf("Unexpected chat %q", body["chat_id"])
+	}
+	if body["text"] != "[host-1] FIX session logon\nFIXT.1.1:A->B" {
+		t.Fatalf("Unexpected text %q", body["text"])
+	}
+}
+
+func TestTelegramErrorHidesToken(t *testing.T) {
+	telegram := &Telegram{BotToken: "123:secret", ChatId: "1", BaseUrl: "http://127.0.0.1:1", HttpClient: http.DefaultClient}
+	err := telegram.Notify(context.Background(), Event{Title: "test"})
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("Expected an error without the bot token, got %v", err)
+	}
+}
+
+func TestEventSeverity(t *testing.T) {
+	cases := []struct {
+		ev   Event
+		want string
+	}{
+		{Event{Type: EventLogonFailure}, SeverityCritical},
+		{Event{Type: EventProlongedDisconnect}, SeverityCritical},
+		{Event{Type: EventProlongedDisconnect, Resolve: true}, SeverityInfo},
+		{Event{Type: EventStale}, SeverityWarning},
+		{Event{Type: EventLargePrint}, SeverityInfo},
+	}
+	for _, c := range cases {
+		if got := c.ev.Severity(); got != c.want {
+			t.Errorf("%+v: expected severity %s, got %s", c.ev, c.want, got)
+		}
+	}
+}
+
+func TestEmailRendersTemplatesAndFiltersSeverity(t *testing.T) {
+	email, err := NewEmail("smtp.example.com:587", "", "", "fix@example.com", []string{"ops@example.com"},
+		SeverityCritical, "{{.Severity}}: {{.Title}} on {{.Host}}", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sent []string
+	email.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		if addr != "smtp.example.com:587" || auth != nil || from != "fix@example.com" || len(to) != 1 {
+			t.Errorf("Unexpected envelope %s %v %s %v", addr, auth, from, to)
+		}
+		sent = append(sent, string(msg))
+		return nil
+	}
+
+	ev := Event{Type: EventStale, Title: "BTC-USD market data stalled", Host: "host-1", Time: time.Now()}
+	if err := email.Notify(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+	ev = Event{Type: EventLogonFailure, Title: "FIX logon failed", Text: "check credentials", Host: "host-1", Time: time.Now()}
+	if err := email.Notify(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 1 {
+		t.Fatalf("Expected only the critical alert to be emailed, got %d", len(sent))
+	}
+	for _, want := range []string{"Subject: critical: FIX logon failed on host-1\r\n", "To: ops@example.com\r\n", "check credentials\r\n", "Type:     logon_failure\r\n"} {
+		if !strings.Contains(sent[0], want) {
+			t.Errorf("Expected %q in message:\n%s", want, sent[0])
+		}
+	}
+}
+
+func TestNewEmailRejectsBadSettings(t *testing.T) {
+	to := []string{"ops@example.com"}
+	if _, err := NewEmail("smtp.example.com:25", "", "", "fix@example.com", to, "urgent", "", ""); err == nil {
+		t.Error("Expected an invalid severity to be rejected")
+	}
+	if _, err := NewEmail("smtp.example.com:25", "", "", "fix@example.com", to, "", "{{.Title", ""); err == nil {
+		t.Error("Expected an invalid subject template to be rejected")
+	}
+	if _, err := NewEmail("smtp.example.com:25", "", "", "", to, "", "", ""); err == nil {
+		t.Error("Expected a missing from address to be rejected")
+	}
+}
+
+type fakeIncidentApi struct {
+	mu     sync.Mutex
+	calls  []string
+	opened chan struct{}
+}
+
+func (f *fakeIncidentApi) Name() string { return "fake" }
+
+func (f *fakeIncidentApi) Open(_ context.Context, key string, _ Event) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, "open "+key)
+	f.mu.Unlock()
+	if f.opened != nil {
+		f.opened <- struct{}{}
+	}
+	return nil
+}
+
+func (f *fakeIncidentApi) Resolve(_ context.Context, key string, _ Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "resolve "+key)
+	return nil
+}
+
+func TestIncidentsOpenAndResolve(t *testing.T) {
+	api := &fakeIncidentApi{opened: make(chan struct{}, 4)}
+	n := &Incidents{Api: api, StaleAfter: 20 * time.Millisecond}
+	ctx := context.Background()
+
+	n.Notify(ctx, Event{Type: EventLogonFailure, Host: "h"})
+	n.Notify(ctx, Event{Type: EventReject, Host: "h", Symbol: "BTC-USD"})
+	n.Notify(ctx, Event{Type: EventProlongedDisconnect, Host: "h", Resolve: true})
+	<-api.opened
+
+	// Recovers before StaleAfter: no incident
+	n.Notify(ctx, Event{Type: EventStale, Host: "h", Symbol: "ETH-USD", MdReqId: "md_1"})
+	n.Notify(ctx, Event{Type: EventStale, Host: "h", Symbol: "ETH-USD", MdReqId: "md_1", Resolve: true})
+
+	// Still stale after StaleAfter: opened, then resolved on recovery
+	n.Notify(ctx, Event{Type: EventStale, Host: "h", Symbol: "BTC-USD", MdReqId: "md_2"})
+	select {
+	case <-api.opened:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a stale incident to open")
+	}
+	n.Notify(ctx, Event{Type: EventStale, Host: "h", Symbol: "BTC-USD", MdReqId: "md_2", Resolve: true})
+	time.Sleep(40 * time.Millisecond)
+
+	want := []string{
+		"open prime-fix-md/h/session",
+		"resolve prime-fix-md/h/session",
+		"open prime-fix-md/h/stale/BTC-USD/md_2",
+		"resolve prime-fix-md/h/stale/BTC-USD/md_2",
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if strings.Join(api.calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Expected calls %v, got %v", want, api.calls)
+	}
+}
+
+func TestPagerDutyTriggersAndResolves(t *testing.T) {
+	var got []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid body: %v", err)
+		}
+		got = append(got, body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	pd := &PagerDuty{RoutingKey: "rk", Url: server.URL, HttpClient: server.Client()}
+	ev := Event{Type: EventLogonFailure, Title: "FIX logon failed", Host: "h", Time: time.Now()}
+	if err := pd.Open(context.Background(), "k", ev); err != nil {
+		t.Fatal(err)
+	}
+	if err := pd.Resolve(context.Background(), "k", ev); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0]["event_action"] != "trigger" || got[1]["event_action"] != "resolve" || got[1]["dedup_key"] != "k" {
+		t.Fatalf("Unexpected events %v", got)
+	}
+	payload, _ := got[0]["payload"].(map[string]interface{})
+	if payload["summary"] != "[h] FIX logon failed" || payload["severity"] != "critical" {
+		t.Fatalf("Unexpected payload %v", payload)
+	}
+}
+
+func TestOpsgenieClosesByAlias(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "GenieKey key" {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		paths = append(paths, r.URL.RequestURI())
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	og := &Opsgenie{ApiKey: "key", Url: server.URL, HttpClient: server.Client()}
+	ev := Event{Type: EventStale, Title: "BTC-USD market data stalled", Host: "h"}
+	if err := og.Open(context.Background(), "prime-fix-md/h/stale/BTC-USD", ev); err != nil {
+		t.Fatal(err)
+	}
+	if err := og.Resolve(context.Background(), "prime-fix-md/h/stale/BTC-USD", ev); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/v2/alerts", "/v2/alerts/prime-fix-md%2Fh%2Fstale%2FBTC-USD/close?identifierType=alias"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Fatalf("Expected %v, got %v", want, paths)
+	}
+}
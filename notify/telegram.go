@@ -0,0 +1,82 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const telegramApiUrl = "https://api.telegram.org"
+
+// Telegram sends each event as a message from a bot to a chat, group or channel
+type Telegram struct {
+	BotToken   string
+	ChatId     string // Numeric chat ID, or @channelname for a public channel
+	BaseUrl    string // Bot API root; empty uses api.telegram.org
+	HttpClient *http.Client
+}
+
+func (t *Telegram) Name() string {
+	return "telegram"
+}
+
+func (t *Telegram) Notify(ctx context.Context, ev Event) error {
+	text := ev.Title
+	if ev.Text != "" {
+		text += "\n" + ev.Text
+	}
+	body, err := json.Marshal(struct {
+		ChatId string `json:"chat_id"`
+		Text   string `json:"text"`
+	}{t.ChatId, "[" + ev.Host + "] " + text})
+	if err != nil {
+		return err
+	}
+
+	base := t.BaseUrl
+	if base == "" {
+		base = telegramApiUrl
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(base, "/")+"/bot"+t.BotToken+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.HttpClient.Do(req)
+	if err != nil {
+		// The URL holds the bot token, so only the cause is reported
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("telegram returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package primeapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const DefaultBaseUrl = "https://api.prime.coinbase.com"
+
+// Client is a minimal Coinbase Prime REST client for the metadata this tool needs.
+// It signs requests with the same access key, signing key and passphrase used for the FIX logon.
+type Client struct {
+	BaseUrl    string
+	AccessKey  string
+	SigningKey string
+	Passphrase string
+	HttpClient *http.Client
+
+	now func() time.Time
+}
+
+func NewClient(baseUrl, accessKey, signingKey, passphrase string, timeout time.Duration) *Client {
+	if baseUrl == "" {
+		baseUrl = DefaultBaseUrl
+	}
+	return &Client{
+		BaseUrl:    baseUrl,
+		AccessKey:  accessKey,
+		SigningKey: signingKey,
+		Passphrase: passphrase,
+		HttpClient: &http.Client{Timeout: timeout},
+		now:        time.Now,
+	}
+}
+
+// ApiError is returned for non-2xx responses
+type ApiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ApiError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("prime api: HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("prime api: HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// sign computes the request signature: base64(HMAC-SHA256(signingKey, timestamp + method + path + body))
+func sign(signingKey, timestamp, method, path, body string) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(timestamp + method + path + body))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u, err := url.Parse(c.BaseUrl + path)
+	if err != nil {
+		return fmt.Errorf("prime api: invalid url: %v", err)
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(c.now().Unix(), 10)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CB-ACCESS-KEY", c.AccessKey)
+	req.Header.Set("X-CB-ACCESS-PASSPHRASE", c.Passphrase)
+	req.Header.Set("X-CB-ACCESS-TIMESTAMP", timestamp)
+	req.Header.Set("X-CB-ACCESS-SIGNATURE", sign(c.SigningKey, timestamp, http.MethodGet, u.Path, ""))
+
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("prime api: %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("prime api: reading %s: %v", path, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &ApiError{StatusCode: resp.StatusCode}
+		var errBody struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &errBody) == nil {
+			apiErr.Message = errBody.Message
+		}
+		return apiErr
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("prime api: decoding %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package primeapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type Portfolio struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	EntityId       string `json:"entity_id"`
+	OrganizationId string `json:"organization_id"`
+}
+
+type portfolioResponse struct {
+	Portfolio Portfolio `json:"portfolio"`
+}
+
+// GetPortfolio fetches the name and owning entity/organization of a portfolio
+func (c *Client) GetPortfolio(ctx context.Context, portfolioId string) (Portfolio, error) {
+	if portfolioId == "" {
+		return Portfolio{}, fmt.Errorf("prime api: portfolio id is required")
+	}
+
+	var resp portfolioResponse
+	if err := c.get(ctx, "/v1/portfolios/"+url.PathEscape(portfolioId), url.Values{}, &resp); err != nil {
+		return Portfolio{}, err
+	}
+	return resp.Portfolio, nil
+}
@@ -0,0 +1,45 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package primeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetPortfolio(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/portfolios/pf-1" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"portfolio":{"id":"pf-1","name":"Main","entity_id":"ent-1","organization_id":"org-1"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "secret", "pass", time.Second)
+	portfolio, err := client.GetPortfolio(context.Background(), "pf-1")
+	if err != nil {
+		t.Fatalf("GetPortfolio: %v", err)
+	}
+	if portfolio.Name != "Main" || portfolio.EntityId != "ent-1" || portfolio.OrganizationId != "org-1" {
+		t.Fatalf("Unexpected portfolio %+v", portfolio)
+	}
+}
@@ -0,0 +1,79 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package primeapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"prime-fix-md-go/products"
+)
+
+// Safety cap so a misbehaving cursor cannot loop forever
+const maxProductPages = 100
+
+type productJson struct {
+	Id             string   `json:"id"`
+	DisplayName    string   `json:"display_name"`
+	BaseIncrement  string   `json:"base_increment"`
+	QuoteIncrement string   `json:"quote_increment"`
+	Permissions    []string `json:"permissions"`
+}
+
+type productsResponse struct {
+	Products   []productJson `json:"products"`
+	Pagination struct {
+		NextCursor string `json:"next_cursor"`
+		HasNext    bool   `json:"has_next"`
+	} `json:"pagination"`
+}
+
+// ListProducts fetches every product available to the portfolio, following pagination
+func (c *Client) ListProducts(ctx context.Context, portfolioId string) ([]products.Product, error) {
+	if portfolioId == "" {
+		return nil, fmt.Errorf("prime api: portfolio id is required to list products")
+	}
+
+	path := fmt.Sprintf("/v1/portfolios/%s/products", url.PathEscape(portfolioId))
+	query := url.Values{}
+
+	var list []products.Product
+	for page := 0; page < maxProductPages; page++ {
+		var resp productsResponse
+		if err := c.get(ctx, path, query, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, p := range resp.Products {
+			list = append(list, products.Product{
+				Symbol:         p.Id,
+				DisplayName:    p.DisplayName,
+				BaseIncrement:  p.BaseIncrement,
+				QuoteIncrement: p.QuoteIncrement,
+				Permissions:    p.Permissions,
+			})
+		}
+
+		if !resp.Pagination.HasNext || resp.Pagination.NextCursor == "" {
+			return list, nil
+		}
+		query.Set("cursor", resp.Pagination.NextCursor)
+	}
+
+	return nil, fmt.Errorf("prime api: product list exceeded %d pages", maxProductPages)
+}
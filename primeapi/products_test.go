@@ -0,0 +1,94 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package primeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListProductsFollowsPagination(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/v1/portfolios/pf-1/products" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		timestamp := r.Header.Get("X-CB-ACCESS-TIMESTAMP")
+		if timestamp != "1700000000" {
+			t.Errorf("Unexpected timestamp %q", timestamp)
+		}
+		// The signature covers the path only, not the query string
+		if got, want := r.Header.Get("X-CB-ACCESS-SIGNATURE"), sign("secret", timestamp, "GET", r.URL.Path, ""); got != want {
+			t.Errorf("Signature mismatch: got %s, want %s", got, want)
+		}
+		if r.Header.Get("X-CB-ACCESS-KEY") != "key" || r.Header.Get("X-CB-ACCESS-PASSPHRASE") != "pass" {
+			t.Errorf("Missing auth headers")
+		}
+
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"products":[{"id":"BTC-USD","base_increment":"0.00000001","quote_increment":"0.01"}],
+				"pagination":{"next_cursor":"abc","has_next":true}}`)
+			return
+		}
+		fmt.Fprint(w, `{"products":[{"id":"ETH-USD","base_increment":"0.0001","quote_increment":"0.01"}],
+			"pagination":{"next_cursor":"","has_next":false}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "secret", "pass", time.Second)
+	client.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	list, err := client.ListProducts(context.Background(), "pf-1")
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	if requests != 2 || len(list) != 2 {
+		t.Fatalf("Expected 2 pages and 2 products, got %d pages and %d products", requests, len(list))
+	}
+	if list[0].Symbol != "BTC-USD" || list[0].QuoteIncrement != "0.01" || list[1].BaseIncrement != "0.0001" {
+		t.Fatalf("Unexpected products %+v", list)
+	}
+}
+
+func TestListProductsApiError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"invalid signature"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "secret", "pass", time.Second)
+	_, err := client.ListProducts(context.Background(), "pf-1")
+
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected ApiError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "invalid signature" {
+		t.Fatalf("Unexpected error %+v", apiErr)
+	}
+
+	if _, err := client.ListProducts(context.Background(), ""); err == nil {
+		t.Fatal("Expected error without portfolio id")
+	}
+}
@@ -0,0 +1,148 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package products
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Product is the metadata known for one tradable symbol. Fields other than Symbol
+// are empty when the source does not provide them.
+type Product struct {
+	Symbol         string
+	DisplayName    string
+	BaseIncrement  string
+	QuoteIncrement string
+	Permissions    []string // Portfolio entitlements for the product, e.g. PERMISSION_READ, PERMISSION_TRADE
+}
+
+// Catalog is the cached product list used to validate symbols before requests are sent.
+// It stays empty (and validation is skipped) until a source such as the REST API fills it.
+type Catalog struct {
+	mu       sync.RWMutex
+	products map[string]Product
+}
+
+func NewCatalog() *Catalog {
+	return &Catalog{products: make(map[string]Product)}
+}
+
+// Replace swaps in a freshly fetched product list
+func (c *Catalog) Replace(list []Product) {
+	products := make(map[string]Product, len(list))
+	for _, p := range list {
+		p.Symbol = strings.ToUpper(strings.TrimSpace(p.Symbol))
+		if p.Symbol != "" {
+			products[p.Symbol] = p
+		}
+	}
+
+	c.mu.Lock()
+	c.products = products
+	c.mu.Unlock()
+}
+
+func (c *Catalog) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.products)
+}
+
+func (c *Catalog) Get(symbol string) (Product, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	p, ok := c.products[strings.ToUpper(symbol)]
+	return p, ok
+}
+
+// Symbols returns all known symbols in sorted order
+func (c *Catalog) Symbols() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	symbols := make([]string, 0, len(c.products))
+	for symbol := range c.products {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// List returns all known products sorted by symbol
+func (c *Catalog) List() []Product {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	list := make([]Product, 0, len(c.products))
+	for _, p := range c.products {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Symbol < list[j].Symbol })
+	return list
+}
+
+// Suggest returns the known symbol closest to an unknown one: an exact match once separators
+// and case are ignored (BTCUSD, btc_usd -> BTC-USD), otherwise the nearest within two edits
+func (c *Catalog) Suggest(symbol string) (string, bool) {
+	target := normalize(symbol)
+	best, bestDistance := "", 3
+
+	for _, candidate := range c.Symbols() {
+		normalized := normalize(candidate)
+		if normalized == target {
+			return candidate, true
+		}
+		if d := editDistance(normalized, target); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, best != ""
+}
+
+func normalize(symbol string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '/', ' ':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(symbol))
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,63 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package products
+
+import (
+	"testing"
+)
+
+func testCatalog() *Catalog {
+	c := NewCatalog()
+	c.Replace([]Product{{Symbol: "BTC-USD"}, {Symbol: "ETH-USD"}, {Symbol: "sol-usd"}, {Symbol: ""}})
+	return c
+}
+
+func TestCatalogReplace(t *testing.T) {
+	c := testCatalog()
+	if c.Len() != 3 {
+		t.Fatalf("Expected 3 products, got %d", c.Len())
+	}
+	if _, ok := c.Get("SOL-USD"); !ok {
+		t.Fatal("Expected symbols to be normalized to upper case")
+	}
+	if got := c.Symbols(); got[0] != "BTC-USD" || got[2] != "SOL-USD" {
+		t.Fatalf("Expected sorted symbols, got %v", got)
+	}
+}
+
+func TestCatalogSuggest(t *testing.T) {
+	c := testCatalog()
+
+	testCases := []struct {
+		input    string
+		expected string
+		found    bool
+	}{
+		{"BTCUSD", "BTC-USD", true},
+		{"btc_usd", "BTC-USD", true},
+		{"BTC-USDT", "BTC-USD", true},
+		{"ETH-UDS", "ETH-USD", true},
+		{"DOGE-EUR", "", false},
+	}
+
+	for _, tc := range testCases {
+		got, found := c.Suggest(tc.input)
+		if got != tc.expected || found != tc.found {
+			t.Fatalf("Suggest(%q): expected %q/%v, got %q/%v", tc.input, tc.expected, tc.found, got, found)
+		}
+	}
+}
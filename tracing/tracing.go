@@ -0,0 +1,360 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package tracing records sampled spans and exports them to an OpenTelemetry collector over
+// OTLP/HTTP with JSON encoding, so no OpenTelemetry SDK is needed. A nil *Tracer and the nil
+// spans it returns are no-ops, so instrumented code needs no checks.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	exportInterval = 5 * time.Second
+	exportBatch    = 512  // Spans per request; reaching it triggers an export early
+	maxPending     = 8192 // Spans beyond this are dropped while the collector is slow or down
+	scopeName      = "prime-fix-md-go"
+)
+
+// Tracer samples root spans and exports finished spans in the background
+type Tracer struct {
+	Endpoint   string            // OTLP/HTTP traces URL, e.g. http://localhost:4318/v1/traces
+	Service    string            // Reported as the service.name resource attribute
+	SampleRate float64           // Fraction of root spans recorded, 0 to 1
+	Headers    map[string]string // Extra request headers, e.g. for collector authentication
+	HttpClient *http.Client
+
+	mu      sync.Mutex
+	pending []*Span
+	dropped int
+
+	kick     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewTracer starts exporting to endpoint; call Shutdown to flush the remaining spans
+func NewTracer(endpoint, service string, sampleRate float64, headers map[string]string, timeout time.Duration) (*Tracer, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("tracing: endpoint is required")
+	}
+	if sampleRate < 0 || sampleRate > 1 {
+		return nil, fmt.Errorf("tracing: sample rate must be between 0 and 1, got %v", sampleRate)
+	}
+
+	t := &Tracer{
+		Endpoint:   endpoint,
+		Service:    service,
+		SampleRate: sampleRate,
+		Headers:    headers,
+		HttpClient: &http.Client{Timeout: timeout},
+		kick:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+	}
+	t.wg.Add(1)
+	go t.run()
+	return t, nil
+}
+
+// Span is one timed operation. Child spans share the trace of their parent.
+type Span struct {
+	tracer   *Tracer
+	traceId  [16]byte
+	spanId   [8]byte
+	parentId [8]byte
+	root     bool
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    []attribute
+	err      string
+}
+
+type attribute struct {
+	Key   string         `json:"key"`
+	Value attributeValue `json:"value"`
+}
+
+type attributeValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"` // int64 is a string in OTLP JSON
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+// Start begins a root span at start if this trace is sampled, otherwise it returns nil
+func (t *Tracer) Start(name string, start time.Time) *Span {
+	if t == nil || t.SampleRate <= 0 || (t.SampleRate < 1 && mathrand.Float64() >= t.SampleRate) {
+		return nil
+	}
+	s := &Span{tracer: t, name: name, start: start, root: true}
+	rand.Read(s.traceId[:])
+	rand.Read(s.spanId[:])
+	return s
+}
+
+// Child begins a span under s starting now
+func (s *Span) Child(name string) *Span {
+	if s == nil {
+		return nil
+	}
+	return s.ChildAt(name, time.Now())
+}
+
+// ChildAt begins a span under s at start, e.g. for work that began before it was observed
+func (s *Span) ChildAt(name string, start time.Time) *Span {
+	if s == nil {
+		return nil
+	}
+	c := &Span{tracer: s.tracer, traceId: s.traceId, parentId: s.spanId, name: name, start: start}
+	rand.Read(c.spanId[:])
+	return c
+}
+
+// SetAttr records a string, integer, float or bool attribute; other types are formatted as strings
+func (s *Span) SetAttr(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	var v attributeValue
+	switch value := value.(type) {
+	case string:
+		v.StringValue = &value
+	case int:
+		i := strconv.Itoa(value)
+		v.IntValue = &i
+	case int64:
+		i := strconv.FormatInt(value, 10)
+		v.IntValue = &i
+	case float64:
+		v.DoubleValue = &value
+	case bool:
+		v.BoolValue = &value
+	default:
+		str := fmt.Sprint(value)
+		v.StringValue = &str
+	}
+	s.attrs = append(s.attrs, attribute{Key: key, Value: v})
+}
+
+// SetError marks the span as failed
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.err = err.Error()
+}
+
+// End finishes the span and queues it for export
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.EndAt(time.Now())
+}
+
+// EndAt finishes the span at end and queues it for export
+func (s *Span) EndAt(end time.Time) {
+	if s == nil {
+		return
+	}
+	s.end = end
+	s.tracer.enqueue(s)
+}
+
+func (t *Tracer) enqueue(s *Span) {
+	t.mu.Lock()
+	if len(t.pending) >= maxPending {
+		t.dropped++
+		t.mu.Unlock()
+		return
+	}
+	t.pending = append(t.pending, s)
+	full := len(t.pending) >= exportBatch
+	t.mu.Unlock()
+
+	if full {
+		select {
+		case t.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (t *Tracer) run() {
+	defer t.wg.Done()
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			t.flush()
+			return
+		case <-ticker.C:
+		case <-t.kick:
+		}
+		t.flush()
+	}
+}
+
+// flush exports everything pending in batches, logging and dropping a batch the collector refuses
+func (t *Tracer) flush() {
+	for {
+		t.mu.Lock()
+		n := min(len(t.pending), exportBatch)
+		batch := t.pending[:n:n]
+		t.pending = t.pending[n:]
+		dropped := t.dropped
+		t.dropped = 0
+		t.mu.Unlock()
+
+		if dropped > 0 {
+			log.Printf("Tracing: dropped %d spans while the collector was behind", dropped)
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := t.export(batch); err != nil {
+			log.Printf("Tracing: failed to export %d spans: %v", len(batch), err)
+			return
+		}
+	}
+}
+
+// Shutdown exports the spans still pending and stops the tracer
+func (t *Tracer) Shutdown() {
+	if t == nil {
+		return
+	}
+	t.stopOnce.Do(func() { close(t.stop) })
+	t.wg.Wait()
+}
+
+func (t *Tracer) export(spans []*Span) error {
+	body, err := json.Marshal(t.request(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, t.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("collector returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// OTLP JSON request body (ExportTraceServiceRequest)
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []attribute `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanJson `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanJson struct {
+	TraceId           string      `json:"traceId"`
+	SpanId            string      `json:"spanId"`
+	ParentSpanId      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []attribute `json:"attributes,omitempty"`
+	Status            *status     `json:"status,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	spanKindInternal = 1
+	spanKindConsumer = 5
+	statusCodeError  = 2
+)
+
+func (t *Tracer) request(spans []*Span) exportRequest {
+	service := t.Service
+	out := make([]spanJson, 0, len(spans))
+	for _, s := range spans {
+		j := spanJson{
+			TraceId:           hex.EncodeToString(s.traceId[:]),
+			SpanId:            hex.EncodeToString(s.spanId[:]),
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        s.attrs,
+		}
+		if s.root {
+			j.Kind = spanKindConsumer
+		} else {
+			j.ParentSpanId = hex.EncodeToString(s.parentId[:])
+		}
+		if s.err != "" {
+			j.Status = &status{Code: statusCodeError, Message: s.err}
+		}
+		out = append(out, j)
+	}
+
+	return exportRequest{ResourceSpans: []resourceSpans{{
+		Resource:   resource{Attributes: []attribute{{Key: "service.name", Value: attributeValue{StringValue: &service}}}},
+		ScopeSpans: []scopeSpans{{Scope: scope{Name: scopeName}, Spans: out}},
+	}}}
+}
@@ -0,0 +1,95 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExportSpans(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received exportRequest
+		apiKey   string
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		apiKey = r.Header.Get("X-Api-Key")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Invalid export body: %v", err)
+		}
+	}))
+	defer collector.Close()
+
+	tracer, err := NewTracer(collector.URL+"/v1/traces", "test-svc", 1, map[string]string{"X-Api-Key": "k"}, time.Second)
+	if err != nil {
+		t.Fatalf("NewTracer failed: %v", err)
+	}
+	start := time.Unix(1700000000, 0)
+	root := tracer.Start("market_data", start)
+	root.SetAttr("md.symbol", "BTC-USD")
+	root.SetAttr("md.entries", 3)
+	child := root.ChildAt("store", start.Add(time.Millisecond))
+	child.SetError(errors.New("disk full"))
+	child.EndAt(start.Add(2 * time.Millisecond))
+	root.EndAt(start.Add(3 * time.Millisecond))
+	tracer.Shutdown()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if apiKey != "k" || len(received.ResourceSpans) != 1 {
+		t.Fatalf("Expected one export with the configured header, got %+v (key %q)", received, apiKey)
+	}
+	rs := received.ResourceSpans[0]
+	if *rs.Resource.Attributes[0].Value.StringValue != "test-svc" {
+		t.Fatalf("Expected service.name test-svc, got %+v", rs.Resource)
+	}
+	spans := rs.ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	store, msg := spans[0], spans[1]
+	if store.TraceId != msg.TraceId || store.ParentSpanId != msg.SpanId || msg.ParentSpanId != "" || len(msg.TraceId) != 32 {
+		t.Fatalf("Expected store to be a child of market_data in one trace, got %+v / %+v", store, msg)
+	}
+	if store.Status == nil || store.Status.Code != statusCodeError || store.StartTimeUnixNano != "1700000000001000000" {
+		t.Fatalf("Expected a failed store span starting 1ms in, got %+v", store)
+	}
+	if msg.Kind != spanKindConsumer || *msg.Attributes[1].Value.IntValue != "3" {
+		t.Fatalf("Expected a consumer root span with md.entries=3, got %+v", msg)
+	}
+}
+
+func TestUnsampledSpansAreNoops(t *testing.T) {
+	var tracer *Tracer
+	span := tracer.Start("market_data", time.Now())
+	span.SetAttr("k", "v")
+	span.Child("parse").End()
+	span.End()
+	tracer.Shutdown()
+
+	tracer = &Tracer{SampleRate: 0}
+	if tracer.Start("market_data", time.Now()) != nil {
+		t.Fatal("Expected no span at sample rate 0")
+	}
+}
@@ -0,0 +1,252 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package upload copies files to S3 or Google Cloud Storage. Both are reached through the
+// S3-compatible XML API with AWS Signature Version 4, so no cloud SDK is needed; GCS uses
+// HMAC keys for this.
+package upload
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderS3  = "s3"
+	ProviderGcs = "gcs"
+
+	gcsEndpoint = "https://storage.googleapis.com"
+)
+
+// Client uploads files to one bucket under a key prefix
+type Client struct {
+	Provider        string
+	Bucket          string
+	Prefix          string
+	Region          string
+	Endpoint        string // Path-style API root, e.g. https://s3.us-east-1.amazonaws.com
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    string
+	HttpClient      *http.Client
+
+	now func() time.Time
+}
+
+// NewClient fills in the endpoint and region for the provider. Credentials left empty are read
+// from AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY / AWS_SESSION_TOKEN (s3) or
+// GCS_HMAC_ACCESS_ID / GCS_HMAC_SECRET (gcs).
+func NewClient(provider, bucket, prefix, region, endpoint, accessKeyId, secretAccessKey string, timeout time.Duration) (*Client, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("upload: bucket is required")
+	}
+
+	c := &Client{
+		Provider:        provider,
+		Bucket:          bucket,
+		Prefix:          strings.Trim(prefix, "/"),
+		Region:          region,
+		Endpoint:        strings.TrimRight(endpoint, "/"),
+		AccessKeyId:     accessKeyId,
+		SecretAccessKey: secretAccessKey,
+		HttpClient:      &http.Client{Timeout: timeout},
+		now:             time.Now,
+	}
+
+	switch provider {
+	case ProviderS3:
+		if c.Region == "" {
+			c.Region = "us-east-1"
+		}
+		if c.Endpoint == "" {
+			c.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", c.Region)
+		}
+		c.AccessKeyId = firstNonEmpty(c.AccessKeyId, os.Getenv("AWS_ACCESS_KEY_ID"))
+		c.SecretAccessKey = firstNonEmpty(c.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY"))
+		c.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	case ProviderGcs:
+		if c.Region == "" {
+			c.Region = "auto"
+		}
+		if c.Endpoint == "" {
+			c.Endpoint = gcsEndpoint
+		}
+		c.AccessKeyId = firstNonEmpty(c.AccessKeyId, os.Getenv("GCS_HMAC_ACCESS_ID"))
+		c.SecretAccessKey = firstNonEmpty(c.SecretAccessKey, os.Getenv("GCS_HMAC_SECRET"))
+	default:
+		return nil, fmt.Errorf("upload: unknown provider %q (expected s3 or gcs)", provider)
+	}
+
+	if c.AccessKeyId == "" || c.SecretAccessKey == "" {
+		return nil, fmt.Errorf("upload: no %s credentials configured", provider)
+	}
+	return c, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Key returns the object key a local file is uploaded to: the prefix plus the file name
+func (c *Client) Key(localPath string) string {
+	return path.Join(c.Prefix, filepath.Base(localPath))
+}
+
+// Location is the bucket URL of key, for messages
+func (c *Client) Location(key string) string {
+	scheme := "s3"
+	if c.Provider == ProviderGcs {
+		scheme = "gs"
+	}
+	return fmt.Sprintf("%s://%s/%s", scheme, c.Bucket, key)
+}
+
+// UploadFile uploads localPath to key with a single PUT (objects up to 5 GB)
+func (c *Client) UploadFile(ctx context.Context, localPath, key string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("upload: %v", err)
+	}
+	defer f.Close()
+
+	// The payload hash is part of the signature, so the file is read twice
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return fmt.Errorf("upload: reading %s: %v", localPath, err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("upload: %v", err)
+	}
+
+	u := c.Endpoint + "/" + escapePath(c.Bucket+"/"+key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, f)
+	if err != nil {
+		return fmt.Errorf("upload: %v", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if c.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.SessionToken)
+	}
+	signV4(req, hex.EncodeToString(hash.Sum(nil)), c.AccessKeyId, c.SecretAccessKey, c.Region, "s3", c.now())
+
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload: %s: %v", c.Location(key), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("upload: %s: HTTP %d: %s", c.Location(key), resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// escapePath is the SigV4 UriEncode of an object path: every byte other than A-Z, a-z, 0-9,
+// '-', '_', '.' and '~' is percent-encoded, except the '/' between segments. url.PathEscape
+// is not enough, since it leaves characters such as '=', ':' and '@' as they are.
+func escapePath(p string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&0x0f])
+		}
+	}
+	return b.String()
+}
+
+// signV4 adds x-amz-date, x-amz-content-sha256 and an Authorization header signing every header
+// already set on req plus Host
+func signV4(req *http.Request, payloadHash, accessKeyId, secretAccessKey, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex(canonicalRequest)
+
+	key := hmacSha256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSha256(key, region)
+	key = hmacSha256(key, service)
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyId, scope, signedHeaders, signature))
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
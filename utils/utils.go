@@ -21,6 +21,8 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/quickfixgo/quickfix"
 )
@@ -52,3 +54,75 @@ func LoadSettings(path string) (*quickfix.Settings, error) {
 	}(f)
 	return quickfix.ParseSettings(f)
 }
+
+var fixTimestampLayouts = []string{
+	"20060102-15:04:05.000000000",
+	"20060102-15:04:05.000000",
+	"20060102-15:04:05.000",
+	"20060102-15:04:05",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+}
+
+var fixTimeOnlyLayouts = []string{
+	"15:04:05.000000000",
+	"15:04:05.000000",
+	"15:04:05.000",
+	"15:04:05",
+}
+
+// ParseFixTime parses FIX UTCTimestamp and UTCTimeOnly values (plus RFC3339). Time-only values
+// are placed on the UTC date of ref; if that lands more than an hour after ref, the previous day is used
+// so entries stamped just before midnight are not pushed into the future.
+func ParseFixTime(s string, ref time.Time) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+
+	// The usual FIX shapes go straight to one layout; time.Parse accepts any fraction after
+	// the seconds, and skipping the failed attempts below saves their error allocations
+	if len(s) >= 17 && s[8] == '-' && s[11] == ':' {
+		if t, err := time.Parse("20060102-15:04:05", s); err == nil {
+			return t.UTC(), true
+		}
+	} else if len(s) >= 8 && s[2] == ':' && s[5] == ':' {
+		if t, err := time.Parse("15:04:05", s); err == nil {
+			return onRefDate(t, ref), true
+		}
+	}
+
+	for _, layout := range fixTimestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+
+	for _, layout := range fixTimeOnlyLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return onRefDate(t, ref), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// onRefDate places a time of day on ref's UTC date, or the day before if that is over an hour ahead of ref
+func onRefDate(t, ref time.Time) time.Time {
+	ref = ref.UTC()
+	full := time.Date(ref.Year(), ref.Month(), ref.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	if full.Sub(ref) > time.Hour {
+		full = full.AddDate(0, 0, -1)
+	}
+	return full
+}
+
+// FormatFixTime renders t as a FIX UTCTimestamp, with nanoseconds only when milliseconds would lose precision
+func FormatFixTime(t time.Time) string {
+	t = t.UTC()
+	if t.Nanosecond()%int(time.Millisecond) != 0 {
+		return t.Format("20060102-15:04:05.000000000")
+	}
+	return t.Format("20060102-15:04:05.000")
+}
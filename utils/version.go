@@ -16,8 +16,120 @@
 
 package utils
 
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
 const Version = "1.0.1"
 
+// Set at build time, e.g. go build -ldflags "-X prime-fix-md-go/utils.Commit=$(git rev-parse --short HEAD)";
+// see the Makefile. Commit and BuildDate fall back to the VCS stamp Go records in the binary.
+var (
+	Commit    string
+	BuildDate string
+	Features  string // Comma-separated extras to report, e.g. "docker"
+)
+
+// BuildInfo identifies the running binary
+type BuildInfo struct {
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit,omitempty"`
+	Modified  bool     `json:"modified,omitempty"` // Built from a tree with uncommitted changes
+	BuildDate string   `json:"buildDate,omitempty"`
+	GoVersion string   `json:"goVersion"`
+	Platform  string   `json:"platform"`
+	Features  []string `json:"features"`
+}
+
+func GetBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Features:  []string{},
+	}
+
+	cgo := false
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = shortCommit(s.Value)
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			case "CGO_ENABLED":
+				cgo = s.Value == "1"
+			}
+		}
+	}
+
+	// SQLite storage needs cgo; without it the client can only run with --no-persist
+	if cgo {
+		info.Features = append(info.Features, "sqlite")
+	}
+	for _, f := range strings.Split(Features, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			info.Features = append(info.Features, f)
+		}
+	}
+	return info
+}
+
+func shortCommit(c string) string {
+	if len(c) > 12 {
+		return c[:12]
+	}
+	return c
+}
+
+// FullVersion is a one-line description of the build, for logs and heartbeats
 func FullVersion() string {
-	return "prime-fix-md-go v" + Version
+	info := GetBuildInfo()
+	details := []string{}
+	if info.Commit != "" {
+		commit := info.Commit
+		if info.Modified {
+			commit += "-dirty"
+		}
+		details = append(details, commit)
+	}
+	if info.BuildDate != "" {
+		details = append(details, "built "+info.BuildDate)
+	}
+	details = append(details, info.GoVersion+" "+info.Platform)
+	return fmt.Sprintf("prime-fix-md-go v%s (%s)", info.Version, strings.Join(details, ", "))
+}
+
+// VersionDetails lists the build information one field per line
+func VersionDetails() string {
+	info := GetBuildInfo()
+	var b strings.Builder
+	fmt.Fprintf(&b, "prime-fix-md-go v%s\n", info.Version)
+	commit := dashIfEmpty(info.Commit)
+	if info.Modified {
+		commit += " (uncommitted changes)"
+	}
+	fmt.Fprintf(&b, "  Commit:   %s\n", commit)
+	fmt.Fprintf(&b, "  Built:    %s\n", dashIfEmpty(info.BuildDate))
+	fmt.Fprintf(&b, "  Go:       %s %s\n", info.GoVersion, info.Platform)
+	fmt.Fprintf(&b, "  Features: %s\n", dashIfEmpty(strings.Join(info.Features, ", ")))
+	return b.String()
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
 }
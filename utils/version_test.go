@@ -0,0 +1,51 @@
+/**
+ * Copyright 2025-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestGetBuildInfoUsesLdflags(t *testing.T) {
+	defer func(c, d, f string) { Commit, BuildDate, Features = c, d, f }(Commit, BuildDate, Features)
+	Commit, BuildDate, Features = "abc1234", "2024-05-01T00:00:00Z", " docker, ,fips"
+
+	info := GetBuildInfo()
+	if info.Commit != "abc1234" || info.BuildDate != "2024-05-01T00:00:00Z" {
+		t.Fatalf("expected ldflags commit and date, got %q %q", info.Commit, info.BuildDate)
+	}
+	n := len(info.Features)
+	if n < 2 || info.Features[n-2] != "docker" || info.Features[n-1] != "fips" {
+		t.Fatalf("expected docker and fips features, got %v", info.Features)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Fatalf("expected Go version %s, got %s", runtime.Version(), info.GoVersion)
+	}
+}
+
+func TestFullVersion(t *testing.T) {
+	defer func(c, d string) { Commit, BuildDate = c, d }(Commit, BuildDate)
+	Commit, BuildDate = "abc1234", "2024-05-01"
+
+	v := FullVersion()
+	for _, want := range []string{"v" + Version, "abc1234", "built 2024-05-01", runtime.Version()} {
+		if !strings.Contains(v, want) {
+			t.Fatalf("expected %q in %q", want, v)
+		}
+	}
+}